@@ -4,21 +4,43 @@ package main
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"github.com/kk7453603/avito_2024_summer/internal/config"
 	"github.com/kk7453603/avito_2024_summer/internal/db"
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver"
 	"github.com/kk7453603/avito_2024_summer/internal/hasher"
 	"github.com/kk7453603/avito_2024_summer/internal/logger"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/audit_log"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/authentication"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/buy_item"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/catalog"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/coin_request"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/directory"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/event_stream"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/fulfilment"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/inventory"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/jwt_token_manager"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/leaderboard"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/ledger"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/notifications"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/policies"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/promotions"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/savings"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/scheduled_transfer"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/team"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/transaction"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/user_info"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/webhook"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/wishlist"
 	"github.com/kk7453603/avito_2024_summer/internal/server"
 	"github.com/kk7453603/avito_2024_summer/internal/server/handlers"
 )
@@ -45,14 +67,77 @@ func main() {
 		os.Exit(1)
 	}
 	authSrv := authentication.New(storage, passwdHasher)
-	usrInfSrv := user_info.New(storage) // creating a user information module
-	txSrv := transaction.New(storage)   // transaction module creation
-	buyItmSrv := buy_item.New(storage)  // creating an item purchase module
+	usrInfSrv := user_info.New(storage)                                   // creating a user information module
+	txSrv := transaction.New(storage, cfg.Transfers)                      // transaction module creation
+	buyItmSrv := buy_item.New(storage)                                    // creating an item purchase module
+	invSrv := inventory.New(storage, cfg.Inventory)                       // creating an inventory module
+	promoSrv := promotions.New(storage)                                   // creating a discounts and promo codes module
+	catalogSrv := catalog.New(storage)                                    // creating a store assortment module
+	fulfilmentSrv := fulfilment.New(storage)                              // creating a merch fulfilment module
+	wishlistSrv := wishlist.New(storage)                                  // creating a wishlist module
+	notifySrv := notifications.New(storage)                               // creating a notifications module
+	leaderboardSrv := leaderboard.New(storage)                            // creating a leaderboard module
+	savingsSrv := savings.New(storage)                                    // creating a savings goals module
+	scheduledSrv := scheduled_transfer.New(storage, txSrv, cfg.Scheduled) // creating a scheduled transfers module
+	requestSrv := coin_request.New(storage, txSrv, cfg.Requests)          // creating a coin requests module
+	teamSrv := team.New(storage, txSrv)                                   // creating a teams module
+	auditSrv := audit_log.New(storage)                                    // creating an audit log module
+	webhookSrv := webhook.New(storage, cfg.Webhooks)                      // creating a webhooks module
+	streamSrv := event_stream.New(storage, cfg.Events)                    // creating a user events stream module
+	ledgerSrv := ledger.New(storage)                                      // creating a finance export module
+	directorySrv := directory.New(storage, cfg.Directory)                 // creating an HR directory sync module
+	policySrv, err := policies.New(storage, cfg.Policies)                 // creating a balance policies module
+	if err != nil {
+		logg.Error("policies.New", "err", err.Error())
+		os.Exit(1)
+	}
 
 	// creating the main request handler
 	usrHandlers := handlers.NewUserHandlers(ctx, authSrv, tknMng, usrInfSrv, txSrv, buyItmSrv)
+	invHandlers := handlers.NewInventoryHandlers(ctx, invSrv)
+	prmHandlers := handlers.NewPromotionHandlers(ctx, promoSrv)
+	ctlHandlers := handlers.NewCatalogHandlers(ctx, catalogSrv)
+	flfHandlers := handlers.NewFulfilmentHandlers(ctx, fulfilmentSrv)
+	wshHandlers := handlers.NewWishlistHandlers(ctx, wishlistSrv, notifySrv)
+	ldbHandlers := handlers.NewLeaderboardHandlers(ctx, leaderboardSrv)
+	svgHandlers := handlers.NewSavingsHandlers(ctx, savingsSrv)
+	schHandlers := handlers.NewScheduledTransferHandlers(ctx, scheduledSrv)
+	plcHandlers := handlers.NewPolicyHandlers(ctx, policySrv)
+	rvwHandlers := handlers.NewTransferReviewHandlers(ctx, txSrv)
+	reqHandlers := handlers.NewCoinRequestHandlers(ctx, requestSrv)
+	tmsHandlers := handlers.NewTeamHandlers(ctx, teamSrv)
+	adtHandlers := handlers.NewAuditLogHandlers(ctx, auditSrv)
+	whkHandlers := handlers.NewWebhookHandlers(ctx, webhookSrv)
+	evtHandlers := handlers.NewEventStreamHandlers(ctx, streamSrv)
+	lgrHandlers := handlers.NewLedgerHandlers(ctx, ledgerSrv)
 	// server creation
-	serv := server.New(ctx, cfg.APIServer, usrHandlers, tknMng)
+	serv := server.New(ctx, cfg.APIServer, &server.Handlers{
+		User:        usrHandlers,
+		Inventory:   invHandlers,
+		Promotions:  prmHandlers,
+		Catalog:     ctlHandlers,
+		Fulfilment:  flfHandlers,
+		Wishlist:    wshHandlers,
+		Leaderboard: ldbHandlers,
+		Savings:     svgHandlers,
+		Scheduled:   schHandlers,
+		Policies:    plcHandlers,
+		Reviews:     rvwHandlers,
+		Requests:    reqHandlers,
+		Teams:       tmsHandlers,
+		Audit:       adtHandlers,
+		Webhooks:    whkHandlers,
+		Events:      evtHandlers,
+		Ledger:      lgrHandlers,
+	}, tknMng, storage, authSrv)
+	// gRPC server for the service-to-service calls, served by the same services
+	grpcSrv := grpcserver.New(cfg.GRPC, &grpcserver.Services{
+		Auth:        authSrv,
+		Tokens:      tknMng,
+		UserInfo:    usrInfSrv,
+		Transaction: txSrv,
+		BuyItem:     buyItmSrv,
+	}, tknMng, authSrv)
 
 	// server startup
 	go func() {
@@ -62,6 +147,29 @@ func main() {
 			os.Exit(1)
 		}
 	}()
+	go func() {
+		if err := grpcSrv.Start(); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			logg.Error("grpcSrv.Start", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	// background workers, stopped with the application context before the storage is closed
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context, *slog.Logger){
+		scheduledSrv.Run, // scheduled transfers worker
+		policySrv.Run,    // balance policies job
+		requestSrv.Run,   // coin requests expiry job
+		webhookSrv.Run,   // webhook delivery worker
+		streamSrv.Run,    // user events listener waking up the event streams
+		directorySrv.Run, // HR directory sync job
+	} {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx, logg)
+		}()
+	}
 
 	// graceful shutdown
 	sigCtx, sigStop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
@@ -72,14 +180,23 @@ func main() {
 	ctxTimeOut, ctxTimeOutCancel := context.WithTimeout(ctx, 5*time.Second)
 	defer ctxTimeOutCancel()
 
+	// the event streams never end on their own
+	streamSrv.Shutdown()
+
 	if err = serv.Shutdown(ctxTimeOut); err != nil {
 		logg.Error("serv.Shutdown", "err", err.Error())
 	}
+	if err = grpcSrv.Shutdown(ctxTimeOut); err != nil {
+		logg.Error("grpcSrv.Shutdown", "err", err.Error())
+	}
+
+	// the workers are to finish with the storage before it's closed
+	ctxCancel()
+	workers.Wait()
 
 	if storage != nil {
 		storage.Close()
 	}
 
-	ctxCancel()
 	logg.Info("Application Stopped!")
 }
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/directory"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/ledger"
+)
+
+// run runs the command given by the arguments.
+func (c *cli) run(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "user":
+		return c.showAccount(ctx, args[1:])
+	case "balance":
+		return c.showBalance(ctx, args[1:])
+	case "history":
+		return c.showHistory(ctx, args[1:])
+	case "inventory":
+		if len(args) > 1 && args[1] == "adjust" {
+			return c.adjustInventory(ctx, args[2:])
+		}
+		return c.showInventory(ctx, args[1:])
+	case "grant":
+		return c.grant(ctx, args[1:])
+	case "item":
+		if len(args) < 2 {
+			return errUsage
+		}
+		switch args[1] {
+		case "create":
+			return c.createItem(ctx, args[2:])
+		case "update":
+			return c.updateItem(ctx, args[2:])
+		}
+	case "lock":
+		return c.setLocked(ctx, args[1:], true)
+	case "unlock":
+		return c.setLocked(ctx, args[1:], false)
+	case "deactivate":
+		return c.setDeactivated(ctx, args[1:], true)
+	case "reactivate":
+		return c.setDeactivated(ctx, args[1:], false)
+	case "claim-token":
+		return c.issueClaimToken(ctx, args[1:])
+	case "import":
+		return c.syncDirectory(ctx, args[1:], false)
+	case "sync":
+		return c.syncDirectory(ctx, args[1:], true)
+	case "export":
+		return c.export(ctx, args[1:])
+	}
+	return errUsage
+}
+
+func (c *cli) showAccount(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	account, err := c.srv.GetAccount(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(account, func(w io.Writer) { printAccount(w, account) })
+}
+
+func (c *cli) showBalance(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	account, err := c.srv.GetAccount(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	balance := map[string]any{"username": account.Username, "coins": account.Coins, "saved": account.Saved}
+	return c.print(balance, func(w io.Writer) {
+		fmt.Fprintf(w, "coins\t%d\nsaved\t%d\n", account.Coins, account.Saved)
+	})
+}
+
+func (c *cli) showHistory(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	history, err := c.srv.GetCoinHistory(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(history, func(w io.Writer) {
+		fmt.Fprintln(w, "DIRECTION\tUSER\tAMOUNT\tTYPE")
+		if history.Receiving != nil {
+			for _, r := range *history.Receiving {
+				fmt.Fprintf(w, "received\t%s\t%d\t%s\n", r.User, r.Amount, r.Kind)
+			}
+		}
+		if history.Sending != nil {
+			for _, s := range *history.Sending {
+				fmt.Fprintf(w, "sent\t%s\t%d\ttransfer\n", s.User, s.Amount)
+			}
+		}
+	})
+}
+
+func (c *cli) showInventory(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	inventory, err := c.srv.GetInventory(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(inventory, func(w io.Writer) {
+		fmt.Fprintln(w, "ITEM\tSKU\tQUANTITY")
+		if inventory != nil {
+			for _, m := range *inventory {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", m.Type, m.Variant, m.Quantity)
+			}
+		}
+	})
+}
+
+func (c *cli) adjustInventory(ctx context.Context, args []string) error {
+	fs := newFlagSet("inventory adjust")
+	reason := fs.String("reason", "", "why the inventory is adjusted")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 3 {
+		return errUsage
+	}
+	quantity, err := strconv.Atoi(args[2])
+	if err != nil {
+		return errUsage
+	}
+
+	left, err := c.srv.AdjustInventory(ctx, args[0], args[1], quantity, *reason)
+	if err != nil {
+		return err
+	}
+	result := map[string]any{"username": args[0], "sku": args[1], "quantity": left}
+	return c.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "%s now owns %d of %s\n", args[0], left, args[1])
+	})
+}
+
+func (c *cli) grant(ctx context.Context, args []string) error {
+	fs := newFlagSet("grant")
+	reason := fs.String("reason", "", "why the coins are granted, shown in the user's history")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 2 {
+		return errUsage
+	}
+	coins, err := strconv.Atoi(args[1])
+	if err != nil {
+		return errUsage
+	}
+
+	balance, err := c.srv.GrantCoins(ctx, args[0], coins, *reason)
+	if err != nil {
+		return err
+	}
+	result := map[string]any{"username": args[0], "granted": coins, "coins": balance}
+	return c.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "granted %d coins to %s, the balance is %d\n", coins, args[0], balance)
+	})
+}
+
+func (c *cli) createItem(ctx context.Context, args []string) error {
+	fs := newFlagSet("item create")
+	slug := fs.String("slug", "", "the item's slug, also the SKU of its default variant")
+	title := fs.String("title", "", "the item's title")
+	price := fs.Int("price", -1, "the item's price in coins")
+	category := optionalString(fs, "category", "the item's category")
+	stock := optionalInt(fs, "stock", "units in stock, unlimited if not given")
+	limit := optionalInt(fs, "per-user-limit", "units one user can own, unlimited if not given")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 0 {
+		return errUsage
+	}
+
+	item := &models.Item{
+		Slug:         *slug,
+		Title:        *title,
+		Price:        *price,
+		Category:     *category,
+		Stock:        *stock,
+		PerUserLimit: *limit,
+	}
+	if err = c.srv.CreateItem(ctx, item); err != nil {
+		return err
+	}
+	return c.print(item, func(w io.Writer) { printItem(w, item) })
+}
+
+func (c *cli) updateItem(ctx context.Context, args []string) error {
+	fs := newFlagSet("item update")
+	title := optionalString(fs, "title", "the item's title")
+	price := optionalInt(fs, "price", "the item's price in coins")
+	category := optionalString(fs, "category", "the item's category")
+	limit := optionalInt(fs, "per-user-limit", "units one user can own")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 1 {
+		return errUsage
+	}
+
+	item, err := c.srv.UpdateItem(ctx, args[0], &models.ItemUpdate{
+		Title:        *title,
+		Price:        *price,
+		Category:     *category,
+		PerUserLimit: *limit,
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(item, func(w io.Writer) { printItem(w, item) })
+}
+
+func (c *cli) setLocked(ctx context.Context, args []string, locked bool) error {
+	fs := newFlagSet("lock")
+	reason := fs.String("reason", "", "why the account is locked or unlocked")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 1 {
+		return errUsage
+	}
+
+	account, err := c.srv.SetLocked(ctx, args[0], locked, *reason)
+	if err != nil {
+		return err
+	}
+	return c.print(account, func(w io.Writer) { printAccount(w, account) })
+}
+
+func (c *cli) setDeactivated(ctx context.Context, args []string, deactivated bool) error {
+	fs := newFlagSet("deactivate")
+	reason := fs.String("reason", "", "why the account is deactivated or reactivated")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 1 {
+		return errUsage
+	}
+
+	account, err := c.srv.SetDeactivated(ctx, args[0], deactivated, *reason)
+	if err != nil {
+		return err
+	}
+	return c.print(account, func(w io.Writer) { printAccount(w, account) })
+}
+
+// issueClaimToken issues a new claim token for the imported account the user hasn't logged in to yet.
+func (c *cli) issueClaimToken(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	token, err := c.srv.IssueClaimToken(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	result := map[string]any{"username": args[0], "claimToken": token}
+	return c.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "claim token of %s\t%s\n", args[0], token)
+	})
+}
+
+// syncDirectory imports the users listed in the file, or syncs the accounts with the full HR directory export
+// in it, deactivating the accounts of the users not listed.
+func (c *cli) syncDirectory(ctx context.Context, args []string, full bool) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "csv or json, by the file's extension if not given")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 1 {
+		return errUsage
+	}
+	if *format == "" {
+		*format = directory.FormatOf(args[0])
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sync := c.directory.Import
+	if full {
+		sync = c.directory.Sync
+	}
+	result, err := sync(ctx, f, *format)
+	if err != nil {
+		return err
+	}
+	return c.print(result, func(w io.Writer) {
+		// the users need the claim tokens to log in the first time
+		for _, u := range result.Created {
+			fmt.Fprintf(w, "created\t%s\tclaim token %s\n", u, result.ClaimTokens[u])
+		}
+		for _, u := range result.Adopted {
+			fmt.Fprintf(w, "adopted\t%s\n", u)
+		}
+		for _, u := range result.Reactivated {
+			fmt.Fprintf(w, "reactivated\t%s\n", u)
+		}
+		for _, u := range result.Deactivated {
+			fmt.Fprintf(w, "deactivated\t%s\n", u)
+		}
+		fmt.Fprintf(w, "unchanged\t%d\n", result.Unchanged)
+	})
+}
+
+// export writes the coin ledger to the output file or stdout. It's NDJSON by default if the output is JSON.
+// The export only reads, so a dry run is the same.
+func (c *cli) export(ctx context.Context, args []string) error {
+	fs := newFlagSet("export")
+	from := fs.String("from", "", "the start of the range")
+	to := fs.String("to", "", "the end of the range, excluded")
+	format := fs.String("format", "", "csv or ndjson")
+	output := fs.String("output", "", "the file to write, stdout if not given")
+	args, err := parseArgs(fs, args)
+	if err != nil || len(args) != 0 {
+		return errUsage
+	}
+
+	start, err := parseTime(*from)
+	if err != nil {
+		return errUsage
+	}
+	end, err := parseTime(*to)
+	if err != nil {
+		return errUsage
+	}
+	if *format == "" {
+		*format = ledger.FormatCSV
+		if c.json {
+			*format = ledger.FormatNDJSON
+		}
+	}
+
+	out := c.out
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// the rows are written in large chunks, the ones exported before a failure are kept
+	w := bufio.NewWriter(out)
+	err = c.ledger.Export(ctx, start, end, *format, w)
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// parseTime parses an RFC 3339 time or a date, taken in UTC.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// print prints the result as JSON or as aligned text, noting that nothing was saved if it's a dry run.
+func (c *cli) print(v any, text func(w io.Writer)) error {
+	if c.json {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		if c.dryRun {
+			return enc.Encode(map[string]any{"dryRun": true, "result": v})
+		}
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	text(w)
+	if c.dryRun {
+		fmt.Fprintln(w, "dry run, nothing was saved")
+	}
+	return w.Flush()
+}
+
+func printAccount(w io.Writer, a *models.Account) {
+	locked, deactivated, synced := "no", "no", "no"
+	if a.LockedAt != nil {
+		locked = "since " + a.LockedAt.Format(time.DateTime)
+	}
+	if a.DeactivatedAt != nil {
+		deactivated = "since " + a.DeactivatedAt.Format(time.DateTime)
+	}
+	if a.DirectoryManaged {
+		synced = "yes"
+	}
+	fmt.Fprintf(w, "id\t%d\nusername\t%s\nrole\t%s\ncoins\t%d\nsaved\t%d\nlocked\t%s\ndeactivated\t%s\n"+
+		"synced with directory\t%s\ncreated\t%s\n",
+		a.ID, a.Username, a.Role, a.Coins, a.Saved, locked, deactivated, synced, a.CreatedAt.Format(time.DateTime))
+}
+
+func printItem(w io.Writer, item *models.Item) {
+	fmt.Fprintf(w, "slug\t%s\ntitle\t%s\nprice\t%d\ncategory\t%s\nstock\t%s\nper-user limit\t%s\n",
+		item.Slug, item.Title, item.Price, orDash(item.Category), intOrDash(item.Stock), intOrDash(item.PerUserLimit))
+	for _, v := range item.Variants {
+		fmt.Fprintf(w, "variant\t%s (stock %s)\n", v.SKU, intOrDash(v.Stock))
+	}
+}
+
+// newFlagSet creates the flag set of a command, the errors are reported by printing the usage.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs parses the flags placed anywhere among the arguments and returns the other arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// optionalString defines a string flag which is nil unless it's given.
+func optionalString(fs *flag.FlagSet, name, usage string) **string {
+	p := new(*string)
+	fs.Func(name, usage, func(s string) error {
+		*p = &s
+		return nil
+	})
+	return p
+}
+
+// optionalInt defines an integer flag which is nil unless it's given.
+func optionalInt(fs *flag.FlagSet, name, usage string) **int {
+	p := new(*int)
+	fs.Func(name, usage, func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*p = &n
+		return nil
+	})
+	return p
+}
+
+func orDash(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
+
+func intOrDash(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n)
+}
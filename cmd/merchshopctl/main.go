@@ -0,0 +1,163 @@
+// Package main = the support staff's command line tool working with the merch shop's database directly.
+//
+// Usage:
+//
+//	merchshopctl [--json] [--dry-run] [--actor NAME] <command> [arguments]
+//
+// The changes are recorded in the audit log on behalf of the actor, the OS user by default.
+// A dry run goes through the same checks and prints the same result, but nothing is saved.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/user"
+	"slices"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/config"
+	"github.com/kk7453603/avito_2024_summer/internal/db"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/admin"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/directory"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/ledger"
+)
+
+const usage = `Usage: merchshopctl [--json] [--dry-run] [--actor NAME] <command> [arguments]
+
+Commands:
+  user <username>                                   show the user's account
+  balance <username>                                show the user's balance
+  history <username>                                show the coins received and sent by the user
+  inventory <username>                              show the items owned by the user
+  inventory adjust <username> <sku> <quantity>      add units of the variant, or take them away if negative
+      [--reason TEXT]
+  grant <username> <amount> --reason TEXT           add coins to the user's balance
+  item create --slug SLUG --title TITLE --price N   add an item with its default variant
+      [--category C] [--stock N] [--per-user-limit N]
+  item update <slug> [--title T] [--price N]        change the item
+      [--category C] [--per-user-limit N]
+  lock <username> [--reason TEXT]                   lock the account, the user can't log in
+  unlock <username> [--reason TEXT]                 unlock the account
+  deactivate <username> [--reason TEXT]             deactivate the account of the user who has left, the user
+                                                    can't log in or receive coins, the directory sync skips it
+  reactivate <username> [--reason TEXT]             reactivate the account, the directory sync skips it
+  claim-token <username>                            issue a new one-time token for the first login to the imported
+                                                    account, the token issued before stops being valid
+  import <file> [--format csv|json]                 create the accounts of the listed users, with their grants,
+                                                    and print the tokens for their first login
+  sync <file> [--format csv|json]                   sync the accounts with the HR directory export, the users
+                                                    not listed are deactivated, as import for the new ones
+  export --from TIME --to TIME                      export the coin ledger of the range for finance,
+      [--format csv|ndjson] [--output FILE]         TIME is RFC 3339 or a date in UTC, --to is excluded
+`
+
+// errUsage is returned when the command line is invalid, the usage is printed.
+var errUsage = errors.New("invalid command line")
+
+// cli runs the commands and prints their results.
+type cli struct {
+	srv       *admin.Service
+	ledger    *ledger.Service
+	directory *directory.Service
+	out       io.Writer
+	json      bool
+	dryRun    bool
+}
+
+func main() {
+	flags := flag.NewFlagSet("merchshopctl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	jsonOut := flags.Bool("json", false, "print the results as JSON")
+	dryRun := flags.Bool("dry-run", false, "check the change and print its result without saving it")
+	actor := flags.String("actor", currentUser(), "the name the changes are audited under")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+	ctx := context.Background()
+
+	storage, err := db.NewPostgresPool(ctx, cfg.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "merchshopctl:", err)
+		os.Exit(1)
+	}
+
+	ctx = audit.WithRequest(ctx, audit.Request{
+		Actor:     actor,
+		UserAgent: "merchshopctl",
+		RequestID: newRequestID(),
+	})
+	if *dryRun {
+		ctx = db.WithDryRun(ctx)
+	}
+
+	c := &cli{
+		srv:       admin.New(storage),
+		ledger:    ledger.New(storage),
+		directory: directory.New(storage, cfg.Directory),
+		out:       os.Stdout,
+		json:      *jsonOut,
+		dryRun:    *dryRun,
+	}
+	err = c.run(ctx, flags.Args())
+	storage.Close()
+
+	switch {
+	case errors.Is(err, errUsage):
+		flags.Usage()
+		os.Exit(2)
+	case err != nil:
+		c.printError(err)
+		os.Exit(1)
+	}
+}
+
+// printError prints the error with its stable code and the details of the report, as a problem if the output is JSON.
+// The causes of the internal failures are printed too, the tool is only run by the support staff.
+func (c *cli) printError(err error) {
+	appErr := apperr.From(err)
+	detail := appErr.Error()
+	if appErr.Kind() == apperr.KindInternal {
+		detail = err.Error()
+	}
+
+	if c.json {
+		problem := map[string]any{"code": appErr.Code(), "detail": detail}
+		maps.Copy(problem, appErr.Extensions())
+		_ = json.NewEncoder(c.out).Encode(problem)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "merchshopctl: %s (%s)\n", detail, appErr.Code())
+	for _, k := range slices.Sorted(maps.Keys(appErr.Extensions())) {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", k, appErr.Extensions()[k])
+	}
+}
+
+// currentUser returns the name of the OS user running the tool.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "merchshopctl"
+}
+
+// newRequestID generates the ID the audit log entries of one run are grouped by.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
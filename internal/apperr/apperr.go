@@ -0,0 +1,119 @@
+// Package apperr defines the typed errors of the domain. Each error has a kind, deciding how it's reported
+// to the clients, e.g. the HTTP status, and a stable machine-readable code, so that the clients don't have
+// to parse the messages. Any error of another type is treated as an internal failure.
+package apperr
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Kind is the class of an error.
+type Kind int
+
+const (
+	KindInternal        Kind = iota // a failure of the service itself, e.g. of the database
+	KindInvalid                     // the request is invalid or breaks a business rule
+	KindUnauthorized                // the client isn't authenticated
+	KindForbidden                   // the client isn't allowed to do this
+	KindNotFound                    // the object doesn't exist
+	KindConflict                    // the request conflicts with the current state of the object
+	KindTooManyRequests             // the client has run into a rate limit
+)
+
+// HTTPStatus returns the HTTP status of the errors of the kind.
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeInvalidRequest is the code of the requests failing the validation of their format.
+const CodeInvalidRequest = "invalid_request"
+
+// ErrDatabase is reported for the errors without a type, they come from the storage.
+var ErrDatabase = New(KindInternal, "database_error", "something happened to the database")
+
+// Error is a domain error.
+type Error struct {
+	kind       Kind
+	code       string
+	message    string
+	extensions map[string]any // additional members of the error report
+}
+
+// New creates an error of the kind with the stable code and the human-readable message.
+func New(kind Kind, code, message string) *Error {
+	return &Error{
+		kind:    kind,
+		code:    code,
+		message: message,
+	}
+}
+
+// Invalid creates an error of the request failing the validation.
+func Invalid(message string) *Error {
+	return New(KindInvalid, CodeInvalidRequest, message)
+}
+
+// Error returns the message.
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Kind returns the class of the error.
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+// Code returns the stable code of the error.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Extensions returns the additional members of the error report.
+func (e *Error) Extensions() map[string]any {
+	return e.extensions
+}
+
+// Is reports whether the target is an error with the same code, so that the copies made by With
+// still match the error they were made of.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.code == e.code
+}
+
+// With returns a copy of the error with the additional member of the error report,
+// e.g. the details of a partially failed operation.
+func (e *Error) With(key string, value any) *Error {
+	cp := *e
+	cp.extensions = maps.Clone(e.extensions)
+	if cp.extensions == nil {
+		cp.extensions = make(map[string]any, 1)
+	}
+	cp.extensions[key] = value
+	return &cp
+}
+
+// From returns the domain error in the chain of err, or ErrDatabase if there is none.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return ErrDatabase
+}
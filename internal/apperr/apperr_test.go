@@ -0,0 +1,48 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_With(t *testing.T) {
+	base := New(KindForbidden, "transfer_blocked", "the transfer is blocked")
+
+	withResults := base.With("results", []int{1})
+	require.ErrorIs(t, withResults, base)
+	require.Equal(t, map[string]any{"results": []int{1}}, withResults.Extensions())
+	require.Nil(t, base.Extensions(), "the original error must stay intact")
+
+	withBoth := withResults.With("batchId", 7)
+	require.Len(t, withBoth.Extensions(), 2)
+	require.Len(t, withResults.Extensions(), 1)
+
+	require.NotErrorIs(t, withResults, New(KindForbidden, "other_code", "the transfer is blocked"))
+}
+
+func TestFrom(t *testing.T) {
+	notFound := New(KindNotFound, "item_not_found", "item not found")
+
+	require.Same(t, notFound, From(notFound))
+	require.Same(t, notFound, From(fmt.Errorf("buying: %w", notFound)))
+	require.Same(t, ErrDatabase, From(errors.New("connection refused")))
+}
+
+func TestKind_HTTPStatus(t *testing.T) {
+	tests := map[Kind]int{
+		KindInternal:        http.StatusInternalServerError,
+		KindInvalid:         http.StatusBadRequest,
+		KindUnauthorized:    http.StatusUnauthorized,
+		KindForbidden:       http.StatusForbidden,
+		KindNotFound:        http.StatusNotFound,
+		KindConflict:        http.StatusConflict,
+		KindTooManyRequests: http.StatusTooManyRequests,
+	}
+	for kind, want := range tests {
+		require.Equal(t, want, kind.HTTPStatus(), kind)
+	}
+}
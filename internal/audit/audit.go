@@ -0,0 +1,112 @@
+// Package audit carries the data of the request being audited through the context and chains
+// the audit log entries with hashes.
+//
+// The hash of an entry covers the previous entry's hash and all the entry's fields except its ID,
+// so an entry changed, removed or inserted into the log breaks the chain from that point on.
+// The log is split into several chains, so that the transactions auditing different objects rarely
+// wait for each other to append their entries.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// GenesisHash is the previous hash of the first entry of each chain.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Chains is the number of hash chains the log is split into.
+const Chains = 16
+
+// ChainOf returns the chain the entries about the target, e.g. user:42, are appended to.
+func ChainOf(target string) int {
+	h := fnv.New32a()
+	h.Write([]byte(target))
+	return int(h.Sum32() % Chains)
+}
+
+// Request is who made the request being audited and from where.
+type Request struct {
+	ActorID   *int
+	Actor     *string
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of the context carrying the request data.
+func WithRequest(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// FromContext returns the request data carried by the context, it's empty for the background jobs.
+func FromContext(ctx context.Context) Request {
+	r, _ := ctx.Value(requestKey{}).(Request)
+	return r
+}
+
+// NewEntry creates the entry of the action on the target, e.g. user:42, made within the request carried
+// by the context. The details, if any, are stored as JSON.
+func NewEntry(ctx context.Context, action, target string, details map[string]any) *models.AuditEntry {
+	r := FromContext(ctx)
+	e := &models.AuditEntry{
+		ActorID:   r.ActorID,
+		Actor:     r.Actor,
+		Action:    action,
+		Target:    target,
+		IP:        r.IP,
+		UserAgent: r.UserAgent,
+		RequestID: r.RequestID,
+	}
+	if details != nil {
+		// the keys of a map are marshalled sorted, the same details always give the same JSON
+		if b, err := json.Marshal(details); err == nil {
+			str := string(b)
+			e.Details = &str
+		}
+	}
+	return e
+}
+
+// Hash calculates the hash of the entry chained to the previous one. Each field is prefixed with its length,
+// so that the fields can't be shifted into each other.
+func Hash(prevHash string, e *models.AuditEntry) string {
+	h := sha256.New()
+	write := func(s string) {
+		h.Write([]byte(strconv.Itoa(len(s))))
+		h.Write([]byte{':'})
+		h.Write([]byte(s))
+	}
+	writeOptional := func(s *string) {
+		if s == nil {
+			h.Write([]byte{'-'})
+			return
+		}
+		write(*s)
+	}
+
+	write(prevHash)
+	if e.ActorID != nil {
+		write(strconv.Itoa(*e.ActorID))
+	} else {
+		h.Write([]byte{'-'})
+	}
+	writeOptional(e.Actor)
+	write(e.Action)
+	write(e.Target)
+	writeOptional(e.Details)
+	write(e.IP)
+	write(e.UserAgent)
+	write(e.RequestID)
+	write(e.CreatedAt.UTC().Format(time.RFC3339Nano))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+func TestNewEntry(t *testing.T) {
+	actorID, actor := 7, "testUser"
+	ctx := WithRequest(context.Background(), Request{
+		ActorID: &actorID, Actor: &actor, IP: "10.0.0.1", UserAgent: "curl/8.0", RequestID: "req-1",
+	})
+
+	e := NewEntry(ctx, models.AuditCoinsTransferred, "user:2", map[string]any{"from": 7, "amount": 50})
+
+	require.Equal(t, 7, *e.ActorID)
+	require.Equal(t, "10.0.0.1", e.IP)
+	require.Equal(t, "req-1", e.RequestID)
+	require.Equal(t, `{"amount":50,"from":7}`, *e.Details)
+
+	// the background jobs have no request
+	e = NewEntry(context.Background(), models.AuditCoinsTransferred, "user:2", nil)
+	require.Nil(t, e.ActorID)
+	require.Nil(t, e.Details)
+}
+
+func TestHash(t *testing.T) {
+	entry := func() *models.AuditEntry {
+		return &models.AuditEntry{
+			Action:    models.AuditLoginFailed,
+			Target:    "user:7",
+			IP:        "10.0.0.1",
+			CreatedAt: time.Date(2025, 3, 10, 12, 0, 0, 123000, time.UTC),
+		}
+	}
+	hash := Hash(GenesisHash, entry())
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, Hash(GenesisHash, entry()))
+
+	tests := []struct {
+		name   string
+		prev   string
+		change func(e *models.AuditEntry)
+	}{
+		{
+			name:   "Other previous entry",
+			prev:   hash,
+			change: func(*models.AuditEntry) {},
+		},
+		{
+			name:   "Changed action",
+			prev:   GenesisHash,
+			change: func(e *models.AuditEntry) { e.Action = models.AuditLogin },
+		},
+		{
+			name:   "Shifted fields",
+			prev:   GenesisHash,
+			change: func(e *models.AuditEntry) { e.Target, e.IP = "user:710.0.0.1", "" },
+		},
+		{
+			name:   "Changed time",
+			prev:   GenesisHash,
+			change: func(e *models.AuditEntry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := entry()
+			tt.change(e)
+			require.NotEqual(t, hash, Hash(tt.prev, e))
+		})
+	}
+}
+
+func TestChainOf(t *testing.T) {
+	chain := ChainOf("user:7")
+	require.Equal(t, chain, ChainOf("user:7"))
+	require.GreaterOrEqual(t, chain, 0)
+	require.Less(t, chain, Chains)
+}
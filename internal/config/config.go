@@ -6,17 +6,35 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/kk7453603/avito_2024_summer/internal/db"
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver"
 	"github.com/kk7453603/avito_2024_summer/internal/logger"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/coin_request"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/directory"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/event_stream"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/inventory"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/jwt_token_manager"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/policies"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/scheduled_transfer"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/transaction"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/webhook"
 	"github.com/kk7453603/avito_2024_summer/internal/server"
 )
 
 // Config holds the entire application configuration.
 type Config struct {
-	Log       *logger.Config            `envconfig:"LOG" required:"true"`
-	DB        *db.Config                `envconfig:"DB" required:"true"`
-	APIServer *server.Config            `envconfig:"HTTP" required:"true"`
-	JWT       *jwt_token_manager.Config `envconfig:"JWT" required:"true"`
+	Log       *logger.Config             `envconfig:"LOG" required:"true"`
+	DB        *db.Config                 `envconfig:"DB" required:"true"`
+	APIServer *server.Config             `envconfig:"HTTP" required:"true"`
+	GRPC      *grpcserver.Config         `envconfig:"GRPC" required:"true"`
+	JWT       *jwt_token_manager.Config  `envconfig:"JWT" required:"true"`
+	Inventory *inventory.Config          `envconfig:"INVENTORY" required:"true"`
+	Scheduled *scheduled_transfer.Config `envconfig:"SCHEDULED_TRANSFERS" required:"true"`
+	Policies  *policies.Config           `envconfig:"POLICY" required:"true"`
+	Transfers *transaction.Config        `envconfig:"TRANSFER_RULES" required:"true"`
+	Requests  *coin_request.Config       `envconfig:"COIN_REQUESTS" required:"true"`
+	Webhooks  *webhook.Config            `envconfig:"WEBHOOKS" required:"true"`
+	Events    *event_stream.Config       `envconfig:"EVENTS" required:"true"`
+	Directory *directory.Config          `envconfig:"DIRECTORY" required:"true"`
 }
 
 // MustLoad is a function that loads environment variables from a `.env` file and
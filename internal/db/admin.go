@@ -0,0 +1,268 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	getAccountByUsername = `
+		SELECT u.id, u.username, u.role, u.coins,
+		       COALESCE((SELECT SUM(g.saved) FROM savings_goals g WHERE g.user_id = u.id), 0)::INT AS saved,
+		       u.locked_at, u.deactivated_at, u.directory_managed, u.created_at
+		FROM users u
+		WHERE u.username = $1;`
+	grantCoins  = `UPDATE users SET coins = coins + $2, updated_at = NOW() WHERE id = $1 RETURNING coins;`
+	recordGrant = `
+		INSERT INTO transactions (sender_id, receiver_id, receiver_account_id, coins, kind, reason)
+		VALUES (NULL, $1, (SELECT id FROM accounts WHERE user_id = $1), $2, 'grant', $3);`
+
+	getVariantItemBySKU  = `SELECT item_slug FROM item_variants WHERE sku = $1;`
+	getInventoryQuantity = `SELECT quantity FROM inventory WHERE user_id = $1 AND sku = $2;`
+
+	createItem = `
+		INSERT INTO store (slug, title, price, category, per_user_limit)
+		VALUES ($1, $2, $3, $4, $5);`
+	createDefaultVariant = `
+		INSERT INTO item_variants (sku, item_slug, title, stock, is_default)
+		VALUES ($1, $1, $2, $3, TRUE);`
+	updateItem = `
+		UPDATE store
+		SET title          = COALESCE($2, title),
+		    price          = COALESCE($3, price),
+		    category       = COALESCE($4, category),
+		    per_user_limit = COALESCE($5, per_user_limit)
+		WHERE slug = $1;`
+
+	lockUser   = `UPDATE users SET locked_at = COALESCE(locked_at, NOW()), updated_at = NOW() WHERE id = $1 RETURNING locked_at;`
+	unlockUser = `UPDATE users SET locked_at = NULL, updated_at = NOW() WHERE id = $1 RETURNING locked_at;`
+)
+
+type dryRunKey struct{}
+
+// WithDryRun returns a copy of the context making the support staff's changes roll back instead of being committed.
+// A dry run goes through the same checks as the change and reports the same result, but nothing is saved,
+// not even the audit log entry.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// finishAdminTx commits the transaction of the support staff's change, or rolls it back if the change failed
+// or is a dry run.
+func finishAdminTx(ctx context.Context, tx pgx.Tx, err error) {
+	if dryRun, _ := ctx.Value(dryRunKey{}).(bool); err != nil || dryRun {
+		_ = tx.Rollback(ctx)
+	} else {
+		_ = tx.Commit(ctx)
+	}
+}
+
+// GetAccountByUsername retrieves the user's account with the coins put aside in the savings goals.
+func (s *Storage) GetAccountByUsername(ctx context.Context, username string) (*models.Account, error) {
+	rows, err := s.pool.Query(ctx, getAccountByUsername, username)
+	if err != nil {
+		return nil, err
+	}
+	account, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[models.Account])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	return account, err
+}
+
+// GrantCoins adds the coins to the user's balance, the grant is recorded in the coin history with the reason.
+// Returns the new balance.
+func (s *Storage) GrantCoins(ctx context.Context, userID, coins int, reason string) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	balance := 0
+	err = tx.QueryRow(ctx, grantCoins, userID, coins).Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrUserNotFound
+		return 0, err
+	} else if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, recordGrant, userID, coins, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditCoinsGranted, "user:"+strconv.Itoa(userID),
+		map[string]any{"coins": coins, "reason": reason, "balance": balance}))
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// AdjustInventory adds the quantity of the variant to the user's inventory, or takes it away if the quantity
+// is negative. Returns the new quantity of the variant in the inventory.
+func (s *Storage) AdjustInventory(ctx context.Context, userID int, sku string, quantity int, reason string) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	slug := ""
+	err = tx.QueryRow(ctx, getVariantItemBySKU, sku).Scan(&slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrVariantNotFound
+		return 0, err
+	} else if err != nil {
+		return 0, err
+	}
+
+	if quantity > 0 {
+		_, err = tx.Exec(ctx, addItemToInventoryByUserID, userID, slug, sku, quantity)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			err = models.ErrUserNotFound
+			return 0, err
+		}
+	} else {
+		var tag pgconn.CommandTag
+		tag, err = tx.Exec(ctx, removeItemFromInventoryByUserID, userID, sku, -quantity)
+		if err == nil && tag.RowsAffected() == 0 {
+			err = models.ErrNotEnoughItems
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	left := 0
+	err = tx.QueryRow(ctx, getInventoryQuantity, userID, sku).Scan(&left)
+	if err != nil {
+		return 0, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditInventoryAdjusted, "user:"+strconv.Itoa(userID),
+		map[string]any{"sku": sku, "quantity": quantity, "left": left, "reason": reason}))
+	if err != nil {
+		return 0, err
+	}
+	return left, nil
+}
+
+// CreateItem adds the item to the store with its default variant, the SKU of which is the item's slug.
+// The stock of the item is the default variant's stock.
+func (s *Storage) CreateItem(ctx context.Context, item *models.Item) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	_, err = tx.Exec(ctx, createItem, item.Slug, item.Title, item.Price, item.Category, item.PerUserLimit)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		err = models.ErrItemExists
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, createDefaultVariant, item.Slug, item.Title, item.Stock)
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		// a variant of another item has the SKU the default variant would get
+		err = models.ErrVariantExists
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	item.Variants, err = collectVariants(ctx, tx, getVariantsBySlug, item.Slug)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditItemCreated, "item:"+item.Slug,
+		map[string]any{"title": item.Title, "price": item.Price, "category": item.Category,
+			"stock": item.Stock, "perUserLimit": item.PerUserLimit}))
+	return err
+}
+
+// UpdateItem changes the fields of the item set in the update and returns the updated item.
+// The variants, with their stock, are managed separately.
+func (s *Storage) UpdateItem(ctx context.Context, slug string, update *models.ItemUpdate) (*models.Item, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	tag, err := tx.Exec(ctx, updateItem, slug, update.Title, update.Price, update.Category, update.PerUserLimit)
+	if err != nil {
+		return nil, err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrItemNotFound
+		return nil, err
+	}
+
+	var item models.Item
+	err = scanItem(tx.QueryRow(ctx, getItemBySlug, slug), &item)
+	if err != nil {
+		return nil, err
+	}
+	item.Variants, err = collectVariants(ctx, tx, getVariantsBySlug, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditItemUpdated, "item:"+slug,
+		map[string]any{"title": update.Title, "price": update.Price, "category": update.Category,
+			"perUserLimit": update.PerUserLimit}))
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetUserLocked locks the user's account, so that the user can't log in, or unlocks it.
+// Returns the time the account is locked at, nil if it's unlocked. Locking a locked account keeps the time.
+func (s *Storage) SetUserLocked(ctx context.Context, userID int, locked bool, reason string) (*time.Time, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	query, action := unlockUser, models.AuditUserUnlocked
+	if locked {
+		query, action = lockUser, models.AuditUserLocked
+	}
+	var lockedAt *time.Time
+	err = tx.QueryRow(ctx, query, userID).Scan(&lockedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrUserNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	var details map[string]any
+	if reason != "" {
+		details = map[string]any{"reason": reason}
+	}
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, action, "user:"+strconv.Itoa(userID), details))
+	if err != nil {
+		return nil, err
+	}
+	return lockedAt, nil
+}
@@ -0,0 +1,129 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	// the transaction appends its entries to the chain of its first entry, kept in a setting local
+	// to the transaction, so that it holds one chain's lock at most
+	getTxAuditChain = `SELECT COALESCE(NULLIF(current_setting('audit.chain', true), '')::INT, $1::INT);`
+	// the entries of a chain are appended one at a time, so that each of them is chained to the chain's latest one;
+	// the lock is held until the end of the transaction making the audited change
+	lockAuditChain    = `SELECT set_config('audit.chain', $1::INT::TEXT, true), pg_advisory_xact_lock(hashtext('audit_log'), $1::INT);`
+	getAuditChainHead = `SELECT hash FROM audit_log WHERE chain = $1 ORDER BY id DESC LIMIT 1;`
+	appendAuditEntry  = `
+		INSERT INTO audit_log (actor_id, actor, action, target, details, ip, user_agent, request_id, created_at, chain, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id;`
+	auditEntryFields = `
+		id, actor_id, actor, action, target, details, ip, user_agent, request_id, created_at, chain, prev_hash, hash
+		FROM audit_log`
+	getAuditEntries = `
+		SELECT ` + auditEntryFields + `
+		WHERE ($1 = '' OR actor = $1) AND ($2 = '' OR action = $2) AND ($3 = '' OR target = $3)
+		  AND ($4 = '' OR request_id = $4)
+		  AND ($5::TIMESTAMP IS NULL OR created_at >= $5) AND ($6::TIMESTAMP IS NULL OR created_at < $6)
+		  AND ($7 = 0 OR id < $7)
+		ORDER BY id DESC
+		LIMIT $8;`
+	getAuditChain = `
+		SELECT ` + auditEntryFields + `
+		WHERE chain = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3;`
+)
+
+// RecordAudit appends the entry to the audit log on its own, for the events not changing anything else,
+// such as a failed login. Sets the entry's ID, creation time and hashes.
+func (s *Storage) RecordAudit(ctx context.Context, entry *models.AuditEntry) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = appendAudit(ctx, tx, entry)
+	return err
+}
+
+// GetAuditEntries retrieves the audit log entries matching the filter, the latest first.
+func (s *Storage) GetAuditEntries(ctx context.Context, f *models.AuditFilter) (*[]models.AuditEntry, error) {
+	return collectAuditEntries(ctx, s.pool, getAuditEntries,
+		f.Actor, f.Action, f.Target, f.RequestID, f.From, f.To, f.BeforeID, f.Limit)
+}
+
+// GetAuditChain retrieves up to limit entries of the audit log's chain following the given ID,
+// in the order they were appended.
+func (s *Storage) GetAuditChain(ctx context.Context, chain int, afterID int64, limit int) (*[]models.AuditEntry, error) {
+	return collectAuditEntries(ctx, s.pool, getAuditChain, chain, afterID, limit)
+}
+
+// appendAudit appends the entry to the audit log within the transaction making the audited change,
+// so that the entry is saved if and only if the change is. All the entries of the transaction go
+// to the chain of its first entry's target, which stays locked until the transaction ends, the other
+// chains are appended to meanwhile.
+func appendAudit(ctx context.Context, tx pgx.Tx, entry *models.AuditEntry) error {
+	err := tx.QueryRow(ctx, getTxAuditChain, audit.ChainOf(entry.Target)).Scan(&entry.Chain)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, lockAuditChain, entry.Chain); err != nil {
+		return err
+	}
+
+	prevHash := audit.GenesisHash
+	err = tx.QueryRow(ctx, getAuditChainHead, entry.Chain).Scan(&prevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// the time is set here rather than by the database, it's hashed as stored
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.PrevHash = prevHash
+	entry.Hash = audit.Hash(prevHash, entry)
+
+	return tx.QueryRow(ctx, appendAuditEntry,
+		entry.ActorID,
+		entry.Actor,
+		entry.Action,
+		entry.Target,
+		entry.Details,
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+		entry.CreatedAt,
+		entry.Chain,
+		entry.PrevHash,
+		entry.Hash,
+	).Scan(&entry.ID)
+}
+
+// collectAuditEntries lists the audit log entries selected by the query.
+func collectAuditEntries(ctx context.Context, q querier, query string, args ...any) (*[]models.AuditEntry, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuditEntry])
+	if err != nil {
+		return nil, err
+	}
+	return &entries, nil
+}
@@ -0,0 +1,87 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	createTransferBatch = `INSERT INTO transfer_batches (sender_id, coins) VALUES ($1, $2) RETURNING id;`
+	recordBatchLeg      = `
+		INSERT INTO transactions (sender_id, receiver_id, sender_account_id, receiver_account_id, initiator_id, coins, batch_id)
+		VALUES ($1, $2, (SELECT id FROM accounts WHERE user_id = $1), (SELECT id FROM accounts WHERE user_id = $2), $1, $3, $4);`
+)
+
+// TransferCoinsBatch transfers coins from one user to several recipients in one transaction, the sender's
+// balance is checked against the total. Each leg is recorded as a transaction sharing the batch ID.
+// Every leg is checked against the anti-fraud rules before any coins move, the earlier legs counting towards
+// the caps of the next ones. The first leg breaking a rule is flagged for review, nothing is sent and
+// a *models.BatchLegError with the rule's error is returned. Returns the batch ID.
+func (s *Storage) TransferCoinsBatch(ctx context.Context, fromUserID int, legs []models.BatchTransfer, rules *models.TransferRules) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	total := 0
+	for i, leg := range legs {
+		var ruleErr error
+		ruleErr, err = enforceRules(ctx, tx, rules, userTransfer(fromUserID, leg.ReceiverID, leg.Amount), total)
+		if err != nil {
+			return 0, err
+		} else if ruleErr != nil {
+			return 0, &models.BatchLegError{Leg: i, Err: ruleErr}
+		}
+		total += leg.Amount
+	}
+
+	tag, err := tx.Exec(ctx, subtractFromCoinsByUserID, total, fromUserID)
+	if err != nil {
+		return 0, err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrNotEnoughCoins
+		return 0, err
+	}
+
+	batchID := 0
+	err = tx.QueryRow(ctx, createTransferBatch, fromUserID, total).Scan(&batchID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, leg := range legs {
+		tag, err = tx.Exec(ctx, addToActiveCoinsByUserID, leg.Amount, leg.ReceiverID)
+		if err != nil {
+			return 0, err
+		} else if tag.RowsAffected() == 0 {
+			err = models.ErrRecipientInactive
+			return 0, err
+		}
+		_, err = tx.Exec(ctx, recordBatchLeg, fromUserID, leg.ReceiverID, leg.Amount, batchID)
+		if err != nil {
+			return 0, err
+		}
+		err = addCoinsReceivedEvent(ctx, tx, fromUserID, leg.ReceiverID, leg.Amount, nil, &batchID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditBatchTransferred, "batch:"+strconv.Itoa(batchID),
+		map[string]any{"from": fromUserID, "amount": total, "recipients": len(legs)}))
+	if err != nil {
+		return 0, err
+	}
+	return batchID, nil
+}
@@ -0,0 +1,198 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	variantColumns    = `sku, item_slug, title, size, color, price_override, stock, is_default`
+	getVariants       = `SELECT ` + variantColumns + ` FROM item_variants ORDER BY item_slug, is_default DESC, sku;`
+	getVariantsBySlug = `SELECT ` + variantColumns + ` FROM item_variants WHERE item_slug = $1 ORDER BY is_default DESC, sku;`
+	getVariant        = `
+		SELECT ` + variantColumns + ` FROM item_variants
+		WHERE item_slug = $1 AND (sku = $2 OR ($2 = '' AND is_default));`
+	createVariant = `
+		INSERT INTO item_variants (sku, item_slug, title, size, color, price_override, stock)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	updateVariant = `
+		WITH old AS (SELECT sku, stock FROM item_variants WHERE sku = $1 FOR UPDATE)
+		UPDATE item_variants v
+		SET title = $2, size = $3, color = $4, price_override = $5, stock = $6, updated_at = NOW()
+		FROM old
+		WHERE v.sku = old.sku
+		RETURNING v.item_slug, v.is_default, old.stock;`
+	restockVariant = `
+		WITH old AS (SELECT sku, stock FROM item_variants WHERE sku = $1 FOR UPDATE)
+		UPDATE item_variants v
+		SET stock = v.stock + $2, updated_at = NOW()
+		FROM old
+		WHERE v.sku = old.sku
+		RETURNING v.sku, v.item_slug, v.title, v.size, v.color, v.price_override, v.stock, v.is_default, old.stock;`
+)
+
+// collectVariants fetches the item variants selected by the query.
+func collectVariants(ctx context.Context, q querier, query string, args ...any) ([]models.Variant, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Variant])
+}
+
+// lookupVariant fetches the item's variant by its SKU, or the default variant if the SKU is empty.
+func lookupVariant(ctx context.Context, q querier, slug, sku string) (*models.Variant, error) {
+	rows, err := q.Query(ctx, getVariant, slug, sku)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[models.Variant])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrVariantNotFound
+	}
+	return variant, err
+}
+
+// CreateVariant saves a new, non-default variant of the item.
+func (s *Storage) CreateVariant(ctx context.Context, variant *models.Variant) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, createVariant,
+		variant.SKU,
+		variant.ItemSlug,
+		variant.Title,
+		variant.Size,
+		variant.Color,
+		variant.PriceOverride,
+		variant.Stock,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			err = models.ErrVariantExists
+			return err
+		case foreignKeyViolation:
+			err = models.ErrItemNotFound
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	variant.IsDefault = false
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditVariantCreated, "variant:"+variant.SKU,
+		map[string]any{"item": variant.ItemSlug, "stock": variant.Stock, "priceOverride": variant.PriceOverride}))
+	return err
+}
+
+// UpdateVariant updates the description, price override and stock of the variant by its SKU
+// and fills in the item it belongs to. If the variant was sold out and gets stock again,
+// the users who wish the item are notified.
+func (s *Storage) UpdateVariant(ctx context.Context, variant *models.Variant) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var oldStock *int
+	err = tx.QueryRow(ctx, updateVariant,
+		variant.SKU,
+		variant.Title,
+		variant.Size,
+		variant.Color,
+		variant.PriceOverride,
+		variant.Stock,
+	).Scan(&variant.ItemSlug, &variant.IsDefault, &oldStock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrVariantNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	err = notifyIfBackInStock(ctx, tx, variant.SKU, oldStock, variant.Stock)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditVariantUpdated, "variant:"+variant.SKU,
+		map[string]any{"stock": variant.Stock, "priceOverride": variant.PriceOverride}))
+	return err
+}
+
+// RestockVariant adds the quantity to the variant's stock, the stock of an unlimited variant stays unlimited.
+// If the variant was sold out, the users who wish the item are notified.
+// Returns the restocked variant.
+func (s *Storage) RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var variant models.Variant
+	var oldStock *int
+	err = tx.QueryRow(ctx, restockVariant, sku, quantity).Scan(
+		&variant.SKU,
+		&variant.ItemSlug,
+		&variant.Title,
+		&variant.Size,
+		&variant.Color,
+		&variant.PriceOverride,
+		&variant.Stock,
+		&variant.IsDefault,
+		&oldStock,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrVariantNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = notifyIfBackInStock(ctx, tx, variant.SKU, oldStock, variant.Stock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditVariantRestocked, "variant:"+variant.SKU,
+		map[string]any{"quantity": quantity, "stock": variant.Stock}))
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
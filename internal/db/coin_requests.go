@@ -0,0 +1,270 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	coinRequestColumns = `
+		r.id, r.requester_id, r.payer_id, q.username AS requester, p.username AS payer, r.coins, r.note,
+		r.status, r.expires_at, r.created_at`
+	coinRequestTables = `
+		FROM coin_requests r
+		JOIN users q ON r.requester_id = q.id
+		JOIN users p ON r.payer_id = p.id`
+	coinRequestFields = coinRequestColumns + coinRequestTables
+	createCoinRequest = `
+		INSERT INTO coin_requests (requester_id, payer_id, coins, note, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at, (SELECT username FROM users WHERE id = requester_id);`
+	recordCoinRequestEvent = `INSERT INTO coin_request_events (request_id, status, actor_id) VALUES ($1, $2, $3);`
+	// the expired requests are hidden even before the job marks them
+	getIncomingCoinRequests = `
+		SELECT ` + coinRequestFields + `
+		WHERE r.payer_id = $1 AND r.status = 'pending' AND r.expires_at > NOW()
+		ORDER BY r.created_at, r.id;`
+	getOutgoingCoinRequests = `
+		SELECT ` + coinRequestFields + `
+		WHERE r.requester_id = $1
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT 100;`
+	getIncomingCoinRequestsPage = `
+		SELECT ` + coinRequestColumns + `, ` + pageTotal + coinRequestTables + `
+		WHERE r.payer_id = $1 AND r.status = 'pending' AND r.expires_at > NOW()
+		ORDER BY r.created_at, r.id
+		LIMIT $2 OFFSET $3;`
+	getOutgoingCoinRequestsPage = `
+		SELECT ` + coinRequestColumns + `, ` + pageTotal + coinRequestTables + `
+		WHERE r.requester_id = $1
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2 OFFSET $3;`
+	getCoinRequest = `
+		SELECT ` + coinRequestFields + `
+		WHERE r.id = $1 AND $2 IN (r.requester_id, r.payer_id);`
+	getCoinRequestEvents = `
+		SELECT e.status, u.username AS actor, e.created_at
+		FROM coin_request_events e LEFT JOIN users u ON e.actor_id = u.id
+		WHERE e.request_id = $1
+		ORDER BY e.created_at, e.id;`
+	// the payer accepts or declines the request, the requester cancels it
+	lockPendingCoinRequest = `
+		SELECT ` + coinRequestFields + `
+		WHERE r.id = $1 AND r.status = 'pending' AND r.expires_at > NOW()
+		  AND (CASE WHEN $3 = 'cancelled' THEN r.requester_id ELSE r.payer_id END) = $2
+		FOR UPDATE OF r;`
+	setCoinRequestStatus  = `UPDATE coin_requests SET status = $2, updated_at = NOW() WHERE id = $1;`
+	isCoinRequestReviewed = `SELECT EXISTS (SELECT 1 FROM transfer_reviews WHERE coin_request_id = $1 AND status = 'open');`
+	// the request resolved without the payment has nothing left to review
+	closeCoinRequestReview = `
+		UPDATE transfer_reviews SET status = 'rejected', reviewed_at = NOW()
+		WHERE coin_request_id = $1 AND status = 'open';`
+	expireCoinRequests = `
+		WITH expired AS (
+			UPDATE coin_requests SET status = 'expired', updated_at = NOW()
+			WHERE status = 'pending' AND expires_at <= NOW()
+			RETURNING id
+		), closed AS (
+			UPDATE transfer_reviews SET status = 'rejected', reviewed_at = NOW()
+			WHERE status = 'open' AND coin_request_id IN (SELECT id FROM expired)
+		)
+		INSERT INTO coin_request_events (request_id, status)
+		SELECT id, 'expired' FROM expired;`
+)
+
+// CreateCoinRequest saves the coin request with its first event, setting its ID, status, creation time
+// and the requester's username.
+func (s *Storage) CreateCoinRequest(ctx context.Context, r *models.CoinRequest) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, createCoinRequest, r.RequesterID, r.PayerID, r.Amount, r.Note, r.ExpiresAt).
+		Scan(&r.ID, &r.Status, &r.CreatedAt, &r.Requester)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, recordCoinRequestEvent, r.ID, r.Status, r.RequesterID)
+	return err
+}
+
+// GetIncomingCoinRequests retrieves the pending requests the user is asked to pay, the oldest first.
+func (s *Storage) GetIncomingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error) {
+	return collectCoinRequests(ctx, s.pool, getIncomingCoinRequests, userID)
+}
+
+// GetOutgoingCoinRequests retrieves the last 100 requests made by the user, the latest first.
+func (s *Storage) GetOutgoingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error) {
+	return collectCoinRequests(ctx, s.pool, getOutgoingCoinRequests, userID)
+}
+
+// GetIncomingCoinRequestsPage retrieves a page of the pending requests addressed to the user, the oldest first.
+func (s *Storage) GetIncomingCoinRequestsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error) {
+	return collectPage[models.CoinRequest](ctx, s.pool, getIncomingCoinRequestsPage, limit, offset, userID)
+}
+
+// GetOutgoingCoinRequestsPage retrieves a page of the requests made by the user, the latest first.
+func (s *Storage) GetOutgoingCoinRequestsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error) {
+	return collectPage[models.CoinRequest](ctx, s.pool, getOutgoingCoinRequestsPage, limit, offset, userID)
+}
+
+// GetCoinRequest retrieves the request made by or addressed to the user along with its history.
+func (s *Storage) GetCoinRequest(ctx context.Context, userID, requestID int) (*models.CoinRequest, error) {
+	rows, err := s.pool.Query(ctx, getCoinRequest, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.CoinRequest])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrCoinRequestNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err = s.pool.Query(ctx, getCoinRequestEvents, requestID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.CoinRequestEvent])
+	if err != nil {
+		return nil, err
+	}
+	request.History = &events
+	return &request, nil
+}
+
+// ResolveCoinRequest moves the pending request to the status on behalf of the user and records the change.
+// Only the payer accepts or declines a request and only the requester cancels it. An accepted request
+// is paid in the same transaction, the request stays pending if the payment fails. The payment breaking
+// an anti-fraud rule is held for review linked to the request, approving the review accepts the request
+// and declining, cancelling or expiring the request closes the review.
+func (s *Storage) ResolveCoinRequest(ctx context.Context, userID, requestID int, status string, rules *models.TransferRules) (*models.CoinRequest, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, lockPendingCoinRequest, requestID, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	request, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.CoinRequest])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrCoinRequestNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	if status == models.CoinRequestAccepted {
+		var ruleErr error
+		ruleErr, err = payCoinRequestTx(ctx, tx, &request, rules)
+		if err != nil {
+			return nil, err
+		} else if ruleErr != nil {
+			return nil, ruleErr
+		}
+	} else {
+		_, err = tx.Exec(ctx, closeCoinRequestReview, requestID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = setCoinRequestStatusTx(ctx, tx, requestID, userID, status,
+		map[string]any{"payer": request.PayerID, "requester": request.RequesterID, "amount": request.Amount})
+	if err != nil {
+		return nil, err
+	}
+	request.Status = status
+	return &request, nil
+}
+
+// payCoinRequestTx pays the locked coin request unless its payment is held for review already. The payment
+// breaking an anti-fraud rule is flagged for review linked to the request instead and the rule's error
+// is returned as ruleErr, the transaction is to be committed for the review to stay.
+func payCoinRequestTx(ctx context.Context, tx pgx.Tx, r *models.CoinRequest, rules *models.TransferRules) (ruleErr, err error) {
+	var reviewed bool
+	err = tx.QueryRow(ctx, isCoinRequestReviewed, r.ID).Scan(&reviewed)
+	if err != nil {
+		return nil, err
+	} else if reviewed {
+		return nil, models.ErrCoinRequestUnderReview
+	}
+
+	payment := userTransfer(r.PayerID, r.RequesterID, r.Amount)
+	rule, ruleErr, err := checkRules(ctx, tx, rules, payment, 0)
+	if err != nil {
+		return nil, err
+	} else if ruleErr != nil {
+		err = flagTransferTx(ctx, tx, payment, rule, &r.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return ruleErr, nil
+	}
+	return nil, transferCoins(ctx, tx, r.PayerID, r.RequesterID, r.Amount)
+}
+
+// setCoinRequestStatusTx moves the locked request to the status on behalf of the actor and records the change
+// in the request's history and in the audit log.
+func setCoinRequestStatusTx(ctx context.Context, tx pgx.Tx, requestID, actorID int, status string, details map[string]any) error {
+	_, err := tx.Exec(ctx, setCoinRequestStatus, requestID, status)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, recordCoinRequestEvent, requestID, status, actorID)
+	if err != nil {
+		return err
+	}
+
+	details["status"] = status
+	return appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditCoinRequestStatus, "coin_request:"+strconv.Itoa(requestID), details))
+}
+
+// ExpireCoinRequests marks the pending requests past their expiry time as expired and records the change.
+// Returns the number of expired requests.
+func (s *Storage) ExpireCoinRequests(ctx context.Context) (int, error) {
+	tag, err := s.pool.Exec(ctx, expireCoinRequests)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// collectCoinRequests lists the coin requests selected by the query.
+func collectCoinRequests(ctx context.Context, q querier, query string, args ...any) (*[]models.CoinRequest, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.CoinRequest])
+	if err != nil {
+		return nil, err
+	}
+	return &requests, nil
+}
@@ -0,0 +1,216 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// directoryGrantReason is the reason of the coins granted to the user imported from the HR directory.
+const directoryGrantReason = "initial grant"
+
+const (
+	getDirectoryUser = `SELECT id, directory_managed, staff_managed, deactivated_at FROM users WHERE username = $1 FOR UPDATE;`
+	// the password is set by the user's first login, with the claim token
+	createDirectoryUser = `INSERT INTO users (username, password, directory_managed, claim_token_hash) VALUES ($1, '', TRUE, $2) RETURNING id, coins;`
+	adoptDirectoryUser  = `UPDATE users SET directory_managed = TRUE, deactivated_at = NULL, updated_at = NOW() WHERE id = $1;`
+	deactivateLeavers   = `
+		WITH leavers AS (
+		    UPDATE users SET deactivated_at = NOW(), updated_at = NOW()
+		    WHERE directory_managed AND deactivated_at IS NULL AND username <> ALL($1)
+		    RETURNING id, username)
+		SELECT id, username FROM leavers ORDER BY id;`
+
+	// the support staff take the account over from the directory for good, the sync leaves it alone
+	deactivateUser = `
+		UPDATE users SET deactivated_at = COALESCE(deactivated_at, NOW()), directory_managed = FALSE, staff_managed = TRUE,
+		                 updated_at = NOW()
+		WHERE id = $1 RETURNING deactivated_at;`
+	reactivateUser = `
+		UPDATE users SET deactivated_at = NULL, directory_managed = FALSE, staff_managed = TRUE, updated_at = NOW()
+		WHERE id = $1 RETURNING deactivated_at;`
+
+	// the token issued before is replaced, an account with a password has no use for one
+	setClaimToken = `UPDATE users SET claim_token_hash = $2, updated_at = NOW() WHERE id = $1 AND password = '' RETURNING id;`
+)
+
+// SyncDirectory reconciles the accounts with the users listed in the HR directory, in one transaction.
+// A missing user is created without a password, which the user's first login sets with the claim token
+// whose hash is listed with the user, and granted the user's coins on top of the initial balance.
+// An existing active account becomes synced with the directory and is reported as adopted, a synced one
+// deactivated by an earlier sync is reactivated. If the list is the full directory, the synced accounts
+// of the users not listed are deactivated. The accounts the support staff have deactivated or reactivated
+// are left alone, whether the users are listed or not.
+func (s *Storage) SyncDirectory(ctx context.Context, users []models.DirectoryUser, full bool) (*models.DirectorySync, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	result := &models.DirectorySync{Created: []string{}, Adopted: []string{}, Reactivated: []string{}, Deactivated: []string{}}
+	usernames := make([]string, 0, len(users))
+	for _, u := range users {
+		usernames = append(usernames, u.Username)
+
+		var (
+			id            int
+			managed       bool
+			staffManaged  bool
+			deactivatedAt *time.Time
+		)
+		err = tx.QueryRow(ctx, getDirectoryUser, u.Username).Scan(&id, &managed, &staffManaged, &deactivatedAt)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			err = createDirectoryUserTx(ctx, tx, &u)
+			if err != nil {
+				return nil, err
+			}
+			result.Created = append(result.Created, u.Username)
+		case err != nil:
+			return nil, err
+		case staffManaged:
+			result.Unchanged++
+		case managed && deactivatedAt != nil:
+			_, err = tx.Exec(ctx, adoptDirectoryUser, id)
+			if err != nil {
+				return nil, err
+			}
+			err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditUserReactivated, "user:"+strconv.Itoa(id),
+				map[string]any{"reason": "listed in the directory"}))
+			if err != nil {
+				return nil, err
+			}
+			result.Reactivated = append(result.Reactivated, u.Username)
+		case !managed && deactivatedAt == nil:
+			// the sync will deactivate the account once the user isn't listed, so taking it over is reported
+			_, err = tx.Exec(ctx, adoptDirectoryUser, id)
+			if err != nil {
+				return nil, err
+			}
+			err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditUserAdopted, "user:"+strconv.Itoa(id),
+				map[string]any{"reason": "listed in the directory"}))
+			if err != nil {
+				return nil, err
+			}
+			result.Adopted = append(result.Adopted, u.Username)
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if !full {
+		return result, nil
+	}
+
+	rows, err := tx.Query(ctx, deactivateLeavers, usernames)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		id       int
+		username string
+		ids      []int
+	)
+	_, err = pgx.ForEachRow(rows, []any{&id, &username}, func() error {
+		ids = append(ids, id)
+		result.Deactivated = append(result.Deactivated, username)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, leaverID := range ids {
+		err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditUserDeactivated, "user:"+strconv.Itoa(leaverID),
+			map[string]any{"reason": "not listed in the directory"}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// createDirectoryUserTx creates the account of the user listed in the directory and grants the user's coins.
+func createDirectoryUserTx(ctx context.Context, tx pgx.Tx, u *models.DirectoryUser) error {
+	id, balance := 0, 0
+	err := tx.QueryRow(ctx, createDirectoryUser, u.Username, u.ClaimTokenHash).Scan(&id, &balance)
+	if err != nil {
+		return err
+	}
+
+	if u.Grant > 0 {
+		err = tx.QueryRow(ctx, grantCoins, id, u.Grant).Scan(&balance)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, recordGrant, id, u.Grant, directoryGrantReason)
+		if err != nil {
+			return err
+		}
+	}
+
+	return appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditUserImported, "user:"+strconv.Itoa(id),
+		map[string]any{"username": u.Username, "grant": u.Grant, "coins": balance}))
+}
+
+// SetClaimToken issues a new claim token for the imported account the user hasn't logged in to yet,
+// the token issued before stops being valid. Returns models.ErrAccountClaimed if the account has a password.
+func (s *Storage) SetClaimToken(ctx context.Context, userID int, tokenHash string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	err = tx.QueryRow(ctx, setClaimToken, userID, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrAccountClaimed
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditClaimTokenIssued, "user:"+strconv.Itoa(userID), nil))
+	return err
+}
+
+// SetUserDeactivated deactivates the user's account, so that the user can't log in or receive coins,
+// or reactivates it. Either way the account is taken over from the HR directory for good, the sync
+// neither adopts nor deactivates it anymore. Returns the time the account is deactivated at, nil if it's active.
+func (s *Storage) SetUserDeactivated(ctx context.Context, userID int, deactivated bool, reason string) (*time.Time, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { finishAdminTx(ctx, tx, err) }()
+
+	query, action := reactivateUser, models.AuditUserReactivated
+	if deactivated {
+		query, action = deactivateUser, models.AuditUserDeactivated
+	}
+	var deactivatedAt *time.Time
+	err = tx.QueryRow(ctx, query, userID).Scan(&deactivatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrUserNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	var details map[string]any
+	if reason != "" {
+		details = map[string]any{"reason": reason}
+	}
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, action, "user:"+strconv.Itoa(userID), details))
+	if err != nil {
+		return nil, err
+	}
+	return deactivatedAt, nil
+}
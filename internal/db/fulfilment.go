@@ -0,0 +1,125 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	purchaseColumns      = `p.id, p.item_slug, p.sku, p.price, p.status, p.office, p.created_at, p.status_changed_at`
+	getPurchasesByUserID = `
+		SELECT ` + purchaseColumns + `, '' AS username FROM purchases p
+		WHERE p.user_id = $1
+		ORDER BY p.created_at DESC, p.id DESC;`
+	getPurchasesByStatus = `
+		SELECT ` + purchaseColumns + `, u.username FROM purchases p JOIN users u ON p.user_id = u.id
+		WHERE p.status = ANY($1) AND ($2 = '' OR p.office = $2)
+		ORDER BY p.created_at, p.id;`
+	getPurchasesPageByUserID = `
+		SELECT ` + purchaseColumns + `, '' AS username, ` + pageTotal + ` FROM purchases p
+		WHERE p.user_id = $1
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $2 OFFSET $3;`
+	getPurchasesPageByStatus = `
+		SELECT ` + purchaseColumns + `, u.username, ` + pageTotal + ` FROM purchases p JOIN users u ON p.user_id = u.id
+		WHERE p.status = ANY($1) AND ($2 = '' OR p.office = $2)
+		ORDER BY p.created_at, p.id
+		LIMIT $3 OFFSET $4;`
+	getPurchaseForUpdate       = `SELECT user_id, sku, price, status FROM purchases WHERE id = $1 FOR UPDATE;`
+	setPurchaseStatus          = `UPDATE purchases SET status = $2, status_changed_at = NOW(), updated_at = NOW() WHERE id = $1;`
+	recordPurchaseStatusChange = `
+		INSERT INTO purchase_status_changes (purchase_id, operator_id, from_status, to_status)
+		VALUES ($1, $2, $3, $4);`
+)
+
+// collectPurchases fetches the purchases selected by the query.
+func collectPurchases(ctx context.Context, q querier, query string, args ...any) (*[]models.Purchase, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Purchase])
+	if err != nil {
+		return nil, err
+	}
+	return &purchases, nil
+}
+
+// GetPurchasesByUserID retrieves all the purchases of a user with their fulfilment statuses, the latest first.
+func (s *Storage) GetPurchasesByUserID(ctx context.Context, userID int) (*[]models.Purchase, error) {
+	return collectPurchases(ctx, s.pool, getPurchasesByUserID, userID)
+}
+
+// GetPurchasesPageByUserID retrieves a page of the purchases of a user with their fulfilment statuses, the latest first.
+func (s *Storage) GetPurchasesPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Purchase], error) {
+	return collectPage[models.Purchase](ctx, s.pool, getPurchasesPageByUserID, limit, offset, userID)
+}
+
+// GetPurchasesPageByStatus retrieves a page of the purchases in the given statuses with their buyers, the oldest first.
+// If the office isn't empty, only purchases to be picked up in that office are returned.
+func (s *Storage) GetPurchasesPageByStatus(ctx context.Context, statuses []string, office string, limit, offset int) (*models.Page[models.Purchase], error) {
+	return collectPage[models.Purchase](ctx, s.pool, getPurchasesPageByStatus, limit, offset, statuses, office)
+}
+
+// GetPurchasesByStatus retrieves the purchases in the given statuses with their buyers, the oldest first.
+// If the office isn't empty, only purchases to be picked up in that office are returned.
+func (s *Storage) GetPurchasesByStatus(ctx context.Context, statuses []string, office string) (*[]models.Purchase, error) {
+	return collectPurchases(ctx, s.pool, getPurchasesByStatus, statuses, office)
+}
+
+// ChangePurchaseStatus moves the purchase to the new status if it's currently in one of the given statuses
+// and records who changed it. Cancelling the purchase refunds it like a return does.
+func (s *Storage) ChangePurchaseStatus(ctx context.Context, purchaseID, operatorID int, from []string, to string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var userID, paid int
+	var sku, status string
+	err = tx.QueryRow(ctx, getPurchaseForUpdate, purchaseID).Scan(&userID, &sku, &paid, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrPurchaseNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+	if !slices.Contains(from, status) {
+		err = models.ErrInvalidStatusTransition
+		return err
+	}
+
+	if to == models.PurchaseCancelled {
+		err = refundPurchase(ctx, tx, userID, purchaseID, sku, paid, &operatorID)
+	} else {
+		_, err = tx.Exec(ctx, setPurchaseStatus, purchaseID, to)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, recordPurchaseStatusChange, purchaseID, operatorID, status, to)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditPurchaseStatus, "purchase:"+strconv.Itoa(purchaseID),
+		map[string]any{"from": status, "to": to}))
+	return err
+}
@@ -5,12 +5,16 @@ package db
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
 	"github.com/kk7453603/avito_2024_summer/internal/models"
 )
 
@@ -131,3 +135,1439 @@ func TestStorage_GetUserByUsername(t *testing.T) {
 		require.Nil(t, fetchedUser)
 	})
 }
+
+func TestStorage_ReturnItemByUserID(t *testing.T) {
+	clearDataBase(t)
+
+	user := &models.User{
+		Username: "testUser3",
+		Password: "hashed_password_3",
+	}
+	err := storage.SaveUser(ctx, user)
+	require.NoError(t, err)
+
+	item, err := storage.GetItemBySlug(ctx, "hoody")
+	require.NoError(t, err)
+
+	_, err = storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{})
+	require.NoError(t, err)
+
+	t.Run("OutsideReturnWindow", func(t *testing.T) {
+		_, err := storage.ReturnItemByUserID(ctx, user.ID, item.Slug, "", 0)
+		require.ErrorIs(t, err, models.ErrNoReturnablePurchase)
+	})
+
+	t.Run("Returned", func(t *testing.T) {
+		refund, err := storage.ReturnItemByUserID(ctx, user.ID, item.Slug, "", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, item.Price, refund)
+
+		coins, err := storage.GetCoinsByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.Coins, coins)
+
+		history, err := storage.GetCoinHistoryByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, *history.Receiving, 1)
+		require.Equal(t, models.TxKindRefund, (*history.Receiving)[0].Kind)
+	})
+
+	t.Run("AlreadyReturned", func(t *testing.T) {
+		_, err := storage.ReturnItemByUserID(ctx, user.ID, item.Slug, "", time.Hour)
+		require.ErrorIs(t, err, models.ErrNoReturnablePurchase)
+	})
+}
+
+func TestStorage_MakePurchaseByUserIDStock(t *testing.T) {
+	clearDataBase(t)
+
+	_, err := pool.Exec(ctx, "UPDATE store SET per_user_limit = 1 WHERE slug = 'cup'")
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "UPDATE item_variants SET stock = 1 WHERE sku = 'cup'")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "UPDATE store SET per_user_limit = NULL WHERE slug = 'cup'")
+		_, _ = pool.Exec(ctx, "UPDATE item_variants SET stock = NULL WHERE sku = 'cup'")
+	})
+
+	first := &models.User{Username: "testUser4", Password: "hashed_password_4"}
+	require.NoError(t, storage.SaveUser(ctx, first))
+	second := &models.User{Username: "testUser5", Password: "hashed_password_5"}
+	require.NoError(t, storage.SaveUser(ctx, second))
+
+	item, err := storage.GetItemBySlug(ctx, "cup")
+	require.NoError(t, err)
+	require.NotNil(t, item.Stock)
+	require.Equal(t, 1, *item.Stock)
+
+	_, err = storage.MakePurchaseByUserID(ctx, first.ID, item, &models.Order{})
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, first.ID, item, &models.Order{})
+	require.ErrorIs(t, err, models.ErrLimitReached)
+	_, err = storage.MakePurchaseByUserID(ctx, second.ID, item, &models.Order{})
+	require.ErrorIs(t, err, models.ErrSoldOut)
+
+	// the failed purchases must not take the money
+	coins, err := storage.GetCoinsByUserID(ctx, second.ID)
+	require.NoError(t, err)
+	require.Equal(t, second.Coins, coins)
+}
+
+func TestStorage_MakePurchaseByUserIDPromoCode(t *testing.T) {
+	clearDataBase(t)
+
+	_, err := pool.Exec(ctx, "INSERT INTO promo_codes (code, kind, value, max_uses) VALUES ('ONCE50', 'percent', 50, 1)")
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO discounts (item_slug, kind, value, starts_at, ends_at)
+		VALUES ('hoody', 'fixed', 100, NOW() - INTERVAL '1 hour', NOW() + INTERVAL '1 hour')`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE discounts, promo_redemptions, purchases CASCADE")
+		_, _ = pool.Exec(ctx, "DELETE FROM promo_codes WHERE code = 'ONCE50'")
+	})
+
+	first := &models.User{Username: "testUser6", Password: "hashed_password_6"}
+	require.NoError(t, storage.SaveUser(ctx, first))
+	second := &models.User{Username: "testUser7", Password: "hashed_password_7"}
+	require.NoError(t, storage.SaveUser(ctx, second))
+
+	item, err := storage.GetItemBySlug(ctx, "hoody")
+	require.NoError(t, err)
+
+	// 300 - 100 (discount) = 200, then -50% with the promo code
+	quote, err := storage.MakePurchaseByUserID(ctx, first.ID, item, &models.Order{PromoCode: "ONCE50"})
+	require.NoError(t, err)
+	require.Equal(t, 300, quote.ListPrice)
+	require.Equal(t, 200, quote.Discount)
+	require.Equal(t, 100, quote.Price)
+
+	_, err = storage.MakePurchaseByUserID(ctx, second.ID, item, &models.Order{PromoCode: "ONCE50"})
+	require.ErrorIs(t, err, models.ErrPromoCodeUsed)
+
+	_, err = storage.MakePurchaseByUserID(ctx, second.ID, item, &models.Order{PromoCode: "UNKNOWN"})
+	require.ErrorIs(t, err, models.ErrInvalidPromoCode)
+
+	// the refund is capped at what was actually paid
+	refund, err := storage.ReturnItemByUserID(ctx, first.ID, item.Slug, "", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, quote.Price, refund)
+}
+
+func TestStorage_MakePurchaseByUserIDVariant(t *testing.T) {
+	clearDataBase(t)
+
+	xl := &models.Variant{SKU: "hoody-xl", ItemSlug: "hoody", Title: "Hoody XL", PriceOverride: ptr(350), Stock: ptr(2)}
+	require.NoError(t, storage.CreateVariant(ctx, xl))
+	require.ErrorIs(t, storage.CreateVariant(ctx, xl), models.ErrVariantExists)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE purchases, gifts CASCADE")
+		_, _ = pool.Exec(ctx, "DELETE FROM item_variants WHERE sku = 'hoody-xl'")
+	})
+
+	user := &models.User{Username: "testUser8", Password: "hashed_password_8"}
+	require.NoError(t, storage.SaveUser(ctx, user))
+
+	item, err := storage.GetItemBySlug(ctx, "hoody")
+	require.NoError(t, err)
+	require.Len(t, item.Variants, 2)
+	require.True(t, item.Variants[0].IsDefault)
+
+	quote, err := storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{Variant: "hoody-xl"})
+	require.NoError(t, err)
+	require.Equal(t, 350, quote.Price)
+	require.Equal(t, "hoody-xl", quote.Variant)
+
+	_, err = storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{})
+	require.NoError(t, err)
+
+	_, err = storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{Variant: "cup"})
+	require.ErrorIs(t, err, models.ErrVariantNotFound)
+
+	inventory, err := storage.GetInventoryByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Merch{
+		{Type: "hoody", Variant: "hoody", Quantity: 1},
+		{Type: "hoody", Variant: "hoody-xl", Quantity: 1},
+	}, *inventory)
+
+	item, err = storage.GetItemBySlug(ctx, "hoody")
+	require.NoError(t, err)
+	require.Nil(t, item.Stock, "the default variant is unlimited")
+	require.Equal(t, 1, *item.Variants[1].Stock)
+
+	refund, err := storage.ReturnItemByUserID(ctx, user.ID, "hoody", "hoody-xl", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 350, refund)
+
+	item, err = storage.GetItemBySlug(ctx, "hoody")
+	require.NoError(t, err)
+	require.Equal(t, 2, *item.Variants[1].Stock)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestStorage_ChangePurchaseStatus(t *testing.T) {
+	clearDataBase(t)
+
+	buyer := &models.User{Username: "testUser9", Password: "hashed_password_9"}
+	require.NoError(t, storage.SaveUser(ctx, buyer))
+	operator := &models.User{Username: "testUser10", Password: "hashed_password_10"}
+	require.NoError(t, storage.SaveUser(ctx, operator))
+
+	item, err := storage.GetItemBySlug(ctx, "cup")
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, buyer.ID, item, &models.Order{Office: "moscow"})
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, buyer.ID, item, &models.Order{Office: "kazan"})
+	require.NoError(t, err)
+
+	queue, err := storage.GetPurchasesByStatus(ctx, []string{models.PurchasePending}, "moscow")
+	require.NoError(t, err)
+	require.Len(t, *queue, 1)
+	handedOver := (*queue)[0].ID
+
+	queue, err = storage.GetPurchasesByStatus(ctx, []string{models.PurchasePending}, "kazan")
+	require.NoError(t, err)
+	require.Len(t, *queue, 1)
+	cancelled := (*queue)[0].ID
+
+	pending := []string{models.PurchasePending}
+	ready := []string{models.PurchaseReadyForPickup}
+
+	err = storage.ChangePurchaseStatus(ctx, handedOver, operator.ID, ready, models.PurchaseHandedOver)
+	require.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+	require.NoError(t, storage.ChangePurchaseStatus(ctx, handedOver, operator.ID, pending, models.PurchaseReadyForPickup))
+	require.NoError(t, storage.ChangePurchaseStatus(ctx, handedOver, operator.ID, ready, models.PurchaseHandedOver))
+
+	// the cancelled purchase is refunded and removed from the inventory
+	require.NoError(t, storage.ChangePurchaseStatus(ctx, cancelled, operator.ID, pending, models.PurchaseCancelled))
+	coins, err := storage.GetCoinsByUserID(ctx, buyer.ID)
+	require.NoError(t, err)
+	require.Equal(t, buyer.Coins-item.Price, coins)
+
+	err = storage.ChangePurchaseStatus(ctx, 0, operator.ID, pending, models.PurchaseCancelled)
+	require.ErrorIs(t, err, models.ErrPurchaseNotFound)
+
+	purchases, err := storage.GetPurchasesByUserID(ctx, buyer.ID)
+	require.NoError(t, err)
+	require.Len(t, *purchases, 2)
+	require.Equal(t, models.PurchaseCancelled, (*purchases)[0].Status)
+	require.Equal(t, models.PurchaseHandedOver, (*purchases)[1].Status)
+}
+
+func TestStorage_WishlistNotifications(t *testing.T) {
+	clearDataBase(t)
+
+	_, err := pool.Exec(ctx, "UPDATE item_variants SET stock = 0 WHERE sku = 'pink-hoody'")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "UPDATE item_variants SET stock = NULL WHERE sku = 'pink-hoody'")
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE discounts, purchases CASCADE")
+	})
+
+	user := &models.User{Username: "testUser11", Password: "hashed_password_11"}
+	require.NoError(t, storage.SaveUser(ctx, user))
+
+	require.NoError(t, storage.AddToWishlist(ctx, user.ID, "pink-hoody"))
+	require.NoError(t, storage.AddToWishlist(ctx, user.ID, "pink-hoody"))
+	require.ErrorIs(t, storage.AddToWishlist(ctx, user.ID, "unknown"), models.ErrItemNotFound)
+
+	wishlist, err := storage.GetWishlistByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, *wishlist, 1)
+
+	_, err = storage.RestockVariant(ctx, "pink-hoody", 5)
+	require.NoError(t, err)
+	_, err = storage.RestockVariant(ctx, "pink-hoody", 5)
+	require.NoError(t, err, "restocking a variant in stock doesn't notify again")
+
+	discount := &models.Discount{
+		Category: ptr("clothes"), Kind: models.DiscountPercent, Value: 10,
+		StartsAt: time.Now().UTC(), EndsAt: time.Now().UTC().Add(time.Hour),
+	}
+	require.NoError(t, storage.CreateDiscount(ctx, discount))
+
+	notifications, err := storage.GetNotificationsByUserID(ctx, user.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, *notifications, 2)
+	require.Equal(t, models.NotificationDiscount, (*notifications)[0].Kind)
+	require.Equal(t, models.NotificationBackInStock, (*notifications)[1].Kind)
+	require.False(t, (*notifications)[0].Read)
+
+	require.NoError(t, storage.MarkNotificationsRead(ctx, user.ID))
+	notifications, err = storage.GetNotificationsByUserID(ctx, user.ID, 10)
+	require.NoError(t, err)
+	require.True(t, (*notifications)[0].Read)
+
+	// the bought item drops off the wishlist
+	item, err := storage.GetItemBySlug(ctx, "pink-hoody")
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{})
+	require.NoError(t, err)
+	wishlist, err = storage.GetWishlistByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, *wishlist)
+}
+
+func TestStorage_Leaderboard(t *testing.T) {
+	clearDataBase(t)
+
+	alice := &models.User{Username: "testUser12", Password: "hashed_password_12"}
+	bob := &models.User{Username: "testUser13", Password: "hashed_password_13"}
+	require.NoError(t, storage.SaveUser(ctx, alice))
+	require.NoError(t, storage.SaveUser(ctx, bob))
+
+	require.NoError(t, storage.TransferCoins(ctx, alice.ID, bob.ID, 100, nil))
+	require.NoError(t, storage.TransferCoins(ctx, bob.ID, alice.ID, 30, nil))
+	// an old transfer counts all-time only
+	_, err := pool.Exec(ctx, "UPDATE transactions SET created_at = NOW() - INTERVAL '10 days' WHERE receiver_id = $1", alice.ID)
+	require.NoError(t, err)
+
+	week, err := storage.GetCoinsLeaderboard(ctx, 7*24*time.Hour, 10)
+	require.NoError(t, err)
+	require.Equal(t, []models.LeaderboardEntry{{Rank: 1, User: bob.Username, Value: 100}}, *week)
+
+	allTime, err := storage.GetCoinsLeaderboard(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, *allTime, 2)
+	require.Equal(t, models.LeaderboardEntry{Rank: 2, User: alice.Username, Value: 30}, (*allTime)[1])
+
+	item, err := storage.GetItemBySlug(ctx, "cup")
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, alice.ID, item, &models.Order{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE purchases CASCADE")
+	})
+
+	items, err := storage.GetItemsLeaderboard(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, []models.LeaderboardEntry{{Rank: 1, User: alice.Username, Value: 1}}, *items)
+
+	require.NoError(t, storage.SetLeaderboardOptOut(ctx, alice.ID, true))
+	items, err = storage.GetItemsLeaderboard(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, *items)
+}
+
+func TestStorage_SavingsGoal(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE savings_goals, purchases CASCADE")
+	})
+
+	user := &models.User{Username: "testUser14", Password: "hashed_password_14"}
+	require.NoError(t, storage.SaveUser(ctx, user))
+
+	goal, err := storage.CreateSavingsGoal(ctx, user.ID, "pink-hoody")
+	require.NoError(t, err)
+	_, err = storage.CreateSavingsGoal(ctx, user.ID, "pink-hoody")
+	require.ErrorIs(t, err, models.ErrGoalExists)
+	_, err = storage.CreateSavingsGoal(ctx, user.ID, "unknown")
+	require.ErrorIs(t, err, models.ErrItemNotFound)
+
+	// the escrowed coins can't be spent
+	goal, err = storage.DepositToSavingsGoal(ctx, user.ID, goal.ID, 600)
+	require.NoError(t, err)
+	require.Equal(t, 600, goal.Saved)
+	coins, err := storage.GetCoinsByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 400, coins)
+	_, err = storage.DepositToSavingsGoal(ctx, user.ID, goal.ID, 500)
+	require.ErrorIs(t, err, models.ErrNotEnoughCoins)
+	_, err = storage.DepositToSavingsGoal(ctx, user.ID+1, goal.ID, 10)
+	require.ErrorIs(t, err, models.ErrGoalNotFound)
+
+	// buying the goal item releases the coins
+	item, err := storage.GetItemBySlug(ctx, "pink-hoody")
+	require.NoError(t, err)
+	_, err = storage.MakePurchaseByUserID(ctx, user.ID, item, &models.Order{})
+	require.NoError(t, err)
+	coins, err = storage.GetCoinsByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1000-item.Price, coins)
+	goals, err := storage.GetSavingsGoalsByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, *goals)
+
+	// closing a goal gives the coins back
+	goal, err = storage.CreateSavingsGoal(ctx, user.ID, "cup")
+	require.NoError(t, err)
+	_, err = storage.DepositToSavingsGoal(ctx, user.ID, goal.ID, 10)
+	require.NoError(t, err)
+	released, err := storage.CloseSavingsGoal(ctx, user.ID, goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, 10, released)
+	_, err = storage.CloseSavingsGoal(ctx, user.ID, goal.ID)
+	require.ErrorIs(t, err, models.ErrGoalNotFound)
+}
+
+func TestStorage_ExecuteDueTransfer(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE scheduled_transfers, notifications, transfer_reviews CASCADE")
+	})
+
+	lead := &models.User{Username: "testUser15", Password: "hashed_password_15"}
+	report := &models.User{Username: "testUser16", Password: "hashed_password_16"}
+	require.NoError(t, storage.SaveUser(ctx, lead))
+	require.NoError(t, storage.SaveUser(ctx, report))
+
+	due := &models.ScheduledTransfer{
+		SenderID: lead.ID, ReceiverID: report.ID, Amount: 600,
+		Schedule: ptr("0 9 1 * *"), NextRunAt: time.Now().UTC().Add(-time.Minute),
+	}
+	require.NoError(t, storage.CreateScheduledTransfer(ctx, due))
+	require.Equal(t, models.TransferActive, due.Status)
+
+	nextRun := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	var transferErrs []error
+	decide := func(_ *models.ScheduledTransfer, err error) *models.TransferOutcome {
+		transferErrs = append(transferErrs, err)
+		outcome := &models.TransferOutcome{Status: models.TransferActive, NextRunAt: nextRun}
+		if err != nil {
+			outcome.Notification = &models.Notification{Kind: models.NotificationTransferSkipped, Message: "skipped"}
+		}
+		return outcome
+	}
+
+	rules := &models.TransferRules{Window: time.Minute, Check: func(st *models.TransferStats, coins int) (string, error) {
+		if st.SentDay+coins > 600 {
+			return models.RuleDailyCap, models.ErrTransferLimitExceeded
+		}
+		return "", nil
+	}}
+
+	executed, err := storage.ExecuteDueTransfer(ctx, rules, decide)
+	require.NoError(t, err)
+	require.True(t, executed)
+	executed, err = storage.ExecuteDueTransfer(ctx, rules, decide)
+	require.NoError(t, err)
+	require.False(t, executed, "the transfer isn't due until the next run")
+
+	// the second run breaks the daily cap and is held for review
+	_, err = pool.Exec(ctx, "UPDATE scheduled_transfers SET next_run_at = NOW() - INTERVAL '1 minute'")
+	require.NoError(t, err)
+	executed, err = storage.ExecuteDueTransfer(ctx, rules, decide)
+	require.NoError(t, err)
+	require.True(t, executed)
+
+	// the third run is short of coins, the coins stay with the sender
+	_, err = pool.Exec(ctx, "UPDATE scheduled_transfers SET next_run_at = NOW() - INTERVAL '1 minute'")
+	require.NoError(t, err)
+	executed, err = storage.ExecuteDueTransfer(ctx, nil, decide)
+	require.NoError(t, err)
+	require.True(t, executed)
+
+	require.Len(t, transferErrs, 3)
+	require.NoError(t, transferErrs[0])
+	require.ErrorIs(t, transferErrs[1], models.ErrTransferLimitExceeded)
+	require.ErrorIs(t, transferErrs[2], models.ErrNotEnoughCoins)
+
+	coins, err := storage.GetCoinsByUserID(ctx, lead.ID)
+	require.NoError(t, err)
+	require.Equal(t, 400, coins)
+
+	reviews, err := storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 1)
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{
+		Action: models.AuditCoinsTransferred, Target: "user:" + strconv.Itoa(report.ID), Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+
+	notifications, err := storage.GetNotificationsByUserID(ctx, lead.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, *notifications, 2)
+
+	transfers, err := storage.GetScheduledTransfersBySenderID(ctx, lead.ID)
+	require.NoError(t, err)
+	require.Len(t, *transfers, 1)
+	require.Equal(t, report.Username, (*transfers)[0].Recipient)
+	require.True(t, nextRun.Equal((*transfers)[0].NextRunAt))
+
+	require.NoError(t, storage.CancelScheduledTransfer(ctx, lead.ID, due.ID))
+	require.ErrorIs(t, storage.CancelScheduledTransfer(ctx, lead.ID, due.ID), models.ErrScheduledTransferNotFound)
+}
+
+func TestStorage_ExecutePolicy(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE policy_runs, notifications CASCADE")
+	})
+
+	veteran := &models.User{Username: "testUser17", Password: "hashed_password_17"}
+	newcomer := &models.User{Username: "testUser18", Password: "hashed_password_18"}
+	require.NoError(t, storage.SaveUser(ctx, veteran))
+	require.NoError(t, storage.SaveUser(ctx, newcomer))
+	_, err := pool.Exec(ctx, "UPDATE users SET created_at = NOW() - INTERVAL '2 years' WHERE id = $1", veteran.ID)
+	require.NoError(t, err)
+
+	// the veteran spends 300 of the old coins and receives 200 fresh ones: 700 old coins remain
+	require.NoError(t, storage.TransferCoins(ctx, veteran.ID, newcomer.ID, 300, nil))
+	require.NoError(t, storage.TransferCoins(ctx, newcomer.ID, veteran.ID, 200, nil))
+
+	now := time.Now().UTC()
+	p := &models.Policy{
+		Name:     models.PolicyExpiry,
+		RunAt:    now.Add(time.Hour).Truncate(time.Second),
+		NoticeAt: now.Add(-time.Hour),
+		Cutoff:   now.AddDate(-1, 0, 0),
+		Reason:   "coins received a year ago expire",
+	}
+
+	actions, err := storage.GetPolicyActions(ctx, p)
+	require.NoError(t, err)
+	require.Equal(t, []models.PolicyAction{{UserID: veteran.ID, User: veteran.Username, Balance: 900, Amount: 700}}, *actions)
+
+	// the notice is sent once
+	run, err := storage.ExecutePolicy(ctx, p, now)
+	require.NoError(t, err)
+	require.NotNil(t, run.NoticedAt)
+	require.Nil(t, run.AppliedAt)
+	_, err = storage.ExecutePolicy(ctx, p, now)
+	require.NoError(t, err)
+	notifications, err := storage.GetNotificationsByUserID(ctx, veteran.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, *notifications, 1)
+	require.Equal(t, models.NotificationPolicyNotice, (*notifications)[0].Kind)
+
+	// the coins are written off once, the write-off is in the coin history
+	run, err = storage.ExecutePolicy(ctx, p, p.RunAt)
+	require.NoError(t, err)
+	require.Equal(t, 1, run.Affected)
+	require.Equal(t, 700, run.Coins)
+	_, err = storage.ExecutePolicy(ctx, p, p.RunAt)
+	require.NoError(t, err)
+
+	coins, err := storage.GetCoinsByUserID(ctx, veteran.ID)
+	require.NoError(t, err)
+	require.Equal(t, 200, coins)
+
+	history, err := storage.GetCoinHistoryByUserID(ctx, veteran.ID)
+	require.NoError(t, err)
+	require.Contains(t, *history.Sending, models.Sending{Amount: 700, Kind: models.PolicyExpiry, Reason: &p.Reason})
+
+	latest, err := storage.GetLatestPolicyRun(ctx, models.PolicyExpiry)
+	require.NoError(t, err)
+	require.True(t, p.RunAt.Equal(latest.RunAt))
+	require.NotNil(t, latest.AppliedAt)
+
+	// the balance cap
+	capped := &models.Policy{Name: models.PolicyBalanceCap, RunAt: p.RunAt, Cap: 1000, Reason: "balance above 1000 coins"}
+	actions, err = storage.GetPolicyActions(ctx, capped)
+	require.NoError(t, err)
+	require.Equal(t, []models.PolicyAction{{UserID: newcomer.ID, User: newcomer.Username, Balance: 1100, Amount: 100}}, *actions)
+}
+
+func TestStorage_TransferReview(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE transfer_reviews CASCADE")
+	})
+
+	sender := &models.User{Username: "testUser19", Password: "hashed_password_19"}
+	recipient := &models.User{Username: "testUser20", Password: "hashed_password_20"}
+	admin := &models.User{Username: "testUser21", Password: "hashed_password_21"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+	require.NoError(t, storage.SaveUser(ctx, admin))
+
+	require.NoError(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, 100, nil))
+	require.NoError(t, storage.TransferCoins(ctx, sender.ID, admin.ID, 50, nil))
+	_, err := pool.Exec(ctx, "UPDATE transactions SET created_at = NOW() - INTERVAL '3 days' WHERE receiver_id = $1", admin.ID)
+	require.NoError(t, err)
+
+	stats, err := storage.GetTransferStats(ctx, sender.ID, recipient.ID, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 100, stats.SentDay)
+	require.Equal(t, 150, stats.SentWeek)
+	require.Equal(t, 100, stats.SentToRecipient)
+	require.Equal(t, 1, stats.RecentTransfers)
+	require.False(t, stats.SenderCreatedAt.IsZero())
+	require.False(t, stats.RecipientCreatedAt.IsZero())
+
+	// the approved transfer is made once
+	require.NoError(t, storage.FlagTransfer(ctx, sender.ID, recipient.ID, 300, models.RuleDailyCap))
+	reviews, err := storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 1)
+	require.Equal(t, sender.Username, (*reviews)[0].Sender)
+
+	review, err := storage.ResolveTransferReview(ctx, (*reviews)[0].ID, admin.ID, models.ReviewApproved)
+	require.NoError(t, err)
+	require.Equal(t, models.ReviewApproved, review.Status)
+	require.NotNil(t, review.ReviewedAt)
+	_, err = storage.ResolveTransferReview(ctx, review.ID, admin.ID, models.ReviewApproved)
+	require.ErrorIs(t, err, models.ErrTransferReviewNotFound)
+
+	coins, err := storage.GetCoinsByUserID(ctx, recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1400, coins)
+
+	// the review stays open if the sender has spent the coins
+	require.NoError(t, storage.FlagTransfer(ctx, sender.ID, recipient.ID, 1000, models.RuleVelocity))
+	reviews, err = storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 1)
+	_, err = storage.ResolveTransferReview(ctx, (*reviews)[0].ID, admin.ID, models.ReviewApproved)
+	require.ErrorIs(t, err, models.ErrNotEnoughCoins)
+	reviews, err = storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 1)
+}
+
+func TestStorage_TransferCoinsRules(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE transfer_reviews CASCADE")
+	})
+
+	sender := &models.User{Username: "testUser39", Password: "hashed_password_39"}
+	recipient := &models.User{Username: "testUser40", Password: "hashed_password_40"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	rules := &models.TransferRules{Window: time.Minute, Check: func(st *models.TransferStats, coins int) (string, error) {
+		if st.SentDay+coins > 100 {
+			return models.RuleDailyCap, models.ErrTransferLimitExceeded
+		}
+		return "", nil
+	}}
+
+	// the concurrent transfers see each other, only two of them fit under the cap
+	errs := make(chan error, 5)
+	for range 5 {
+		go func() { errs <- storage.TransferCoins(ctx, sender.ID, recipient.ID, 50, rules) }()
+	}
+	made := 0
+	for range 5 {
+		err := <-errs
+		if err == nil {
+			made++
+		} else {
+			require.ErrorIs(t, err, models.ErrTransferLimitExceeded)
+		}
+	}
+	require.Equal(t, 2, made)
+
+	coins, err := storage.GetCoinsByUserID(ctx, sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, 900, coins)
+	reviews, err := storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 3)
+
+	// the earlier legs of the batch count towards the caps of the next ones
+	_, err = storage.TransferCoinsBatch(ctx, recipient.ID, []models.BatchTransfer{
+		{ReceiverID: sender.ID, Amount: 60}, {ReceiverID: sender.ID, Amount: 60},
+	}, rules)
+	var legErr *models.BatchLegError
+	require.ErrorAs(t, err, &legErr)
+	require.Equal(t, 1, legErr.Leg)
+	require.ErrorIs(t, err, models.ErrTransferLimitExceeded)
+}
+
+func TestStorage_TransferCoinsBatch(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE transfer_batches CASCADE")
+	})
+
+	sender := &models.User{Username: "testUser22", Password: "hashed_password_22"}
+	first := &models.User{Username: "testUser23", Password: "hashed_password_23"}
+	second := &models.User{Username: "testUser24", Password: "hashed_password_24"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, first))
+	require.NoError(t, storage.SaveUser(ctx, second))
+
+	batchID, err := storage.TransferCoinsBatch(ctx, sender.ID, []models.BatchTransfer{
+		{ReceiverID: first.ID, Amount: 300}, {ReceiverID: second.ID, Amount: 200},
+	}, nil)
+	require.NoError(t, err)
+	require.NotZero(t, batchID)
+
+	// the batch is sent entirely or not at all
+	_, err = storage.TransferCoinsBatch(ctx, sender.ID, []models.BatchTransfer{
+		{ReceiverID: first.ID, Amount: 300}, {ReceiverID: second.ID, Amount: 300},
+	}, nil)
+	require.ErrorIs(t, err, models.ErrNotEnoughCoins)
+
+	coins, err := storage.GetCoinsByUserID(ctx, sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, 500, coins)
+
+	history, err := storage.GetCoinHistoryByUserID(ctx, sender.ID)
+	require.NoError(t, err)
+	require.Len(t, *history.Sending, 2)
+	for _, leg := range *history.Sending {
+		require.Equal(t, batchID, *leg.BatchID)
+	}
+
+	// a batch counts as one transfer for the velocity rule
+	stats, err := storage.GetTransferStats(ctx, sender.ID, first.ID, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.RecentTransfers)
+	require.Equal(t, 300, stats.SentToRecipient)
+}
+
+func TestStorage_CoinRequest(t *testing.T) {
+	clearDataBase(t)
+
+	requester := &models.User{Username: "testUser25", Password: "hashed_password_25"}
+	payer := &models.User{Username: "testUser26", Password: "hashed_password_26"}
+	require.NoError(t, storage.SaveUser(ctx, requester))
+	require.NoError(t, storage.SaveUser(ctx, payer))
+
+	request := &models.CoinRequest{
+		RequesterID: requester.ID, PayerID: payer.ID, Amount: 300, Note: ptr("shared gift"),
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+	require.NoError(t, storage.CreateCoinRequest(ctx, request))
+	require.Equal(t, models.CoinRequestPending, request.Status)
+	require.Equal(t, requester.Username, request.Requester)
+
+	incoming, err := storage.GetIncomingCoinRequests(ctx, payer.ID)
+	require.NoError(t, err)
+	require.Len(t, *incoming, 1)
+
+	// only the payer accepts the request, it's paid once
+	_, err = storage.ResolveCoinRequest(ctx, requester.ID, request.ID, models.CoinRequestAccepted, nil)
+	require.ErrorIs(t, err, models.ErrCoinRequestNotFound)
+	accepted, err := storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, nil)
+	require.NoError(t, err)
+	require.Equal(t, models.CoinRequestAccepted, accepted.Status)
+	_, err = storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, nil)
+	require.ErrorIs(t, err, models.ErrCoinRequestNotFound)
+
+	coins, err := storage.GetCoinsByUserID(ctx, requester.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1300, coins)
+
+	got, err := storage.GetCoinRequest(ctx, requester.ID, request.ID)
+	require.NoError(t, err)
+	require.Len(t, *got.History, 2)
+	require.Equal(t, payer.Username, *(*got.History)[1].Actor)
+
+	// an unanswered request expires
+	expiring := &models.CoinRequest{
+		RequesterID: requester.ID, PayerID: payer.ID, Amount: 100,
+		ExpiresAt: time.Now().UTC().Add(-time.Minute),
+	}
+	require.NoError(t, storage.CreateCoinRequest(ctx, expiring))
+	expired, err := storage.ExpireCoinRequests(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+	got, err = storage.GetCoinRequest(ctx, payer.ID, expiring.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.CoinRequestExpired, got.Status)
+	require.Nil(t, (*got.History)[1].Actor)
+}
+
+func TestStorage_CoinRequestReview(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE transfer_reviews CASCADE")
+	})
+
+	requester := &models.User{Username: "testUser41", Password: "hashed_password_41"}
+	payer := &models.User{Username: "testUser42", Password: "hashed_password_42"}
+	admin := &models.User{Username: "testUser43", Password: "hashed_password_43"}
+	require.NoError(t, storage.SaveUser(ctx, requester))
+	require.NoError(t, storage.SaveUser(ctx, payer))
+	require.NoError(t, storage.SaveUser(ctx, admin))
+
+	rules := &models.TransferRules{Window: time.Minute, Check: func(*models.TransferStats, int) (string, error) {
+		return models.RuleFreshAccounts, models.ErrFreshAccountTransfer
+	}}
+	newRequest := func() *models.CoinRequest {
+		r := &models.CoinRequest{RequesterID: requester.ID, PayerID: payer.ID, Amount: 200, ExpiresAt: time.Now().UTC().Add(time.Hour)}
+		require.NoError(t, storage.CreateCoinRequest(ctx, r))
+		return r
+	}
+
+	// the payment held for review can't be made again until it's decided
+	request := newRequest()
+	_, err := storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, rules)
+	require.ErrorIs(t, err, models.ErrFreshAccountTransfer)
+	_, err = storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, nil)
+	require.ErrorIs(t, err, models.ErrCoinRequestUnderReview)
+
+	// approving the review pays the request once
+	reviews, err := storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 1)
+	require.Equal(t, request.ID, *(*reviews)[0].CoinRequestID)
+	_, err = storage.ResolveTransferReview(ctx, (*reviews)[0].ID, admin.ID, models.ReviewApproved)
+	require.NoError(t, err)
+	got, err := storage.GetCoinRequest(ctx, payer.ID, request.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.CoinRequestAccepted, got.Status)
+	_, err = storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, nil)
+	require.ErrorIs(t, err, models.ErrCoinRequestNotFound)
+
+	coins, err := storage.GetCoinsByUserID(ctx, payer.ID)
+	require.NoError(t, err)
+	require.Equal(t, 800, coins)
+
+	// declining the request closes its review
+	request = newRequest()
+	_, err = storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestAccepted, rules)
+	require.ErrorIs(t, err, models.ErrFreshAccountTransfer)
+	_, err = storage.ResolveCoinRequest(ctx, payer.ID, request.ID, models.CoinRequestDeclined, nil)
+	require.NoError(t, err)
+	reviews, err = storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Empty(t, *reviews)
+
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{
+		Action: models.AuditCoinRequestStatus, Target: "coin_request:" + strconv.Itoa(request.ID), Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+}
+
+func TestStorage_TeamWallet(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE teams CASCADE")
+	})
+
+	owner := &models.User{Username: "testUser27", Password: "hashed_password_27"}
+	member := &models.User{Username: "testUser28", Password: "hashed_password_28"}
+	recipient := &models.User{Username: "testUser29", Password: "hashed_password_29"}
+	require.NoError(t, storage.SaveUser(ctx, owner))
+	require.NoError(t, storage.SaveUser(ctx, member))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	team := &models.Team{Name: "platform", ApprovalThreshold: ptr(100)}
+	require.NoError(t, storage.CreateTeam(ctx, team, owner.ID))
+	require.ErrorIs(t, storage.CreateTeam(ctx, &models.Team{Name: "platform"}, member.ID), models.ErrTeamExists)
+	require.NoError(t, storage.SetTeamMember(ctx, team.ID, member.ID,
+		&models.TeamMembership{Role: models.TeamRoleMember, SpendLimit: ptr(150)}))
+	require.NoError(t, storage.DepositToTeam(ctx, owner.ID, team.ID, 450, nil))
+	// anyone can chip in
+	require.NoError(t, storage.DepositToTeam(ctx, recipient.ID, team.ID, 50, nil))
+	require.ErrorIs(t, storage.DepositToTeam(ctx, owner.ID, team.ID+1000, 50, nil), models.ErrTeamNotFound)
+
+	// the team keeps its last owner
+	require.ErrorIs(t, storage.RemoveTeamMember(ctx, team.ID, owner.ID), models.ErrLastTeamOwner)
+
+	decide := func(status string) func(*models.Team, *models.TeamMember) (string, error) {
+		return func(*models.Team, *models.TeamMember) (string, error) { return status, nil }
+	}
+	sent := &models.TeamSpend{TeamID: team.ID, MemberID: member.ID, ReceiverID: recipient.ID, Amount: 80}
+	require.NoError(t, storage.SpendFromTeam(ctx, sent, decide(models.SpendSent), nil))
+	pending := &models.TeamSpend{TeamID: team.ID, MemberID: member.ID, ReceiverID: recipient.ID, Amount: 120}
+	require.NoError(t, storage.SpendFromTeam(ctx, pending, decide(models.SpendPending), nil))
+
+	got, err := storage.GetTeam(ctx, member.ID, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, 420, got.Coins)
+	require.Len(t, *got.Members, 2)
+	require.Equal(t, 200, (*got.Members)[1].Spent)
+
+	approved, err := storage.ResolveTeamSpend(ctx, team.ID, pending.ID, owner.ID, models.SpendApproved, nil)
+	require.NoError(t, err)
+	require.Equal(t, models.SpendApproved, approved.Status)
+	_, err = storage.ResolveTeamSpend(ctx, team.ID, pending.ID, owner.ID, models.SpendApproved, nil)
+	require.ErrorIs(t, err, models.ErrTeamSpendNotFound)
+
+	coins, err := storage.GetCoinsByUserID(ctx, recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1150, coins)
+
+	// the recipient sees the team as the sender, the member's own history stays empty
+	history, err := storage.GetCoinHistoryByUserID(ctx, recipient.ID)
+	require.NoError(t, err)
+	require.Len(t, *history.Receiving, 2)
+	require.Len(t, *history.Sending, 1)
+	require.Equal(t, "platform", (*history.Sending)[0].User)
+	require.Equal(t, "platform", (*history.Receiving)[0].User)
+	history, err = storage.GetCoinHistoryByUserID(ctx, member.ID)
+	require.NoError(t, err)
+	require.Empty(t, *history.Sending)
+}
+
+func TestStorage_TeamWalletRules(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE teams, transfer_reviews CASCADE")
+	})
+
+	owner := &models.User{Username: "testUser44", Password: "hashed_password_44"}
+	member := &models.User{Username: "testUser45", Password: "hashed_password_45"}
+	recipient := &models.User{Username: "testUser46", Password: "hashed_password_46"}
+	admin := &models.User{Username: "testUser47", Password: "hashed_password_47"}
+	for _, u := range []*models.User{owner, member, recipient, admin} {
+		require.NoError(t, storage.SaveUser(ctx, u))
+	}
+
+	team := &models.Team{Name: "security"}
+	require.NoError(t, storage.CreateTeam(ctx, team, owner.ID))
+	require.NoError(t, storage.SetTeamMember(ctx, team.ID, member.ID, &models.TeamMembership{Role: models.TeamRoleMember}))
+
+	rules := &models.TransferRules{Window: time.Minute, Check: func(st *models.TransferStats, coins int) (string, error) {
+		if st.SentDay+coins > 100 {
+			return models.RuleDailyCap, models.ErrTransferLimitExceeded
+		}
+		return "", nil
+	}}
+
+	// the deposits count as the depositor's transfers
+	require.NoError(t, storage.DepositToTeam(ctx, owner.ID, team.ID, 400, nil))
+	require.ErrorIs(t, storage.DepositToTeam(ctx, owner.ID, team.ID, 50, rules), models.ErrTransferLimitExceeded)
+
+	// the spend breaking a rule is held for review, the spends count as the member's transfers
+	decide := func(*models.Team, *models.TeamMember) (string, error) { return models.SpendSent, nil }
+	require.NoError(t, storage.SpendFromTeam(ctx,
+		&models.TeamSpend{TeamID: team.ID, MemberID: member.ID, ReceiverID: recipient.ID, Amount: 80}, decide, rules))
+	held := &models.TeamSpend{TeamID: team.ID, MemberID: member.ID, ReceiverID: recipient.ID, Amount: 50}
+	require.ErrorIs(t, storage.SpendFromTeam(ctx, held, decide, rules), models.ErrTransferLimitExceeded)
+	require.Equal(t, models.SpendHeld, held.Status)
+
+	got, err := storage.GetTeam(ctx, owner.ID, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, 320, got.Coins)
+
+	reviews, err := storage.GetTransferReviews(ctx, models.ReviewOpen)
+	require.NoError(t, err)
+	require.Len(t, *reviews, 2)
+	deposit, spend := (*reviews)[0], (*reviews)[1]
+	require.Equal(t, owner.Username, deposit.Initiator)
+	require.Equal(t, "security", deposit.Recipient)
+	require.Equal(t, member.Username, spend.Initiator)
+	require.Equal(t, "security", spend.Sender)
+	require.Equal(t, held.ID, *spend.TeamSpendID)
+
+	// approving the review makes the held spend, rejecting it leaves the coins where they are
+	_, err = storage.ResolveTransferReview(ctx, spend.ID, admin.ID, models.ReviewApproved)
+	require.NoError(t, err)
+	_, err = storage.ResolveTransferReview(ctx, deposit.ID, admin.ID, models.ReviewRejected)
+	require.NoError(t, err)
+
+	spends, err := storage.GetTeamSpends(ctx, team.ID, models.SpendApproved)
+	require.NoError(t, err)
+	require.Len(t, *spends, 1)
+	require.Equal(t, held.ID, (*spends)[0].ID)
+	coins, err := storage.GetCoinsByUserID(ctx, recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1130, coins)
+	coins, err = storage.GetCoinsByUserID(ctx, owner.ID)
+	require.NoError(t, err)
+	require.Equal(t, 600, coins)
+
+	stats, err := storage.GetTransferStats(ctx, member.ID, recipient.ID, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 130, stats.SentDay)
+	require.Equal(t, 130, stats.SentToRecipient)
+
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{
+		Action: models.AuditTeamSpent, Target: "team_spend:" + strconv.Itoa(held.ID), Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+	entries, err = storage.GetAuditEntries(ctx, &models.AuditFilter{
+		Action: models.AuditTeamDeposited, Target: "team:" + strconv.Itoa(team.ID), Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+}
+
+func TestStorage_AuditLog(t *testing.T) {
+	clearDataBase(t)
+
+	sender := &models.User{Username: "testUser30", Password: "hashed_password_30"}
+	recipient := &models.User{Username: "testUser31", Password: "hashed_password_31"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	requestID := "audit-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	reqCtx := audit.WithRequest(ctx, audit.Request{
+		ActorID: &sender.ID, Actor: &sender.Username, IP: "10.0.0.1", UserAgent: "curl/8.0", RequestID: requestID,
+	})
+
+	// the entry is written with the transfer and not without it
+	require.NoError(t, storage.TransferCoins(reqCtx, sender.ID, recipient.ID, 100, nil))
+	require.ErrorIs(t, storage.TransferCoins(reqCtx, sender.ID, recipient.ID, 5000, nil), models.ErrNotEnoughCoins)
+
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{RequestID: requestID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+	entry := (*entries)[0]
+	require.Equal(t, models.AuditCoinsTransferred, entry.Action)
+	require.Equal(t, "user:"+strconv.Itoa(recipient.ID), entry.Target)
+	require.Equal(t, sender.Username, *entry.Actor)
+	require.Equal(t, audit.Hash(entry.PrevHash, &entry), entry.Hash)
+	require.Equal(t, audit.ChainOf(entry.Target), entry.Chain)
+
+	// the entry is chained to the previous one of its chain, the recipient's registration
+	previous, err := storage.GetAuditEntries(ctx, &models.AuditFilter{BeforeID: entry.ID, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, models.AuditUserRegistered, (*previous)[0].Action)
+	require.Equal(t, entry.Chain, (*previous)[0].Chain)
+	require.Equal(t, (*previous)[0].Hash, entry.PrevHash)
+
+	// the log is append-only
+	_, err = pool.Exec(ctx, "UPDATE audit_log SET action = 'tampered' WHERE id = $1", entry.ID)
+	require.Error(t, err)
+	_, err = pool.Exec(ctx, "DELETE FROM audit_log WHERE id = $1", entry.ID)
+	require.Error(t, err)
+}
+
+func TestStorage_Pages(t *testing.T) {
+	clearDataBase(t)
+
+	sender := &models.User{Username: "testUser50", Password: "hashed_password_50"}
+	recipient := &models.User{Username: "testUser51", Password: "hashed_password_51"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+	for coins := 1; coins <= 5; coins++ {
+		require.NoError(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, coins, nil))
+	}
+
+	// the page is cut by the query, the total counts the whole history, the latest transfers first
+	sent, err := storage.GetSentCoinsPageByUserID(ctx, sender.ID, 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, 5, sent.Total)
+	require.Equal(t, []models.Sending{
+		{User: recipient.Username, Amount: 4, Kind: models.TxKindTransfer},
+		{User: recipient.Username, Amount: 3, Kind: models.TxKindTransfer},
+	}, sent.Items)
+
+	// past the end the page is empty but the total is still known
+	received, err := storage.GetReceivedCoinsPageByUserID(ctx, recipient.ID, 20, 10)
+	require.NoError(t, err)
+	require.Empty(t, received.Items)
+	require.NotNil(t, received.Items)
+	require.Equal(t, 5, received.Total)
+
+	none, err := storage.GetReceivedCoinsPageByUserID(ctx, sender.ID, 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, models.Page[models.Receiving]{Items: []models.Receiving{}, Limit: 20}, *none)
+}
+
+func TestStorage_AuditedChanges(t *testing.T) {
+	clearDataBase(t)
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "TRUNCATE TABLE teams, webhook_subscriptions CASCADE")
+	})
+
+	owner := &models.User{Username: "testUser48", Password: "hashed_password_48"}
+	member := &models.User{Username: "testUser49", Password: "hashed_password_49"}
+	require.NoError(t, storage.SaveUser(ctx, owner))
+	require.NoError(t, storage.SaveUser(ctx, member))
+
+	requestID := "audit-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	reqCtx := audit.WithRequest(ctx, audit.Request{ActorID: &owner.ID, Actor: &owner.Username, RequestID: requestID})
+
+	team := &models.Team{Name: "audited"}
+	require.NoError(t, storage.CreateTeam(reqCtx, team, owner.ID))
+	require.NoError(t, storage.SetTeamMember(reqCtx, team.ID, member.ID, &models.TeamMembership{Role: models.TeamRoleMember}))
+	require.NoError(t, storage.RemoveTeamMember(reqCtx, team.ID, member.ID))
+	sub := &models.WebhookSubscription{URL: "https://audit.example.com/hook", Secret: "top-secret", Events: []string{}}
+	require.NoError(t, storage.CreateWebhookSubscription(reqCtx, sub))
+	require.NoError(t, storage.DeleteWebhookSubscription(reqCtx, sub.ID))
+	// nothing changed, nothing audited
+	require.ErrorIs(t, storage.DeleteWebhookSubscription(reqCtx, sub.ID), models.ErrWebhookNotFound)
+
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{RequestID: requestID, Limit: 10})
+	require.NoError(t, err)
+	actions := make([]string, 0, len(*entries))
+	for _, e := range *entries {
+		actions = append(actions, e.Action)
+		require.Equal(t, audit.ChainOf(e.Target), e.Chain)
+		// the webhook secret stays out of the log
+		if e.Details != nil {
+			require.NotContains(t, *e.Details, sub.Secret)
+		}
+	}
+	require.Equal(t, []string{
+		models.AuditWebhookDeleted, models.AuditWebhookCreated,
+		models.AuditTeamMemberRemoved, models.AuditTeamMemberSet, models.AuditTeamCreated,
+	}, actions)
+
+	// each chain verifies on its own
+	for chain := range audit.Chains {
+		entries, err := storage.GetAuditChain(ctx, chain, 0, 1000)
+		require.NoError(t, err)
+		prevHash := audit.GenesisHash
+		for i := range *entries {
+			e := &(*entries)[i]
+			require.Equal(t, chain, e.Chain)
+			require.Equal(t, prevHash, e.PrevHash)
+			require.Equal(t, audit.Hash(prevHash, e), e.Hash)
+			prevHash = e.Hash
+		}
+	}
+}
+
+func TestStorage_Webhooks(t *testing.T) {
+	clearDataBase(t)
+	_, err := pool.Exec(ctx, "TRUNCATE TABLE outbox_events, webhook_subscriptions CASCADE")
+	require.NoError(t, err)
+
+	sender := &models.User{Username: "testUser32", Password: "hashed_password_32"}
+	recipient := &models.User{Username: "testUser33", Password: "hashed_password_33"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	purchases := &models.WebhookSubscription{URL: "https://shop.example.com/hook", Secret: "secret", Events: []string{models.EventMerchPurchased}}
+	everything := &models.WebhookSubscription{URL: "https://bot.example.com/hook", Secret: "secret", Events: []string{}}
+	require.NoError(t, storage.CreateWebhookSubscription(ctx, purchases))
+	require.NoError(t, storage.CreateWebhookSubscription(ctx, everything))
+
+	// the event is written with the transfer and not without it
+	require.NoError(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, 100, nil))
+	require.ErrorIs(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, 5000, nil), models.ErrNotEnoughCoins)
+
+	dispatched, err := storage.DispatchOutboxEvents(ctx, 100)
+	require.NoError(t, err)
+	require.Equal(t, 1, dispatched)
+	dispatched, err = storage.DispatchOutboxEvents(ctx, 100)
+	require.NoError(t, err)
+	require.Zero(t, dispatched)
+
+	// the only delivery goes to the subscription to all the events, it isn't due while leased
+	d, err := storage.ClaimDueWebhook(ctx, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, everything.ID, d.SubscriptionID)
+	require.Equal(t, models.EventCoinsReceived, d.EventType)
+	require.JSONEq(t, `{"fromUser":"testUser32","toUser":"testUser33","amount":100,"team":null,"batchId":null}`, d.Payload)
+	none, err := storage.ClaimDueWebhook(ctx, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, none)
+
+	// once the lease ends, the delivery is claimed again and the outcome of the first claim is dropped
+	_, err = pool.Exec(ctx, "UPDATE webhook_deliveries SET next_attempt_at = NOW() WHERE id = $1", d.ID)
+	require.NoError(t, err)
+	reclaimed, err := storage.ClaimDueWebhook(ctx, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, d.ID, reclaimed.ID)
+	saved, err := storage.SaveWebhookOutcome(ctx, d,
+		&models.DeliveryOutcome{Status: models.DeliveryDelivered, Attempts: 1, NextAttemptAt: d.NextAttemptAt})
+	require.NoError(t, err)
+	require.False(t, saved)
+
+	// the delivery fails for good
+	saved, err = storage.SaveWebhookOutcome(ctx, reclaimed,
+		&models.DeliveryOutcome{Status: models.DeliveryDead, Attempts: 1, NextAttemptAt: reclaimed.NextAttemptAt, LastError: ptr("timeout")})
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	dead, err := storage.GetWebhookDeliveries(ctx, models.DeliveryDead, everything.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, *dead, 1)
+	require.Equal(t, "timeout", *(*dead)[0].LastError)
+
+	replayed, err := storage.ReplayDeadDeliveries(ctx, everything.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, replayed)
+	require.ErrorIs(t, storage.ReplayWebhookDelivery(ctx, (*dead)[0].ID), models.ErrWebhookDeliveryNotFound)
+
+	// the subscription's secret isn't shown once created
+	subs, err := storage.GetWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, *subs, 2)
+	require.Empty(t, (*subs)[0].Secret)
+	require.ErrorIs(t, storage.DeleteWebhookSubscription(ctx, everything.ID+100), models.ErrWebhookNotFound)
+}
+
+func TestStorage_UserEvents(t *testing.T) {
+	clearDataBase(t)
+
+	sender := &models.User{Username: "testUser34", Password: "hashed_password_34"}
+	recipient := &models.User{Username: "testUser35", Password: "hashed_password_35"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	listenCtx, listenCancel := context.WithCancel(ctx)
+	defer listenCancel()
+	notified := make(chan int, 10)
+	listening := make(chan struct{})
+	go func() {
+		_ = storage.ListenUserEvents(listenCtx, func(userID int) {
+			if userID == 0 {
+				close(listening)
+				return
+			}
+			notified <- userID
+		})
+	}()
+	<-listening
+
+	lastID, err := storage.GetLastUserEventID(ctx, recipient.ID)
+	require.NoError(t, err)
+	require.Zero(t, lastID)
+
+	// the events are written and announced with the transfer
+	require.NoError(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, 100, nil))
+	got := map[int]bool{}
+	for len(got) < 2 {
+		select {
+		case userID := <-notified:
+			got[userID] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("no notification received")
+		}
+	}
+	require.True(t, got[sender.ID])
+	require.True(t, got[recipient.ID])
+
+	events, err := storage.GetUserEvents(ctx, recipient.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, *events, 2)
+	require.Equal(t, models.UserEventBalance, (*events)[0].Kind)
+	require.JSONEq(t, `{"coins":1100}`, (*events)[0].Payload)
+	require.Equal(t, models.UserEventTransfer, (*events)[1].Kind)
+	require.JSONEq(t, `{"fromUser":"testUser34","amount":100,"kind":"transfer","batchId":null}`, (*events)[1].Payload)
+
+	// the sender sees the balance change only
+	events, err = storage.GetUserEvents(ctx, sender.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, *events, 1)
+
+	// resuming after the last event gives nothing
+	lastID, err = storage.GetLastUserEventID(ctx, recipient.ID)
+	require.NoError(t, err)
+	events, err = storage.GetUserEvents(ctx, recipient.ID, lastID, 10)
+	require.NoError(t, err)
+	require.Empty(t, *events)
+}
+
+func TestStorage_AdminTools(t *testing.T) {
+	clearDataBase(t)
+
+	user := &models.User{Username: "testUser36", Password: "hashed_password_36"}
+	require.NoError(t, storage.SaveUser(ctx, user))
+
+	requestID := "admin-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	actor := "support"
+	reqCtx := audit.WithRequest(ctx, audit.Request{Actor: &actor, UserAgent: "merchshopctl", RequestID: requestID})
+
+	// a dry run reports the new balance, but nothing is saved
+	balance, err := storage.GrantCoins(WithDryRun(reqCtx), user.ID, 100, "hackathon prize")
+	require.NoError(t, err)
+	require.Equal(t, 1100, balance)
+	account, err := storage.GetAccountByUsername(ctx, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, 1000, account.Coins)
+
+	balance, err = storage.GrantCoins(reqCtx, user.ID, 100, "hackathon prize")
+	require.NoError(t, err)
+	require.Equal(t, 1100, balance)
+	history, err := storage.GetCoinHistoryByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Receiving{{Amount: 100, Kind: models.TxKindGrant}}, *history.Receiving)
+
+	// the inventory can't go below zero
+	left, err := storage.AdjustInventory(reqCtx, user.ID, "cup", 2, "lost parcel")
+	require.NoError(t, err)
+	require.Equal(t, 2, left)
+	_, err = storage.AdjustInventory(reqCtx, user.ID, "cup", -3, "")
+	require.ErrorIs(t, err, models.ErrNotEnoughItems)
+	_, err = storage.AdjustInventory(reqCtx, user.ID, "unknown", 1, "")
+	require.ErrorIs(t, err, models.ErrVariantNotFound)
+
+	lockedAt, err := storage.SetUserLocked(reqCtx, user.ID, true, "left the company")
+	require.NoError(t, err)
+	require.NotNil(t, lockedAt)
+	locked, err := storage.GetUserByUsername(ctx, user.Username)
+	require.NoError(t, err)
+	require.NotNil(t, locked.LockedAt)
+
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{RequestID: requestID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, *entries, 3)
+	require.Equal(t, models.AuditUserLocked, (*entries)[0].Action)
+	require.Equal(t, models.AuditInventoryAdjusted, (*entries)[1].Action)
+	require.Equal(t, models.AuditCoinsGranted, (*entries)[2].Action)
+	require.Equal(t, actor, *(*entries)[2].Actor)
+}
+
+func TestStorage_AdminItems(t *testing.T) {
+	_, err := pool.Exec(ctx, "DELETE FROM item_variants WHERE item_slug = 'sticker'")
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "DELETE FROM store WHERE slug = 'sticker'")
+	require.NoError(t, err)
+
+	stock := 50
+	item := &models.Item{Slug: "sticker", Title: "Sticker", Price: 5, Stock: &stock}
+	require.NoError(t, storage.CreateItem(ctx, item))
+	require.Len(t, item.Variants, 1)
+	require.Equal(t, "sticker", item.Variants[0].SKU)
+	require.True(t, item.Variants[0].IsDefault)
+
+	require.ErrorIs(t, storage.CreateItem(ctx, item), models.ErrItemExists)
+
+	price := 7
+	updated, err := storage.UpdateItem(ctx, "sticker", &models.ItemUpdate{Price: &price})
+	require.NoError(t, err)
+	require.Equal(t, 7, updated.Price)
+	require.Equal(t, "Sticker", updated.Title)
+	require.Equal(t, &stock, updated.Stock)
+
+	_, err = storage.UpdateItem(ctx, "unknown", &models.ItemUpdate{Price: &price})
+	require.ErrorIs(t, err, models.ErrItemNotFound)
+}
+
+func TestStorage_SyncDirectory(t *testing.T) {
+	clearDataBase(t)
+
+	// a user who registered before the first sync becomes synced with the directory
+	veteran := &models.User{Username: "testUser37", Password: "hashed_password_37"}
+	require.NoError(t, storage.SaveUser(ctx, veteran))
+	// a user managed by the support staff is left alone
+	staff := &models.User{Username: "testUser38", Password: "hashed_password_38"}
+	require.NoError(t, storage.SaveUser(ctx, staff))
+
+	directory := []models.DirectoryUser{
+		{Username: veteran.Username, ClaimTokenHash: hasher.HashToken("veteranToken")},
+		{Username: "newHire01", Grant: 50, ClaimTokenHash: hasher.HashToken("hireToken")},
+	}
+
+	// a dry run reports the changes, but nothing is saved
+	result, err := storage.SyncDirectory(WithDryRun(ctx), directory, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"newHire01"}, result.Created)
+	_, err = storage.GetUserByUsername(ctx, "newHire01")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	result, err = storage.SyncDirectory(ctx, directory, true)
+	require.NoError(t, err)
+	require.Equal(t, &models.DirectorySync{
+		Created: []string{"newHire01"}, Adopted: []string{veteran.Username}, Reactivated: []string{}, Deactivated: []string{},
+	}, result)
+	// the account the sync will deactivate once the user isn't listed is taken over in the audit log
+	entries, err := storage.GetAuditEntries(ctx, &models.AuditFilter{
+		Action: models.AuditUserAdopted, Target: "user:" + strconv.Itoa(veteran.ID), Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, *entries, 1)
+
+	// the new hire has no password until the first login, the grant is in the history
+	hire, err := storage.GetUserByUsername(ctx, "newHire01")
+	require.NoError(t, err)
+	require.Empty(t, hire.Password)
+	require.Equal(t, hasher.HashToken("hireToken"), *hire.ClaimTokenHash)
+	require.Equal(t, 1050, hire.Coins)
+	history, err := storage.GetCoinHistoryByUserID(ctx, hire.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Receiving{{Amount: 50, Kind: models.TxKindGrant}}, *history.Receiving)
+
+	// a token reissued in the meantime replaces the one the login was checked with
+	require.NoError(t, storage.SetClaimToken(ctx, hire.ID, hasher.HashToken("reissuedToken")))
+	hire.Password = "hashed_password_39"
+	require.ErrorIs(t, storage.ClaimUser(ctx, hire), models.ErrInvalidPassword)
+
+	// the token is used up by the first login
+	hire.ClaimTokenHash = ptr(hasher.HashToken("reissuedToken"))
+	require.NoError(t, storage.ClaimUser(ctx, hire))
+	require.Nil(t, hire.ClaimTokenHash)
+	hire.ClaimTokenHash = ptr(hasher.HashToken("reissuedToken"))
+	require.ErrorIs(t, storage.ClaimUser(ctx, hire), models.ErrInvalidPassword)
+	require.ErrorIs(t, storage.SetClaimToken(ctx, hire.ID, hasher.HashToken("lateToken")), models.ErrAccountClaimed)
+	claimed, err := storage.GetUserByUsername(ctx, "newHire01")
+	require.NoError(t, err)
+	require.Nil(t, claimed.ClaimTokenHash)
+
+	// the veteran has left: the account is deactivated, not deleted, and doesn't receive coins anymore
+	result, err = storage.SyncDirectory(ctx, directory[1:], true)
+	require.NoError(t, err)
+	require.Equal(t, []string{veteran.Username}, result.Deactivated)
+	left, err := storage.GetUserByUsername(ctx, veteran.Username)
+	require.NoError(t, err)
+	require.NotNil(t, left.DeactivatedAt)
+	require.ErrorIs(t, storage.TransferCoins(ctx, hire.ID, veteran.ID, 10, nil), models.ErrRecipientInactive)
+	require.NoError(t, storage.TransferCoins(ctx, hire.ID, staff.ID, 10, nil))
+
+	// the veteran is back
+	result, err = storage.SyncDirectory(ctx, directory, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{veteran.Username}, result.Reactivated)
+
+	// the support staff take the account over, the sync doesn't reactivate it
+	deactivatedAt, err := storage.SetUserDeactivated(ctx, veteran.ID, true, "left the company")
+	require.NoError(t, err)
+	require.NotNil(t, deactivatedAt)
+	result, err = storage.SyncDirectory(ctx, directory, true)
+	require.NoError(t, err)
+	require.Empty(t, result.Reactivated)
+	require.Empty(t, result.Deactivated)
+	account, err := storage.GetAccountByUsername(ctx, veteran.Username)
+	require.NoError(t, err)
+	require.NotNil(t, account.DeactivatedAt)
+	require.False(t, account.DirectoryManaged)
+
+	// the account reactivated by the staff is neither adopted while the user is listed nor deactivated once not
+	deactivatedAt, err = storage.SetUserDeactivated(ctx, veteran.ID, false, "rehired as a contractor")
+	require.NoError(t, err)
+	require.Nil(t, deactivatedAt)
+	result, err = storage.SyncDirectory(ctx, directory, true)
+	require.NoError(t, err)
+	require.Empty(t, result.Adopted)
+	require.Empty(t, result.Reactivated)
+	result, err = storage.SyncDirectory(ctx, directory[1:], true)
+	require.NoError(t, err)
+	require.Empty(t, result.Deactivated)
+	account, err = storage.GetAccountByUsername(ctx, veteran.Username)
+	require.NoError(t, err)
+	require.Nil(t, account.DeactivatedAt)
+	require.False(t, account.DirectoryManaged)
+}
+
+func TestStorage_ExportLedger(t *testing.T) {
+	clearDataBase(t)
+	from := time.Now().Add(-time.Minute)
+
+	sender := &models.User{Username: "testUser37", Password: "hashed_password_37"}
+	recipient := &models.User{Username: "testUser38", Password: "hashed_password_38"}
+	require.NoError(t, storage.SaveUser(ctx, sender))
+	require.NoError(t, storage.SaveUser(ctx, recipient))
+
+	_, err := storage.GrantCoins(ctx, sender.ID, 100, "hackathon prize")
+	require.NoError(t, err)
+	require.NoError(t, storage.TransferCoins(ctx, sender.ID, recipient.ID, 50, nil))
+	item, err := storage.GetItemBySlug(ctx, "cup")
+	require.NoError(t, err)
+	quote, err := storage.MakePurchaseByUserID(ctx, sender.ID, item, &models.Order{})
+	require.NoError(t, err)
+
+	var entries []models.LedgerEntry
+	err = storage.ExportLedger(ctx, from, time.Now().Add(time.Minute), func(e *models.LedgerEntry) error {
+		entries = append(entries, *e)
+		return nil
+	})
+	require.NoError(t, err)
+
+	type row struct {
+		kind     string
+		username string
+		amount   int
+		balance  int
+	}
+	got := make([]row, len(entries))
+	for i, e := range entries {
+		got[i] = row{e.Kind, e.Username, e.Amount, e.Balance}
+	}
+	require.Equal(t, []row{
+		{models.TxKindGrant, sender.Username, 100, 1100},
+		{models.TxKindTransfer, sender.Username, -50, 1050},
+		{models.TxKindTransfer, recipient.Username, 50, 1050},
+		{"purchase", sender.Username, -quote.Price, 1050 - quote.Price},
+	}, got)
+	require.Equal(t, recipient.Username, *entries[1].Counterparty)
+	require.Equal(t, "hackathon prize", *entries[0].Reason)
+
+	// the balances account for the entries outside the range, the end of the range is excluded
+	var transfers []models.LedgerEntry
+	err = storage.ExportLedger(ctx, entries[1].At, entries[3].At, func(e *models.LedgerEntry) error {
+		transfers = append(transfers, *e)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, entries[1:3], transfers)
+}
@@ -0,0 +1,248 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	getReturnablePurchase = `
+		SELECT id, sku, price FROM purchases
+		WHERE user_id = $1 AND item_slug = $2 AND ($3 = '' OR sku = $3) AND returned_at IS NULL
+		  AND created_at >= NOW() - make_interval(secs => $4)
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE;`
+	// a purchase that hasn't been handed over yet is cancelled by the refund
+	markPurchaseReturned = `
+		WITH old AS (SELECT id, status FROM purchases WHERE id = $1),
+		     upd AS (
+		         UPDATE purchases p
+		         SET returned_at       = NOW(),
+		             updated_at        = NOW(),
+		             status            = CASE WHEN p.status = 'handed_over' THEN p.status ELSE 'cancelled' END,
+		             status_changed_at = CASE WHEN p.status = 'handed_over' THEN p.status_changed_at ELSE NOW() END
+		         FROM old
+		         WHERE p.id = old.id
+		         RETURNING p.id, old.status AS from_status, p.status AS to_status)
+		INSERT INTO purchase_status_changes (purchase_id, operator_id, from_status, to_status)
+		SELECT id, $2, from_status, to_status FROM upd WHERE from_status <> to_status;`
+	removeItemFromInventoryByUserID = `
+		UPDATE inventory SET quantity = quantity - $3, updated_at = NOW()
+		WHERE user_id = $1 AND sku = $2 AND quantity >= $3;`
+	isUserActive        = `SELECT deactivated_at IS NULL FROM users WHERE id = $1 FOR SHARE;`
+	incrementStockBySKU = `UPDATE item_variants SET stock = stock + $2, updated_at = NOW() WHERE sku = $1 AND stock IS NOT NULL RETURNING stock;`
+	recordRefund        = `INSERT INTO transactions (sender_id, receiver_id, receiver_account_id, coins, kind, purchase_id) VALUES (NULL, $1, (SELECT id FROM accounts WHERE user_id = $1), $2, 'refund', $3);`
+	recordGift          = `INSERT INTO gifts (sender_id, receiver_id, item_slug, sku, quantity) VALUES ($1, $2, $3, $4, $5);`
+
+	getReceivedGiftHistoryByUserID = `SELECT u.username, g.item_slug, g.sku, g.quantity FROM gifts g JOIN users u ON g.sender_id = u.id WHERE g.receiver_id = $1;`
+	getSendingGiftHistoryByUserID  = `SELECT u.username, g.item_slug, g.sku, g.quantity FROM gifts g JOIN users u ON g.receiver_id = u.id WHERE g.sender_id = $1;`
+	// the pages of the gift history, the latest gifts first
+	getReceivedGiftsPageByUserID = `
+		SELECT u.username, g.item_slug, g.sku, g.quantity, ` + pageTotal + `
+		FROM gifts g JOIN users u ON g.sender_id = u.id
+		WHERE g.receiver_id = $1
+		ORDER BY g.id DESC
+		LIMIT $2 OFFSET $3;`
+	getSentGiftsPageByUserID = `
+		SELECT u.username, g.item_slug, g.sku, g.quantity, ` + pageTotal + `
+		FROM gifts g JOIN users u ON g.receiver_id = u.id
+		WHERE g.sender_id = $1
+		ORDER BY g.id DESC
+		LIMIT $2 OFFSET $3;`
+)
+
+// ReturnItemByUserID returns one unit of the item back to the shop. The latest purchase of the item
+// made within the return window is refunded, the refund can't exceed what was paid for it.
+// If the SKU isn't empty, only purchases of that variant are considered.
+// Returns the number of refunded coins.
+func (s *Storage) ReturnItemByUserID(ctx context.Context, userID int, slug, sku string, window time.Duration) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	// Looking for the purchase to be refunded
+	var purchaseID, paid int
+	var purchasedSKU string
+	err = tx.QueryRow(ctx, getReturnablePurchase, userID, slug, sku, window.Seconds()).Scan(&purchaseID, &purchasedSKU, &paid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrNoReturnablePurchase
+		return 0, err
+	} else if err != nil {
+		return 0, err
+	}
+
+	err = refundPurchase(ctx, tx, userID, purchaseID, purchasedSKU, paid, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditItemReturned, "purchase:"+strconv.Itoa(purchaseID),
+		map[string]any{"user": userID, "item": slug, "sku": purchasedSKU, "refund": paid}))
+	if err != nil {
+		return 0, err
+	}
+
+	return paid, nil
+}
+
+// refundPurchase takes the purchased item back from the user's inventory, puts it back on sale,
+// marks the purchase returned and refunds the paid price. The operator is nil if the buyer returns the item.
+func refundPurchase(ctx context.Context, tx pgx.Tx, userID, purchaseID int, sku string, paid int, operatorID *int) error {
+	// Removing the item from the inventory
+	tag, err := tx.Exec(ctx, removeItemFromInventoryByUserID, userID, sku, 1)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrItemNotOwned
+	}
+
+	_, err = tx.Exec(ctx, markPurchaseReturned, purchaseID, operatorID)
+	if err != nil {
+		return err
+	}
+
+	// Putting the item back on sale, the stock of unlimited variants isn't tracked
+	var stock int
+	err = tx.QueryRow(ctx, incrementStockBySKU, sku, 1).Scan(&stock)
+	if err == nil {
+		oldStock := stock - 1
+		err = notifyIfBackInStock(ctx, tx, sku, &oldStock, &stock)
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Free items are returned without any money movement
+	if paid == 0 {
+		return nil
+	}
+
+	// Giving the money back to the user
+	_, err = tx.Exec(ctx, addToCoinsByUserID, paid, userID)
+	if err != nil {
+		return err
+	}
+
+	// Refund record
+	_, err = tx.Exec(ctx, recordRefund, userID, paid, purchaseID)
+	return err
+}
+
+// GiftItem moves the given quantity of the item's variant from one user's inventory to another's
+// and records the gift. The default variant is gifted if the SKU is empty.
+func (s *Storage) GiftItem(ctx context.Context, fromUserID, toUserID int, slug, sku string, quantity int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	variant, err := lookupVariant(ctx, tx, slug, sku)
+	if err != nil {
+		return err
+	}
+
+	// Taking the items from the sender
+	tag, err := tx.Exec(ctx, removeItemFromInventoryByUserID, fromUserID, variant.SKU, quantity)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrNotEnoughItems
+		return err
+	}
+
+	// Giving the items to the recipient, a deactivated account doesn't receive gifts
+	active := false
+	err = tx.QueryRow(ctx, isUserActive, toUserID).Scan(&active)
+	if err != nil {
+		return err
+	} else if !active {
+		err = models.ErrRecipientInactive
+		return err
+	}
+	_, err = tx.Exec(ctx, addItemToInventoryByUserID, toUserID, slug, variant.SKU, quantity)
+	if err != nil {
+		return err
+	}
+
+	// Gift record
+	_, err = tx.Exec(ctx, recordGift, fromUserID, toUserID, slug, variant.SKU, quantity)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditItemGifted, "user:"+strconv.Itoa(toUserID),
+		map[string]any{"from": fromUserID, "item": slug, "sku": variant.SKU, "quantity": quantity}))
+	return err
+}
+
+// GetReceivedGiftsPageByUserID retrieves a page of the items gifted to the user, the latest first.
+func (s *Storage) GetReceivedGiftsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftReceiving], error) {
+	return collectPage[models.GiftReceiving](ctx, s.pool, getReceivedGiftsPageByUserID, limit, offset, userID)
+}
+
+// GetSentGiftsPageByUserID retrieves a page of the items gifted by the user, the latest first.
+func (s *Storage) GetSentGiftsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftSending], error) {
+	return collectPage[models.GiftSending](ctx, s.pool, getSentGiftsPageByUserID, limit, offset, userID)
+}
+
+// GetGiftHistoryByUserID retrieves the history of items gifted to and by a user.
+func (s *Storage) GetGiftHistoryByUserID(ctx context.Context, userID int) (*models.GiftHistory, error) {
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// RECEIVED
+	var recs *[]models.GiftReceiving
+	g.Go(func() error {
+		data, err := fetchHistory[models.GiftReceiving](gCtx, s.pool, getReceivedGiftHistoryByUserID, userID)
+		if err != nil {
+			return err
+		}
+		recs = data
+		return nil
+	})
+
+	// SENT
+	var sends *[]models.GiftSending
+	g.Go(func() error {
+		data, err := fetchHistory[models.GiftSending](gCtx, s.pool, getSendingGiftHistoryByUserID, userID)
+		if err != nil {
+			return err
+		}
+		sends = data
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	gh := models.GiftHistory{
+		Receiving: recs,
+		Sending:   sends,
+	}
+	return &gh, nil
+}
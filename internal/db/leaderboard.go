@@ -0,0 +1,65 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	// only transfers between users count, refunds and other credits are not recognition
+	getCoinsLeaderboard = `
+		SELECT RANK() OVER (ORDER BY SUM(t.coins) DESC)::INT AS rank, u.username, SUM(t.coins)::INT AS value
+		FROM transactions t JOIN users u ON t.receiver_id = u.id
+		WHERE t.kind = 'transfer' AND NOT u.leaderboard_opt_out
+		  AND ($1::DOUBLE PRECISION = 0 OR t.created_at >= NOW() - make_interval(secs => $1::DOUBLE PRECISION))
+		GROUP BY u.id, u.username
+		ORDER BY value DESC, u.username
+		LIMIT $2;`
+	getItemsLeaderboard = `
+		SELECT RANK() OVER (ORDER BY SUM(i.quantity) DESC)::INT AS rank, u.username, SUM(i.quantity)::INT AS value
+		FROM inventory i JOIN users u ON i.user_id = u.id
+		WHERE NOT u.leaderboard_opt_out
+		GROUP BY u.id, u.username
+		HAVING SUM(i.quantity) > 0
+		ORDER BY value DESC, u.username
+		LIMIT $1;`
+	setLeaderboardOptOut = `UPDATE users SET leaderboard_opt_out = $2, updated_at = NOW() WHERE id = $1;`
+)
+
+// GetCoinsLeaderboard ranks users by the coins received from other users within the period,
+// a zero period covers all time. The users who opted out are not ranked.
+func (s *Storage) GetCoinsLeaderboard(ctx context.Context, period time.Duration, limit int) (*[]models.LeaderboardEntry, error) {
+	return collectLeaderboard(ctx, s.pool, getCoinsLeaderboard, period.Seconds(), limit)
+}
+
+// GetItemsLeaderboard ranks users by the number of items in their inventory.
+// The users who opted out are not ranked.
+func (s *Storage) GetItemsLeaderboard(ctx context.Context, limit int) (*[]models.LeaderboardEntry, error) {
+	return collectLeaderboard(ctx, s.pool, getItemsLeaderboard, limit)
+}
+
+// SetLeaderboardOptOut hides the user from the leaderboards or shows them again.
+func (s *Storage) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error {
+	_, err := s.pool.Exec(ctx, setLeaderboardOptOut, userID, optOut)
+	return err
+}
+
+// collectLeaderboard fetches the leaderboard entries selected by the query.
+func collectLeaderboard(ctx context.Context, q querier, query string, args ...any) (*[]models.LeaderboardEntry, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.LeaderboardEntry])
+	if err != nil {
+		return nil, err
+	}
+	return &entries, nil
+}
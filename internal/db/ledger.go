@@ -0,0 +1,66 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// The balance after an entry is the user's current coins, with the saved ones, less the entries following it,
+// so only the entries since the start of the range are read. The ledger is read in one statement,
+// the balances are consistent even if the coins move while it's streamed.
+const getLedger = `
+	WITH entries AS (
+		SELECT t.created_at, 'transaction' AS source, t.id, t.kind, t.receiver_id AS user_id,
+		       s.name AS counterparty, t.coins AS amount, t.reason,
+		       NULL::VARCHAR AS item, NULL::VARCHAR AS sku
+		FROM transactions t
+		LEFT JOIN accounts s ON t.sender_account_id = s.id
+		WHERE t.receiver_id IS NOT NULL AND t.created_at >= $1
+		UNION ALL
+		SELECT t.created_at, 'transaction', t.id, t.kind, t.sender_id, r.name, -t.coins, t.reason, NULL, NULL
+		FROM transactions t
+		LEFT JOIN accounts r ON t.receiver_account_id = r.id
+		WHERE t.sender_id IS NOT NULL AND t.created_at >= $1
+		UNION ALL
+		SELECT p.created_at, 'purchase', p.id, 'purchase', p.user_id, NULL, -p.price, NULL, p.item_slug, p.sku
+		FROM purchases p
+		WHERE p.created_at >= $1
+	), saved AS (
+		SELECT user_id, SUM(saved) AS coins FROM savings_goals GROUP BY user_id
+	), balances AS (
+		SELECT e.*, u.username,
+		       u.coins + COALESCE(sv.coins, 0) - COALESCE(SUM(e.amount) OVER (
+		           PARTITION BY e.user_id ORDER BY e.created_at DESC, e.source DESC, e.id DESC
+		           ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS balance
+		FROM entries e
+		JOIN users u ON u.id = e.user_id
+		LEFT JOIN saved sv ON sv.user_id = e.user_id
+	)
+	SELECT created_at, source, id, kind, user_id, username, counterparty, amount, balance::INT, reason, item, sku
+	FROM balances
+	WHERE created_at < $2
+	ORDER BY created_at, source, id, user_id;`
+
+// ExportLedger streams the changes of the users' coins made in the range [from, to) in the order they were made,
+// passing them to emit one by one. The entry passed is reused, emit must not keep it.
+// Stops at the first error returned by emit.
+func (s *Storage) ExportLedger(ctx context.Context, from, to time.Time, emit func(*models.LedgerEntry) error) error {
+	rows, err := s.pool.Query(ctx, getLedger, from.UTC(), to.UTC())
+	if err != nil {
+		return err
+	}
+
+	var e models.LedgerEntry
+	_, err = pgx.ForEachRow(rows, []any{
+		&e.At, &e.Source, &e.ID, &e.Kind, &e.UserID, &e.Username, &e.Counterparty,
+		&e.Amount, &e.Balance, &e.Reason, &e.Item, &e.SKU,
+	}, func() error {
+		return emit(&e)
+	})
+	return err
+}
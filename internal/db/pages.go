@@ -0,0 +1,65 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// pageTotal is selected last by the page queries, the number of the matching rows before LIMIT and OFFSET.
+const pageTotal = `COUNT(*) OVER () AS total`
+
+// pagedRow hides the total, the last column of a page query's row, from the struct scan and scans it aside.
+type pagedRow struct {
+	pgx.CollectableRow
+	total *int
+}
+
+func (r pagedRow) FieldDescriptions() []pgconn.FieldDescription {
+	fields := r.CollectableRow.FieldDescriptions()
+	return fields[:len(fields)-1]
+}
+
+func (r pagedRow) Scan(dest ...any) error {
+	return r.CollectableRow.Scan(append(dest, r.total)...)
+}
+
+// collectPage runs the page query, which selects pageTotal last and takes the limit and the offset
+// as its last two parameters, and collects the page with the size of the whole collection.
+func collectPage[T any](ctx context.Context, q querier, query string, limit, offset int, args ...any) (*models.Page[T], error) {
+	page, err := queryPage[T](ctx, q, query, limit, offset, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// No row past the end of the collection carries its size
+	if len(page.Items) == 0 && offset > 0 {
+		first, err := queryPage[T](ctx, q, query, 1, 0, args...)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = first.Total
+	}
+	return page, nil
+}
+
+// queryPage runs the page query once.
+func queryPage[T any](ctx context.Context, q querier, query string, limit, offset int, args ...any) (*models.Page[T], error) {
+	rows, err := q.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.Page[T]{Limit: limit, Offset: offset}
+	page.Items, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (T, error) {
+		return pgx.RowToStructByName[T](pagedRow{row, &page.Total})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,203 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	// Spending takes the oldest coins first, so the coins received since the cutoff are the last ones left
+	// and everything above them in the balance was granted before the cutoff
+	getExpiringCoins = `
+		SELECT u.id AS user_id, u.username, u.coins, (u.coins - COALESCE(r.recent, 0))::INT AS amount
+		FROM users u
+		LEFT JOIN (SELECT receiver_id, SUM(coins) AS recent FROM transactions
+		           WHERE receiver_id IS NOT NULL AND created_at >= $1
+		           GROUP BY receiver_id) r ON r.receiver_id = u.id
+		WHERE u.created_at < $1 AND u.coins > COALESCE(r.recent, 0)
+		ORDER BY u.id;`
+	getCoinsAboveCap = `
+		SELECT id AS user_id, username, coins, coins - $1 AS amount FROM users
+		WHERE coins > $1
+		ORDER BY id;`
+	recordWriteOff = `
+		INSERT INTO transactions (sender_id, receiver_id, sender_account_id, coins, kind, reason)
+		VALUES ($1, NULL, (SELECT id FROM accounts WHERE user_id = $1), $2, $3, $4);`
+
+	getLatestPolicyRun = `
+		SELECT policy, run_at, noticed_at, applied_at, affected, coins FROM policy_runs
+		WHERE policy = $1
+		ORDER BY applied_at IS NULL DESC, run_at DESC
+		LIMIT 1;`
+	createPolicyRun = `INSERT INTO policy_runs (policy, run_at) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	// the run being handled by another replica is skipped
+	lockPolicyRun = `
+		SELECT policy, run_at, noticed_at, applied_at, affected, coins FROM policy_runs
+		WHERE policy = $1 AND run_at = $2
+		FOR UPDATE SKIP LOCKED;`
+	markPolicyRunNoticed = `UPDATE policy_runs SET noticed_at = NOW() WHERE policy = $1 AND run_at = $2;`
+	markPolicyRunApplied = `UPDATE policy_runs SET applied_at = NOW(), affected = $3, coins = $4 WHERE policy = $1 AND run_at = $2;`
+)
+
+// GetPolicyActions lists the users the policy would affect now with the number of coins to write off.
+func (s *Storage) GetPolicyActions(ctx context.Context, p *models.Policy) (*[]models.PolicyAction, error) {
+	return collectPolicyActions(ctx, s.pool, p)
+}
+
+// GetLatestPolicyRun retrieves the pending run of the policy, or the latest applied one if none is pending.
+func (s *Storage) GetLatestPolicyRun(ctx context.Context, policy string) (*models.PolicyRun, error) {
+	rows, err := s.pool.Query(ctx, getLatestPolicyRun, policy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	run, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.PolicyRun])
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ExecutePolicy moves the policy run forward: from its notice time on the affected users are notified once,
+// from its run time on the coins are written off once, each write-off is recorded with the reason.
+// The run stays locked until it's saved, the run locked by another replica is skipped and nil is returned.
+func (s *Storage) ExecutePolicy(ctx context.Context, p *models.Policy, now time.Time) (*models.PolicyRun, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, createPolicyRun, p.Name, p.RunAt)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, lockPolicyRun, p.Name, p.RunAt)
+	if err != nil {
+		return nil, err
+	}
+	run, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.PolicyRun])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case run.AppliedAt != nil:
+	case !now.Before(p.RunAt):
+		run.Affected, run.Coins, err = applyPolicy(ctx, tx, p)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx, markPolicyRunApplied, p.Name, p.RunAt, run.Affected, run.Coins)
+		if err != nil {
+			return nil, err
+		}
+		run.AppliedAt = &now
+	case run.NoticedAt == nil && !now.Before(p.NoticeAt):
+		err = noticePolicy(ctx, tx, p)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx, markPolicyRunNoticed, p.Name, p.RunAt)
+		if err != nil {
+			return nil, err
+		}
+		run.NoticedAt = &now
+	}
+
+	return &run, nil
+}
+
+// applyPolicy writes the coins off the affected users' balances. Returns the number of affected users
+// and written off coins.
+func applyPolicy(ctx context.Context, tx pgx.Tx, p *models.Policy) (int, int, error) {
+	actions, err := collectPolicyActions(ctx, tx, p)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	affected, coins := 0, 0
+	for _, a := range *actions {
+		// The balance could have gone down since the actions were listed
+		tag, err := tx.Exec(ctx, subtractFromCoinsByUserID, a.Amount, a.UserID)
+		if err != nil {
+			return 0, 0, err
+		} else if tag.RowsAffected() == 0 {
+			continue
+		}
+
+		_, err = tx.Exec(ctx, recordWriteOff, a.UserID, a.Amount, p.Name, p.Reason)
+		if err != nil {
+			return 0, 0, err
+		}
+		err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditCoinsWrittenOff, "user:"+strconv.Itoa(a.UserID),
+			map[string]any{"policy": p.Name, "amount": a.Amount, "reason": p.Reason}))
+		if err != nil {
+			return 0, 0, err
+		}
+		affected++
+		coins += a.Amount
+	}
+	return affected, coins, nil
+}
+
+// noticePolicy notifies the affected users about the coming write-off.
+func noticePolicy(ctx context.Context, tx pgx.Tx, p *models.Policy) error {
+	actions, err := collectPolicyActions(ctx, tx, p)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range *actions {
+		message := fmt.Sprintf("%d coins will be written off on %s UTC: %s",
+			a.Amount, p.RunAt.UTC().Format("2006-01-02 15:04"), p.Reason)
+		_, err = tx.Exec(ctx, recordNotification, a.UserID, models.NotificationPolicyNotice, nil, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectPolicyActions lists the users affected by the policy.
+func collectPolicyActions(ctx context.Context, q querier, p *models.Policy) (*[]models.PolicyAction, error) {
+	var rows pgx.Rows
+	var err error
+	switch p.Name {
+	case models.PolicyExpiry:
+		rows, err = q.Query(ctx, getExpiringCoins, p.Cutoff)
+	case models.PolicyBalanceCap:
+		rows, err = q.Query(ctx, getCoinsAboveCap, p.Cap)
+	default:
+		return nil, models.ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PolicyAction])
+	if err != nil {
+		return nil, err
+	}
+	return &actions, nil
+}
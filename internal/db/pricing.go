@@ -0,0 +1,223 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/pricing"
+)
+
+// PostgreSQL error codes of the constraint violations.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const (
+	discountColumns           = `id, item_slug, category, kind, value, starts_at, ends_at`
+	getActiveDiscounts        = `SELECT ` + discountColumns + ` FROM discounts WHERE starts_at <= NOW() AND ends_at > NOW();`
+	getActiveDiscountsForItem = `
+		SELECT ` + discountColumns + ` FROM discounts
+		WHERE (item_slug = $1 OR category = $2) AND starts_at <= NOW() AND ends_at > NOW();`
+	getDiscounts   = `SELECT ` + discountColumns + ` FROM discounts ORDER BY starts_at DESC, id DESC;`
+	createDiscount = `
+		INSERT INTO discounts (item_slug, category, kind, value, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id;`
+	deleteDiscount = `DELETE FROM discounts WHERE id = $1;`
+
+	promoCodeColumns      = `code, kind, value, item_slug, category, max_uses, used, starts_at, ends_at`
+	getPromoCodes         = `SELECT ` + promoCodeColumns + ` FROM promo_codes ORDER BY created_at DESC;`
+	getPromoCodeForUpdate = `SELECT ` + promoCodeColumns + ` FROM promo_codes WHERE code = $1 FOR UPDATE;`
+	createPromoCode       = `
+		INSERT INTO promo_codes (code, kind, value, item_slug, category, max_uses, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+	isPromoCodeRedeemedByUserID = `SELECT EXISTS (SELECT 1 FROM promo_redemptions WHERE code = $1 AND user_id = $2);`
+	redeemPromoCode             = `
+		WITH used AS (UPDATE promo_codes SET used = used + 1 WHERE code = $1)
+		INSERT INTO promo_redemptions (code, user_id, purchase_id) VALUES ($1, $2, $3);`
+)
+
+// collectDiscounts fetches the discounts selected by the query.
+func collectDiscounts(ctx context.Context, q querier, query string, args ...any) ([]models.Discount, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Discount])
+}
+
+// lockPromoCode locks the promo code until the end of the transaction
+// and checks that the user can redeem it for the item.
+func lockPromoCode(ctx context.Context, tx pgx.Tx, code string, userID int, item *models.Item) (*models.PromoCode, error) {
+	rows, err := tx.Query(ctx, getPromoCodeForUpdate, code)
+	if err != nil {
+		return nil, err
+	}
+	promo, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[models.PromoCode])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrInvalidPromoCode
+	} else if err != nil {
+		return nil, err
+	}
+
+	if promo.MaxUses != nil && promo.Used >= *promo.MaxUses {
+		return nil, models.ErrPromoCodeUsed
+	}
+	if !pricing.PromoActive(promo, time.Now().UTC()) || !pricing.Applies(promo.ItemSlug, promo.Category, item) {
+		return nil, models.ErrInvalidPromoCode
+	}
+
+	redeemed := false
+	err = tx.QueryRow(ctx, isPromoCodeRedeemedByUserID, code, userID).Scan(&redeemed)
+	if err != nil {
+		return nil, err
+	} else if redeemed {
+		return nil, models.ErrPromoCodeUsed
+	}
+
+	return promo, nil
+}
+
+// GetActiveDiscounts retrieves the discounts active at the moment.
+func (s *Storage) GetActiveDiscounts(ctx context.Context) (*[]models.Discount, error) {
+	discounts, err := collectDiscounts(ctx, s.pool, getActiveDiscounts)
+	if err != nil {
+		return nil, err
+	}
+	return &discounts, nil
+}
+
+// GetDiscounts retrieves all the scheduled discounts, the latest first.
+func (s *Storage) GetDiscounts(ctx context.Context) (*[]models.Discount, error) {
+	discounts, err := collectDiscounts(ctx, s.pool, getDiscounts)
+	if err != nil {
+		return nil, err
+	}
+	return &discounts, nil
+}
+
+// CreateDiscount saves a new scheduled discount and updates the discount with the generated ID.
+// The users who wish the discounted items are notified.
+func (s *Storage) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, createDiscount,
+		discount.ItemSlug,
+		discount.Category,
+		discount.Kind,
+		discount.Value,
+		discount.StartsAt,
+		discount.EndsAt,
+	).Scan(&discount.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, notifyDiscount, discount.ItemSlug, discount.Category, discount.StartsAt, discount.EndsAt)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditDiscountCreated, "discount:"+strconv.Itoa(discount.ID),
+		map[string]any{"kind": discount.Kind, "value": discount.Value, "item": discount.ItemSlug, "category": discount.Category}))
+	return err
+}
+
+// DeleteDiscount deletes the discount by its ID.
+func (s *Storage) DeleteDiscount(ctx context.Context, id int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, deleteDiscount, id)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrDiscountNotFound
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditDiscountDeleted, "discount:"+strconv.Itoa(id), nil))
+	return err
+}
+
+// GetPromoCodes retrieves all the promo codes, the latest first.
+func (s *Storage) GetPromoCodes(ctx context.Context) (*[]models.PromoCode, error) {
+	rows, err := s.pool.Query(ctx, getPromoCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promos, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PromoCode])
+	if err != nil {
+		return nil, err
+	}
+	return &promos, nil
+}
+
+// CreatePromoCode saves a new promo code.
+func (s *Storage) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, createPromoCode,
+		promo.Code,
+		promo.Kind,
+		promo.Value,
+		promo.ItemSlug,
+		promo.Category,
+		promo.MaxUses,
+		promo.StartsAt,
+		promo.EndsAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		err = models.ErrPromoCodeExists
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditPromoCodeCreated, "promo_code:"+promo.Code,
+		map[string]any{"kind": promo.Kind, "value": promo.Value, "maxUses": promo.MaxUses}))
+	return err
+}
@@ -0,0 +1,178 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	goalColumns      = `g.id, g.item_slug, g.saved, s.price AS target`
+	createGoal       = `INSERT INTO savings_goals (user_id, item_slug) VALUES ($1, $2) RETURNING id;`
+	getGoalsByUserID = `
+		SELECT ` + goalColumns + ` FROM savings_goals g JOIN store s ON s.slug = g.item_slug
+		WHERE g.user_id = $1
+		ORDER BY g.created_at, g.id;`
+	getGoalByID       = `SELECT ` + goalColumns + ` FROM savings_goals g JOIN store s ON s.slug = g.item_slug WHERE g.id = $1;`
+	getSavedForItem   = `SELECT COALESCE(SUM(saved), 0) FROM savings_goals WHERE user_id = $1 AND item_slug = $2;`
+	addToGoal         = `UPDATE savings_goals SET saved = saved + $3, updated_at = NOW() WHERE id = $1 AND user_id = $2;`
+	deleteGoal        = `DELETE FROM savings_goals WHERE id = $1 AND user_id = $2 RETURNING saved;`
+	deleteGoalForItem = `DELETE FROM savings_goals WHERE user_id = $1 AND item_slug = $2 RETURNING saved;`
+)
+
+// CreateSavingsGoal starts saving coins for the item, a user can have one goal per item.
+func (s *Storage) CreateSavingsGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error) {
+	goalID := 0
+	err := s.pool.QueryRow(ctx, createGoal, userID, slug).Scan(&goalID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case foreignKeyViolation:
+			return nil, models.ErrItemNotFound
+		case uniqueViolation:
+			return nil, models.ErrGoalExists
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getGoal(ctx, s.pool, goalID)
+}
+
+// GetSavingsGoalsByUserID retrieves the user's savings goals with the current prices of their items.
+func (s *Storage) GetSavingsGoalsByUserID(ctx context.Context, userID int) (*[]models.SavingsGoal, error) {
+	rows, err := s.pool.Query(ctx, getGoalsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.SavingsGoal])
+	if err != nil {
+		return nil, err
+	}
+	return &goals, nil
+}
+
+// GetSavedCoinsByUserID retrieves the number of coins the user has set aside for the item.
+func (s *Storage) GetSavedCoinsByUserID(ctx context.Context, userID int, slug string) (int, error) {
+	saved := 0
+	err := s.pool.QueryRow(ctx, getSavedForItem, userID, slug).Scan(&saved)
+	return saved, err
+}
+
+// DepositToSavingsGoal moves the coins from the user's balance into the goal's escrow.
+func (s *Storage) DepositToSavingsGoal(ctx context.Context, userID, goalID, amount int) (*models.SavingsGoal, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, addToGoal, goalID, userID, amount)
+	if err != nil {
+		return nil, err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrGoalNotFound
+		return nil, err
+	}
+
+	tag, err = tx.Exec(ctx, subtractFromCoinsByUserID, amount, userID)
+	if err != nil {
+		return nil, err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrNotEnoughCoins
+		return nil, err
+	}
+
+	goal, err := getGoal(ctx, tx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditGoalDeposited, "goal:"+strconv.Itoa(goalID),
+		map[string]any{"user": userID, "item": goal.Item, "amount": amount, "saved": goal.Saved}))
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// CloseSavingsGoal deletes the goal and releases its coins back to the user's balance.
+// Returns the number of released coins.
+func (s *Storage) CloseSavingsGoal(ctx context.Context, userID, goalID int) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	saved := 0
+	err = tx.QueryRow(ctx, deleteGoal, goalID, userID).Scan(&saved)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrGoalNotFound
+		return 0, err
+	} else if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, addToCoinsByUserID, saved, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditGoalClosed, "goal:"+strconv.Itoa(goalID),
+		map[string]any{"user": userID, "released": saved}))
+	if err != nil {
+		return 0, err
+	}
+	return saved, nil
+}
+
+// releaseSavingsGoal deletes the user's goal for the item, if any, and puts its coins back to the balance.
+func releaseSavingsGoal(ctx context.Context, tx pgx.Tx, userID int, slug string) error {
+	saved := 0
+	err := tx.QueryRow(ctx, deleteGoalForItem, userID, slug).Scan(&saved)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, addToCoinsByUserID, saved, userID)
+	return err
+}
+
+// getGoal fetches the savings goal by its ID.
+func getGoal(ctx context.Context, q querier, goalID int) (*models.SavingsGoal, error) {
+	rows, err := q.Query(ctx, getGoalByID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goal, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.SavingsGoal])
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
@@ -0,0 +1,160 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	scheduledTransferColumns = `
+		st.id, st.sender_id, st.receiver_id, u.username, st.coins, st.schedule, st.next_run_at,
+		st.status, st.attempts, st.last_error`
+	createScheduledTransfer = `
+		INSERT INTO scheduled_transfers (sender_id, receiver_id, coins, schedule, next_run_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status;`
+	getScheduledTransfersBySenderID = `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers st JOIN users u ON st.receiver_id = u.id
+		WHERE st.sender_id = $1
+		ORDER BY st.created_at DESC, st.id DESC;`
+	cancelScheduledTransfer = `
+		UPDATE scheduled_transfers SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND sender_id = $2 AND status = 'active';`
+	// the transfers being run by other replicas are skipped
+	lockDueTransfer = `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers st JOIN users u ON st.receiver_id = u.id
+		WHERE st.status = 'active' AND st.next_run_at <= NOW()
+		ORDER BY st.next_run_at
+		LIMIT 1
+		FOR UPDATE OF st SKIP LOCKED;`
+	saveTransferOutcome = `
+		UPDATE scheduled_transfers
+		SET status = $2, next_run_at = $3, attempts = $4, last_error = $5, updated_at = NOW()
+		WHERE id = $1;`
+	recordNotification = `INSERT INTO notifications (user_id, kind, item_slug, message) VALUES ($1, $2, $3, $4);`
+)
+
+// CreateScheduledTransfer saves the scheduled transfer, setting its ID and status.
+func (s *Storage) CreateScheduledTransfer(ctx context.Context, t *models.ScheduledTransfer) error {
+	return s.pool.QueryRow(ctx, createScheduledTransfer,
+		t.SenderID, t.ReceiverID, t.Amount, t.Schedule, t.NextRunAt,
+	).Scan(&t.ID, &t.Status)
+}
+
+// GetScheduledTransfersBySenderID retrieves the user's scheduled transfers, the latest created first.
+func (s *Storage) GetScheduledTransfersBySenderID(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error) {
+	rows, err := s.pool.Query(ctx, getScheduledTransfersBySenderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ScheduledTransfer])
+	if err != nil {
+		return nil, err
+	}
+	return &transfers, nil
+}
+
+// CancelScheduledTransfer stops the user's active scheduled transfer.
+func (s *Storage) CancelScheduledTransfer(ctx context.Context, userID, transferID int) error {
+	tag, err := s.pool.Exec(ctx, cancelScheduledTransfer, transferID, userID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrScheduledTransferNotFound
+	}
+	return nil
+}
+
+// ExecuteDueTransfer runs the earliest due scheduled transfer. The transfer stays locked until its outcome
+// is saved, the transfers locked by other replicas are skipped. The coins movement is checked against
+// the anti-fraud rules, the transfer breaking a rule is flagged for review instead. The outcome is decided
+// from the error of the coins movement, which is undone if it fails. Returns false if no transfer is due.
+func (s *Storage) ExecuteDueTransfer(
+	ctx context.Context, rules *models.TransferRules, decide func(*models.ScheduledTransfer, error) *models.TransferOutcome,
+) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, lockDueTransfer)
+	if err != nil {
+		return false, err
+	}
+	transfer, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.ScheduledTransfer])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// The transfer is made in a savepoint, so that a failed one can be rolled back keeping the lock
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	flagged, transferErr := executeTransferTx(ctx, sp, &transfer, rules)
+	if transferErr != nil && !flagged {
+		err = sp.Rollback(ctx)
+	} else {
+		err = sp.Commit(ctx)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	outcome := decide(&transfer, transferErr)
+	_, err = tx.Exec(ctx, saveTransferOutcome,
+		transfer.ID, outcome.Status, outcome.NextRunAt, outcome.Attempts, outcome.LastError,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if n := outcome.Notification; n != nil {
+		_, err = tx.Exec(ctx, recordNotification, transfer.SenderID, n.Kind, n.Item, n.Message)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// executeTransferTx moves the coins of the scheduled transfer if the anti-fraud rules allow it and records it
+// in the audit log. Returns true along with the rule's error if the transfer is flagged for review instead,
+// the flag is to be kept then.
+func executeTransferTx(ctx context.Context, tx pgx.Tx, t *models.ScheduledTransfer, rules *models.TransferRules) (bool, error) {
+	ruleErr, err := enforceRules(ctx, tx, rules, userTransfer(t.SenderID, t.ReceiverID, t.Amount), 0)
+	if err != nil {
+		return false, err
+	} else if ruleErr != nil {
+		return true, ruleErr
+	}
+
+	err = transferCoins(ctx, tx, t.SenderID, t.ReceiverID, t.Amount)
+	if err != nil {
+		return false, err
+	}
+	return false, appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditCoinsTransferred, "user:"+strconv.Itoa(t.ReceiverID),
+		map[string]any{"from": t.SenderID, "amount": t.Amount, "scheduled_transfer": t.ID}))
+}
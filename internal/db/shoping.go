@@ -3,31 +3,64 @@ package db
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
 	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/pricing"
 )
 
 const (
 	getIDByUsername                = `SELECT id FROM users WHERE username=$1`
-	getUserByUsername              = `SELECT * FROM users WHERE username=$1`
+	getUserByUsername              = `SELECT id, username, password, coins, role, locked_at, deactivated_at, claim_token_hash, created_at, updated_at FROM users WHERE username=$1`
+	getRoleByUserID                = `SELECT role FROM users WHERE id=$1`
+	getAccountStatusByUserID       = `SELECT locked_at IS NOT NULL, deactivated_at IS NOT NULL FROM users WHERE id=$1`
 	getCoinsByUserID               = `SELECT coins FROM users WHERE id=$1`
-	getInventoryByUserID           = `SELECT item_slug, quantity FROM inventory WHERE user_id = $1`
-	getReceivedCoinHistoryByUserID = `SELECT u.username, t.coins FROM transactions t JOIN users u ON t.sender_id = u.id WHERE t.receiver_id = $1;`
-	getSendingCoinHistoryByUserID  = `SELECT u.username, t.coins FROM transactions t JOIN users u ON t.receiver_id = u.id WHERE t.sender_id = $1;`
+	getInventoryByUserID           = `SELECT item_slug, sku, quantity FROM inventory WHERE user_id = $1 ORDER BY item_slug, sku`
+	getReceivedCoinHistoryByUserID = `SELECT COALESCE(a.name, '') AS username, t.coins, t.kind, t.batch_id FROM transactions t LEFT JOIN accounts a ON t.sender_account_id = a.id WHERE t.receiver_id = $1;`
+	getSendingCoinHistoryByUserID  = `SELECT COALESCE(a.name, '') AS username, t.coins, t.kind, t.reason, t.batch_id FROM transactions t LEFT JOIN accounts a ON t.receiver_account_id = a.id WHERE t.sender_id = $1;`
 	saveUser                       = `INSERT INTO users (username, password) VALUES ($1, $2) RETURNING id, coins, created_at, updated_at;`
-	subtractFromCoinsByUserID      = `UPDATE users SET coins = COALESCE(coins, 0) - $1 WHERE id = $2;`
+	claimUser                      = `UPDATE users SET password = $2, claim_token_hash = NULL, updated_at = NOW() WHERE id = $1 AND password = '' AND claim_token_hash = $3 RETURNING updated_at;`
+	subtractFromCoinsByUserID      = `UPDATE users SET coins = COALESCE(coins, 0) - $1 WHERE id = $2 AND coins >= $1;`
 	addToCoinsByUserID             = `UPDATE users SET coins = COALESCE(coins, 0) + $1 WHERE id = $2;`
-	recordTransaction              = `INSERT INTO transactions (sender_id, receiver_id, coins) VALUES($1, $2, $3);`
-	getItemBySlug                  = `SELECT * FROM store WHERE slug = $1;`
-	addItemToInventoryByUserID     = `
-		INSERT INTO inventory (user_id, item_slug, quantity, updated_at)
-		VALUES ($1, $2, $3, NOW())
-		ON CONFLICT (user_id, item_slug) 
+	addToActiveCoinsByUserID       = `UPDATE users SET coins = COALESCE(coins, 0) + $1 WHERE id = $2 AND deactivated_at IS NULL;`
+	recordTransaction              = `INSERT INTO transactions (sender_id, receiver_id, sender_account_id, receiver_account_id, initiator_id, coins) VALUES($1, $2, (SELECT id FROM accounts WHERE user_id = $1), (SELECT id FROM accounts WHERE user_id = $2), $1, $3);`
+	recordPurchase                 = `
+		INSERT INTO purchases (user_id, item_slug, sku, price, list_price, discount, promo_code, office)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		RETURNING id;`
+	// the stock of an item is the total of its variants, NULL if any of them is unlimited
+	itemColumns = `s.slug, s.title, s.price, s.category,
+		(SELECT CASE WHEN BOOL_OR(v.stock IS NULL) THEN NULL ELSE SUM(v.stock)::INTEGER END
+		 FROM item_variants v WHERE v.item_slug = s.slug) AS stock,
+		s.per_user_limit`
+	getItemBySlug                = `SELECT ` + itemColumns + ` FROM store s WHERE s.slug = $1;`
+	getItems                     = `SELECT ` + itemColumns + ` FROM store s ORDER BY s.price, s.slug;`
+	countActivePurchasesByUserID = `SELECT COUNT(*) FROM purchases WHERE user_id = $1 AND item_slug = $2 AND returned_at IS NULL;`
+	decrementStockBySKU          = `UPDATE item_variants SET stock = stock - 1, updated_at = NOW() WHERE sku = $1 AND stock > 0;`
+	addItemToInventoryByUserID   = `
+		INSERT INTO inventory (user_id, item_slug, sku, quantity, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, sku) 
 		DO UPDATE SET quantity = inventory.quantity + excluded.quantity, updated_at = NOW();`
+	// the pages of the coin history, the latest transactions first
+	getReceivedCoinsPageByUserID = `
+		SELECT COALESCE(a.name, '') AS username, t.coins, t.kind, t.batch_id, ` + pageTotal + `
+		FROM transactions t LEFT JOIN accounts a ON t.sender_account_id = a.id
+		WHERE t.receiver_id = $1
+		ORDER BY t.id DESC
+		LIMIT $2 OFFSET $3;`
+	getSentCoinsPageByUserID = `
+		SELECT COALESCE(a.name, '') AS username, t.coins, t.kind, t.reason, t.batch_id, ` + pageTotal + `
+		FROM transactions t LEFT JOIN accounts a ON t.receiver_account_id = a.id
+		WHERE t.sender_id = $1
+		ORDER BY t.id DESC
+		LIMIT $2 OFFSET $3;`
 )
 
 // GetIDByUsername retrieves the user ID associated with the given username.
@@ -45,6 +78,10 @@ func (s *Storage) GetUserByUsername(ctx context.Context, username string) (*mode
 		&user.Username,
 		&user.Password,
 		&user.Coins,
+		&user.Role,
+		&user.LockedAt,
+		&user.DeactivatedAt,
+		&user.ClaimTokenHash,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -54,6 +91,19 @@ func (s *Storage) GetUserByUsername(ctx context.Context, username string) (*mode
 	return &user, err
 }
 
+// GetRoleByUserID retrieves the role of a user by their ID.
+func (s *Storage) GetRoleByUserID(ctx context.Context, userID int) (string, error) {
+	role := ""
+	err := s.pool.QueryRow(ctx, getRoleByUserID, userID).Scan(&role)
+	return role, err
+}
+
+// GetAccountStatusByUserID reports whether the user's account is locked and whether it's deactivated.
+func (s *Storage) GetAccountStatusByUserID(ctx context.Context, userID int) (locked, deactivated bool, err error) {
+	err = s.pool.QueryRow(ctx, getAccountStatusByUserID, userID).Scan(&locked, &deactivated)
+	return locked, deactivated, err
+}
+
 // GetCoinsByUserID retrieves the number of coins a user has by their ID.
 func (s *Storage) GetCoinsByUserID(ctx context.Context, userID int) (int, error) {
 	coins := 0
@@ -72,7 +122,7 @@ func (s *Storage) GetInventoryByUserID(ctx context.Context, userID int) (*[]mode
 	items := make([]models.Merch, 0, 8)
 	for rows.Next() {
 		var item models.Merch
-		err := rows.Scan(&item.Type, &item.Quantity)
+		err := rows.Scan(&item.Type, &item.Variant, &item.Quantity)
 		if err != nil {
 			return nil, err
 		}
@@ -86,6 +136,16 @@ func (s *Storage) GetInventoryByUserID(ctx context.Context, userID int) (*[]mode
 	return &items, nil
 }
 
+// GetReceivedCoinsPageByUserID retrieves a page of the coins received by the user, the latest first.
+func (s *Storage) GetReceivedCoinsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Receiving], error) {
+	return collectPage[models.Receiving](ctx, s.pool, getReceivedCoinsPageByUserID, limit, offset, userID)
+}
+
+// GetSentCoinsPageByUserID retrieves a page of the coins sent by the user or written off their balance, the latest first.
+func (s *Storage) GetSentCoinsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Sending], error) {
+	return collectPage[models.Sending](ctx, s.pool, getSentCoinsPageByUserID, limit, offset, userID)
+}
+
 // GetCoinHistoryByUserID retrieves the coin transaction history of a user by their ID.
 func (s *Storage) GetCoinHistoryByUserID(ctx context.Context, userID int) (*models.CoinHistory, error) {
 	g, gCtx := errgroup.WithContext(ctx)
@@ -93,7 +153,7 @@ func (s *Storage) GetCoinHistoryByUserID(ctx context.Context, userID int) (*mode
 	// RECEIVED
 	var recs *[]models.Receiving
 	g.Go(func() error {
-		data, err := fetchCoinHistory[models.Receiving](gCtx, s.pool, getReceivedCoinHistoryByUserID, userID)
+		data, err := fetchHistory[models.Receiving](gCtx, s.pool, getReceivedCoinHistoryByUserID, userID)
 		if err != nil {
 			return err
 		}
@@ -104,7 +164,7 @@ func (s *Storage) GetCoinHistoryByUserID(ctx context.Context, userID int) (*mode
 	// SENT
 	var sends *[]models.Sending
 	g.Go(func() error {
-		data, err := fetchCoinHistory[models.Sending](gCtx, s.pool, getSendingCoinHistoryByUserID, userID)
+		data, err := fetchHistory[models.Sending](gCtx, s.pool, getSendingCoinHistoryByUserID, userID)
 		if err != nil {
 			return err
 		}
@@ -123,13 +183,13 @@ func (s *Storage) GetCoinHistoryByUserID(ctx context.Context, userID int) (*mode
 	return &ch, nil
 }
 
-// coinHistory is a generic constraint for coin history types (Receiving or Sending).
-type coinHistory interface {
-	models.Receiving | models.Sending
+// historyEntry is a generic constraint for history entry types (received or sent coins and gifts).
+type historyEntry interface {
+	models.Receiving | models.Sending | models.GiftReceiving | models.GiftSending
 }
 
-// fetchCoinHistory fetches coin history data (either Receiving or Sending) for a user.
-func fetchCoinHistory[T coinHistory](ctx context.Context, pool *pgxpool.Pool, query string, userID int) (*[]T, error) {
+// fetchHistory fetches history data (either received or sent entries) for a user.
+func fetchHistory[T historyEntry](ctx context.Context, pool *pgxpool.Pool, query string, userID int) (*[]T, error) {
 	rows, err := pool.Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
@@ -142,8 +202,21 @@ func fetchCoinHistory[T coinHistory](ctx context.Context, pool *pgxpool.Pool, qu
 }
 
 // SaveUser saves a new user to the database and updates the user struct with generated fields.
+// The registration is recorded in the audit log on behalf of the new user.
 func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
-	err := s.pool.QueryRow(ctx, saveUser, user.Username, user.Password).Scan(
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, saveUser, user.Username, user.Password).Scan(
 		&user.ID,
 		&user.Coins,
 		&user.CreatedAt,
@@ -152,11 +225,49 @@ func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	entry := audit.NewEntry(ctx, models.AuditUserRegistered, "user:"+strconv.Itoa(user.ID), nil)
+	entry.ActorID, entry.Actor = &user.ID, &user.Username
+	err = appendAudit(ctx, tx, entry)
+	return err
+}
+
+// ClaimUser sets the password of the account imported from the HR directory on the user's first login,
+// the claim token, whose hash is user.ClaimTokenHash, is used up. Returns models.ErrInvalidPassword
+// if the account has been claimed or its token reissued in the meantime.
+func (s *Storage) ClaimUser(ctx context.Context, user *models.User) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, claimUser, user.ID, user.Password, user.ClaimTokenHash).Scan(&user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrInvalidPassword
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	user.ClaimTokenHash = nil
+
+	entry := audit.NewEntry(ctx, models.AuditUserClaimed, "user:"+strconv.Itoa(user.ID), nil)
+	entry.ActorID, entry.Actor = &user.ID, &user.Username
+	err = appendAudit(ctx, tx, entry)
+	return err
 }
 
-// TransferCoins transfers coins from one user to another and records the transaction.
-func (s *Storage) TransferCoins(ctx context.Context, fromUserID, toUserID, coins int) error {
+// TransferCoins transfers coins from one user to another and records the transaction. The transfer is checked
+// against the anti-fraud rules first, the one breaking a rule isn't made but flagged for review, and the rule's
+// error is returned.
+func (s *Storage) TransferCoins(ctx context.Context, fromUserID, toUserID, coins int, rules *models.TransferRules) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -169,32 +280,60 @@ func (s *Storage) TransferCoins(ctx context.Context, fromUserID, toUserID, coins
 		}
 	}()
 
+	ruleErr, err := enforceRules(ctx, tx, rules, userTransfer(fromUserID, toUserID, coins), 0)
+	if err != nil {
+		return err
+	} else if ruleErr != nil {
+		return ruleErr
+	}
+
+	err = transferCoins(ctx, tx, fromUserID, toUserID, coins)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditCoinsTransferred, "user:"+strconv.Itoa(toUserID),
+		map[string]any{"from": fromUserID, "amount": coins}))
+	return err
+}
+
+// transferCoins moves coins from one user to another and records the transaction.
+func transferCoins(ctx context.Context, q querier, fromUserID, toUserID, coins int) error {
 	// Subtract money from the sender
-	_, err = tx.Exec(ctx, subtractFromCoinsByUserID, coins, fromUserID)
+	tag, err := q.Exec(ctx, subtractFromCoinsByUserID, coins, fromUserID)
 	if err != nil {
 		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrNotEnoughCoins
 	}
 
-	// Adding money to the recipient
-	_, err = tx.Exec(ctx, addToCoinsByUserID, coins, toUserID)
+	// Adding money to the recipient, a deactivated account doesn't receive coins from the users
+	tag, err = q.Exec(ctx, addToActiveCoinsByUserID, coins, toUserID)
 	if err != nil {
 		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrRecipientInactive
 	}
 
 	// Transaction record
-	_, err = tx.Exec(ctx, recordTransaction, fromUserID, toUserID, coins)
+	_, err = q.Exec(ctx, recordTransaction, fromUserID, toUserID, coins)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return addCoinsReceivedEvent(ctx, q, fromUserID, toUserID, coins, nil, nil)
 }
 
-// MakePurchaseByUserID processes a purchase of an item by a user.
-func (s *Storage) MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item) error {
+// MakePurchaseByUserID processes a purchase of an item's variant by a user, the default variant is bought
+// if the order doesn't name one. The price is calculated inside the transaction from the current price of the variant,
+// the active discounts and the promo code, if given. The variant's stock and the item's per-user purchase limit
+// are checked in the same transaction. The coins saved for the item are released before paying.
+// The purchase waits to be handed over in the order's office.
+// Returns the charged price with the applied discounts.
+func (s *Storage) MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item, order *models.Order) (*models.Quote, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
@@ -204,27 +343,168 @@ func (s *Storage) MakePurchaseByUserID(ctx context.Context, userID int, item *mo
 		}
 	}()
 
-	// Subtract money from the user
-	_, err = tx.Exec(ctx, subtractFromCoinsByUserID, item.Price, userID)
+	// Current state of the item
+	var current models.Item
+	err = scanItem(tx.QueryRow(ctx, getItemBySlug, item.Slug), &current)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	variant, err := lookupVariant(ctx, tx, current.Slug, order.Variant)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pricing
+	priced := pricing.ForVariant(&current, variant)
+	discounts, err := collectDiscounts(ctx, tx, getActiveDiscountsForItem, current.Slug, current.Category)
+	if err != nil {
+		return nil, err
+	}
+	var promo *models.PromoCode
+	if order.PromoCode != "" {
+		promo, err = lockPromoCode(ctx, tx, order.PromoCode, userID, priced)
+		if err != nil {
+			return nil, err
+		}
+	}
+	quote := pricing.Quote(priced, discounts, promo)
+	quote.Variant = variant.SKU
+
+	// The coins saved for the item are released to pay for it
+	err = releaseSavingsGoal(ctx, tx, userID, current.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subtract money from the user, the user's row stays locked until the end of the transaction
+	tag, err := tx.Exec(ctx, subtractFromCoinsByUserID, quote.Price, userID)
+	if err != nil {
+		return nil, err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrNotEnoughCoins
+		return nil, err
+	}
+
+	// Checking the per-user purchase limit
+	if current.PerUserLimit != nil {
+		bought := 0
+		err = tx.QueryRow(ctx, countActivePurchasesByUserID, userID, current.Slug).Scan(&bought)
+		if err != nil {
+			return nil, err
+		} else if bought >= *current.PerUserLimit {
+			err = models.ErrLimitReached
+			return nil, err
+		}
+	}
+
+	// Taking the variant from the stock
+	if variant.Stock != nil {
+		tag, err = tx.Exec(ctx, decrementStockBySKU, variant.SKU)
+		if err != nil {
+			return nil, err
+		} else if tag.RowsAffected() == 0 {
+			err = models.ErrSoldOut
+			return nil, err
+		}
 	}
 
 	// Add the item to the inventory
-	_, err = tx.Exec(ctx, addItemToInventoryByUserID, userID, item.Slug, 1)
+	_, err = tx.Exec(ctx, addItemToInventoryByUserID, userID, current.Slug, variant.SKU, 1)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	// The bought item drops off the wishlist
+	_, err = tx.Exec(ctx, removeFromWishlist, userID, current.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Purchase record with the applied discounts, used later for returns and reports
+	purchaseID := 0
+	err = tx.QueryRow(ctx, recordPurchase,
+		userID, current.Slug, variant.SKU, quote.Price, quote.ListPrice, quote.Discount, quote.PromoCode, order.Office,
+	).Scan(&purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redeeming the promo code
+	if promo != nil {
+		_, err = tx.Exec(ctx, redeemPromoCode, promo.Code, userID, purchaseID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Event for the webhooks, delivered once the purchase is committed
+	err = addMerchPurchasedEvent(ctx, tx, purchaseID, userID, current.Slug, variant.SKU, quote.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditItemPurchased, "purchase:"+strconv.Itoa(purchaseID),
+		map[string]any{"user": userID, "item": current.Slug, "sku": variant.SKU, "price": quote.Price}))
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
 }
 
-// GetItemBySlug retrieves an item's details by its slug.
+// GetItemBySlug retrieves an item's details with its variants by its slug.
 func (s *Storage) GetItemBySlug(ctx context.Context, slug string) (*models.Item, error) {
 	var item models.Item
-	err := s.pool.QueryRow(ctx, getItemBySlug, slug).Scan(&item.Slug, &item.Title, &item.Price)
+	err := scanItem(s.pool.QueryRow(ctx, getItemBySlug, slug), &item)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Variants, err = collectVariants(ctx, s.pool, getVariantsBySlug, slug)
 	if err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
+
+// scanItem scans a store row selected by getItemBySlug into the item.
+func scanItem(row pgx.Row, item *models.Item) error {
+	return row.Scan(
+		&item.Slug,
+		&item.Title,
+		&item.Price,
+		&item.Category,
+		&item.Stock,
+		&item.PerUserLimit,
+	)
+}
+
+// GetItems retrieves all items of the store with their variants, including their remaining stock.
+func (s *Storage) GetItems(ctx context.Context) (*[]models.Item, error) {
+	rows, err := s.pool.Query(ctx, getItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Item])
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := collectVariants(ctx, s.pool, getVariants)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]int, len(items))
+	for i := range items {
+		bySlug[items[i].Slug] = i
+	}
+	for _, v := range variants {
+		if i, ok := bySlug[v.ItemSlug]; ok {
+			items[i].Variants = append(items[i].Variants, v)
+		}
+	}
+
+	return &items, nil
+}
@@ -0,0 +1,524 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	createTeam    = `INSERT INTO teams (name, approval_threshold) VALUES ($1, $2) RETURNING id, coins;`
+	addTeamMember = `
+		INSERT INTO team_members (team_id, user_id, role, spend_limit) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET role = excluded.role, spend_limit = excluded.spend_limit;`
+	removeTeamMember = `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2;`
+	countTeamOwners  = `SELECT COUNT(*) FROM team_members WHERE team_id = $1 AND role = 'owner';`
+	lockTeam         = `SELECT id FROM teams WHERE id = $1 FOR UPDATE;`
+	teamFields       = `
+		t.id, t.name, t.coins, t.approval_threshold, m.role
+		FROM teams t JOIN team_members m ON m.team_id = t.id`
+	getTeamsByUserID = `
+		SELECT ` + teamFields + `
+		WHERE m.user_id = $1
+		ORDER BY t.name;`
+	getTeam = `
+		SELECT ` + teamFields + `
+		WHERE m.user_id = $1 AND t.id = $2;`
+	lockTeamOfMember = `
+		SELECT ` + teamFields + `
+		WHERE m.user_id = $1 AND t.id = $2
+		FOR UPDATE OF t;`
+	getTeamRole = `SELECT role FROM team_members WHERE team_id = $1 AND user_id = $2;`
+	// the spends waiting for approval or review count towards the limit, so that they can't be piled up
+	teamMemberFields = `
+		m.user_id, u.username, m.role, m.spend_limit,
+		COALESCE((SELECT SUM(s.coins) FROM team_spends s
+		          WHERE s.team_id = m.team_id AND s.member_id = m.user_id
+		            AND s.status IN ('sent', 'approved', 'pending', 'held') AND s.created_at >= NOW() - INTERVAL '30 days'), 0)::INT AS spent
+		FROM team_members m JOIN users u ON m.user_id = u.id`
+	getTeamMembers = `
+		SELECT ` + teamMemberFields + `
+		WHERE m.team_id = $1
+		ORDER BY m.role = 'owner' DESC, u.username;`
+	getTeamMember = `
+		SELECT ` + teamMemberFields + `
+		WHERE m.team_id = $1 AND m.user_id = $2;`
+
+	addToTeamCoins    = `UPDATE teams SET coins = coins + $1, updated_at = NOW() WHERE id = $2;`
+	subtractFromTeam  = `UPDATE teams SET coins = coins - $1, updated_at = NOW() WHERE id = $2 AND coins >= $1;`
+	recordTeamDeposit = `
+		INSERT INTO transactions (sender_id, sender_account_id, receiver_account_id, initiator_id, coins)
+		VALUES ($1, (SELECT id FROM accounts WHERE user_id = $1), (SELECT id FROM accounts WHERE team_id = $2), $1, $3);`
+	// the team wallet sends the coins, the member makes the transfer
+	recordTeamSpendLeg = `
+		INSERT INTO transactions (receiver_id, sender_account_id, receiver_account_id, initiator_id, coins)
+		VALUES ($3, (SELECT id FROM accounts WHERE team_id = $2), (SELECT id FROM accounts WHERE user_id = $3), $1, $4);`
+	createTeamSpend = `
+		INSERT INTO team_spends (team_id, member_id, receiver_id, coins, status) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;`
+	teamSpendFields = `
+		s.id, s.team_id, s.member_id, s.receiver_id, m.username AS member, u.username AS recipient, s.coins,
+		s.status, s.created_at, s.decided_at
+		FROM team_spends s
+		JOIN users m ON s.member_id = m.id
+		JOIN users u ON s.receiver_id = u.id`
+	getTeamSpends = `
+		SELECT ` + teamSpendFields + `
+		WHERE s.team_id = $1 AND s.status = $2
+		ORDER BY s.created_at, s.id;`
+	lockPendingTeamSpend = `
+		SELECT ` + teamSpendFields + `
+		WHERE s.id = $1 AND s.team_id = $2 AND s.status = 'pending'
+		FOR UPDATE OF s;`
+	holdTeamSpend   = `UPDATE team_spends SET status = 'held' WHERE id = $1;`
+	decideTeamSpend = `
+		UPDATE team_spends SET status = $2, decided_by = $3, decided_at = NOW()
+		WHERE id = $1
+		RETURNING decided_at;`
+)
+
+// CreateTeam saves the team with the user as its owner, setting its ID and wallet balance.
+func (s *Storage) CreateTeam(ctx context.Context, team *models.Team, ownerID int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, createTeam, team.Name, team.ApprovalThreshold).Scan(&team.ID, &team.Coins)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		err = models.ErrTeamExists
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, addTeamMember, team.ID, ownerID, models.TeamRoleOwner, nil)
+	if err != nil {
+		return err
+	}
+	team.Role = models.TeamRoleOwner
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamCreated, "team:"+strconv.Itoa(team.ID),
+		map[string]any{"name": team.Name, "owner": ownerID, "approval_threshold": team.ApprovalThreshold}))
+	return err
+}
+
+// GetTeamsByUserID retrieves the teams the user is a member of with the user's role.
+func (s *Storage) GetTeamsByUserID(ctx context.Context, userID int) (*[]models.Team, error) {
+	rows, err := s.pool.Query(ctx, getTeamsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Team])
+	if err != nil {
+		return nil, err
+	}
+	return &teams, nil
+}
+
+// GetTeam retrieves the team of the user with its members and their spends within 30 days.
+func (s *Storage) GetTeam(ctx context.Context, userID, teamID int) (*models.Team, error) {
+	rows, err := s.pool.Query(ctx, getTeam, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	team, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.Team])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrTeamNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err = s.pool.Query(ctx, getTeamMembers, teamID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TeamMember])
+	if err != nil {
+		return nil, err
+	}
+	team.Members = &members
+	return &team, nil
+}
+
+// GetTeamRole retrieves the user's role in the team.
+func (s *Storage) GetTeamRole(ctx context.Context, teamID, userID int) (string, error) {
+	role := ""
+	err := s.pool.QueryRow(ctx, getTeamRole, teamID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", models.ErrTeamNotFound
+	}
+	return role, err
+}
+
+// SetTeamMember adds the user to the team or changes the member's role and spending limit.
+// The team must keep at least one owner.
+func (s *Storage) SetTeamMember(ctx context.Context, teamID, userID int, m *models.TeamMembership) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	if err = lockTeamForOwners(ctx, tx, teamID); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, addTeamMember, teamID, userID, m.Role, m.SpendLimit)
+	if err != nil {
+		return err
+	}
+	err = checkTeamOwners(ctx, tx, teamID)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamMemberSet, "team:"+strconv.Itoa(teamID),
+		map[string]any{"user": userID, "role": m.Role, "spend_limit": m.SpendLimit}))
+	return err
+}
+
+// RemoveTeamMember removes the user from the team. The team must keep at least one owner.
+func (s *Storage) RemoveTeamMember(ctx context.Context, teamID, userID int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	if err = lockTeamForOwners(ctx, tx, teamID); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, removeTeamMember, teamID, userID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		err = models.ErrTeamMemberNotFound
+		return err
+	}
+	err = checkTeamOwners(ctx, tx, teamID)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamMemberRemoved, "team:"+strconv.Itoa(teamID),
+		map[string]any{"user": userID}))
+	return err
+}
+
+// DepositToTeam transfers coins from the user to the team wallet and records the transaction. The deposit is checked
+// against the anti-fraud rules first, the one breaking a rule isn't made but flagged for review, and the rule's
+// error is returned.
+func (s *Storage) DepositToTeam(ctx context.Context, userID, teamID, coins int, rules *models.TransferRules) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	// the team is locked before the user, in the order the spends from its wallet lock them
+	id := 0
+	err = tx.QueryRow(ctx, lockTeam, teamID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrTeamNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	deposit := &accountTransfer{initiatorID: userID, senderID: userID, receiverTeamID: teamID, coins: coins}
+	ruleErr, err := enforceRules(ctx, tx, rules, deposit, 0)
+	if err != nil {
+		return err
+	} else if ruleErr != nil {
+		return ruleErr
+	}
+
+	err = depositToTeamTx(ctx, tx, userID, teamID, coins)
+	if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamDeposited, "team:"+strconv.Itoa(teamID),
+		map[string]any{"from": userID, "amount": coins}))
+	return err
+}
+
+// depositToTeamTx moves coins from the user to the team wallet and records the transaction.
+func depositToTeamTx(ctx context.Context, q querier, userID, teamID, coins int) error {
+	tag, err := q.Exec(ctx, addToTeamCoins, coins, teamID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrTeamNotFound
+	}
+
+	tag, err = q.Exec(ctx, subtractFromCoinsByUserID, coins, userID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrNotEnoughCoins
+	}
+
+	_, err = q.Exec(ctx, recordTeamDeposit, userID, teamID, coins)
+	return err
+}
+
+// SpendFromTeam saves the member's spend from the team wallet. The status of the spend is decided from
+// the team and the member with the member's spends within 30 days, the spend is made right away if it's sent.
+// The team stays locked until the spend is saved, so that the member's spends are checked one at a time.
+// The spend to be made is checked against the anti-fraud rules, the one breaking a rule is saved as held
+// for review, and the rule's error is returned. Sets the spend's ID, status, creation time and the member's username.
+func (s *Storage) SpendFromTeam(
+	ctx context.Context, spend *models.TeamSpend, decide func(*models.Team, *models.TeamMember) (string, error), rules *models.TransferRules,
+) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, lockTeamOfMember, spend.MemberID, spend.TeamID)
+	if err != nil {
+		return err
+	}
+	team, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.Team])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrTeamNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	rows, err = tx.Query(ctx, getTeamMember, spend.TeamID, spend.MemberID)
+	if err != nil {
+		return err
+	}
+	member, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TeamMember])
+	if err != nil {
+		return err
+	}
+
+	spend.Status, err = decide(&team, &member)
+	if err != nil {
+		return err
+	}
+	var (
+		rule    string
+		ruleErr error
+	)
+	if spend.Status == models.SpendSent {
+		rule, ruleErr, err = checkRules(ctx, tx, rules, teamSpendTransfer(spend), 0)
+		if err != nil {
+			return err
+		} else if ruleErr != nil {
+			spend.Status = models.SpendHeld
+		} else if err = spendTeamCoins(ctx, tx, spend); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, createTeamSpend, spend.TeamID, spend.MemberID, spend.ReceiverID, spend.Amount, spend.Status).
+		Scan(&spend.ID, &spend.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if ruleErr != nil {
+		err = flagTransferTx(ctx, tx, teamSpendTransfer(spend), rule, nil, &spend.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamSpent, "team_spend:"+strconv.Itoa(spend.ID),
+		map[string]any{"team": spend.TeamID, "member": spend.MemberID, "to": spend.ReceiverID, "amount": spend.Amount, "status": spend.Status}))
+	if err != nil {
+		return err
+	}
+	spend.Member = member.User
+	return ruleErr
+}
+
+// GetTeamSpends retrieves the team's spends with the given status, the oldest first.
+func (s *Storage) GetTeamSpends(ctx context.Context, teamID int, status string) (*[]models.TeamSpend, error) {
+	rows, err := s.pool.Query(ctx, getTeamSpends, teamID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spends, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TeamSpend])
+	if err != nil {
+		return nil, err
+	}
+	return &spends, nil
+}
+
+// ResolveTeamSpend approves or rejects the team's pending spend on behalf of the owner. An approved spend
+// is made in the same transaction, the spend stays pending if the team wallet is short of coins. The approved
+// spend breaking an anti-fraud rule is held for review instead, and the rule's error is returned.
+func (s *Storage) ResolveTeamSpend(
+	ctx context.Context, teamID, spendID, ownerID int, status string, rules *models.TransferRules,
+) (*models.TeamSpend, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	// the team is locked before the member checked against the rules, in the order the member's spends lock them
+	id := 0
+	err = tx.QueryRow(ctx, lockTeam, teamID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrTeamSpendNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, lockPendingTeamSpend, spendID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TeamSpend])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrTeamSpendNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	var ruleErr error
+	if status == models.SpendApproved {
+		var rule string
+		rule, ruleErr, err = checkRules(ctx, tx, rules, teamSpendTransfer(&spend), 0)
+		if err != nil {
+			return nil, err
+		} else if ruleErr != nil {
+			status = models.SpendHeld
+			err = flagTransferTx(ctx, tx, teamSpendTransfer(&spend), rule, nil, &spend.ID)
+		} else {
+			err = spendTeamCoins(ctx, tx, &spend)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if status == models.SpendHeld {
+		_, err = tx.Exec(ctx, holdTeamSpend, spendID)
+	} else {
+		err = tx.QueryRow(ctx, decideTeamSpend, spendID, status, ownerID).Scan(&spend.DecidedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTeamSpendResolved, "team_spend:"+strconv.Itoa(spendID),
+		map[string]any{"team": teamID, "status": status, "amount": spend.Amount}))
+	if err != nil {
+		return nil, err
+	}
+	if ruleErr != nil {
+		return nil, ruleErr
+	}
+	spend.Status = status
+	return &spend, nil
+}
+
+// teamSpendTransfer is the member's transfer of the spend's coins from the team wallet to the recipient.
+func teamSpendTransfer(spend *models.TeamSpend) *accountTransfer {
+	return &accountTransfer{initiatorID: spend.MemberID, senderTeamID: spend.TeamID, receiverID: spend.ReceiverID, coins: spend.Amount}
+}
+
+// spendTeamCoins moves coins from the team wallet to the recipient and records the transaction
+// made by the member.
+func spendTeamCoins(ctx context.Context, q querier, spend *models.TeamSpend) error {
+	tag, err := q.Exec(ctx, subtractFromTeam, spend.Amount, spend.TeamID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrNotEnoughTeamCoins
+	}
+
+	tag, err = q.Exec(ctx, addToActiveCoinsByUserID, spend.Amount, spend.ReceiverID)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return models.ErrRecipientInactive
+	}
+
+	_, err = q.Exec(ctx, recordTeamSpendLeg, spend.MemberID, spend.TeamID, spend.ReceiverID, spend.Amount)
+	if err != nil {
+		return err
+	}
+
+	return addCoinsReceivedEvent(ctx, q, spend.MemberID, spend.ReceiverID, spend.Amount, &spend.TeamID, nil)
+}
+
+// lockTeamForOwners locks the team, so that its owners are changed one at a time.
+func lockTeamForOwners(ctx context.Context, tx pgx.Tx, teamID int) error {
+	id := 0
+	err := tx.QueryRow(ctx, lockTeam, teamID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrTeamNotFound
+	}
+	return err
+}
+
+// checkTeamOwners makes sure the team has an owner left.
+func checkTeamOwners(ctx context.Context, tx pgx.Tx, teamID int) error {
+	owners := 0
+	if err := tx.QueryRow(ctx, countTeamOwners, teamID).Scan(&owners); err != nil {
+		return err
+	} else if owners == 0 {
+		return models.ErrLastTeamOwner
+	}
+	return nil
+}
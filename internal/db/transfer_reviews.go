@@ -0,0 +1,274 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	// the velocity window is looked through even if it's longer than a week, a batch transfer counts once;
+	// the deposits to and the spends from the team wallets made by the user count as the user's transfers
+	getTransferStats = `
+		WITH recipient AS (SELECT id, created_at FROM accounts WHERE user_id = $2 OR team_id = $3)
+		SELECT
+			COALESCE(SUM(t.coins) FILTER (WHERE t.created_at >= NOW() - INTERVAL '1 day'), 0)::INT AS sent_day,
+			COALESCE(SUM(t.coins) FILTER (WHERE t.created_at >= NOW() - INTERVAL '7 days'), 0)::INT AS sent_week,
+			COALESCE(SUM(t.coins) FILTER (WHERE t.receiver_account_id = (SELECT id FROM recipient)
+			                                AND t.created_at >= NOW() - INTERVAL '7 days'), 0)::INT AS sent_to_recipient,
+			(COUNT(*) FILTER (WHERE t.batch_id IS NULL AND t.created_at >= NOW() - make_interval(secs => $4)) +
+			 COUNT(DISTINCT t.batch_id) FILTER (WHERE t.created_at >= NOW() - make_interval(secs => $4)))::INT AS recent_transfers,
+			(SELECT created_at FROM users WHERE id = $1) AS sender_created_at,
+			(SELECT created_at FROM recipient) AS recipient_created_at
+		FROM transactions t
+		WHERE t.initiator_id = $1 AND t.kind = 'transfer'
+		  AND t.created_at >= NOW() - GREATEST(INTERVAL '7 days', make_interval(secs => $4));`
+
+	lockTransferSender = `SELECT id FROM users WHERE id = $1 FOR UPDATE;`
+	flagTransfer       = `
+		INSERT INTO transfer_reviews (initiator_id, sender_account_id, receiver_account_id, coins, rule, coin_request_id, team_spend_id)
+		VALUES ($1, (SELECT id FROM accounts WHERE user_id = $2 OR team_id = $3),
+		        (SELECT id FROM accounts WHERE user_id = $4 OR team_id = $5), $6, $7, $8, $9);`
+	transferReviewColumns = `
+		r.id, r.initiator_id, sa.user_id AS sender_id, sa.team_id AS sender_team_id,
+		ra.user_id AS receiver_id, ra.team_id AS receiver_team_id, i.username AS initiator,
+		sa.name AS sender, ra.name AS recipient, r.coins, r.rule, r.status, r.created_at, r.reviewed_at,
+		r.coin_request_id, r.team_spend_id`
+	transferReviewTables = `
+		FROM transfer_reviews r
+		JOIN users i ON r.initiator_id = i.id
+		JOIN accounts sa ON r.sender_account_id = sa.id
+		JOIN accounts ra ON r.receiver_account_id = ra.id`
+	transferReviewFields = transferReviewColumns + transferReviewTables
+	getTransferReviews   = `
+		SELECT ` + transferReviewFields + `
+		WHERE r.status = $1
+		ORDER BY r.created_at, r.id;`
+	getTransferReviewsPage = `
+		SELECT ` + transferReviewColumns + `, ` + pageTotal + transferReviewTables + `
+		WHERE r.status = $1
+		ORDER BY r.created_at, r.id
+		LIMIT $2 OFFSET $3;`
+	// the coin request paid by the transfer is locked before the review, in the order its resolution locks them
+	lockReviewedCoinRequest = `
+		SELECT id FROM coin_requests
+		WHERE id = (SELECT coin_request_id FROM transfer_reviews WHERE id = $1)
+		FOR UPDATE;`
+	lockOpenTransferReview = `
+		SELECT ` + transferReviewFields + `
+		WHERE r.id = $1 AND r.status = 'open'
+		FOR UPDATE OF r;`
+	resolveTransferReview = `
+		UPDATE transfer_reviews SET status = $2, reviewer_id = $3, reviewed_at = NOW()
+		WHERE id = $1
+		RETURNING reviewed_at;`
+)
+
+// accountTransfer is a transfer of coins between two accounts made by a user. Each account is given by its owner,
+// either a user or a team, the other owner's ID is 0. The anti-fraud rules look at the transfers the user makes.
+type accountTransfer struct {
+	initiatorID    int
+	senderID       int
+	senderTeamID   int
+	receiverID     int
+	receiverTeamID int
+	coins          int
+}
+
+// userTransfer is the transfer of the coins from the user's account to another user's one.
+func userTransfer(senderID, receiverID, coins int) *accountTransfer {
+	return &accountTransfer{initiatorID: senderID, senderID: senderID, receiverID: receiverID, coins: coins}
+}
+
+// GetTransferStats retrieves the sender's outgoing transfers within the last day and week, the coins sent
+// to the recipient within the week, the number of transfers within the window and both users' registration time.
+func (s *Storage) GetTransferStats(ctx context.Context, senderID, recipientID int, window time.Duration) (*models.TransferStats, error) {
+	return getTransferStatsTx(ctx, s.pool, userTransfer(senderID, recipientID, 0), window)
+}
+
+// getTransferStatsTx retrieves the stats of the transfers made by the initiator of the transfer with the querier,
+// a transaction or the pool.
+func getTransferStatsTx(ctx context.Context, q querier, t *accountTransfer, window time.Duration) (*models.TransferStats, error) {
+	rows, err := q.Query(ctx, getTransferStats, t.initiatorID, t.receiverID, t.receiverTeamID, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TransferStats])
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// enforceRules checks the transfer against the anti-fraud rules inside the transaction making it, nil rules
+// aren't checked. The transfer breaking a rule is flagged for review and the rule's error is returned as ruleErr:
+// no coins are to be moved then, but the transaction is to be committed for the review to stay.
+func enforceRules(ctx context.Context, q querier, rules *models.TransferRules, t *accountTransfer, sentAlong int) (ruleErr, err error) {
+	rule, ruleErr, err := checkRules(ctx, q, rules, t, sentAlong)
+	if err != nil || ruleErr == nil {
+		return nil, err
+	}
+	if err = flagTransferTx(ctx, q, t, rule, nil, nil); err != nil {
+		return nil, err
+	}
+	return ruleErr, nil
+}
+
+// checkRules checks the transfer against the anti-fraud rules, nil rules aren't checked. The initiator's row
+// is locked first, so that the user's concurrent transfers are checked one after another, each against
+// the ones committed before it. The coins sent along with the transfer, e.g. by the earlier legs of a batch,
+// count towards the outgoing caps. Returns the name and the error of the broken rule.
+func checkRules(
+	ctx context.Context, q querier, rules *models.TransferRules, t *accountTransfer, sentAlong int,
+) (rule string, ruleErr, err error) {
+	if rules == nil {
+		return "", nil, nil
+	}
+	if _, err = q.Exec(ctx, lockTransferSender, t.initiatorID); err != nil {
+		return "", nil, err
+	}
+
+	stats, err := getTransferStatsTx(ctx, q, t, rules.Window)
+	if err != nil {
+		return "", nil, err
+	}
+	stats.SentDay += sentAlong
+	stats.SentWeek += sentAlong
+
+	rule, ruleErr = rules.Check(stats, t.coins)
+	return rule, ruleErr, nil
+}
+
+// flagTransferTx queues the transfer blocked by the anti-fraud rule for review, linked to the coin request
+// it pays or the team spend it makes, if any.
+func flagTransferTx(ctx context.Context, q querier, t *accountTransfer, rule string, coinRequestID, teamSpendID *int) error {
+	_, err := q.Exec(ctx, flagTransfer, t.initiatorID, t.senderID, t.senderTeamID, t.receiverID, t.receiverTeamID,
+		t.coins, rule, coinRequestID, teamSpendID)
+	return err
+}
+
+// FlagTransfer queues the transfer blocked by the anti-fraud rule for review.
+func (s *Storage) FlagTransfer(ctx context.Context, fromUserID, toUserID, coins int, rule string) error {
+	return flagTransferTx(ctx, s.pool, userTransfer(fromUserID, toUserID, coins), rule, nil, nil)
+}
+
+// GetTransferReviews retrieves the flagged transfers with the given status, the oldest first.
+func (s *Storage) GetTransferReviews(ctx context.Context, status string) (*[]models.TransferReview, error) {
+	rows, err := s.pool.Query(ctx, getTransferReviews, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TransferReview])
+	if err != nil {
+		return nil, err
+	}
+	return &reviews, nil
+}
+
+// GetTransferReviewsPage retrieves a page of the flagged transfers with the given status, the oldest first.
+func (s *Storage) GetTransferReviewsPage(ctx context.Context, status string, limit, offset int) (*models.Page[models.TransferReview], error) {
+	return collectPage[models.TransferReview](ctx, s.pool, getTransferReviewsPage, limit, offset, status)
+}
+
+// ResolveTransferReview closes the open review of the flagged transfer. An approved transfer is made
+// in the same transaction bypassing the anti-fraud rules, the review stays open if it fails. Approving
+// the payment of a coin request accepts the request on behalf of the payer, the held spend from a team wallet
+// is approved or rejected along with its review.
+func (s *Storage) ResolveTransferReview(ctx context.Context, reviewID, reviewerID int, status string) (*models.TransferReview, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, lockReviewedCoinRequest, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, lockOpenTransferReview, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.TransferReview])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrTransferReviewNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case status == models.ReviewApproved:
+		err = makeReviewedTransfer(ctx, tx, &review, reviewerID)
+	case review.TeamSpendID != nil:
+		_, err = tx.Exec(ctx, decideTeamSpend, *review.TeamSpendID, models.SpendRejected, reviewerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, resolveTransferReview, reviewID, status, reviewerID).Scan(&review.ReviewedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]any{
+		"status": status, "initiator": review.InitiatorID, "from": review.Sender, "to": review.Recipient, "amount": review.Amount,
+	}
+	if review.CoinRequestID != nil {
+		details["coin_request"] = *review.CoinRequestID
+	}
+	if review.TeamSpendID != nil {
+		details["team_spend"] = *review.TeamSpendID
+	}
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditTransferReviewed, "transfer_review:"+strconv.Itoa(reviewID), details))
+	if err != nil {
+		return nil, err
+	}
+	review.Status = status
+	return &review, nil
+}
+
+// makeReviewedTransfer makes the transfer of the approved review bypassing the anti-fraud rules: moves the coins
+// between the users, deposits them to the team wallet or makes the held spend from it. Approving the payment
+// of a coin request accepts the request on behalf of the payer, as the request resolved otherwise has closed
+// its review, it's still pending here.
+func makeReviewedTransfer(ctx context.Context, tx pgx.Tx, r *models.TransferReview, reviewerID int) error {
+	switch {
+	case r.TeamSpendID != nil:
+		spend := &models.TeamSpend{
+			ID: *r.TeamSpendID, TeamID: *r.SenderTeamID, MemberID: r.InitiatorID, ReceiverID: *r.ReceiverID, Amount: r.Amount,
+		}
+		if err := spendTeamCoins(ctx, tx, spend); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, decideTeamSpend, spend.ID, models.SpendApproved, reviewerID)
+		return err
+	case r.ReceiverTeamID != nil:
+		return depositToTeamTx(ctx, tx, r.InitiatorID, *r.ReceiverTeamID, r.Amount)
+	}
+
+	if err := transferCoins(ctx, tx, *r.SenderID, *r.ReceiverID, r.Amount); err != nil || r.CoinRequestID == nil {
+		return err
+	}
+	return setCoinRequestStatusTx(ctx, tx, *r.CoinRequestID, *r.SenderID, models.CoinRequestAccepted,
+		map[string]any{"payer": *r.SenderID, "requester": *r.ReceiverID, "amount": r.Amount, "transfer_review": r.ID})
+}
@@ -0,0 +1,86 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	getUserEvents = `
+		SELECT id, user_id, kind, payload, created_at
+		FROM user_events
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3;`
+	getLastUserEventID     = `SELECT COALESCE(MAX(id), 0) FROM user_events WHERE user_id = $1;`
+	deleteUserEventsBefore = `DELETE FROM user_events WHERE created_at < $1;`
+	listenUserEvents       = `LISTEN user_events;`
+)
+
+// GetUserEvents retrieves up to limit events of the user following the given ID, the earliest first.
+func (s *Storage) GetUserEvents(ctx context.Context, userID int, afterID int64, limit int) (*[]models.UserEvent, error) {
+	rows, err := s.pool.Query(ctx, getUserEvents, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.UserEvent])
+	if err != nil {
+		return nil, err
+	}
+	return &events, nil
+}
+
+// GetLastUserEventID retrieves the ID of the user's latest event, zero if there is none.
+func (s *Storage) GetLastUserEventID(ctx context.Context, userID int) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, getLastUserEventID, userID).Scan(&id)
+	return id, err
+}
+
+// DeleteUserEventsBefore removes the events created before the given time. Returns the number of them.
+func (s *Storage) DeleteUserEventsBefore(ctx context.Context, before time.Time) (int, error) {
+	tag, err := s.pool.Exec(ctx, deleteUserEventsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ListenUserEvents calls notify with the user's ID each time a transaction adding events of the user
+// is committed, on any replica, until the context is done or the connection fails. notify is called
+// with zero once listening has started, as the events committed before it may have been missed.
+// The connection is taken out of the pool for good.
+func (s *Storage) ListenUserEvents(ctx context.Context, notify func(userID int)) error {
+	poolConn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := poolConn.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err = conn.Exec(ctx, listenUserEvents); err != nil {
+		return err
+	}
+	notify(0)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return ctx.Err()
+		} else if err != nil {
+			return err
+		}
+		if userID, err := strconv.Atoi(n.Payload); err == nil {
+			notify(userID)
+		}
+	}
+}
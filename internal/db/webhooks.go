@@ -0,0 +1,359 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	// the payloads are built from the usernames, the IDs mean nothing to the receivers
+	recordCoinsReceived = `
+		INSERT INTO outbox_events (event_type, payload)
+		SELECT 'coins.received', json_build_object(
+			'fromUser', s.username, 'toUser', r.username, 'amount', $3::INTEGER,
+			'team', (SELECT name FROM teams WHERE id = $4), 'batchId', $5::INTEGER)::TEXT
+		FROM users s, users r
+		WHERE s.id = $1 AND r.id = $2;`
+	recordMerchPurchased = `
+		INSERT INTO outbox_events (event_type, payload)
+		SELECT 'merch.purchased', json_build_object(
+			'purchaseId', $1::INTEGER, 'user', u.username, 'item', $3::TEXT, 'sku', $4::TEXT, 'price', $5::INTEGER)::TEXT
+		FROM users u
+		WHERE u.id = $2;`
+	// the events taken by other replicas are skipped; an event matching no active subscription
+	// is marked dispatched all the same
+	fanOutOutboxEvents = `
+		WITH events AS (
+			SELECT id, event_type FROM outbox_events
+			WHERE dispatched_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), deliveries AS (
+			INSERT INTO webhook_deliveries (event_id, subscription_id)
+			SELECT e.id, ws.id
+			FROM events e JOIN webhook_subscriptions ws
+				ON ws.active AND (cardinality(ws.events) = 0 OR e.event_type = ANY (ws.events))
+			ON CONFLICT (event_id, subscription_id) DO NOTHING
+		)
+		UPDATE outbox_events SET dispatched_at = NOW()
+		WHERE id IN (SELECT id FROM events);`
+	webhookDeliveryFields = `
+		d.id, d.event_id, d.subscription_id, e.event_type, e.payload, e.created_at AS event_created_at,
+		ws.url, ws.secret, d.status, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at`
+	webhookDeliveryJoins = `
+		JOIN outbox_events e ON d.event_id = e.id
+		JOIN webhook_subscriptions ws ON d.subscription_id = ws.id`
+	webhookDeliveryColumns = webhookDeliveryFields + `
+		FROM webhook_deliveries d` + webhookDeliveryJoins
+	// the claimed delivery isn't due again until the lease ends, so the other replicas skip it while it's sent;
+	// the deliveries of a paused subscription wait
+	claimDueDelivery = `
+		WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions ws ON d.subscription_id = ws.id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND ws.active
+			ORDER BY d.next_attempt_at
+			LIMIT 1
+			FOR UPDATE OF d SKIP LOCKED
+		), d AS (
+			UPDATE webhook_deliveries SET next_attempt_at = NOW() + make_interval(secs => $1)
+			WHERE id = (SELECT id FROM due)
+			RETURNING *
+		)
+		SELECT ` + webhookDeliveryFields + `
+		FROM d` + webhookDeliveryJoins + `;`
+	// the outcome is saved only while the lease holds, a delivery claimed again once the lease ended
+	// belongs to the replica that claimed it
+	saveDeliveryOutcome = `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		WHERE id = $1 AND status = 'pending' AND next_attempt_at = $6;`
+	getWebhookDeliveries = `
+		SELECT ` + webhookDeliveryColumns + `
+		WHERE d.status = $1 AND ($2 = 0 OR d.subscription_id = $2)
+		ORDER BY d.id DESC
+		LIMIT $3;`
+	getWebhookDeliveriesPage = `
+		SELECT ` + webhookDeliveryFields + `, ` + pageTotal + `
+		FROM webhook_deliveries d` + webhookDeliveryJoins + `
+		WHERE d.status = $1 AND ($2 = 0 OR d.subscription_id = $2)
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4;`
+	replayWebhookDelivery = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), last_error = NULL, delivered_at = NULL
+		WHERE id = $1 AND status IN ('delivered', 'dead')
+		RETURNING subscription_id;`
+	replayDeadDeliveries = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), last_error = NULL
+		WHERE subscription_id = $1 AND status = 'dead';`
+	createWebhookSubscription = `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, events, active, created_at;`
+	getWebhookSubscriptions = `
+		SELECT id, url, '' AS secret, events, active, created_at
+		FROM webhook_subscriptions
+		ORDER BY id;`
+	updateWebhookSubscription = `
+		UPDATE webhook_subscriptions SET url = $2, events = $3, active = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, url, '' AS secret, events, active, created_at;`
+	deleteWebhookSubscription = `DELETE FROM webhook_subscriptions WHERE id = $1 RETURNING url;`
+)
+
+// CreateWebhookSubscription saves the subscription, setting its ID and creation time.
+func (s *Storage) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, createWebhookSubscription, sub.URL, sub.Secret, sub.Events)
+	if err != nil {
+		return err
+	}
+	created, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.WebhookSubscription])
+	if err != nil {
+		return err
+	}
+
+	// the secret stays out of the audit log
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditWebhookCreated, "webhook:"+strconv.Itoa(created.ID),
+		map[string]any{"url": created.URL, "events": created.Events}))
+	if err != nil {
+		return err
+	}
+	*sub = created
+	return nil
+}
+
+// GetWebhookSubscriptions retrieves all the webhook subscriptions without their secrets.
+func (s *Storage) GetWebhookSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error) {
+	rows, err := s.pool.Query(ctx, getWebhookSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.WebhookSubscription])
+	if err != nil {
+		return nil, err
+	}
+	return &subs, nil
+}
+
+// UpdateWebhookSubscription changes the subscription's URL, events and whether it's active.
+// The secret is kept as it is and isn't returned.
+func (s *Storage) UpdateWebhookSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, updateWebhookSubscription, id, u.URL, u.Events, *u.Active)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.WebhookSubscription])
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrWebhookNotFound
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditWebhookUpdated, "webhook:"+strconv.Itoa(id),
+		map[string]any{"url": sub.URL, "events": sub.Events, "active": sub.Active}))
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// DeleteWebhookSubscription removes the subscription with its deliveries.
+func (s *Storage) DeleteWebhookSubscription(ctx context.Context, id int) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var url string
+	err = tx.QueryRow(ctx, deleteWebhookSubscription, id).Scan(&url)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrWebhookNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditWebhookDeleted, "webhook:"+strconv.Itoa(id),
+		map[string]any{"url": url}))
+	return err
+}
+
+// GetWebhookDeliveries retrieves up to limit deliveries in the status, the latest first.
+// A zero subscription ID matches any subscription.
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, status string, subscriptionID, limit int) (*[]models.WebhookDelivery, error) {
+	rows, err := s.pool.Query(ctx, getWebhookDeliveries, status, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.WebhookDelivery])
+	if err != nil {
+		return nil, err
+	}
+	return &deliveries, nil
+}
+
+// GetWebhookDeliveriesPage retrieves a page of the deliveries in the status, the latest first.
+// A zero subscription ID matches any subscription.
+func (s *Storage) GetWebhookDeliveriesPage(ctx context.Context, status string, subscriptionID, limit, offset int) (*models.Page[models.WebhookDelivery], error) {
+	return collectPage[models.WebhookDelivery](ctx, s.pool, getWebhookDeliveriesPage, limit, offset, status, subscriptionID)
+}
+
+// ReplayWebhookDelivery sends the finished delivery again, with a fresh set of attempts.
+func (s *Storage) ReplayWebhookDelivery(ctx context.Context, id int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	subscriptionID := 0
+	err = tx.QueryRow(ctx, replayWebhookDelivery, id).Scan(&subscriptionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrWebhookDeliveryNotFound
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditWebhookReplayed, "webhook:"+strconv.Itoa(subscriptionID),
+		map[string]any{"delivery": id}))
+	return err
+}
+
+// ReplayDeadDeliveries sends again all the dead deliveries of the subscription, with a fresh set of attempts.
+// Returns the number of the deliveries replayed.
+func (s *Storage) ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, replayDeadDeliveries, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	replayed := int(tag.RowsAffected())
+	if replayed == 0 {
+		return 0, nil
+	}
+
+	err = appendAudit(ctx, tx, audit.NewEntry(ctx, models.AuditWebhookReplayed, "webhook:"+strconv.Itoa(subscriptionID),
+		map[string]any{"dead": replayed}))
+	if err != nil {
+		return 0, err
+	}
+	return replayed, nil
+}
+
+// DispatchOutboxEvents creates the deliveries of up to limit outbox events to the active subscriptions
+// matching them and marks the events dispatched. Returns the number of the events dispatched.
+func (s *Storage) DispatchOutboxEvents(ctx context.Context, limit int) (int, error) {
+	tag, err := s.pool.Exec(ctx, fanOutOutboxEvents, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDueWebhook leases the earliest due webhook delivery for the given time, during which it isn't due
+// for the other replicas, and returns it with the lease's end as its next attempt time. The lease should
+// outlast the attempt, the delivery is claimed again once it ends. Returns nil if no delivery is due.
+func (s *Storage) ClaimDueWebhook(ctx context.Context, lease time.Duration) (*models.WebhookDelivery, error) {
+	rows, err := s.pool.Query(ctx, claimDueDelivery, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.WebhookDelivery])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// SaveWebhookOutcome saves the outcome of the attempt to make the claimed delivery. Returns false
+// if the delivery's lease has ended meanwhile and the outcome is dropped.
+func (s *Storage) SaveWebhookOutcome(ctx context.Context, d *models.WebhookDelivery, outcome *models.DeliveryOutcome) (bool, error) {
+	tag, err := s.pool.Exec(ctx, saveDeliveryOutcome,
+		d.ID, outcome.Status, outcome.Attempts, outcome.NextAttemptAt, outcome.LastError, d.NextAttemptAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// addCoinsReceivedEvent writes the coins.received event to the outbox within the transaction moving the coins.
+// The team and the batch are nil unless the coins come from a team wallet or a batch transfer.
+func addCoinsReceivedEvent(ctx context.Context, q querier, fromUserID, toUserID, coins int, teamID, batchID *int) error {
+	_, err := q.Exec(ctx, recordCoinsReceived, fromUserID, toUserID, coins, teamID, batchID)
+	return err
+}
+
+// addMerchPurchasedEvent writes the merch.purchased event to the outbox within the purchase transaction.
+func addMerchPurchasedEvent(ctx context.Context, q querier, purchaseID, userID int, item, sku string, price int) error {
+	_, err := q.Exec(ctx, recordMerchPurchased, purchaseID, userID, item, sku, price)
+	return err
+}
@@ -0,0 +1,112 @@
+// Package db provides functionality for interacting with the PostgreSQL database.
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	addToWishlist       = `INSERT INTO wishlists (user_id, item_slug) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	removeFromWishlist  = `DELETE FROM wishlists WHERE user_id = $1 AND item_slug = $2;`
+	getWishlistByUserID = `
+		SELECT ` + itemColumns + ` FROM wishlists w JOIN store s ON s.slug = w.item_slug
+		WHERE w.user_id = $1
+		ORDER BY w.created_at DESC;`
+
+	notifyBackInStock = `
+		INSERT INTO notifications (user_id, kind, item_slug, message)
+		SELECT w.user_id, 'back_in_stock', w.item_slug, v.title || ' is back in stock'
+		FROM wishlists w JOIN item_variants v ON v.item_slug = w.item_slug
+		WHERE v.sku = $1;`
+	notifyDiscount = `
+		INSERT INTO notifications (user_id, kind, item_slug, message)
+		SELECT w.user_id, 'discount', w.item_slug,
+		       s.title || ' is on sale from ' || TO_CHAR($3::TIMESTAMP, 'YYYY-MM-DD HH24:MI') ||
+		       ' to ' || TO_CHAR($4::TIMESTAMP, 'YYYY-MM-DD HH24:MI') || ' UTC'
+		FROM wishlists w JOIN store s ON s.slug = w.item_slug
+		WHERE w.item_slug = $1 OR s.category = $2;`
+	getNotificationsByUserID = `
+		SELECT id, kind, item_slug, message, read_at IS NOT NULL AS read, created_at FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2;`
+	getNotificationsPageByUserID = `
+		SELECT id, kind, item_slug, message, read_at IS NOT NULL AS read, created_at, ` + pageTotal + ` FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3;`
+	markNotificationsRead = `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL;`
+)
+
+// AddToWishlist adds the item to the user's wishlist, adding it twice has no effect.
+func (s *Storage) AddToWishlist(ctx context.Context, userID int, slug string) error {
+	_, err := s.pool.Exec(ctx, addToWishlist, userID, slug)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return models.ErrItemNotFound
+	}
+	return err
+}
+
+// RemoveFromWishlist removes the item from the user's wishlist.
+func (s *Storage) RemoveFromWishlist(ctx context.Context, userID int, slug string) error {
+	_, err := s.pool.Exec(ctx, removeFromWishlist, userID, slug)
+	return err
+}
+
+// GetWishlistByUserID retrieves the items of the user's wishlist, the latest added first.
+func (s *Storage) GetWishlistByUserID(ctx context.Context, userID int) (*[]models.Item, error) {
+	rows, err := s.pool.Query(ctx, getWishlistByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Item])
+	if err != nil {
+		return nil, err
+	}
+	return &items, nil
+}
+
+// notifyIfBackInStock notifies the users who wish the variant's item when the variant's stock
+// goes up from zero or becomes unlimited.
+func notifyIfBackInStock(ctx context.Context, q querier, sku string, oldStock, newStock *int) error {
+	if oldStock == nil || *oldStock > 0 || (newStock != nil && *newStock == 0) {
+		return nil
+	}
+	_, err := q.Exec(ctx, notifyBackInStock, sku)
+	return err
+}
+
+// GetNotificationsByUserID retrieves up to limit latest notifications of the user.
+func (s *Storage) GetNotificationsByUserID(ctx context.Context, userID, limit int) (*[]models.Notification, error) {
+	rows, err := s.pool.Query(ctx, getNotificationsByUserID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Notification])
+	if err != nil {
+		return nil, err
+	}
+	return &notifications, nil
+}
+
+// GetNotificationsPageByUserID retrieves a page of the user's notifications, the latest first.
+func (s *Storage) GetNotificationsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Notification], error) {
+	return collectPage[models.Notification](ctx, s.pool, getNotificationsPageByUserID, limit, offset, userID)
+}
+
+// MarkNotificationsRead marks all the user's notifications read.
+func (s *Storage) MarkNotificationsRead(ctx context.Context, userID int) error {
+	_, err := s.pool.Exec(ctx, markNotificationsRead, userID)
+	return err
+}
@@ -0,0 +1,136 @@
+package grpcserver
+
+import (
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver/pb"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// toMerch converts the inventory. The services return the lists as pointers, a nil list is left unset.
+func toMerch(inventory *[]models.Merch) []*pb.Merch {
+	if inventory == nil {
+		return nil
+	}
+	res := make([]*pb.Merch, 0, len(*inventory))
+	for _, m := range *inventory {
+		res = append(res, &pb.Merch{Type: m.Type, Variant: m.Variant, Quantity: int32(m.Quantity)})
+	}
+	return res
+}
+
+func toPurchases(purchases *[]models.Purchase) []*pb.Purchase {
+	if purchases == nil {
+		return nil
+	}
+	res := make([]*pb.Purchase, 0, len(*purchases))
+	for _, p := range *purchases {
+		res = append(res, &pb.Purchase{
+			Id:              int32(p.ID),
+			Item:            p.Item,
+			Variant:         p.Variant,
+			Price:           int32(p.Price),
+			Status:          p.Status,
+			Office:          p.Office,
+			CreatedAt:       p.CreatedAt.Unix(),
+			StatusChangedAt: p.StatusChangedAt.Unix(),
+		})
+	}
+	return res
+}
+
+func toGoals(goals *[]models.SavingsGoal) []*pb.SavingsGoal {
+	if goals == nil {
+		return nil
+	}
+	res := make([]*pb.SavingsGoal, 0, len(*goals))
+	for _, g := range *goals {
+		res = append(res, &pb.SavingsGoal{Id: int32(g.ID), Item: g.Item, Saved: int32(g.Saved), Target: int32(g.Target)})
+	}
+	return res
+}
+
+func toCoinHistory(history *models.CoinHistory) *pb.CoinHistory {
+	res := &pb.CoinHistory{}
+	if history == nil {
+		return res
+	}
+	if history.Receiving != nil {
+		for _, r := range *history.Receiving {
+			res.Received = append(res.Received, &pb.CoinReceived{
+				FromUser: r.User, Amount: int32(r.Amount), Type: r.Kind, BatchId: toInt32(r.BatchID),
+			})
+		}
+	}
+	if history.Sending != nil {
+		for _, s := range *history.Sending {
+			res.Sent = append(res.Sent, &pb.CoinSent{
+				ToUser: s.User, Amount: int32(s.Amount), Type: s.Kind, Reason: s.Reason, BatchId: toInt32(s.BatchID),
+			})
+		}
+	}
+	return res
+}
+
+func toGiftHistory(history *models.GiftHistory) *pb.GiftHistory {
+	res := &pb.GiftHistory{}
+	if history == nil {
+		return res
+	}
+	if history.Receiving != nil {
+		for _, r := range *history.Receiving {
+			res.Received = append(res.Received, &pb.GiftReceived{
+				FromUser: r.User, Item: r.Item, Variant: r.Variant, Quantity: int32(r.Quantity),
+			})
+		}
+	}
+	if history.Sending != nil {
+		for _, s := range *history.Sending {
+			res.Sent = append(res.Sent, &pb.GiftSent{
+				ToUser: s.User, Item: s.Item, Variant: s.Variant, Quantity: int32(s.Quantity),
+			})
+		}
+	}
+	return res
+}
+
+func toItems(items *[]models.Item) []*pb.Item {
+	if items == nil {
+		return nil
+	}
+	res := make([]*pb.Item, 0, len(*items))
+	for _, item := range *items {
+		variants := make([]*pb.Variant, 0, len(item.Variants))
+		for _, v := range item.Variants {
+			variants = append(variants, &pb.Variant{
+				Sku:           v.SKU,
+				Title:         v.Title,
+				Size:          v.Size,
+				Color:         v.Color,
+				PriceOverride: toInt32(v.PriceOverride),
+				Stock:         toInt32(v.Stock),
+				IsDefault:     v.IsDefault,
+				SalePrice:     toInt32(v.SalePrice),
+			})
+		}
+		res = append(res, &pb.Item{
+			Slug:         item.Slug,
+			Title:        item.Title,
+			Price:        int32(item.Price),
+			Category:     item.Category,
+			Stock:        toInt32(item.Stock),
+			PerUserLimit: toInt32(item.PerUserLimit),
+			SalePrice:    toInt32(item.SalePrice),
+			Variants:     variants,
+			InWishlist:   item.InWishlist,
+		})
+	}
+	return res
+}
+
+// toInt32 converts an optional number, nil stays unset.
+func toInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver/pb"
+)
+
+const (
+	authMetadata      = "authorization" // the metadata keys are lower case
+	requestIDMetadata = "x-request-id"
+)
+
+// validRequestID limits the request IDs accepted from clients, so that they are safe to store and log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type userKey struct{}
+
+// user is the authorized caller.
+type user struct {
+	ID       int
+	Username string
+}
+
+// userFromContext returns the caller authorized by the auth interceptor.
+func userFromContext(ctx context.Context) (user, bool) {
+	u, ok := ctx.Value(userKey{}).(user)
+	return u, ok
+}
+
+// requestInterceptor assigns an ID to each call, the one from the "x-request-id" metadata is kept if it's valid.
+// The ID is returned in the response header and, with the caller's address and user agent,
+// attached to the context, so that the changes made within the call are audited.
+func requestInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		requestID := firstValue(md, requestIDMetadata)
+		if !validRequestID.MatchString(requestID) {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+
+		r := audit.Request{
+			UserAgent: firstValue(md, "user-agent"),
+			RequestID: requestID,
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			r.IP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(r.IP); err == nil {
+				r.IP = host
+			}
+		}
+
+		return handler(audit.WithRequest(ctx, r), req)
+	}
+}
+
+// authInterceptor validates the JWT token in the "authorization: Bearer <token>" metadata of every call
+// except Auth, the same way as the HTTP API's JWT middleware. The caller is set in the context.
+// The tokens of the users whose accounts have been locked or deactivated since they were issued are rejected.
+func authInterceptor(tknMng tokenManager, accounts accountChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.MerchShop_Auth_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		header := firstValue(md, authMetadata)
+		if header == "" {
+			return nil, status.Error(codes.Unauthenticated, "the 'authorization' metadata is missing")
+		}
+
+		parts := strings.Split(header, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid token format")
+		}
+		if len(parts[1]) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "token is empty")
+		}
+
+		claims, err := tknMng.ParseClaims(parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		sub, _ := (*claims)["sub"].(string)
+		userID, err := strconv.Atoi(sub)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "context parsing failure")
+		}
+		username, _ := (*claims)["username"].(string)
+
+		// The account may have been locked or deactivated after the token was issued
+		if err = accounts.CheckAccount(ctx, userID); err != nil {
+			return nil, statusFromError(err)
+		}
+
+		// The caller becomes the actor of the audited changes
+		r := audit.FromContext(ctx)
+		r.ActorID = &userID
+		if username != "" {
+			r.Actor = &username
+		}
+		ctx = audit.WithRequest(ctx, r)
+
+		return handler(context.WithValue(ctx, userKey{}, user{ID: userID, Username: username}), req)
+	}
+}
+
+// firstValue returns the first value of the metadata key, empty if there is none.
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
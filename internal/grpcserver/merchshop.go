@@ -0,0 +1,211 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver/pb"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers"
+)
+
+// errInDB is returned for the database failures, the same message as in the HTTP API.
+var errInDB = status.Error(codes.Internal, apperr.ErrDatabase.Error())
+
+// kindCodes are the status codes of the kinds of the domain errors.
+var kindCodes = map[apperr.Kind]codes.Code{
+	apperr.KindInvalid:         codes.InvalidArgument,
+	apperr.KindUnauthorized:    codes.Unauthenticated,
+	apperr.KindForbidden:       codes.PermissionDenied,
+	apperr.KindNotFound:        codes.NotFound,
+	apperr.KindConflict:        codes.FailedPrecondition,
+	apperr.KindTooManyRequests: codes.ResourceExhausted,
+}
+
+// statusFromError converts the domain error in the chain of err to the status with the code of its kind,
+// keeping the message with its context. Any other error is reported as errInDB.
+func statusFromError(err error) error {
+	code, ok := kindCodes[apperr.From(err).Kind()]
+	if !ok {
+		return errInDB
+	}
+	return status.Error(code, err.Error())
+}
+
+// merchShop serves the MerchShop RPCs.
+type merchShop struct {
+	pb.UnimplementedMerchShopServer
+
+	authSrv   handlers.AuthService        // Service for authentication-related operations.
+	tknMng    handlers.TokenManager       // Manager for JWT token operations.
+	usrInfSrv handlers.UserInfoService    // Service for retrieving user information.
+	txSrv     handlers.TransactionService // Service for handling coin transactions.
+	buyItmSrv handlers.BuyItemService     // Service for handling item purchases.
+}
+
+// Auth authenticates the user, registering a new one on the first call, and issues a token.
+func (ms *merchShop) Auth(ctx context.Context, req *pb.AuthRequest) (*pb.AuthResponse, error) {
+	login := models.Login{Username: req.GetUsername(), Password: req.GetPassword(), ClaimToken: req.GetClaimToken()}
+	if err := binding.Validator.ValidateStruct(&login); err != nil {
+		return nil, statusFromError(apperr.Invalid(err.Error()))
+	}
+
+	// an imported account is refused without its claim token, or once it has been claimed in the meantime
+	user, ok, err := ms.authSrv.GetOrRegUser(ctx, login.Username, login.Password, login.ClaimToken)
+	if err != nil {
+		return nil, statusFromError(err)
+	} else if ok {
+		if !ms.authSrv.ComparePassword(user.Password, login.Password) {
+			if err = ms.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				return nil, statusFromError(err)
+			}
+			return nil, statusFromError(models.ErrInvalidPassword)
+		}
+		if user.LockedAt != nil {
+			if err = ms.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				return nil, statusFromError(err)
+			}
+			return nil, statusFromError(models.ErrAccountLocked)
+		}
+		if user.DeactivatedAt != nil {
+			if err = ms.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				return nil, statusFromError(err)
+			}
+			return nil, statusFromError(models.ErrAccountDeactivated)
+		}
+		if err = ms.authSrv.RecordAuthEvent(ctx, models.AuditLogin, user); err != nil {
+			return nil, statusFromError(err)
+		}
+	}
+
+	token, err := ms.tknMng.NewToken(strconv.Itoa(user.ID), user.Username)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "token generation failure")
+	}
+	// the token isn't handed out unless its issuance is audited
+	if err = ms.authSrv.RecordAuthEvent(ctx, models.AuditTokenIssued, user); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.AuthResponse{Token: token}, nil
+}
+
+// Info returns the caller's balance, inventory, purchases, savings goals, coin and gift history.
+func (ms *merchShop) Info(ctx context.Context, _ *pb.InfoRequest) (*pb.InfoResponse, error) {
+	caller, ok := userFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "context parsing failure")
+	}
+
+	coins, err := ms.usrInfSrv.GetCoins(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	inventory, err := ms.usrInfSrv.GetInventory(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	coinHistory, err := ms.usrInfSrv.GetCoinHistory(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	giftHistory, err := ms.usrInfSrv.GetGiftHistory(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	purchases, err := ms.usrInfSrv.GetPurchases(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	goals, err := ms.usrInfSrv.GetGoals(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.InfoResponse{
+		Coins:       int32(coins),
+		Inventory:   toMerch(inventory),
+		Purchases:   toPurchases(purchases),
+		Goals:       toGoals(goals),
+		CoinHistory: toCoinHistory(coinHistory),
+		GiftHistory: toGiftHistory(giftHistory),
+	}, nil
+}
+
+// SendCoins transfers coins from the caller to another user.
+func (ms *merchShop) SendCoins(ctx context.Context, req *pb.SendCoinsRequest) (*pb.SendCoinsResponse, error) {
+	caller, ok := userFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "context parsing failure")
+	}
+	send := models.Sending{User: req.GetToUser(), Amount: int(req.GetAmount())}
+	if err := binding.Validator.ValidateStruct(&send); err != nil {
+		return nil, statusFromError(apperr.Invalid(err.Error()))
+	}
+
+	recipientID, err := ms.txSrv.GetIDRecipient(ctx, send.User)
+	if err != nil {
+		return nil, statusFromError(err)
+	} else if recipientID == 0 {
+		return nil, statusFromError(models.ErrRecipientNotFound)
+	}
+
+	if senderCoins, err := ms.txSrv.GetSenderCoins(ctx, caller.ID); err != nil {
+		return nil, statusFromError(err)
+	} else if senderCoins < send.Amount {
+		return nil, statusFromError(models.ErrNotEnoughCoins)
+	}
+
+	if err = ms.txSrv.SendCoinsToUser(ctx, caller.ID, recipientID, send.Amount); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.SendCoinsResponse{}, nil
+}
+
+// BuyItem buys the item's variant for the caller, the default variant if none is given.
+func (ms *merchShop) BuyItem(ctx context.Context, req *pb.BuyItemRequest) (*pb.BuyItemResponse, error) {
+	caller, ok := userFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "context parsing failure")
+	}
+	order := &models.Order{
+		Variant:   req.GetVariant(),
+		PromoCode: req.GetPromoCode(),
+		Office:    req.GetOffice(),
+	}
+
+	quote, err := ms.buyItmSrv.BuyItem(ctx, caller.ID, req.GetItem(), order)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.BuyItemResponse{
+		ListPrice: int32(quote.ListPrice),
+		Discount:  int32(quote.Discount),
+		Price:     int32(quote.Price),
+		PromoCode: quote.PromoCode,
+		Variant:   quote.Variant,
+	}, nil
+}
+
+// Catalog returns the store's items with their prices and remaining stock, marking the items
+// in the caller's wishlist.
+func (ms *merchShop) Catalog(ctx context.Context, _ *pb.CatalogRequest) (*pb.CatalogResponse, error) {
+	caller, ok := userFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "context parsing failure")
+	}
+
+	items, err := ms.buyItmSrv.GetCatalog(ctx, caller.ID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.CatalogResponse{Items: toItems(items)}, nil
+}
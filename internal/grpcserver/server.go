@@ -0,0 +1,97 @@
+// Package grpcserver contains the gRPC server exposing the merch shop to other backends service-to-service.
+// The RPCs are served by the same services as the HTTP handlers and follow the same rules.
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver/pb"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers"
+)
+
+// Config holds configuration values for the gRPC server, such as host and port.
+type Config struct {
+	Host string `envconfig:"HOST" default:"localhost"`
+	Port string `envconfig:"PORT" default:"9090"`
+}
+
+type tokenManager interface {
+	ParseClaims(string) (*jwt.MapClaims, error)
+}
+
+type accountChecker interface {
+	CheckAccount(ctx context.Context, userID int) error
+}
+
+// Services groups the services the RPCs are served by.
+type Services struct {
+	Auth        handlers.AuthService        // Service for authentication-related operations
+	Tokens      handlers.TokenManager       // Manager for JWT token operations
+	UserInfo    handlers.UserInfoService    // Service for retrieving user information
+	Transaction handlers.TransactionService // Service for handling coin transactions
+	BuyItem     handlers.BuyItemService     // Service for handling item purchases
+}
+
+// GRPCServer represents the gRPC server with its configuration.
+type GRPCServer struct {
+	cfg    *Config
+	server *grpc.Server
+}
+
+// New creates a new instance of GRPCServer with the provided configuration and services.
+// The calls are authorized by the JWT tokens issued by Auth, the same as in the HTTP API,
+// as long as the caller's account is neither locked nor deactivated.
+func New(cfg *Config, srvs *Services, tknMng tokenManager, accounts accountChecker) *GRPCServer {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		requestInterceptor(),
+		authInterceptor(tknMng, accounts),
+	))
+	pb.RegisterMerchShopServer(server, &merchShop{
+		authSrv:   srvs.Auth,
+		tknMng:    srvs.Tokens,
+		usrInfSrv: srvs.UserInfo,
+		txSrv:     srvs.Transaction,
+		buyItmSrv: srvs.BuyItem,
+	})
+
+	return &GRPCServer{
+		cfg:    cfg,
+		server: server,
+	}
+}
+
+// Start begins the gRPC server, listening on the configured host and port.
+func (gs *GRPCServer) Start() error {
+	lis, err := net.Listen("tcp", gs.cfg.Host+":"+gs.cfg.Port)
+	if err != nil {
+		return err
+	}
+	return gs.Serve(lis)
+}
+
+// Serve serves the RPCs on the given listener until the server is stopped.
+func (gs *GRPCServer) Serve(lis net.Listener) error {
+	return gs.server.Serve(lis)
+}
+
+// Shutdown gently terminates the server by finishing the running calls,
+// the calls still running once the context is done are cancelled.
+func (gs *GRPCServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		gs.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		gs.server.Stop()
+		return ctx.Err()
+	}
+}
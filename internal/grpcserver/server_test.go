@@ -0,0 +1,289 @@
+//go:build integration
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/grpcserver/pb"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+)
+
+var validToken = "validToken"
+
+// dummyTokenManager – простая реализация менеджера токенов для тестирования.
+// При получении токена "validToken" возвращает корректные claims.
+type dummyTokenManager struct{}
+
+func (d *dummyTokenManager) NewToken(userID string, username string) (string, error) {
+	return validToken, nil
+}
+
+func (d *dummyTokenManager) ParseClaims(token string) (*jwt.MapClaims, error) {
+	if token == validToken {
+		claims := jwt.MapClaims{
+			"sub":      "1",        // идентификатор пользователя (строкой)
+			"username": "testUser", // имя пользователя
+		}
+		return &claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// dummyAccountChecker – проверка аккаунтов, возвращающая заданную ошибку для любого пользователя.
+type dummyAccountChecker struct {
+	err error
+}
+
+func (d *dummyAccountChecker) CheckAccount(ctx context.Context, userID int) error {
+	return d.err
+}
+
+// newClient запускает сервер на bufconn и возвращает подключённого к нему клиента.
+func newClient(t *testing.T, srvs *Services) pb.MerchShopClient {
+	t.Helper()
+	return newClientWithAccounts(t, srvs, &dummyAccountChecker{})
+}
+
+// newClientWithAccounts запускает сервер с заданной проверкой аккаунтов.
+func newClientWithAccounts(t *testing.T, srvs *Services, accounts accountChecker) pb.MerchShopClient {
+	t.Helper()
+
+	dTokenMng := &dummyTokenManager{}
+	srvs.Tokens = dTokenMng
+	gs := New(&Config{}, srvs, dTokenMng, accounts)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(func() { _ = gs.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewMerchShopClient(conn)
+}
+
+// authorized добавляет токен в метаданные вызова.
+func authorized(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// TestMerchShop_Auth проверяет выдачу токена новому и существующему пользователю
+// и отказ при неверном пароле.
+func TestMerchShop_Auth(t *testing.T) {
+	lockedAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		exists   bool
+		matches  bool
+		locked   bool
+		left     bool
+		wantCode codes.Code
+	}{
+		{name: "Новый пользователь", username: "testUser", password: "password", wantCode: codes.OK},
+		{name: "Существующий пользователь", username: "testUser", password: "password", exists: true, matches: true, wantCode: codes.OK},
+		{name: "Неверный пароль", username: "testUser", password: "password", exists: true, wantCode: codes.Unauthenticated},
+		{name: "Короткое имя", username: "user", password: "password", wantCode: codes.InvalidArgument},
+		{name: "Заблокированный аккаунт", username: "testUser", password: "password", exists: true, matches: true, locked: true, wantCode: codes.PermissionDenied},
+		{name: "Деактивированный аккаунт", username: "testUser", password: "password", exists: true, matches: true, left: true, wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: 1, Username: "testUser", Password: "hashed"}
+			if tt.locked {
+				user.LockedAt = &lockedAt
+			}
+			if tt.left {
+				user.DeactivatedAt = &lockedAt
+			}
+
+			mAuthSvc := mocks.NewAuthService(t)
+			if tt.wantCode != codes.InvalidArgument {
+				mAuthSvc.On("GetOrRegUser", mock.Anything, tt.username, tt.password, "").Return(user, tt.exists, nil)
+			}
+			if tt.exists {
+				mAuthSvc.On("ComparePassword", user.Password, tt.password).Return(tt.matches)
+			}
+			switch {
+			case tt.exists && (!tt.matches || tt.locked || tt.left):
+				mAuthSvc.On("RecordAuthEvent", mock.Anything, models.AuditLoginFailed, user).Return(nil)
+			case tt.exists:
+				mAuthSvc.On("RecordAuthEvent", mock.Anything, models.AuditLogin, user).Return(nil)
+			}
+			if tt.wantCode == codes.OK {
+				mAuthSvc.On("RecordAuthEvent", mock.Anything, models.AuditTokenIssued, user).Return(nil)
+			}
+
+			client := newClient(t, &Services{Auth: mAuthSvc})
+			resp, err := client.Auth(context.Background(), &pb.AuthRequest{Username: tt.username, Password: tt.password})
+
+			require.Equal(t, tt.wantCode, status.Code(err), err)
+			if tt.wantCode == codes.OK {
+				require.Equal(t, validToken, resp.GetToken())
+			}
+		})
+	}
+}
+
+// TestMerchShop_AuthClaimToken проверяет, что токен первого входа передаётся сервису,
+// а импортированный аккаунт без верного токена не занимается.
+func TestMerchShop_AuthClaimToken(t *testing.T) {
+	mAuthSvc := mocks.NewAuthService(t)
+	mAuthSvc.On("GetOrRegUser", mock.Anything, "newHire01", "password", "wrongToken").
+		Return(nil, false, models.ErrInvalidClaimToken)
+
+	client := newClient(t, &Services{Auth: mAuthSvc})
+	_, err := client.Auth(context.Background(), &pb.AuthRequest{Username: "newHire01", Password: "password", ClaimToken: "wrongToken"})
+
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+}
+
+// TestMerchShop_Unauthenticated проверяет, что вызовы без корректного токена отклоняются.
+func TestMerchShop_Unauthenticated(t *testing.T) {
+	client := newClient(t, &Services{})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "Без токена", ctx: context.Background()},
+		{name: "Неверный формат", ctx: metadata.AppendToOutgoingContext(context.Background(), "authorization", validToken)},
+		{name: "Неверный токен", ctx: authorized("invalidToken")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Info(tt.ctx, &pb.InfoRequest{})
+			require.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
+
+// TestMerchShop_InactiveAccount проверяет, что токены заблокированных и деактивированных пользователей отклоняются.
+func TestMerchShop_InactiveAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "Заблокированный аккаунт", err: models.ErrAccountLocked, wantCode: codes.PermissionDenied},
+		{name: "Деактивированный аккаунт", err: models.ErrAccountDeactivated, wantCode: codes.PermissionDenied},
+		{name: "Ошибка БД", err: errors.New("db is down"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClientWithAccounts(t, &Services{}, &dummyAccountChecker{err: tt.err})
+			_, err := client.Info(authorized(validToken), &pb.InfoRequest{})
+			require.Equal(t, tt.wantCode, status.Code(err), err)
+		})
+	}
+}
+
+// TestMerchShop_SendCoins проверяет передачу монет и соответствие ошибок сервиса кодам gRPC.
+func TestMerchShop_SendCoins(t *testing.T) {
+	tests := []struct {
+		name     string
+		sendErr  error
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "Успешная передача", wantCode: codes.OK},
+		{name: "Превышен лимит", sendErr: models.ErrTransferLimitExceeded, wantCode: codes.PermissionDenied},
+		{
+			name:     "Обёрнутая ошибка сохраняет контекст",
+			sendErr:  fmt.Errorf("%w: at most 500 coins a day, 450 already sent", models.ErrTransferLimitExceeded),
+			wantCode: codes.PermissionDenied,
+			wantMsg:  models.ErrTransferLimitExceeded.Error() + ": at most 500 coins a day, 450 already sent",
+		},
+		{name: "Слишком часто", sendErr: models.ErrTransferTooFrequent, wantCode: codes.ResourceExhausted},
+		{name: "Не хватает монет", sendErr: models.ErrNotEnoughCoins, wantCode: codes.InvalidArgument},
+		{name: "Получатель деактивирован", sendErr: models.ErrRecipientInactive, wantCode: codes.InvalidArgument},
+		{name: "Ошибка БД", sendErr: errors.New("db is down"), wantCode: codes.Internal, wantMsg: apperr.ErrDatabase.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mTxSvc := mocks.NewTransactionService(t)
+			mTxSvc.On("GetIDRecipient", mock.Anything, "otherUser").Return(2, nil)
+			mTxSvc.On("GetSenderCoins", mock.Anything, 1).Return(250, nil)
+			mTxSvc.On("SendCoinsToUser", mock.Anything, 1, 2, 50).Return(tt.sendErr)
+
+			client := newClient(t, &Services{Transaction: mTxSvc})
+			_, err := client.SendCoins(authorized(validToken), &pb.SendCoinsRequest{ToUser: "otherUser", Amount: 50})
+
+			require.Equal(t, tt.wantCode, status.Code(err), err)
+			if tt.wantMsg != "" {
+				require.Equal(t, tt.wantMsg, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+// TestMerchShop_BuyItem проверяет покупку товара варианта по умолчанию.
+func TestMerchShop_BuyItem(t *testing.T) {
+	mBuySvc := mocks.NewBuyItemService(t)
+	mBuySvc.On("BuyItem", mock.Anything, 1, "t-shirt", &models.Order{}).
+		Return(&models.Quote{ListPrice: 80, Price: 80, Variant: "t-shirt"}, nil)
+	mBuySvc.On("BuyItem", mock.Anything, 1, "t-shirt", &models.Order{Variant: "t-shirt-xl"}).
+		Return(nil, models.ErrVariantNotFound)
+
+	client := newClient(t, &Services{BuyItem: mBuySvc})
+	resp, err := client.BuyItem(authorized(validToken), &pb.BuyItemRequest{Item: "t-shirt"})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 80, resp.GetPrice())
+	require.Equal(t, "t-shirt", resp.GetVariant())
+
+	_, err = client.BuyItem(authorized(validToken), &pb.BuyItemRequest{Item: "t-shirt", Variant: "t-shirt-xl"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestMerchShop_Catalog проверяет получение каталога с отметкой товаров из списка желаний.
+func TestMerchShop_Catalog(t *testing.T) {
+	stock := 3
+	items := &[]models.Item{
+		{Slug: "cup", Title: "Cup", Price: 20, Stock: &stock, InWishlist: true},
+		{Slug: "pen", Title: "Pen", Price: 10},
+	}
+
+	mBuySvc := mocks.NewBuyItemService(t)
+	mBuySvc.On("GetCatalog", mock.Anything, 1).Return(items, nil)
+
+	client := newClient(t, &Services{BuyItem: mBuySvc})
+	resp, err := client.Catalog(authorized(validToken), &pb.CatalogRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetItems(), 2)
+	require.Equal(t, "cup", resp.GetItems()[0].GetSlug())
+	require.EqualValues(t, 3, resp.GetItems()[0].GetStock())
+	require.True(t, resp.GetItems()[0].GetInWishlist())
+	require.Nil(t, resp.GetItems()[1].Stock)
+}
@@ -0,0 +1,19 @@
+package hasher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToken(t *testing.T) {
+	token1, hash1, err := NewToken()
+	require.NoError(t, err)
+	token2, _, err := NewToken()
+	require.NoError(t, err)
+
+	require.Len(t, token1, 32)
+	require.NotEqual(t, token1, token2, "Tokens must be unique")
+	require.Equal(t, HashToken(token1), hash1)
+	require.NotEqual(t, HashToken(token2), hash1)
+}
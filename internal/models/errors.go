@@ -0,0 +1,119 @@
+package models
+
+import "github.com/kk7453603/avito_2024_summer/internal/apperr"
+
+// The domain errors, their codes are a part of the API and must not change.
+var (
+	// ErrNoReturnablePurchase is returned when the user has no purchase of the item inside the return window.
+	ErrNoReturnablePurchase = apperr.New(apperr.KindInvalid, "no_returnable_purchase", "no purchase of this item can be returned")
+	// ErrItemNotOwned is returned when the user's inventory doesn't hold the item anymore.
+	ErrItemNotOwned = apperr.New(apperr.KindConflict, "item_not_owned", "the item is not in the inventory")
+	// ErrNotEnoughItems is returned when the user owns fewer units of the item than requested.
+	ErrNotEnoughItems = apperr.New(apperr.KindInvalid, "not_enough_items", "you don't have enough items")
+	// ErrRecipientNotFound is returned when the recipient username doesn't exist.
+	ErrRecipientNotFound = apperr.New(apperr.KindInvalid, "recipient_not_found", "`toUser` is not found")
+	// ErrNotEnoughCoins is returned when the user's balance doesn't cover the operation.
+	ErrNotEnoughCoins = apperr.New(apperr.KindInvalid, "not_enough_coins", "you don't have enough coins")
+	// ErrSoldOut is returned when the item has no stock left.
+	ErrSoldOut = apperr.New(apperr.KindConflict, "sold_out", "the item is sold out")
+	// ErrLimitReached is returned when the user has already bought the maximum allowed units of the item.
+	ErrLimitReached = apperr.New(apperr.KindConflict, "limit_reached", "the purchase limit for the item is reached")
+	// ErrInvalidPromoCode is returned when the promo code doesn't exist, is inactive or doesn't apply to the item.
+	ErrInvalidPromoCode = apperr.New(apperr.KindInvalid, "invalid_promo_code", "the promo code is not valid for the item")
+	// ErrPromoCodeUsed is returned when the promo code has been used up or already used by the user.
+	ErrPromoCodeUsed = apperr.New(apperr.KindInvalid, "promo_code_used", "the promo code has already been used")
+	// ErrPromoCodeExists is returned when a promo code with the same code already exists.
+	ErrPromoCodeExists = apperr.New(apperr.KindConflict, "promo_code_exists", "the promo code already exists")
+	// ErrDiscountNotFound is returned when the discount doesn't exist.
+	ErrDiscountNotFound = apperr.New(apperr.KindNotFound, "discount_not_found", "discount not found")
+	// ErrInvalidDiscount is returned when the discount or promo code settings are inconsistent.
+	ErrInvalidDiscount = apperr.New(apperr.KindInvalid, "invalid_discount", "a discount must be limited to either an item or a category, percentages can't exceed 100")
+	// ErrItemNotFound is returned when the item doesn't exist in the store.
+	ErrItemNotFound = apperr.New(apperr.KindNotFound, "item_not_found", "item not found")
+	// ErrVariantNotFound is returned when the item has no variant with the given SKU.
+	ErrVariantNotFound = apperr.New(apperr.KindNotFound, "variant_not_found", "variant not found")
+	// ErrVariantExists is returned when a variant with the same SKU already exists.
+	ErrVariantExists = apperr.New(apperr.KindConflict, "variant_exists", "the variant already exists")
+	// ErrPurchaseNotFound is returned when the purchase doesn't exist.
+	ErrPurchaseNotFound = apperr.New(apperr.KindNotFound, "purchase_not_found", "purchase not found")
+	// ErrInvalidPurchaseStatus is returned when the fulfilment status is unknown.
+	ErrInvalidPurchaseStatus = apperr.New(apperr.KindInvalid, "invalid_purchase_status", "unknown purchase status")
+	// ErrInvalidStatusTransition is returned when the purchase can't be moved from its current status to the requested one.
+	ErrInvalidStatusTransition = apperr.New(apperr.KindConflict, "invalid_status_transition", "the purchase can't be moved to this status")
+	// ErrInvalidLeaderboard is returned when the leaderboard ranking or period is unknown.
+	ErrInvalidLeaderboard = apperr.New(apperr.KindInvalid, "invalid_leaderboard", "`by` must be coins or items, `period` must be week, month or all, items are ranked all-time only")
+	// ErrGoalNotFound is returned when the user has no savings goal with the given ID.
+	ErrGoalNotFound = apperr.New(apperr.KindNotFound, "goal_not_found", "savings goal not found")
+	// ErrGoalExists is returned when the user already saves for the item.
+	ErrGoalExists = apperr.New(apperr.KindConflict, "goal_exists", "a savings goal for the item already exists")
+	// ErrScheduledTransferNotFound is returned when the user has no active scheduled transfer with the given ID.
+	ErrScheduledTransferNotFound = apperr.New(apperr.KindNotFound, "scheduled_transfer_not_found", "scheduled transfer not found")
+	// ErrInvalidTransferSchedule is returned when a scheduled transfer has neither a future run time nor a valid schedule.
+	ErrInvalidTransferSchedule = apperr.New(apperr.KindInvalid, "invalid_transfer_schedule", "either `runAt` in the future or a valid cron `schedule` must be given")
+	// ErrPolicyNotFound is returned when the balance policy is unknown or disabled.
+	ErrPolicyNotFound = apperr.New(apperr.KindNotFound, "policy_not_found", "the policy is unknown or disabled")
+	// ErrSelfRecipient is returned when the user addresses an operation to themselves.
+	ErrSelfRecipient = apperr.New(apperr.KindInvalid, "self_recipient", "`toUser` must not be yourself")
+	// ErrTransferLimitExceeded is returned when the transfer exceeds an outgoing cap.
+	ErrTransferLimitExceeded = apperr.New(apperr.KindForbidden, "transfer_limit_exceeded", "the transfer exceeds the outgoing limit and is sent for review")
+	// ErrTransferTooFrequent is returned when the sender makes too many transfers in a short time.
+	ErrTransferTooFrequent = apperr.New(apperr.KindTooManyRequests, "transfer_too_frequent", "too many transfers in a short time, the transfer is sent for review")
+	// ErrFreshAccountTransfer is returned when coins are sent between two freshly registered accounts.
+	ErrFreshAccountTransfer = apperr.New(apperr.KindForbidden, "fresh_account_transfer", "transfers between freshly registered accounts are not allowed, the transfer is sent for review")
+	// ErrTransferReviewNotFound is returned when there is no open review of the flagged transfer.
+	ErrTransferReviewNotFound = apperr.New(apperr.KindNotFound, "transfer_review_not_found", "open transfer review not found")
+	// ErrInvalidReviewStatus is returned when the status of a transfer review is unknown.
+	ErrInvalidReviewStatus = apperr.New(apperr.KindInvalid, "invalid_review_status", "`status` must be open, approved or rejected")
+	// ErrInvalidBatch is returned when the batch transfer can't be split between its recipients.
+	ErrInvalidBatch = apperr.New(apperr.KindInvalid, "invalid_batch", "either `total` or an `amount` for every recipient must be given, recipients must not repeat and get at least 1 coin")
+	// ErrPayerNotFound is returned when the user asked for coins doesn't exist.
+	ErrPayerNotFound = apperr.New(apperr.KindInvalid, "payer_not_found", "`fromUser` is not found")
+	// ErrSelfPayer is returned when the user asks themselves for coins.
+	ErrSelfPayer = apperr.New(apperr.KindInvalid, "self_payer", "`fromUser` must not be yourself")
+	// ErrCoinRequestNotFound is returned when there is no pending coin request the user can act on.
+	ErrCoinRequestNotFound = apperr.New(apperr.KindNotFound, "coin_request_not_found", "pending coin request not found")
+	// ErrCoinRequestUnderReview is returned when the payment of the coin request is held for review already.
+	ErrCoinRequestUnderReview = apperr.New(apperr.KindConflict, "coin_request_under_review", "the payment of the coin request is held for review")
+	// ErrTeamNotFound is returned when the team doesn't exist or the user isn't its member.
+	ErrTeamNotFound = apperr.New(apperr.KindNotFound, "team_not_found", "team not found")
+	// ErrTeamExists is returned when the team name is taken.
+	ErrTeamExists = apperr.New(apperr.KindConflict, "team_exists", "a team with this name already exists")
+	// ErrNotTeamOwner is returned when a member tries an action allowed to the team owners only.
+	ErrNotTeamOwner = apperr.New(apperr.KindForbidden, "not_team_owner", "only the team owners can do this")
+	// ErrLastTeamOwner is returned when the last owner of the team is removed or demoted.
+	ErrLastTeamOwner = apperr.New(apperr.KindConflict, "last_team_owner", "the team must keep at least one owner")
+	// ErrUserNotFound is returned when the user named in the request doesn't exist.
+	ErrUserNotFound = apperr.New(apperr.KindNotFound, "user_not_found", "user not found")
+	// ErrTeamMemberNotFound is returned when the user isn't a member of the team.
+	ErrTeamMemberNotFound = apperr.New(apperr.KindNotFound, "team_member_not_found", "the user is not a member of the team")
+	// ErrTeamSpendLimit is returned when the spend exceeds the member's spending limit.
+	ErrTeamSpendLimit = apperr.New(apperr.KindForbidden, "team_spend_limit", "the spend exceeds your spending limit for the team wallet")
+	// ErrNotEnoughTeamCoins is returned when the team wallet is short of coins.
+	ErrNotEnoughTeamCoins = apperr.New(apperr.KindInvalid, "not_enough_team_coins", "the team wallet doesn't have enough coins")
+	// ErrTeamSpendNotFound is returned when there is no pending spend of the team.
+	ErrTeamSpendNotFound = apperr.New(apperr.KindNotFound, "team_spend_not_found", "pending team spend not found")
+	// ErrWebhookNotFound is returned when there is no webhook subscription with the given ID.
+	ErrWebhookNotFound = apperr.New(apperr.KindNotFound, "webhook_not_found", "webhook subscription not found")
+	// ErrWebhookDeliveryNotFound is returned when there is no finished webhook delivery with the given ID.
+	ErrWebhookDeliveryNotFound = apperr.New(apperr.KindNotFound, "webhook_delivery_not_found", "webhook delivery not found or still pending")
+	// ErrInvalidPassword is returned when the password doesn't match the user's one.
+	ErrInvalidPassword = apperr.New(apperr.KindUnauthorized, "invalid_password", "invalid password")
+	// ErrTooManyStreams is returned when the user has too many event streams open.
+	ErrTooManyStreams = apperr.New(apperr.KindTooManyRequests, "too_many_streams", "too many event streams open")
+	// ErrAccountLocked is returned when the user's account is locked by the support staff.
+	ErrAccountLocked = apperr.New(apperr.KindForbidden, "account_locked", "the account is locked")
+	// ErrItemExists is returned when an item with the same slug already exists.
+	ErrItemExists = apperr.New(apperr.KindConflict, "item_exists", "an item with this slug already exists")
+	// ErrInvalidGrant is returned when a grant of coins has no positive amount or no reason.
+	ErrInvalidGrant = apperr.New(apperr.KindInvalid, "invalid_grant", "a grant must have a positive amount and a reason")
+	// ErrAccountDeactivated is returned when the user's account is deactivated, as the user has left.
+	ErrAccountDeactivated = apperr.New(apperr.KindForbidden, "account_deactivated", "the account is deactivated")
+	// ErrRecipientInactive is returned when the coins are sent to a deactivated account.
+	ErrRecipientInactive = apperr.New(apperr.KindInvalid, "recipient_inactive", "the recipient's account is deactivated")
+	// ErrInvalidDirectory is returned when the import or the directory export can't be read or lists invalid users.
+	ErrInvalidDirectory = apperr.New(apperr.KindInvalid, "invalid_directory", "every user must have a valid username listed once and a grant of 0 or more coins, a directory export must not be empty")
+	// ErrInvalidClaimToken is returned when the first login to an imported account has no valid claim token.
+	ErrInvalidClaimToken = apperr.New(apperr.KindUnauthorized, "invalid_claim_token", "the account must be claimed with the token issued with it")
+	// ErrAccountClaimed is returned when a claim token is requested for an account which already has a password.
+	ErrAccountClaimed = apperr.New(apperr.KindConflict, "account_claimed", "the account has already been claimed")
+)
@@ -2,33 +2,106 @@ package models
 
 import "time"
 
+// Roles of users.
+const (
+	RoleEmployee = "employee" // regular user of the shop
+	RoleOperator = "operator" // hands out the purchased merch in an office
+	RoleAdmin    = "admin"    // manages the store: discounts, promo codes, etc.
+)
+
+// Kinds of entries in the transactions history.
+const (
+	TxKindTransfer = "transfer" // coins sent from one user to another
+	TxKindRefund   = "refund"   // coins returned by the shop for a returned item
+	TxKindGrant    = "grant"    // coins granted by the support staff, with a reason
+)
+
 type User struct {
-	ID        int       `json:"id" db:"id" binding:"required"`
-	Username  string    `json:"username" db:"username" binding:"required"`
-	Password  string    `json:"password" db:"password" binding:"required"`
-	Coins     int       `json:"coins" db:"coins" binding:"required"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" binding:"required"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" binding:"required"`
+	ID             int        `json:"id" db:"id" binding:"required"`
+	Username       string     `json:"username" db:"username" binding:"required"`
+	Password       string     `json:"password" db:"password" binding:"required"`
+	Coins          int        `json:"coins" db:"coins" binding:"required"`
+	Role           string     `json:"role" db:"role"`
+	LockedAt       *time.Time `json:"locked_at" db:"locked_at"`           // nil - the account isn't locked
+	DeactivatedAt  *time.Time `json:"deactivated_at" db:"deactivated_at"` // nil - the account is active
+	ClaimTokenHash *string    `json:"-" db:"claim_token_hash"`            // the hash of the imported account's claim token, nil once claimed
+	CreatedAt      time.Time  `json:"created_at" db:"created_at" binding:"required"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at" binding:"required"`
+}
+
+// Account is the user's account as the support staff sees it, without the password.
+type Account struct {
+	ID               int        `json:"id" db:"id"`
+	Username         string     `json:"username" db:"username"`
+	Role             string     `json:"role" db:"role"`
+	Coins            int        `json:"coins" db:"coins"`
+	Saved            int        `json:"saved" db:"saved"` // coins put aside in the savings goals
+	LockedAt         *time.Time `json:"lockedAt" db:"locked_at"`
+	DeactivatedAt    *time.Time `json:"deactivatedAt" db:"deactivated_at"`       // the user has left
+	DirectoryManaged bool       `json:"directoryManaged" db:"directory_managed"` // synced with the HR directory
+	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// Profile is the user's name and balance.
+type Profile struct {
+	Username string `json:"username"`
+	Coins    int    `json:"coins"`
 }
 
 type Login struct {
-	Username string `json:"username" binding:"required,min=8,alphanum"`
-	Password string `json:"password" binding:"required,min=8"`
+	Username   string `json:"username" binding:"required,min=8,alphanum"`
+	Password   string `json:"password" binding:"required,min=8"`
+	ClaimToken string `json:"claimToken"` // required by the first login to an account imported from the HR directory
 }
 
 type Merch struct {
 	Type     string `json:"type" db:"item_slug"`
+	Variant  string `json:"variant" db:"sku"`
 	Quantity int    `json:"quantity" db:"quantity"`
 }
 
 type Receiving struct {
-	User   string `json:"fromUser" db:"username"`
-	Amount int    `json:"amount" db:"coins"`
+	User    string `json:"fromUser" db:"username"`
+	Amount  int    `json:"amount" db:"coins"`
+	Kind    string `json:"type" db:"kind"`
+	BatchID *int   `json:"batchId,omitempty" db:"batch_id"` // the transfer is a leg of a batch
 }
 
 type Sending struct {
-	User   string `json:"toUser" db:"username" binding:"required,min=8,alphanum"`
-	Amount int    `json:"amount" db:"coins" binding:"required,gte=1"`
+	User    string  `json:"toUser" db:"username" binding:"required,min=8,alphanum"`
+	Amount  int     `json:"amount" db:"coins" binding:"required,gte=1"`
+	Kind    string  `json:"type,omitempty" db:"kind"`        // transfer or a write-off by a balance policy
+	Reason  *string `json:"reason,omitempty" db:"reason"`    // reason of the write-off
+	BatchID *int    `json:"batchId,omitempty" db:"batch_id"` // the transfer is a leg of a batch
+}
+
+type BatchLeg struct {
+	User   string `json:"toUser" binding:"required,min=8,alphanum"`
+	Amount int    `json:"amount" binding:"omitempty,gte=1"`
+}
+
+// BatchSending is a transfer to several recipients, either each amount or the total split evenly is given.
+type BatchSending struct {
+	Recipients []BatchLeg `json:"recipients" binding:"required,min=1,max=100,dive"`
+	Total      int        `json:"total" binding:"omitempty,gte=1"`
+}
+
+// BatchTransfer is a leg of a batch transfer with the resolved recipient.
+type BatchTransfer struct {
+	ReceiverID int
+	Amount     int
+}
+
+type BatchLegResult struct {
+	User   string `json:"toUser"`
+	Amount int    `json:"amount"`
+	Error  string `json:"error,omitempty"` // the leg that stopped the batch
+}
+
+// BatchResult is the outcome of a batch transfer, either all the legs are sent or none of them.
+type BatchResult struct {
+	BatchID int              `json:"batchId,omitempty"`
+	Results []BatchLegResult `json:"results"`
 }
 
 type CoinHistory struct {
@@ -36,8 +109,667 @@ type CoinHistory struct {
 	Sending   *[]Sending   `json:"sent"`
 }
 
+type Gift struct {
+	User     string `json:"toUser" binding:"required,min=8,alphanum"`
+	Item     string `json:"item" binding:"required"`
+	Variant  string `json:"variant"` // empty - the default variant of the item
+	Quantity int    `json:"quantity" binding:"required,gte=1"`
+}
+
+type GiftReceiving struct {
+	User     string `json:"fromUser" db:"username"`
+	Item     string `json:"item" db:"item_slug"`
+	Variant  string `json:"variant" db:"sku"`
+	Quantity int    `json:"quantity" db:"quantity"`
+}
+
+type GiftSending struct {
+	User     string `json:"toUser" db:"username"`
+	Item     string `json:"item" db:"item_slug"`
+	Variant  string `json:"variant" db:"sku"`
+	Quantity int    `json:"quantity" db:"quantity"`
+}
+
+type GiftHistory struct {
+	Receiving *[]GiftReceiving `json:"received"`
+	Sending   *[]GiftSending   `json:"sent"`
+}
+
 type Item struct {
-	Slug  string `json:"slug" db:"slug"`
-	Title string `json:"title" db:"title"`
-	Price int    `json:"price" db:"price"`
+	Slug         string    `json:"slug" db:"slug"`
+	Title        string    `json:"title" db:"title"`
+	Price        int       `json:"price" db:"price"`
+	Category     *string   `json:"category" db:"category"`
+	Stock        *int      `json:"stock" db:"stock"`                 // total of all variants, nil - unlimited
+	PerUserLimit *int      `json:"perUserLimit" db:"per_user_limit"` // nil - unlimited
+	SalePrice    *int      `json:"salePrice,omitempty" db:"-"`       // price with the active discount, if any
+	Variants     []Variant `json:"variants" db:"-"`
+	InWishlist   bool      `json:"inWishlist" db:"-"` // the item is in the wishlist of the user viewing the catalog
+}
+
+// ItemUpdate is a partial update of an item of the store, the fields left nil aren't changed.
+type ItemUpdate struct {
+	Title        *string `json:"title,omitempty"`
+	Price        *int    `json:"price,omitempty"`
+	Category     *string `json:"category,omitempty"`
+	PerUserLimit *int    `json:"perUserLimit,omitempty"`
+}
+
+// Variant is a sellable version of an item (size, colour) with its own stock and, optionally, price.
+// Every item has exactly one default variant, it's sold when no variant is chosen.
+type Variant struct {
+	SKU           string  `json:"sku" db:"sku" binding:"required,max=255"`
+	ItemSlug      string  `json:"item" db:"item_slug"`
+	Title         string  `json:"title" db:"title" binding:"required,max=255"`
+	Size          *string `json:"size" db:"size" binding:"omitempty,max=32"`
+	Color         *string `json:"color" db:"color" binding:"omitempty,max=64"`
+	PriceOverride *int    `json:"priceOverride" db:"price_override" binding:"omitempty,gte=0"` // nil - the item's price
+	Stock         *int    `json:"stock" db:"stock" binding:"omitempty,gte=0"`                  // nil - unlimited
+	IsDefault     bool    `json:"isDefault" db:"is_default"`
+	SalePrice     *int    `json:"salePrice,omitempty" db:"-"` // price with the active discount, if any
+}
+
+type Restock struct {
+	Quantity int `json:"quantity" binding:"required,gte=1"`
+}
+
+// Kinds of notifications.
+const (
+	NotificationBackInStock = "back_in_stock" // an item from the wishlist can be bought again
+	NotificationDiscount    = "discount"      // an item from the wishlist goes on sale
+
+	NotificationTransferSkipped = "transfer_skipped" // a scheduled transfer is skipped for lack of coins
+	NotificationTransferFailed  = "transfer_failed"  // a scheduled transfer failed after all retries
+	NotificationPolicyNotice    = "policy_notice"    // coins are going to be written off by a balance policy
+)
+
+type Notification struct {
+	ID        int       `json:"id" db:"id"`
+	Kind      string    `json:"type" db:"kind"`
+	Item      *string   `json:"item" db:"item_slug"`
+	Message   string    `json:"message" db:"message"`
+	Read      bool      `json:"read" db:"read"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Kinds of discounts and promo codes.
+const (
+	DiscountPercent = "percent" // the value is a percentage of the price
+	DiscountFixed   = "fixed"   // the value is a number of coins
+)
+
+type Discount struct {
+	ID       int       `json:"id" db:"id"`
+	ItemSlug *string   `json:"item" db:"item_slug"`
+	Category *string   `json:"category" db:"category"`
+	Kind     string    `json:"kind" db:"kind" binding:"required,oneof=percent fixed"`
+	Value    int       `json:"value" db:"value" binding:"required,gte=1"`
+	StartsAt time.Time `json:"startsAt" db:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"endsAt" db:"ends_at" binding:"required,gtfield=StartsAt"`
+}
+
+type PromoCode struct {
+	Code     string     `json:"code" db:"code" binding:"required,max=64,alphanum"`
+	Kind     string     `json:"kind" db:"kind" binding:"required,oneof=percent fixed"`
+	Value    int        `json:"value" db:"value" binding:"required,gte=1"`
+	ItemSlug *string    `json:"item" db:"item_slug"`
+	Category *string    `json:"category" db:"category"`
+	MaxUses  *int       `json:"maxUses" db:"max_uses" binding:"omitempty,gte=1"` // nil - unlimited, 1 - single-use
+	Used     int        `json:"used" db:"used"`
+	StartsAt *time.Time `json:"startsAt" db:"starts_at"`
+	EndsAt   *time.Time `json:"endsAt" db:"ends_at"`
+}
+
+// Order holds the buyer's choices for a purchase of an item.
+type Order struct {
+	Variant   string // empty - the default variant of the item
+	PromoCode string // empty - no promo code
+	Office    string // office to pick the item up in, empty - not chosen yet
+}
+
+// Fulfilment statuses of purchases.
+const (
+	PurchasePending        = "pending"          // paid, waiting to be prepared
+	PurchaseReadyForPickup = "ready_for_pickup" // prepared in the office
+	PurchaseHandedOver     = "handed_over"      // given to the buyer
+	PurchaseCancelled      = "cancelled"        // cancelled or returned, the money is refunded
+)
+
+type Purchase struct {
+	ID              int       `json:"id" db:"id"`
+	User            string    `json:"user,omitempty" db:"username"`
+	Item            string    `json:"item" db:"item_slug"`
+	Variant         string    `json:"variant" db:"sku"`
+	Price           int       `json:"price" db:"price"`
+	Status          string    `json:"status" db:"status"`
+	Office          *string   `json:"office" db:"office"`
+	CreatedAt       time.Time `json:"createdAt" db:"created_at"`
+	StatusChangedAt time.Time `json:"statusChangedAt" db:"status_changed_at"`
+}
+
+// PurchaseCreation is an order of an item in API v2, the optional fields are empty if not chosen.
+type PurchaseCreation struct {
+	Item      string `json:"item" binding:"required"`
+	Variant   string `json:"variant"`
+	PromoCode string `json:"promoCode"`
+	Office    string `json:"office"`
+}
+
+type PurchaseStatusChange struct {
+	Status string `json:"status" binding:"required,oneof=pending ready_for_pickup handed_over cancelled"`
+}
+
+// Leaderboard rankings and periods.
+const (
+	LeaderboardByCoins = "coins" // coins received from other users
+	LeaderboardByItems = "items" // items held in the inventory
+
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodAllTime = "all"
+)
+
+type LeaderboardEntry struct {
+	Rank  int    `json:"rank" db:"rank"`
+	User  string `json:"user" db:"username"`
+	Value int    `json:"value" db:"value"`
+}
+
+type LeaderboardVisibility struct {
+	Visible *bool `json:"visible" binding:"required"`
+}
+
+// SavingsGoal holds the coins the user sets aside for an item, they aren't spendable until released.
+type SavingsGoal struct {
+	ID     int    `json:"id" db:"id"`
+	Item   string `json:"item" db:"item_slug"`
+	Saved  int    `json:"saved" db:"saved"`
+	Target int    `json:"target" db:"target"` // current price of the item
+}
+
+type SavingsGoalCreation struct {
+	Item string `json:"item" binding:"required"`
+}
+
+type SavingsDeposit struct {
+	Amount int `json:"amount" binding:"required,gte=1"`
+}
+
+// Statuses of scheduled transfers.
+const (
+	TransferActive    = "active"    // waiting for the next run
+	TransferCompleted = "completed" // the one-off transfer is made
+	TransferSkipped   = "skipped"   // the one-off transfer is skipped for lack of coins
+	TransferFailed    = "failed"    // the one-off transfer failed after all retries
+	TransferCancelled = "cancelled" // cancelled by the sender
+)
+
+type ScheduledTransfer struct {
+	ID         int       `json:"id" db:"id"`
+	SenderID   int       `json:"-" db:"sender_id"`
+	ReceiverID int       `json:"-" db:"receiver_id"`
+	Recipient  string    `json:"toUser" db:"username"`
+	Amount     int       `json:"amount" db:"coins"`
+	Schedule   *string   `json:"schedule" db:"schedule"` // nil - a one-off transfer
+	NextRunAt  time.Time `json:"nextRunAt" db:"next_run_at"`
+	Status     string    `json:"status" db:"status"`
+	Attempts   int       `json:"attempts" db:"attempts"`
+	LastError  *string   `json:"lastError" db:"last_error"`
+}
+
+type ScheduledTransferCreation struct {
+	User     string     `json:"toUser" binding:"required,min=8,alphanum"`
+	Amount   int        `json:"amount" binding:"required,gte=1"`
+	RunAt    *time.Time `json:"runAt"`
+	Schedule *string    `json:"schedule" binding:"omitempty,max=64"`
+}
+
+// TransferOutcome is the state of a scheduled transfer after its run.
+type TransferOutcome struct {
+	Status       string
+	NextRunAt    time.Time
+	Attempts     int
+	LastError    *string
+	Notification *Notification // the sender is notified, nil - not notified
+}
+
+// Balance policies.
+const (
+	PolicyExpiry     = "expiry"      // the coins granted long ago expire
+	PolicyBalanceCap = "balance_cap" // the balance above the cap is written off
+)
+
+// Policy is a run of a balance policy.
+type Policy struct {
+	Name     string
+	RunAt    time.Time
+	NoticeAt time.Time // the affected users are notified from this time on
+	Cutoff   time.Time // expiry: the coins granted before it expire
+	Cap      int       // balance_cap: the maximum balance
+	Reason   string    // recorded with each write-off
+}
+
+type PolicyAction struct {
+	UserID  int    `json:"-" db:"user_id"`
+	User    string `json:"user" db:"username"`
+	Balance int    `json:"balance" db:"coins"`
+	Amount  int    `json:"amount" db:"amount"` // coins to write off
+}
+
+type PolicyRun struct {
+	Policy    string     `json:"policy" db:"policy"`
+	RunAt     time.Time  `json:"runAt" db:"run_at"`
+	NoticedAt *time.Time `json:"noticedAt" db:"noticed_at"`
+	AppliedAt *time.Time `json:"appliedAt" db:"applied_at"`
+	Affected  int        `json:"affected" db:"affected"`
+	Coins     int        `json:"coins" db:"coins"`
+}
+
+// PolicyReport lists the users a policy run would affect.
+type PolicyReport struct {
+	Policy   string          `json:"policy"`
+	RunAt    time.Time       `json:"runAt"`
+	Reason   string          `json:"reason"`
+	Affected *[]PolicyAction `json:"affected"`
+}
+
+// Anti-fraud rules on coin transfers.
+const (
+	RuleDailyCap      = "daily_cap"      // the coins sent within a day are capped
+	RuleWeeklyCap     = "weekly_cap"     // the coins sent within a week are capped
+	RuleRecipientCap  = "recipient_cap"  // the coins sent to one recipient within a week are capped
+	RuleVelocity      = "velocity"       // too many transfers within a short window
+	RuleFreshAccounts = "fresh_accounts" // both the sender and the recipient have just registered
+)
+
+// TransferStats holds the sender's recent outgoing transfers the anti-fraud rules are checked against.
+type TransferStats struct {
+	SentDay            int       `db:"sent_day"`
+	SentWeek           int       `db:"sent_week"`
+	SentToRecipient    int       `db:"sent_to_recipient"` // within a week
+	RecentTransfers    int       `db:"recent_transfers"`  // within the velocity window
+	SenderCreatedAt    time.Time `db:"sender_created_at"`
+	RecipientCreatedAt time.Time `db:"recipient_created_at"`
+}
+
+// TransferRules are the anti-fraud rules a transfer is checked against inside the transaction making it.
+// Check returns the name and the error of the first rule the transfer of the coins breaks, if any.
+type TransferRules struct {
+	Window time.Duration // the velocity window the recent transfers are counted in
+	Check  func(stats *TransferStats, coins int) (string, error)
+}
+
+// BatchLegError is the error of the batch transfer's leg stopping the whole batch.
+type BatchLegError struct {
+	Leg int // the index of the leg
+	Err error
+}
+
+func (e *BatchLegError) Error() string { return e.Err.Error() }
+
+func (e *BatchLegError) Unwrap() error { return e.Err }
+
+// Statuses of flagged transfers.
+const (
+	ReviewOpen     = "open"     // waiting for a decision
+	ReviewApproved = "approved" // the transfer is made
+	ReviewRejected = "rejected" // the transfer is dismissed
+)
+
+// TransferReview is a transfer blocked by an anti-fraud rule and queued for review.
+type TransferReview struct {
+	ID          int `json:"id" db:"id"`
+	InitiatorID int `json:"-" db:"initiator_id"`
+	// the owners of the accounts the transfer moves the coins between, either a user or a team each
+	SenderID       *int       `json:"-" db:"sender_id"`
+	SenderTeamID   *int       `json:"-" db:"sender_team_id"`
+	ReceiverID     *int       `json:"-" db:"receiver_id"`
+	ReceiverTeamID *int       `json:"-" db:"receiver_team_id"`
+	Initiator      string     `json:"initiator" db:"initiator"`
+	Sender         string     `json:"fromUser" db:"sender"`
+	Recipient      string     `json:"toUser" db:"recipient"`
+	Amount         int        `json:"amount" db:"coins"`
+	Rule           string     `json:"rule" db:"rule"`
+	Status         string     `json:"status" db:"status"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewedAt" db:"reviewed_at"`
+	// the coin request the transfer pays, approving the review accepts it
+	CoinRequestID *int `json:"coinRequestId,omitempty" db:"coin_request_id"`
+	// the held spend from the team wallet, approving the review makes it
+	TeamSpendID *int `json:"teamSpendId,omitempty" db:"team_spend_id"`
+}
+
+type TransferReviewDecision struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+}
+
+// Statuses of coin requests.
+const (
+	CoinRequestPending   = "pending"   // waiting for the payer
+	CoinRequestAccepted  = "accepted"  // the payer has sent the coins
+	CoinRequestDeclined  = "declined"  // declined by the payer
+	CoinRequestCancelled = "cancelled" // withdrawn by the requester
+	CoinRequestExpired   = "expired"   // not answered in time
+)
+
+// CoinRequest is a request of coins from a colleague, the payer accepts or declines it.
+type CoinRequest struct {
+	ID          int                 `json:"id" db:"id"`
+	RequesterID int                 `json:"-" db:"requester_id"`
+	PayerID     int                 `json:"-" db:"payer_id"`
+	Requester   string              `json:"requester" db:"requester"`
+	Payer       string              `json:"payer" db:"payer"`
+	Amount      int                 `json:"amount" db:"coins"`
+	Note        *string             `json:"note" db:"note"`
+	Status      string              `json:"status" db:"status"`
+	ExpiresAt   time.Time           `json:"expiresAt" db:"expires_at"`
+	CreatedAt   time.Time           `json:"createdAt" db:"created_at"`
+	History     *[]CoinRequestEvent `json:"history,omitempty" db:"-"`
+}
+
+type CoinRequestCreation struct {
+	User   string  `json:"fromUser" binding:"required,min=8,alphanum"`
+	Amount int     `json:"amount" binding:"required,gte=1"`
+	Note   *string `json:"note" binding:"omitempty,max=255"`
+}
+
+// CoinRequestEvent is a change of the coin request's status.
+type CoinRequestEvent struct {
+	Status    string    `json:"status" db:"status"`
+	Actor     *string   `json:"actor" db:"actor"` // nil - changed by the system
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// CoinRequests lists the pending requests the user is asked to pay and the user's own requests.
+type CoinRequests struct {
+	Incoming *[]CoinRequest `json:"incoming"`
+	Outgoing *[]CoinRequest `json:"outgoing"`
+}
+
+// Roles of team members.
+const (
+	TeamRoleOwner  = "owner"  // manages the members and approves the spends above the threshold
+	TeamRoleMember = "member" // spends from the wallet within the limit
+)
+
+// Team is a team with a shared wallet.
+type Team struct {
+	ID                int           `json:"id" db:"id"`
+	Name              string        `json:"name" db:"name"`
+	Coins             int           `json:"coins" db:"coins"`
+	ApprovalThreshold *int          `json:"approvalThreshold" db:"approval_threshold"` // nil - no approval needed
+	Role              string        `json:"role" db:"role"`                            // of the user asking
+	Members           *[]TeamMember `json:"members,omitempty" db:"-"`
+}
+
+type TeamMember struct {
+	UserID     int    `json:"-" db:"user_id"`
+	User       string `json:"user" db:"username"`
+	Role       string `json:"role" db:"role"`
+	SpendLimit *int   `json:"spendLimit" db:"spend_limit"` // within 30 days, nil - unlimited
+	Spent      int    `json:"spent" db:"spent"`            // within 30 days, including the spends waiting for approval
+}
+
+type TeamCreation struct {
+	Name              string `json:"name" binding:"required,max=64"`
+	ApprovalThreshold *int   `json:"approvalThreshold" binding:"omitempty,gte=1"`
+}
+
+type TeamMembership struct {
+	Role       string `json:"role" binding:"required,oneof=owner member"`
+	SpendLimit *int   `json:"spendLimit" binding:"omitempty,gte=0"`
+}
+
+type TeamDeposit struct {
+	Amount int `json:"amount" binding:"required,gte=1"`
+}
+
+type TeamSpending struct {
+	User   string `json:"toUser" binding:"required,min=8,alphanum"`
+	Amount int    `json:"amount" binding:"required,gte=1"`
+}
+
+// Statuses of spends from a team wallet.
+const (
+	SpendSent     = "sent"     // made right away
+	SpendPending  = "pending"  // waiting for the owner's approval
+	SpendHeld     = "held"     // broke an anti-fraud rule, waiting for the review
+	SpendApproved = "approved" // made after the owner's approval
+	SpendRejected = "rejected" // rejected by the owner
+)
+
+// TeamSpend is a transfer from the team wallet made by a member.
+type TeamSpend struct {
+	ID         int        `json:"id" db:"id"`
+	TeamID     int        `json:"-" db:"team_id"`
+	MemberID   int        `json:"-" db:"member_id"`
+	ReceiverID int        `json:"-" db:"receiver_id"`
+	Member     string     `json:"member" db:"member"`
+	Recipient  string     `json:"toUser" db:"recipient"`
+	Amount     int        `json:"amount" db:"coins"`
+	Status     string     `json:"status" db:"status"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	DecidedAt  *time.Time `json:"decidedAt" db:"decided_at"`
+}
+
+type TeamSpendDecision struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+}
+
+// Sources of the entries of the coin ledger.
+const (
+	LedgerTransaction = "transaction" // a row of the transactions, seen by its sender or recipient
+	LedgerPurchase    = "purchase"    // a purchase, its kind is "purchase"
+)
+
+// LedgerEntry is a change of the user's coins in the finance export. A transfer between two users
+// is exported twice, once for each of them. Balance is the user's coins after the change,
+// including the coins put aside in the savings goals.
+type LedgerEntry struct {
+	At           time.Time `json:"at" db:"created_at"`
+	Source       string    `json:"source" db:"source"`
+	ID           int       `json:"id" db:"id"` // ID of the transaction or the purchase
+	Kind         string    `json:"kind" db:"kind"`
+	UserID       int       `json:"userId" db:"user_id"`
+	Username     string    `json:"username" db:"username"`
+	Counterparty *string   `json:"counterparty" db:"counterparty"` // the other user or team, nil - the shop
+	Amount       int       `json:"amount" db:"amount"`             // negative if the coins are spent
+	Balance      int       `json:"balance" db:"balance"`
+	Reason       *string   `json:"reason" db:"reason"`
+	Item         *string   `json:"item" db:"item"`
+	SKU          *string   `json:"sku" db:"sku"`
+}
+
+// DirectoryUser is an employee listed in the HR directory export or in a bulk import.
+type DirectoryUser struct {
+	Username       string `json:"username"`
+	Grant          int    `json:"grant"` // coins granted on top of the initial balance when the account is created
+	ClaimTokenHash string `json:"-"`     // the hash of the claim token the account is created with
+}
+
+// DirectorySync is the outcome of a bulk import or of a sync with the HR directory.
+type DirectorySync struct {
+	Created     []string          `json:"created"`
+	Adopted     []string          `json:"adopted"` // the existing accounts which have become synced with the directory
+	Reactivated []string          `json:"reactivated"`
+	Deactivated []string          `json:"deactivated"`
+	Unchanged   int               `json:"unchanged"`
+	ClaimTokens map[string]string `json:"claimTokens,omitempty"` // the one-time tokens of the created accounts by username
+}
+
+// Actions recorded in the audit log.
+const (
+	AuditLogin             = "auth.login"
+	AuditLoginFailed       = "auth.login_failed"
+	AuditTokenIssued       = "auth.token_issued"
+	AuditUserRegistered    = "user.registered"
+	AuditCoinsTransferred  = "coins.transferred"
+	AuditBatchTransferred  = "coins.batch_transferred"
+	AuditItemPurchased     = "item.purchased"
+	AuditPurchaseStatus    = "purchase.status_changed"
+	AuditDiscountCreated   = "discount.created"
+	AuditDiscountDeleted   = "discount.deleted"
+	AuditPromoCodeCreated  = "promo_code.created"
+	AuditVariantCreated    = "variant.created"
+	AuditVariantUpdated    = "variant.updated"
+	AuditVariantRestocked  = "variant.restocked"
+	AuditTransferReviewed  = "transfer_review.resolved"
+	AuditCoinRequestStatus = "coin_request.status_changed"
+	AuditTeamDeposited     = "team.deposited"
+	AuditTeamSpent         = "team_spend.created"
+	AuditTeamSpendResolved = "team_spend.resolved"
+	AuditTeamCreated       = "team.created"
+	AuditTeamMemberSet     = "team.member_set"
+	AuditTeamMemberRemoved = "team.member_removed"
+	AuditItemReturned      = "item.returned"
+	AuditItemGifted        = "item.gifted"
+	AuditCoinsWrittenOff   = "coins.written_off"
+	AuditGoalDeposited     = "goal.deposited"
+	AuditGoalClosed        = "goal.closed"
+	AuditWebhookCreated    = "webhook.created"
+	AuditWebhookUpdated    = "webhook.updated"
+	AuditWebhookDeleted    = "webhook.deleted"
+	AuditWebhookReplayed   = "webhook.replayed"
+	AuditCoinsGranted      = "coins.granted"
+	AuditInventoryAdjusted = "inventory.adjusted"
+	AuditItemCreated       = "item.created"
+	AuditItemUpdated       = "item.updated"
+	AuditUserLocked        = "user.locked"
+	AuditUserUnlocked      = "user.unlocked"
+	AuditUserImported      = "user.imported"
+	AuditUserAdopted       = "user.adopted"
+	AuditUserClaimed       = "user.claimed"
+	AuditClaimTokenIssued  = "user.claim_token_issued"
+	AuditUserDeactivated   = "user.deactivated"
+	AuditUserReactivated   = "user.reactivated"
+)
+
+// AuditEntry is a record of the append-only audit log. Each entry is chained to the previous one by its hash.
+type AuditEntry struct {
+	ID        int64     `json:"id" db:"id"`
+	ActorID   *int      `json:"actorId" db:"actor_id"` // nil - the system or an anonymous user
+	Actor     *string   `json:"actor" db:"actor"`
+	Action    string    `json:"action" db:"action"`
+	Target    string    `json:"target" db:"target"`   // kind:id of the changed object, e.g. user:42
+	Details   *string   `json:"details" db:"details"` // JSON
+	IP        string    `json:"ip" db:"ip"`
+	UserAgent string    `json:"userAgent" db:"user_agent"`
+	RequestID string    `json:"requestId" db:"request_id"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	Chain     int       `json:"chain" db:"chain"` // the hash chain the entry is appended to
+	PrevHash  string    `json:"prevHash" db:"prev_hash"`
+	Hash      string    `json:"hash" db:"hash"`
+}
+
+// AuditFilter selects the audit log entries, the empty fields match any entry.
+type AuditFilter struct {
+	Actor     string
+	Action    string
+	Target    string
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+	BeforeID  int64 // for paging through the log, 0 - from the latest entry
+	Limit     int
+}
+
+// AuditVerification is the result of checking the audit log's hash chain.
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt *int64 `json:"brokenAt,omitempty"` // the first entry not matching the chain
+}
+
+// Types of the events delivered to the webhooks.
+const (
+	EventCoinsReceived  = "coins.received"
+	EventMerchPurchased = "merch.purchased"
+)
+
+// WebhookSubscription is an endpoint the events are delivered to.
+type WebhookSubscription struct {
+	ID        int       `json:"id" db:"id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"secret,omitempty" db:"secret"` // shown once, when the subscription is created
+	Events    []string  `json:"events" db:"events"`           // empty - all events
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type WebhookSubscriptionCreation struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=128"` // generated if not given
+	Events []string `json:"events" binding:"dive,oneof=coins.received merch.purchased"`
+}
+
+type WebhookSubscriptionUpdate struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"dive,oneof=coins.received merch.purchased"`
+	Active *bool    `json:"active" binding:"required"`
+}
+
+// Statuses of webhook deliveries.
+const (
+	DeliveryPending   = "pending"   // waiting for the next attempt
+	DeliveryDelivered = "delivered" // accepted by the endpoint
+	DeliveryDead      = "dead"      // the attempts are exhausted, waiting to be replayed
+)
+
+// WebhookDelivery is the delivery of an outbox event to a subscription.
+type WebhookDelivery struct {
+	ID             int64      `json:"id" db:"id"`
+	EventID        int64      `json:"eventId" db:"event_id"`
+	SubscriptionID int        `json:"subscriptionId" db:"subscription_id"`
+	EventType      string     `json:"eventType" db:"event_type"`
+	Payload        string     `json:"-" db:"payload"` // JSON
+	EventCreatedAt time.Time  `json:"-" db:"event_created_at"`
+	URL            string     `json:"url" db:"url"`
+	Secret         string     `json:"-" db:"secret"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastError      *string    `json:"lastError" db:"last_error"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	DeliveredAt    *time.Time `json:"deliveredAt" db:"delivered_at"`
+}
+
+// DeliveryOutcome is the state of a webhook delivery after an attempt.
+type DeliveryOutcome struct {
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+}
+
+// Kinds of the events streamed to the user.
+const (
+	UserEventBalance  = "balance"  // the balance has changed
+	UserEventTransfer = "transfer" // coins are received
+	UserEventPurchase = "purchase" // an item is bought or its purchase changes status
+)
+
+// UserEvent is a change streamed to the user, the ID grows with each event.
+type UserEvent struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int       `json:"-" db:"user_id"`
+	Kind      string    `json:"kind" db:"kind"`
+	Payload   string    `json:"payload" db:"payload"` // JSON
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Quote is the price charged for an item with all the applied discounts.
+type Quote struct {
+	ListPrice int     `json:"listPrice"`
+	Discount  int     `json:"discount"`
+	Price     int     `json:"price"`
+	PromoCode *string `json:"promoCode,omitempty"`
+	Variant   string  `json:"variant,omitempty"`
+}
+
+// Page is a part of a collection in API v2, Total is the size of the whole collection.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
@@ -0,0 +1,179 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package admin provides the support staff's tools: looking up the users' accounts, granting coins,
+// fixing inventories, managing the store's items, locking and deactivating accounts, issuing claim tokens.
+// The changes are recorded in the audit log and can be dry run with db.WithDryRun.
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase interface defines methods for looking up and changing the users' accounts and the store's items.
+type DataBase interface {
+	GetAccountByUsername(ctx context.Context, username string) (*models.Account, error)
+	GetCoinHistoryByUserID(ctx context.Context, userID int) (*models.CoinHistory, error)
+	GetInventoryByUserID(ctx context.Context, userID int) (*[]models.Merch, error)
+	GrantCoins(ctx context.Context, userID, coins int, reason string) (int, error)
+	AdjustInventory(ctx context.Context, userID int, sku string, quantity int, reason string) (int, error)
+	CreateItem(ctx context.Context, item *models.Item) error
+	UpdateItem(ctx context.Context, slug string, update *models.ItemUpdate) (*models.Item, error)
+	SetUserLocked(ctx context.Context, userID int, locked bool, reason string) (*time.Time, error)
+	SetUserDeactivated(ctx context.Context, userID int, deactivated bool, reason string) (*time.Time, error)
+	SetClaimToken(ctx context.Context, userID int, tokenHash string) error
+}
+
+// Service provides the support staff's tools.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage: storage}
+}
+
+// GetAccount retrieves the user's account by the username.
+// Returns models.ErrUserNotFound if the user doesn't exist.
+func (s *Service) GetAccount(ctx context.Context, username string) (*models.Account, error) {
+	return s.storage.GetAccountByUsername(ctx, username)
+}
+
+// GetCoinHistory retrieves the coins received and sent by the user.
+func (s *Service) GetCoinHistory(ctx context.Context, username string) (*models.CoinHistory, error) {
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.GetCoinHistoryByUserID(ctx, account.ID)
+}
+
+// GetInventory retrieves the items owned by the user.
+func (s *Service) GetInventory(ctx context.Context, username string) (*[]models.Merch, error) {
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.GetInventoryByUserID(ctx, account.ID)
+}
+
+// GrantCoins adds the coins to the user's balance and returns the new balance.
+// The amount must be positive and the reason is required, it's shown in the user's coin history.
+func (s *Service) GrantCoins(ctx context.Context, username string, coins int, reason string) (int, error) {
+	reason = strings.TrimSpace(reason)
+	if coins <= 0 || reason == "" {
+		return 0, models.ErrInvalidGrant
+	}
+
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	return s.storage.GrantCoins(ctx, account.ID, coins, reason)
+}
+
+// AdjustInventory adds the quantity of the variant to the user's inventory, a negative quantity takes the units
+// away. Returns the quantity of the variant left in the inventory.
+// Returns models.ErrNotEnoughItems if the user owns fewer units than taken away.
+func (s *Service) AdjustInventory(ctx context.Context, username, sku string, quantity int, reason string) (int, error) {
+	if quantity == 0 {
+		return 0, apperr.Invalid("the quantity must not be zero")
+	}
+
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	return s.storage.AdjustInventory(ctx, account.ID, sku, quantity, strings.TrimSpace(reason))
+}
+
+// CreateItem adds the item to the store with a default variant having the item's slug as its SKU.
+// Returns models.ErrItemExists if an item with the same slug exists.
+func (s *Service) CreateItem(ctx context.Context, item *models.Item) error {
+	if err := validateItem(item.Slug, &item.Title, &item.Price, item.Stock, item.PerUserLimit); err != nil {
+		return err
+	}
+	return s.storage.CreateItem(ctx, item)
+}
+
+// UpdateItem changes the fields of the item set in the update and returns the updated item.
+// Returns models.ErrItemNotFound if the item doesn't exist.
+func (s *Service) UpdateItem(ctx context.Context, slug string, update *models.ItemUpdate) (*models.Item, error) {
+	if update.Title == nil && update.Price == nil && update.Category == nil && update.PerUserLimit == nil {
+		return nil, apperr.Invalid("nothing to update")
+	}
+	if err := validateItem(slug, update.Title, update.Price, nil, update.PerUserLimit); err != nil {
+		return nil, err
+	}
+	return s.storage.UpdateItem(ctx, slug, update)
+}
+
+// SetLocked locks the user's account, so that the user can't log in, or unlocks it,
+// and returns the account. The tokens issued before the account was locked are rejected too.
+func (s *Service) SetLocked(ctx context.Context, username string, locked bool, reason string) (*models.Account, error) {
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	account.LockedAt, err = s.storage.SetUserLocked(ctx, account.ID, locked, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// SetDeactivated deactivates the account of the user who has left, so that the user can't log in or receive coins,
+// or reactivates it, and returns the account. The account isn't synced with the HR directory anymore.
+func (s *Service) SetDeactivated(ctx context.Context, username string, deactivated bool, reason string) (*models.Account, error) {
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	account.DeactivatedAt, err = s.storage.SetUserDeactivated(ctx, account.ID, deactivated, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, err
+	}
+	account.DirectoryManaged = false
+	return account, nil
+}
+
+// IssueClaimToken issues a new claim token for the imported account the user hasn't logged in to yet,
+// e.g. if the token issued with the account is lost or the account was created by the scheduled sync.
+// The token issued before stops being valid. Returns models.ErrAccountClaimed if the account has a password.
+func (s *Service) IssueClaimToken(ctx context.Context, username string) (string, error) {
+	account, err := s.storage.GetAccountByUsername(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	token, hash, err := hasher.NewToken()
+	if err != nil {
+		return "", err
+	}
+	if err = s.storage.SetClaimToken(ctx, account.ID, hash); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// validateItem checks the fields of an item being created or updated, the nil fields aren't checked.
+func validateItem(slug string, title *string, price, stock, perUserLimit *int) error {
+	switch {
+	case strings.TrimSpace(slug) == "":
+		return apperr.Invalid("the slug is required")
+	case title != nil && strings.TrimSpace(*title) == "":
+		return apperr.Invalid("the title must not be empty")
+	case price != nil && *price < 0:
+		return apperr.Invalid("the price must not be negative")
+	case stock != nil && *stock < 0:
+		return apperr.Invalid("the stock must not be negative")
+	case perUserLimit != nil && *perUserLimit < 1:
+		return apperr.Invalid("the purchase limit must be at least 1")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/admin/mocks"
+)
+
+func TestService_GrantCoins(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		coins       int
+		reason      string
+		lookupErr   error
+		grantErr    error
+		wantBalance int
+		wantErr     error
+	}{
+		{
+			name:        "Coins granted",
+			username:    "testUser",
+			coins:       100,
+			reason:      "  hackathon prize ",
+			wantBalance: 1100,
+		},
+		{
+			name:     "Amount not positive",
+			username: "testUser",
+			coins:    0,
+			reason:   "hackathon prize",
+			wantErr:  models.ErrInvalidGrant,
+		},
+		{
+			name:     "Reason missing",
+			username: "testUser",
+			coins:    100,
+			reason:   "   ",
+			wantErr:  models.ErrInvalidGrant,
+		},
+		{
+			name:      "User not found",
+			username:  "unknownUser",
+			coins:     100,
+			reason:    "hackathon prize",
+			lookupErr: models.ErrUserNotFound,
+			wantErr:   models.ErrUserNotFound,
+		},
+		{
+			name:     "Database error",
+			username: "testUser",
+			coins:    100,
+			reason:   "hackathon prize",
+			grantErr: errors.New("database error"),
+			wantErr:  errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDataBase(t)
+			service := New(mockDB)
+
+			if tt.coins > 0 && tt.reason != "   " {
+				var account *models.Account
+				if tt.lookupErr == nil {
+					account = &models.Account{ID: 1, Username: tt.username, Coins: 1000}
+					mockDB.On("GrantCoins", mock.Anything, 1, tt.coins, "hackathon prize").
+						Return(tt.wantBalance, tt.grantErr).Once()
+				}
+				mockDB.On("GetAccountByUsername", mock.Anything, tt.username).Return(account, tt.lookupErr).Once()
+			}
+
+			balance, err := service.GrantCoins(context.Background(), tt.username, tt.coins, tt.reason)
+
+			require.Equal(t, tt.wantBalance, balance)
+			require.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_AdjustInventory(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("GetAccountByUsername", mock.Anything, "testUser").Return(&models.Account{ID: 1}, nil)
+	mockDB.On("AdjustInventory", mock.Anything, 1, "hoody-m", -2, "wrong size").Return(0, models.ErrNotEnoughItems).Once()
+	mockDB.On("AdjustInventory", mock.Anything, 1, "hoody-m", 1, "").Return(3, nil).Once()
+
+	_, err := service.AdjustInventory(ctx, "testUser", "hoody-m", -2, "wrong size")
+	require.ErrorIs(t, err, models.ErrNotEnoughItems)
+
+	left, err := service.AdjustInventory(ctx, "testUser", "hoody-m", 1, "")
+	require.NoError(t, err)
+	require.Equal(t, 3, left)
+
+	// Нулевое изменение ничего не меняет и отклоняется до обращения к базе.
+	_, err = service.AdjustInventory(ctx, "testUser", "hoody-m", 0, "")
+	require.Equal(t, apperr.KindInvalid, apperr.From(err).Kind())
+}
+
+func TestService_CreateItem(t *testing.T) {
+	stock, limit, negative := 50, 1, -1
+
+	tests := []struct {
+		name      string
+		item      *models.Item
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name: "Item created",
+			item: &models.Item{Slug: "sticker", Title: "Sticker", Price: 5, Stock: &stock, PerUserLimit: &limit},
+		},
+		{
+			name:      "Slug taken",
+			item:      &models.Item{Slug: "cup", Title: "Cup", Price: 20},
+			createErr: models.ErrItemExists,
+			wantErr:   true,
+		},
+		{
+			name:    "Slug missing",
+			item:    &models.Item{Title: "Sticker", Price: 5},
+			wantErr: true,
+		},
+		{
+			name:    "Negative price",
+			item:    &models.Item{Slug: "sticker", Title: "Sticker", Price: -5},
+			wantErr: true,
+		},
+		{
+			name:    "Negative stock",
+			item:    &models.Item{Slug: "sticker", Title: "Sticker", Price: 5, Stock: &negative},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDataBase(t)
+			service := New(mockDB)
+
+			if !tt.wantErr || tt.createErr != nil {
+				mockDB.On("CreateItem", mock.Anything, tt.item).Return(tt.createErr).Once()
+			}
+
+			err := service.CreateItem(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_UpdateItem(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB)
+	ctx := context.Background()
+
+	price := 25
+	update := &models.ItemUpdate{Price: &price}
+	mockDB.On("UpdateItem", mock.Anything, "cup", update).Return(&models.Item{Slug: "cup", Title: "Cup", Price: 25}, nil).Once()
+
+	item, err := service.UpdateItem(ctx, "cup", update)
+	require.NoError(t, err)
+	require.Equal(t, 25, item.Price)
+
+	// Пустое изменение отклоняется.
+	_, err = service.UpdateItem(ctx, "cup", &models.ItemUpdate{})
+	require.Equal(t, apperr.KindInvalid, apperr.From(err).Kind())
+}
+
+func TestService_SetLocked(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB)
+	lockedAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	mockDB.On("GetAccountByUsername", mock.Anything, "testUser").Return(&models.Account{ID: 1, Username: "testUser"}, nil).Once()
+	mockDB.On("SetUserLocked", mock.Anything, 1, true, "left the company").Return(&lockedAt, nil).Once()
+
+	account, err := service.SetLocked(context.Background(), "testUser", true, "left the company")
+	require.NoError(t, err)
+	require.Equal(t, &lockedAt, account.LockedAt)
+}
+
+func TestService_SetDeactivated(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB)
+	deactivatedAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	mockDB.On("GetAccountByUsername", mock.Anything, "testUser").
+		Return(&models.Account{ID: 1, Username: "testUser", DirectoryManaged: true}, nil).Once()
+	mockDB.On("SetUserDeactivated", mock.Anything, 1, true, "left the company").Return(&deactivatedAt, nil).Once()
+
+	account, err := service.SetDeactivated(context.Background(), "testUser", true, " left the company ")
+	require.NoError(t, err)
+	require.Equal(t, &deactivatedAt, account.DeactivatedAt)
+	require.False(t, account.DirectoryManaged)
+}
+
+func TestService_IssueClaimToken(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB)
+
+	var hash string
+	mockDB.On("GetAccountByUsername", mock.Anything, "newHire1").Return(&models.Account{ID: 3, Username: "newHire1"}, nil).Twice()
+	mockDB.On("SetClaimToken", mock.Anything, 3, mock.Anything).
+		Run(func(args mock.Arguments) { hash = args.String(2) }).Return(nil).Once()
+
+	token, err := service.IssueClaimToken(context.Background(), "newHire1")
+	require.NoError(t, err)
+	// only the hash of the token is stored
+	require.Equal(t, hasher.HashToken(token), hash)
+
+	mockDB.On("SetClaimToken", mock.Anything, 3, mock.Anything).Return(models.ErrAccountClaimed).Once()
+	_, err = service.IssueClaimToken(context.Background(), "newHire1")
+	require.ErrorIs(t, err, models.ErrAccountClaimed)
+}
@@ -0,0 +1,303 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// AdjustInventory provides a mock function with given fields: ctx, userID, sku, quantity, reason
+func (_m *DataBase) AdjustInventory(ctx context.Context, userID int, sku string, quantity int, reason string) (int, error) {
+	ret := _m.Called(ctx, userID, sku, quantity, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustInventory")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int, string) (int, error)); ok {
+		return rf(ctx, userID, sku, quantity, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, int, string) int); ok {
+		r0 = rf(ctx, userID, sku, quantity, reason)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, int, string) error); ok {
+		r1 = rf(ctx, userID, sku, quantity, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateItem provides a mock function with given fields: ctx, item
+func (_m *DataBase) CreateItem(ctx context.Context, item *models.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAccountByUsername provides a mock function with given fields: ctx, username
+func (_m *DataBase) GetAccountByUsername(ctx context.Context, username string) (*models.Account, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountByUsername")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Account, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Account); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoinHistoryByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetCoinHistoryByUserID(ctx context.Context, userID int) (*models.CoinHistory, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoinHistoryByUserID")
+	}
+
+	var r0 *models.CoinHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.CoinHistory, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.CoinHistory); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInventoryByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetInventoryByUserID(ctx context.Context, userID int) (*[]models.Merch, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInventoryByUserID")
+	}
+
+	var r0 *[]models.Merch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Merch, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Merch); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Merch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantCoins provides a mock function with given fields: ctx, userID, coins, reason
+func (_m *DataBase) GrantCoins(ctx context.Context, userID int, coins int, reason string) (int, error) {
+	ret := _m.Called(ctx, userID, coins, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantCoins")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (int, error)); ok {
+		return rf(ctx, userID, coins, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) int); ok {
+		r0 = rf(ctx, userID, coins, reason)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, userID, coins, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetClaimToken provides a mock function with given fields: ctx, userID, tokenHash
+func (_m *DataBase) SetClaimToken(ctx context.Context, userID int, tokenHash string) error {
+	ret := _m.Called(ctx, userID, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetClaimToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserDeactivated provides a mock function with given fields: ctx, userID, deactivated, reason
+func (_m *DataBase) SetUserDeactivated(ctx context.Context, userID int, deactivated bool, reason string) (*time.Time, error) {
+	ret := _m.Called(ctx, userID, deactivated, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserDeactivated")
+	}
+
+	var r0 *time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, string) (*time.Time, error)); ok {
+		return rf(ctx, userID, deactivated, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, string) *time.Time); ok {
+		r0 = rf(ctx, userID, deactivated, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool, string) error); ok {
+		r1 = rf(ctx, userID, deactivated, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserLocked provides a mock function with given fields: ctx, userID, locked, reason
+func (_m *DataBase) SetUserLocked(ctx context.Context, userID int, locked bool, reason string) (*time.Time, error) {
+	ret := _m.Called(ctx, userID, locked, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserLocked")
+	}
+
+	var r0 *time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, string) (*time.Time, error)); ok {
+		return rf(ctx, userID, locked, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool, string) *time.Time); ok {
+		r0 = rf(ctx, userID, locked, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool, string) error); ok {
+		r1 = rf(ctx, userID, locked, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItem provides a mock function with given fields: ctx, slug, update
+func (_m *DataBase) UpdateItem(ctx context.Context, slug string, update *models.ItemUpdate) (*models.Item, error) {
+	ret := _m.Called(ctx, slug, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItem")
+	}
+
+	var r0 *models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.ItemUpdate) (*models.Item, error)); ok {
+		return rf(ctx, slug, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.ItemUpdate) *models.Item); ok {
+		r0 = rf(ctx, slug, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.ItemUpdate) error); ok {
+		r1 = rf(ctx, slug, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
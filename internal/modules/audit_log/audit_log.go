@@ -0,0 +1,87 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package audit_log provides functionality for searching the audit log and checking its hash chain.
+package audit_log
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	defaultLimit = 100  // number of entries shown when the limit isn't given
+	maxLimit     = 1000 // maximum number of entries shown
+	chainPage    = 1000 // number of entries read at a time while checking the chain
+)
+
+// DataBase interface defines methods for reading the audit log.
+type DataBase interface {
+	GetAuditEntries(ctx context.Context, f *models.AuditFilter) (*[]models.AuditEntry, error)
+	GetAuditChain(ctx context.Context, chain int, afterID int64, limit int) (*[]models.AuditEntry, error)
+}
+
+// Service provides functionality for reading the audit log.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage: storage}
+}
+
+// GetEntries lists the audit log entries matching the filter, the latest first. A non-positive limit
+// falls back to the default one, the limit can't exceed 1000 entries.
+func (s *Service) GetEntries(ctx context.Context, f *models.AuditFilter) (*[]models.AuditEntry, error) {
+	switch {
+	case f.Limit <= 0:
+		f.Limit = defaultLimit
+	case f.Limit > maxLimit:
+		f.Limit = maxLimit
+	}
+	return s.storage.GetAuditEntries(ctx, f)
+}
+
+// Verify walks each chain of the audit log from its first entry and checks that each entry is chained
+// to the previous one and its hash matches its fields. Stops at the first broken entry.
+func (s *Service) Verify(ctx context.Context) (*models.AuditVerification, error) {
+	result := &models.AuditVerification{Valid: true}
+	for chain := range audit.Chains {
+		if err := s.verifyChain(ctx, chain, result); err != nil || !result.Valid {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// verifyChain checks the entries of the chain, counting them in the result, and marks the result
+// broken at the first broken entry.
+func (s *Service) verifyChain(ctx context.Context, chain int, result *models.AuditVerification) error {
+	prevHash := audit.GenesisHash
+	var afterID int64
+
+	for {
+		entries, err := s.storage.GetAuditChain(ctx, chain, afterID, chainPage)
+		if err != nil {
+			return err
+		}
+
+		for i := range *entries {
+			e := &(*entries)[i]
+			if e.PrevHash != prevHash || audit.Hash(prevHash, e) != e.Hash {
+				result.Valid = false
+				result.BrokenAt = &e.ID
+				return nil
+			}
+			prevHash = e.Hash
+			afterID = e.ID
+			result.Checked++
+		}
+
+		if len(*entries) < chainPage {
+			return nil
+		}
+	}
+}
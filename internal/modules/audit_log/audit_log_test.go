@@ -0,0 +1,138 @@
+package audit_log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/audit_log/mocks"
+)
+
+// chain builds a valid audit log chain of n entries about the target, with IDs from firstID apart by step.
+func chain(target string, n int, firstID, step int64) []models.AuditEntry {
+	entries := make([]models.AuditEntry, n)
+	prevHash := audit.GenesisHash
+	for i := range entries {
+		e := audit.NewEntry(context.Background(), models.AuditCoinsTransferred, target, map[string]any{"amount": i + 1})
+		e.ID = firstID + int64(i)*step
+		e.Chain = audit.ChainOf(target)
+		e.CreatedAt = time.Date(2025, 3, 10, 12, 0, i, 0, time.UTC)
+		e.PrevHash = prevHash
+		e.Hash = audit.Hash(prevHash, e)
+		prevHash = e.Hash
+		entries[i] = *e
+	}
+	return entries
+}
+
+func TestService_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		tamper      func(entries []models.AuditEntry) []models.AuditEntry
+		wantValid   bool
+		wantChecked int
+		wantBroken  int64
+	}{
+		{
+			name:        "Intact chain",
+			tamper:      func(entries []models.AuditEntry) []models.AuditEntry { return entries },
+			wantValid:   true,
+			wantChecked: 3,
+		},
+		{
+			name: "Changed entry",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				details := `{"amount":1000}`
+				entries[1].Details = &details
+				return entries
+			},
+			wantChecked: 1,
+			wantBroken:  2,
+		},
+		{
+			name: "Removed entry",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantChecked: 1,
+			wantBroken:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			entries := tt.tamper(chain("user:2", 3, 1, 1))
+			mockDB.On("GetAuditChain", mock.Anything, audit.ChainOf("user:2"), int64(0), chainPage).Return(&entries, nil).Once()
+			mockDB.On("GetAuditChain", mock.Anything, mock.Anything, int64(0), chainPage).Return(&[]models.AuditEntry{}, nil).Maybe()
+
+			result, err := service.Verify(ctx)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantValid, result.Valid)
+			require.Equal(t, tt.wantChecked, result.Checked)
+			if !tt.wantValid {
+				require.Equal(t, tt.wantBroken, *result.BrokenAt)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_VerifyChains(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+
+	// the chains are appended to at the same time, so their IDs interleave
+	first, second := "user:2", "team:1"
+	require.NotEqual(t, audit.ChainOf(first), audit.ChainOf(second))
+	firstEntries, secondEntries := chain(first, 2, 1, 2), chain(second, 3, 2, 2)
+	mockDB.On("GetAuditChain", mock.Anything, audit.ChainOf(first), int64(0), chainPage).Return(&firstEntries, nil).Once()
+	mockDB.On("GetAuditChain", mock.Anything, audit.ChainOf(second), int64(0), chainPage).Return(&secondEntries, nil).Once()
+	mockDB.On("GetAuditChain", mock.Anything, mock.Anything, int64(0), chainPage).Return(&[]models.AuditEntry{}, nil).
+		Times(audit.Chains - 2)
+
+	result, err := service.Verify(context.Background())
+
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+	require.Equal(t, 5, result.Checked)
+	mockDB.AssertExpectations(t)
+}
+
+func TestService_GetEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "Default limit", limit: 0, wantLimit: 100},
+		{name: "Given limit", limit: 20, wantLimit: 20},
+		{name: "Limit too big", limit: 5000, wantLimit: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+
+			mockDB.On("GetAuditEntries", mock.Anything, mock.MatchedBy(func(f *models.AuditFilter) bool {
+				return f.Limit == tt.wantLimit && f.Action == models.AuditLoginFailed
+			})).Return(&[]models.AuditEntry{}, nil).Once()
+
+			_, err := service.GetEntries(context.Background(), &models.AuditFilter{Action: models.AuditLoginFailed, Limit: tt.limit})
+
+			require.NoError(t, err)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
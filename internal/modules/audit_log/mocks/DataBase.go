@@ -0,0 +1,89 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// GetAuditChain provides a mock function with given fields: ctx, chain, afterID, limit
+func (_m *DataBase) GetAuditChain(ctx context.Context, chain int, afterID int64, limit int) (*[]models.AuditEntry, error) {
+	ret := _m.Called(ctx, chain, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditChain")
+	}
+
+	var r0 *[]models.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, int) (*[]models.AuditEntry, error)); ok {
+		return rf(ctx, chain, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, int) *[]models.AuditEntry); ok {
+		r0 = rf(ctx, chain, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64, int) error); ok {
+		r1 = rf(ctx, chain, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuditEntries provides a mock function with given fields: ctx, f
+func (_m *DataBase) GetAuditEntries(ctx context.Context, f *models.AuditFilter) (*[]models.AuditEntry, error) {
+	ret := _m.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEntries")
+	}
+
+	var r0 *[]models.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditFilter) (*[]models.AuditEntry, error)); ok {
+		return rf(ctx, f)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditFilter) *[]models.AuditEntry); ok {
+		r0 = rf(ctx, f)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.AuditFilter) error); ok {
+		r1 = rf(ctx, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,9 +6,15 @@ package authentication
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
 	"errors"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
 	"github.com/kk7453603/avito_2024_summer/internal/models"
 )
 
@@ -16,6 +22,9 @@ import (
 type DataBase interface {
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	SaveUser(ctx context.Context, user *models.User) error
+	ClaimUser(ctx context.Context, user *models.User) error
+	GetAccountStatusByUserID(ctx context.Context, userID int) (locked, deactivated bool, err error)
+	RecordAudit(ctx context.Context, entry *models.AuditEntry) error
 }
 
 // Hasher interface defines methods for password hashing and comparison.
@@ -24,39 +33,72 @@ type Hasher interface {
 	Compare(hashedPasswd, passwd string) bool
 }
 
+// accountStatusTTL is how long the status of an account is cached for the token checks,
+// so a lock or a deactivation takes effect on the issued tokens within it.
+const accountStatusTTL = 10 * time.Second
+
+// accountStatus is the cached result of an account check.
+type accountStatus struct {
+	err       error
+	expiresAt time.Time
+}
+
 // AuthService provides authentication-related functionality.
 type AuthService struct {
 	storage DataBase
 	passwd  Hasher
+	now     func() time.Time
+
+	mu       sync.Mutex
+	accounts map[int]accountStatus
+	sweptAt  time.Time // when the expired statuses were last dropped
 }
 
 // New creates a new instance of AuthService with the given storage and Hasher.
 func New(storage DataBase, passwd Hasher) *AuthService {
-	return &AuthService{storage, passwd}
+	return &AuthService{
+		storage:  storage,
+		passwd:   passwd,
+		now:      time.Now,
+		accounts: make(map[int]accountStatus),
+	}
 }
 
 // GetOrRegUser retrieves an existing user or registers a new one if they don't exist.
-func (s *AuthService) GetOrRegUser(ctx context.Context, username, password string) (*models.User, bool, error) {
+// An account imported from the HR directory has no password until the user's first login, which sets it,
+// the same as registering, but only with the claim token issued with the account. Otherwise the failed login
+// is audited and models.ErrInvalidClaimToken returned. A deactivated or locked account isn't claimed this way.
+func (s *AuthService) GetOrRegUser(ctx context.Context, username, password, claimToken string) (*models.User, bool, error) {
 	user, err := s.storage.GetUserByUsername(ctx, username)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, false, err
 	}
 
-	if user != nil {
+	if user != nil && (user.Password != "" || user.DeactivatedAt != nil || user.LockedAt != nil) {
 		return user, true, nil
 	}
+	if user != nil && !validClaimToken(user, claimToken) {
+		if err = s.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+			return nil, false, err
+		}
+		return nil, false, models.ErrInvalidClaimToken
+	}
 
 	hashedPasswd, err := s.passwd.Hash(password)
 	if err != nil {
 		return nil, false, err
 	}
 
-	user = &models.User{
-		Username: username,
-		Password: hashedPasswd,
+	if user != nil {
+		user.Password = hashedPasswd
+		err = s.storage.ClaimUser(ctx, user)
+	} else {
+		user = &models.User{
+			Username: username,
+			Password: hashedPasswd,
+		}
+		err = s.storage.SaveUser(ctx, user)
 	}
-
-	err = s.storage.SaveUser(ctx, user)
 	if err != nil {
 		return nil, false, err
 	}
@@ -64,7 +106,60 @@ func (s *AuthService) GetOrRegUser(ctx context.Context, username, password strin
 	return user, false, nil
 }
 
+// validClaimToken reports whether the token is the one the user's imported account is claimed with.
+func validClaimToken(user *models.User, token string) bool {
+	return user.ClaimTokenHash != nil &&
+		subtle.ConstantTimeCompare([]byte(hasher.HashToken(token)), []byte(*user.ClaimTokenHash)) == 1
+}
+
 // ComparePassword checks if the provided password matches the hashed password.
 func (s *AuthService) ComparePassword(hashedPasswd, passwd string) bool {
 	return s.passwd.Compare(hashedPasswd, passwd)
 }
+
+// CheckAccount returns an error if the user's account is locked or deactivated,
+// the tokens issued before then are rejected by it. The status is cached for accountStatusTTL.
+func (s *AuthService) CheckAccount(ctx context.Context, userID int) error {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.accounts[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.err
+	}
+
+	locked, deactivated, err := s.storage.GetAccountStatusByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case locked:
+		err = models.ErrAccountLocked
+	case deactivated:
+		err = models.ErrAccountDeactivated
+	}
+
+	s.mu.Lock()
+	// the expired statuses are dropped once per TTL, so the cache doesn't outgrow the active users
+	if now.Sub(s.sweptAt) >= accountStatusTTL {
+		for id, status := range s.accounts {
+			if !now.Before(status.expiresAt) {
+				delete(s.accounts, id)
+			}
+		}
+		s.sweptAt = now
+	}
+	s.accounts[userID] = accountStatus{err: err, expiresAt: now.Add(accountStatusTTL)}
+	s.mu.Unlock()
+
+	return err
+}
+
+// RecordAuthEvent records the login, failed login or token issuance of the user in the audit log.
+// The event is attributed to the user whose account is used, as the request isn't authorized yet.
+func (s *AuthService) RecordAuthEvent(ctx context.Context, action string, user *models.User) error {
+	entry := audit.NewEntry(ctx, action, "user:"+strconv.Itoa(user.ID), nil)
+	entry.ActorID, entry.Actor = &user.ID, &user.Username
+	return s.storage.RecordAudit(ctx, entry)
+}
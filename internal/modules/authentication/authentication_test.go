@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
 	"github.com/kk7453603/avito_2024_summer/internal/models"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/authentication/mocks"
 )
@@ -18,6 +21,7 @@ func TestAuthService_GetOrRegUser(t *testing.T) {
 		existingUser *models.User
 		username     string
 		password     string
+		claimToken   string
 		hashPassword string
 		shouldExist  bool
 	}{
@@ -36,6 +40,22 @@ func TestAuthService_GetOrRegUser(t *testing.T) {
 			hashPassword: "hashedNewPasswd",
 			shouldExist:  false,
 		},
+		{
+			name:         "Imported user claims the account",
+			existingUser: &models.User{ID: 3, Username: "newHire1", ClaimTokenHash: ptr(hasher.HashToken("claimToken"))},
+			username:     "newHire1",
+			password:     "newPasswd",
+			claimToken:   "claimToken",
+			hashPassword: "hashedNewPasswd",
+			shouldExist:  false,
+		},
+		{
+			name:         "Deactivated imported user isn't let in",
+			existingUser: &models.User{ID: 4, Username: "leaver01", DeactivatedAt: ptr(time.Now())},
+			username:     "leaver01",
+			password:     "newPasswd",
+			shouldExist:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -43,18 +63,25 @@ func TestAuthService_GetOrRegUser(t *testing.T) {
 			mockDB := new(mocks.DataBase)
 			mockHasher := new(mocks.Hasher)
 
-			if tt.existingUser == nil {
+			switch {
+			case tt.existingUser == nil:
 				mockDB.On("GetUserByUsername", mock.Anything, tt.username).Return((*models.User)(nil), nil)
 				mockHasher.On("Hash", tt.password).Return(tt.hashPassword, nil)
 				mockDB.On("SaveUser", mock.Anything, mock.Anything).Return(nil)
-			} else {
+			case !tt.shouldExist:
+				mockDB.On("GetUserByUsername", mock.Anything, tt.username).Return(tt.existingUser, nil)
+				mockHasher.On("Hash", tt.password).Return(tt.hashPassword, nil)
+				mockDB.On("ClaimUser", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+					return u.ID == tt.existingUser.ID && u.Password == tt.hashPassword
+				})).Return(nil)
+			default:
 				mockDB.On("GetUserByUsername", mock.Anything, tt.username).Return(tt.existingUser, nil)
 			}
 
 			service := New(mockDB, mockHasher)
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
-			user, exists, err := service.GetOrRegUser(ctx, tt.username, tt.password)
+			user, exists, err := service.GetOrRegUser(ctx, tt.username, tt.password, tt.claimToken)
 
 			require.NoError(t, err)
 			require.Equal(t, tt.shouldExist, exists)
@@ -128,7 +155,7 @@ func TestAuthService_GetOrRegUserError(t *testing.T) {
 			service := New(mockDB, mockHasher)
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
-			_, _, err := service.GetOrRegUser(ctx, tt.username, tt.password)
+			_, _, err := service.GetOrRegUser(ctx, tt.username, tt.password, "")
 
 			if tt.expectError {
 				require.Error(t, err)
@@ -144,6 +171,39 @@ func TestAuthService_GetOrRegUserError(t *testing.T) {
 	}
 }
 
+func TestAuthService_GetOrRegUserClaimToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenHash  *string
+		claimToken string
+	}{
+		{name: "No token given", tokenHash: ptr(hasher.HashToken("claimToken"))},
+		{name: "Wrong token", tokenHash: ptr(hasher.HashToken("claimToken")), claimToken: "otherToken"},
+		{name: "No token issued", claimToken: "claimToken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			mockHasher := new(mocks.Hasher)
+			user := &models.User{ID: 3, Username: "newHire1", ClaimTokenHash: tt.tokenHash}
+
+			mockDB.On("GetUserByUsername", mock.Anything, user.Username).Return(user, nil)
+			// the attempt to take the account over is audited, the account is left unclaimed
+			mockDB.On("RecordAudit", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+				return e.Action == models.AuditLoginFailed && e.Target == "user:3"
+			})).Return(nil).Once()
+
+			service := New(mockDB, mockHasher)
+			_, _, err := service.GetOrRegUser(context.Background(), user.Username, "newPasswd", tt.claimToken)
+
+			require.ErrorIs(t, err, models.ErrInvalidClaimToken)
+			mockDB.AssertExpectations(t)
+			mockHasher.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAuthService_ComparePassword(t *testing.T) {
 	mockHasher := new(mocks.Hasher)
 
@@ -166,3 +226,52 @@ func TestAuthService_ComparePassword(t *testing.T) {
 	require.False(t, result)
 	mockHasher.AssertCalled(t, "Compare", hashedPassword, "wrong_password")
 }
+
+func TestAuthService_RecordAuthEvent(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB, new(mocks.Hasher))
+	ctx := audit.WithRequest(context.Background(), audit.Request{IP: "10.0.0.1", RequestID: "req-1"})
+	user := &models.User{ID: 7, Username: "testUser"}
+
+	mockDB.On("RecordAudit", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditLoginFailed && e.Target == "user:7" && *e.ActorID == 7 &&
+			*e.Actor == "testUser" && e.IP == "10.0.0.1" && e.RequestID == "req-1"
+	})).Return(nil).Once()
+
+	require.NoError(t, service.RecordAuthEvent(ctx, models.AuditLoginFailed, user))
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthService_CheckAccount(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB, new(mocks.Hasher))
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 1).Return(false, false, nil).Once()
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 2).Return(true, false, nil).Once()
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 3).Return(false, true, nil).Once()
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 4).Return(false, false, errors.New("db error")).Once()
+
+	require.NoError(t, service.CheckAccount(context.Background(), 1))
+	require.ErrorIs(t, service.CheckAccount(context.Background(), 2), models.ErrAccountLocked)
+	require.ErrorIs(t, service.CheckAccount(context.Background(), 3), models.ErrAccountDeactivated)
+	require.Error(t, service.CheckAccount(context.Background(), 4))
+
+	// the statuses are cached, the failed lookups aren't
+	require.NoError(t, service.CheckAccount(context.Background(), 1))
+	require.ErrorIs(t, service.CheckAccount(context.Background(), 2), models.ErrAccountLocked)
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 4).Return(false, false, nil).Once()
+	require.NoError(t, service.CheckAccount(context.Background(), 4))
+
+	// the user locked in the meantime is rejected once the cached status expires
+	now = now.Add(accountStatusTTL)
+	mockDB.On("GetAccountStatusByUserID", mock.Anything, 1).Return(true, false, nil).Once()
+	require.ErrorIs(t, service.CheckAccount(context.Background(), 1), models.ErrAccountLocked)
+
+	mockDB.AssertExpectations(t)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -4,8 +4,8 @@ package mocks
 
 import (
 	context "context"
-	models "github.com/kk7453603/avito_2024_summer/internal/models"
 
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +14,59 @@ type DataBase struct {
 	mock.Mock
 }
 
+// ClaimUser provides a mock function with given fields: ctx, user
+func (_m *DataBase) ClaimUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAccountStatusByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetAccountStatusByUserID(ctx context.Context, userID int) (bool, bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountStatusByUserID")
+	}
+
+	var r0 bool
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) bool); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int) error); ok {
+		r2 = rf(ctx, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetUserByUsername provides a mock function with given fields: ctx, username
 func (_m *DataBase) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	ret := _m.Called(ctx, username)
@@ -44,6 +97,24 @@ func (_m *DataBase) GetUserByUsername(ctx context.Context, username string) (*mo
 	return r0, r1
 }
 
+// RecordAudit provides a mock function with given fields: ctx, entry
+func (_m *DataBase) RecordAudit(ctx context.Context, entry *models.AuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordAudit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveUser provides a mock function with given fields: ctx, user
 func (_m *DataBase) SaveUser(ctx context.Context, user *models.User) error {
 	ret := _m.Called(ctx, user)
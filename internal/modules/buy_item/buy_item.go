@@ -1,8 +1,8 @@
 //go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
 
 // Package buy_item provides functionality for handling the purchase of items by users.
-// It includes methods for retrieving item details, checking a buyer's coin balance,
-// and processing purchases.
+// It includes methods for retrieving item details and the store catalog with the active discounts applied,
+// checking a buyer's coin balance, and processing purchases.
 package buy_item
 
 import (
@@ -11,13 +11,18 @@ import (
 	"errors"
 
 	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/pricing"
 )
 
 // DataBase interface defines methods for handling item purchases and user data.
 type DataBase interface {
 	GetItemBySlug(ctx context.Context, slug string) (*models.Item, error)
 	GetCoinsByUserID(ctx context.Context, userID int) (int, error)
-	MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item) error
+	GetSavedCoinsByUserID(ctx context.Context, userID int, slug string) (int, error)
+	MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item, order *models.Order) (*models.Quote, error)
+	GetItems(ctx context.Context) (*[]models.Item, error)
+	GetActiveDiscounts(ctx context.Context) (*[]models.Discount, error)
+	GetWishlistByUserID(ctx context.Context, userID int) (*[]models.Item, error)
 }
 
 // BuyItemService provides functionality for handling item purchases.
@@ -30,15 +35,32 @@ func New(storage DataBase) *BuyItemService {
 	return &BuyItemService{storage}
 }
 
-// GetItem retrieves an item by its slug, handling DataBase errors.
+// GetItem retrieves an item by its slug with the sale price set, if it's discounted, handling DataBase errors.
 func (s *BuyItemService) GetItem(ctx context.Context, slug string) (*models.Item, error) {
 	item, err := s.storage.GetItemBySlug(ctx, slug)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, err
 	}
+	if item == nil {
+		return nil, nil
+	}
+
+	discounts, err := s.storage.GetActiveDiscounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	setSalePrice(item, discounts)
 	return item, nil
 }
 
+// setSalePrice sets the sale price of the item and its variants if any of the discounts applies to them.
+func setSalePrice(item *models.Item, discounts *[]models.Discount) {
+	if discounts == nil {
+		return
+	}
+	pricing.SetSalePrice(item, *discounts)
+}
+
 // GetBuyerCoins retrieves the number of coins a buyer has by their ID.
 func (s *BuyItemService) GetBuyerCoins(ctx context.Context, userID int) (int, error) {
 	coins, err := s.storage.GetCoinsByUserID(ctx, userID)
@@ -48,7 +70,98 @@ func (s *BuyItemService) GetBuyerCoins(ctx context.Context, userID int) (int, er
 	return coins, nil
 }
 
-// BuyItem processes the purchase of an item by a user.
-func (s *BuyItemService) BuyItem(ctx context.Context, userID int, item *models.Item) error {
-	return s.storage.MakePurchaseByUserID(ctx, userID, item)
+// GetSavedCoins retrieves the number of coins the buyer has set aside for the item,
+// they are released to pay for it.
+func (s *BuyItemService) GetSavedCoins(ctx context.Context, userID int, slug string) (int, error) {
+	return s.storage.GetSavedCoinsByUserID(ctx, userID, slug)
+}
+
+// GetCatalog retrieves all items of the store with their remaining stock and sale prices,
+// marking the items in the user's wishlist. Returns an empty list if none exists.
+func (s *BuyItemService) GetCatalog(ctx context.Context, userID int) (*[]models.Item, error) {
+	items, err := s.storage.GetItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		return &[]models.Item{}, nil
+	}
+
+	discounts, err := s.storage.GetActiveDiscounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wishlist, err := s.storage.GetWishlistByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wished := make(map[string]bool)
+	if wishlist != nil {
+		for _, item := range *wishlist {
+			wished[item.Slug] = true
+		}
+	}
+
+	for i := range *items {
+		setSalePrice(&(*items)[i], discounts)
+		(*items)[i].InWishlist = wished[(*items)[i].Slug]
+	}
+	return items, nil
+}
+
+// BuyItem processes the purchase of the item's variant by a user, applying the order's promo code if it's not empty.
+// The default variant is bought if the order doesn't name one. The item then waits to be handed over in the order's office
+// and drops off the user's wishlist.
+// The charged price is calculated at the moment of the purchase and returned.
+// Returns models.ErrItemNotFound or models.ErrVariantNotFound if there is no such item or variant,
+// models.ErrNotEnoughCoins if the user can't pay the variant's price even with the coins saved for the item,
+// models.ErrSoldOut or models.ErrLimitReached if the item can't be sold to the user.
+func (s *BuyItemService) BuyItem(ctx context.Context, userID int, slug string, order *models.Order) (*models.Quote, error) {
+	item, err := s.GetItem(ctx, slug)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, models.ErrItemNotFound
+	}
+
+	variant := findVariant(item, order.Variant)
+	if variant == nil {
+		return nil, models.ErrVariantNotFound
+	}
+
+	price := item.Price
+	if variant.PriceOverride != nil {
+		price = *variant.PriceOverride
+	}
+	if variant.SalePrice != nil {
+		price = *variant.SalePrice
+	}
+
+	// A promo code can lower the price further, then the balance is checked only during the purchase
+	buyerCoins, err := s.GetBuyerCoins(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if order.PromoCode == "" && buyerCoins < price {
+		// The coins saved for the item are released to pay for it
+		saved, err := s.GetSavedCoins(ctx, userID, item.Slug)
+		if err != nil {
+			return nil, err
+		} else if buyerCoins+saved < price {
+			return nil, models.ErrNotEnoughCoins
+		}
+	}
+
+	order.Variant = variant.SKU
+	return s.storage.MakePurchaseByUserID(ctx, userID, item, order)
+}
+
+// findVariant returns the item's variant with the SKU, or the default variant if the SKU is empty.
+func findVariant(item *models.Item, sku string) *models.Variant {
+	for i := range item.Variants {
+		v := &item.Variants[i]
+		if (sku == "" && v.IsDefault) || (sku != "" && v.SKU == sku) {
+			return v
+		}
+	}
+	return nil
 }
@@ -27,12 +27,49 @@ func TestBuyItemService_GetItem(t *testing.T) {
 	}
 
 	mockDB.On("GetItemBySlug", mock.Anything, slug).Return(expectedItem, nil)
+	mockDB.On("GetActiveDiscounts", mock.Anything).Return(&[]models.Discount{}, nil)
 
 	item, err := service.GetItem(ctx, slug)
 
 	require.NoError(t, err)
 	require.NotNil(t, item)
 	require.Equal(t, expectedItem, item)
+	require.Nil(t, item.SalePrice)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestBuyItemService_GetItemDiscounted(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	slug := "hoody"
+	otherSlug := "cup"
+	discounts := &[]models.Discount{
+		{ItemSlug: &slug, Kind: models.DiscountPercent, Value: 10},
+		{ItemSlug: &otherSlug, Kind: models.DiscountFixed, Value: 5},
+	}
+
+	override := 400
+	mockDB.On("GetItemBySlug", mock.Anything, slug).Return(&models.Item{
+		Slug:  slug,
+		Price: 300,
+		Variants: []models.Variant{
+			{SKU: slug, IsDefault: true},
+			{SKU: "hoody-xxl", PriceOverride: &override},
+		},
+	}, nil)
+	mockDB.On("GetActiveDiscounts", mock.Anything).Return(discounts, nil)
+
+	item, err := service.GetItem(ctx, slug)
+
+	require.NoError(t, err)
+	require.NotNil(t, item.SalePrice)
+	require.Equal(t, 270, *item.SalePrice)
+	require.Equal(t, 270, *item.Variants[0].SalePrice)
+	require.Equal(t, 360, *item.Variants[1].SalePrice)
 
 	mockDB.AssertExpectations(t)
 }
@@ -158,34 +195,142 @@ func TestBuyItemService_GetBuyerCoinsError(t *testing.T) {
 	}
 }
 
+func TestBuyItemService_GetSavedCoins(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+
+	mockDB.On("GetSavedCoinsByUserID", mock.Anything, 1, "pink-hoody").Return(200, nil)
+
+	saved, err := service.GetSavedCoins(context.Background(), 1, "pink-hoody")
+	require.NoError(t, err)
+	require.Equal(t, 200, saved)
+
+	mockDB.AssertExpectations(t)
+}
+
 func TestBuyItemService_BuyItem(t *testing.T) {
+	override := 300
 	item := &models.Item{
 		Slug:  "valid-item",
 		Title: "Valid Item",
 		Price: 100,
+		Variants: []models.Variant{
+			{SKU: "valid-item", IsDefault: true},
+			{SKU: "valid-item-xl", PriceOverride: &override},
+		},
 	}
 
+	promo := "SPRING"
+	quote := &models.Quote{ListPrice: 100, Discount: 20, Price: 80, PromoCode: &promo}
+
 	tests := []struct {
 		name        string
 		userID      int
-		item        *models.Item
+		slug        string
+		sku         string
+		promoCode   string
+		coins       int
+		saved       int
+		boughtSKU   string // the variant passed to the storage, empty if the purchase isn't made
+		mockQuote   *models.Quote
 		mockError   error
 		expectedErr error
 	}{
 		{
-			name:        "No errors",
+			name:      "No errors",
+			userID:    1,
+			slug:      item.Slug,
+			coins:     150,
+			boughtSKU: "valid-item",
+			mockQuote: &models.Quote{ListPrice: 100, Price: 100},
+		},
+		{
+			name:      "Variant",
+			userID:    1,
+			slug:      item.Slug,
+			sku:       "valid-item-xl",
+			coins:     300,
+			boughtSKU: "valid-item-xl",
+			mockQuote: &models.Quote{ListPrice: 300, Price: 300, Variant: "valid-item-xl"},
+		},
+		{
+			name:        "Item not found",
+			userID:      1,
+			slug:        "unknown",
+			expectedErr: models.ErrItemNotFound,
+		},
+		{
+			name:        "Variant not found",
+			userID:      1,
+			slug:        item.Slug,
+			sku:         "valid-item-xxs",
+			expectedErr: models.ErrVariantNotFound,
+		},
+		{
+			name:        "Not enough coins for the variant",
+			userID:      1,
+			slug:        item.Slug,
+			sku:         "valid-item-xl",
+			coins:       150,
+			saved:       100,
+			expectedErr: models.ErrNotEnoughCoins,
+		},
+		{
+			name:      "Saved coins pay the rest",
+			userID:    1,
+			slug:      item.Slug,
+			sku:       "valid-item-xl",
+			coins:     150,
+			saved:     150,
+			boughtSKU: "valid-item-xl",
+			mockQuote: &models.Quote{ListPrice: 300, Price: 300, Variant: "valid-item-xl"},
+		},
+		{
+			name:      "With promo code",
+			userID:    1,
+			slug:      item.Slug,
+			promoCode: promo,
+			coins:     50, // the balance is checked during the purchase when the promo code lowers the price
+			boughtSKU: "valid-item",
+			mockQuote: quote,
+		},
+		{
+			name:        "Promo code already used",
 			userID:      1,
-			item:        item,
-			mockError:   nil,
-			expectedErr: nil,
+			slug:        item.Slug,
+			promoCode:   promo,
+			coins:       150,
+			boughtSKU:   "valid-item",
+			mockError:   models.ErrPromoCodeUsed,
+			expectedErr: models.ErrPromoCodeUsed,
 		},
 		{
 			name:        "Database error",
 			userID:      1,
-			item:        item,
+			slug:        item.Slug,
+			coins:       150,
+			boughtSKU:   "valid-item",
 			mockError:   errors.New("database error"),
 			expectedErr: errors.New("database error"),
 		},
+		{
+			name:        "Sold out",
+			userID:      1,
+			slug:        item.Slug,
+			coins:       150,
+			boughtSKU:   "valid-item",
+			mockError:   models.ErrSoldOut,
+			expectedErr: models.ErrSoldOut,
+		},
+		{
+			name:        "Limit reached",
+			userID:      1,
+			slug:        item.Slug,
+			coins:       150,
+			boughtSKU:   "valid-item",
+			mockError:   models.ErrLimitReached,
+			expectedErr: models.ErrLimitReached,
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,13 +339,93 @@ func TestBuyItemService_BuyItem(t *testing.T) {
 			service := New(mockDB)
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
-			mockDB.On("MakePurchaseByUserID", mock.Anything, tt.userID, tt.item).Return(tt.mockError)
+			if tt.slug == item.Slug {
+				mockDB.On("GetItemBySlug", mock.Anything, tt.slug).Return(item, nil)
+				mockDB.On("GetActiveDiscounts", mock.Anything).Return(&[]models.Discount{}, nil)
+			} else {
+				mockDB.On("GetItemBySlug", mock.Anything, tt.slug).Return(nil, sql.ErrNoRows)
+			}
+			mockDB.On("GetCoinsByUserID", mock.Anything, tt.userID).Return(tt.coins, nil).Maybe()
+			mockDB.On("GetSavedCoinsByUserID", mock.Anything, tt.userID, item.Slug).Return(tt.saved, nil).Maybe()
+
+			order := &models.Order{Variant: tt.sku, PromoCode: tt.promoCode}
+			if tt.boughtSKU != "" {
+				bought := &models.Order{Variant: tt.boughtSKU, PromoCode: tt.promoCode}
+				mockDB.On("MakePurchaseByUserID", mock.Anything, tt.userID, item, bought).Return(tt.mockQuote, tt.mockError)
+			}
 
-			err := service.BuyItem(ctx, tt.userID, tt.item)
+			q, err := service.BuyItem(ctx, tt.userID, tt.slug, order)
 			if tt.expectedErr != nil {
 				require.Error(t, err)
+				require.Equal(t, tt.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.mockQuote, q)
+			}
+
+			mockDB.AssertExpectations(t)
+
+			ctxCancel()
+		})
+	}
+}
+
+func TestBuyItemService_GetCatalog(t *testing.T) {
+	stock := 50
+	salePrice := 15
+	cup := "cup"
+	discounts := &[]models.Discount{{ItemSlug: &cup, Kind: models.DiscountFixed, Value: 5}}
+	wishlist := &[]models.Item{{Slug: "pink-hoody"}}
+
+	tests := []struct {
+		name      string
+		mockItems *[]models.Item
+		mockError error
+		wantItems *[]models.Item
+		wantErr   bool
+	}{
+		{
+			name: "Catalog with limited and unlimited items",
+			mockItems: &[]models.Item{
+				{Slug: "cup", Title: "Cup", Price: 20},
+				{Slug: "pink-hoody", Title: "Pink Hoody", Price: 500, Stock: &stock},
+			},
+			wantItems: &[]models.Item{
+				{Slug: "cup", Title: "Cup", Price: 20, SalePrice: &salePrice},
+				{Slug: "pink-hoody", Title: "Pink Hoody", Price: 500, Stock: &stock, InWishlist: true},
+			},
+		},
+		{
+			name:      "Empty catalog",
+			mockItems: nil,
+			wantItems: &[]models.Item{},
+		},
+		{
+			name:      "Database error",
+			mockError: errors.New("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+
+			mockDB.On("GetItems", mock.Anything).Return(tt.mockItems, tt.mockError)
+			if tt.mockItems != nil {
+				mockDB.On("GetActiveDiscounts", mock.Anything).Return(discounts, nil)
+				mockDB.On("GetWishlistByUserID", mock.Anything, 1).Return(wishlist, nil)
+			}
+
+			items, err := service.GetCatalog(ctx, 1)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, items)
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, tt.wantItems, items)
 			}
 
 			mockDB.AssertExpectations(t)
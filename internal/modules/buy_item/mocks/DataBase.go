@@ -4,8 +4,8 @@ package mocks
 
 import (
 	context "context"
-	models "github.com/kk7453603/avito_2024_summer/internal/models"
 
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +14,36 @@ type DataBase struct {
 	mock.Mock
 }
 
+// GetActiveDiscounts provides a mock function with given fields: ctx
+func (_m *DataBase) GetActiveDiscounts(ctx context.Context) (*[]models.Discount, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveDiscounts")
+	}
+
+	var r0 *[]models.Discount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.Discount, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.Discount); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Discount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCoinsByUserID provides a mock function with given fields: ctx, userID
 func (_m *DataBase) GetCoinsByUserID(ctx context.Context, userID int) (int, error) {
 	ret := _m.Called(ctx, userID)
@@ -72,22 +102,122 @@ func (_m *DataBase) GetItemBySlug(ctx context.Context, slug string) (*models.Ite
 	return r0, r1
 }
 
-// MakePurchaseByUserID provides a mock function with given fields: ctx, userID, item
-func (_m *DataBase) MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item) error {
-	ret := _m.Called(ctx, userID, item)
+// GetItems provides a mock function with given fields: ctx
+func (_m *DataBase) GetItems(ctx context.Context) (*[]models.Item, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItems")
+	}
+
+	var r0 *[]models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.Item, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.Item); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSavedCoinsByUserID provides a mock function with given fields: ctx, userID, slug
+func (_m *DataBase) GetSavedCoinsByUserID(ctx context.Context, userID int, slug string) (int, error) {
+	ret := _m.Called(ctx, userID, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSavedCoinsByUserID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int, error)); ok {
+		return rf(ctx, userID, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int); ok {
+		r0 = rf(ctx, userID, slug)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWishlistByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetWishlistByUserID(ctx context.Context, userID int) (*[]models.Item, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWishlistByUserID")
+	}
+
+	var r0 *[]models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Item, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Item); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakePurchaseByUserID provides a mock function with given fields: ctx, userID, item, order
+func (_m *DataBase) MakePurchaseByUserID(ctx context.Context, userID int, item *models.Item, order *models.Order) (*models.Quote, error) {
+	ret := _m.Called(ctx, userID, item, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakePurchaseByUserID")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Item) error); ok {
-		r0 = rf(ctx, userID, item)
+	var r0 *models.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Item, *models.Order) (*models.Quote, error)); ok {
+		return rf(ctx, userID, item, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Item, *models.Order) *models.Quote); ok {
+		r0 = rf(ctx, userID, item, order)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Quote)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Item, *models.Order) error); ok {
+		r1 = rf(ctx, userID, item, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -0,0 +1,49 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package catalog provides functionality for managing the store's assortment,
+// such as the variants (sizes, colours) of items with their own prices and stock.
+package catalog
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase interface defines methods for storing the store's assortment.
+type DataBase interface {
+	CreateVariant(ctx context.Context, variant *models.Variant) error
+	UpdateVariant(ctx context.Context, variant *models.Variant) error
+	RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error)
+}
+
+// Service provides functionality for managing the store's assortment.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// CreateVariant adds a new variant to the item. Only the variant created by the migration
+// is the default one, new variants are always additional.
+// Returns models.ErrItemNotFound if the item doesn't exist.
+func (s *Service) CreateVariant(ctx context.Context, slug string, variant *models.Variant) error {
+	variant.ItemSlug = slug
+	variant.IsDefault = false
+	return s.storage.CreateVariant(ctx, variant)
+}
+
+// UpdateVariant replaces the description, price override and stock of the variant with the given SKU.
+func (s *Service) UpdateVariant(ctx context.Context, sku string, variant *models.Variant) error {
+	variant.SKU = sku
+	return s.storage.UpdateVariant(ctx, variant)
+}
+
+// RestockVariant adds the delivered quantity to the variant's stock.
+// The users who wish the item are notified if the variant was sold out.
+func (s *Service) RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error) {
+	return s.storage.RestockVariant(ctx, sku, quantity)
+}
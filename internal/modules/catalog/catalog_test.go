@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/catalog/mocks"
+)
+
+func TestService_CreateVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant *models.Variant
+		dbErr   error
+	}{
+		{
+			name:    "New variant",
+			variant: &models.Variant{SKU: "hoody-xl", Title: "XL"},
+		},
+		{
+			name:    "Default flag is ignored",
+			variant: &models.Variant{SKU: "hoody-xs", Title: "XS", IsDefault: true},
+		},
+		{
+			name:    "Item not found",
+			variant: &models.Variant{SKU: "cap-m", Title: "M"},
+			dbErr:   models.ErrItemNotFound,
+		},
+		{
+			name:    "Duplicate SKU",
+			variant: &models.Variant{SKU: "hoody", Title: "Hoody"},
+			dbErr:   models.ErrVariantExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("CreateVariant", mock.Anything, mock.MatchedBy(func(v *models.Variant) bool {
+				return v.ItemSlug == "hoody" && !v.IsDefault
+			})).Return(tt.dbErr).Once()
+
+			err := service.CreateVariant(ctx, "hoody", tt.variant)
+			require.ErrorIs(t, err, tt.dbErr)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_UpdateVariant(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	stock := 5
+	variant := &models.Variant{SKU: "ignored", Title: "XL", Stock: &stock}
+	mockDB.On("UpdateVariant", mock.Anything, mock.MatchedBy(func(v *models.Variant) bool {
+		return v.SKU == "hoody-xl" && *v.Stock == 5
+	})).Return(nil).Once()
+
+	err := service.UpdateVariant(ctx, "hoody-xl", variant)
+	require.NoError(t, err)
+	mockDB.AssertExpectations(t)
+
+	mockDB.On("UpdateVariant", mock.Anything, mock.Anything).Return(models.ErrVariantNotFound).Once()
+	err = service.UpdateVariant(ctx, "missing", &models.Variant{Title: "M"})
+	require.ErrorIs(t, err, models.ErrVariantNotFound)
+}
+
+func TestService_RestockVariant(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	stock := 10
+	restocked := &models.Variant{SKU: "hoody-xl", ItemSlug: "hoody", Title: "XL", Stock: &stock}
+	mockDB.On("RestockVariant", mock.Anything, "hoody-xl", 10).Return(restocked, nil).Once()
+	mockDB.On("RestockVariant", mock.Anything, "missing", 1).Return(nil, models.ErrVariantNotFound).Once()
+
+	variant, err := service.RestockVariant(ctx, "hoody-xl", 10)
+	require.NoError(t, err)
+	require.Equal(t, restocked, variant)
+
+	variant, err = service.RestockVariant(ctx, "missing", 1)
+	require.ErrorIs(t, err, models.ErrVariantNotFound)
+	require.Nil(t, variant)
+	mockDB.AssertExpectations(t)
+}
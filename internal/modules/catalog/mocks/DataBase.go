@@ -0,0 +1,95 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// CreateVariant provides a mock function with given fields: ctx, variant
+func (_m *DataBase) CreateVariant(ctx context.Context, variant *models.Variant) error {
+	ret := _m.Called(ctx, variant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Variant) error); ok {
+		r0 = rf(ctx, variant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestockVariant provides a mock function with given fields: ctx, sku, quantity
+func (_m *DataBase) RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error) {
+	ret := _m.Called(ctx, sku, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestockVariant")
+	}
+
+	var r0 *models.Variant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*models.Variant, error)); ok {
+		return rf(ctx, sku, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *models.Variant); ok {
+		r0 = rf(ctx, sku, quantity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Variant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, sku, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateVariant provides a mock function with given fields: ctx, variant
+func (_m *DataBase) UpdateVariant(ctx context.Context, variant *models.Variant) error {
+	ret := _m.Called(ctx, variant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Variant) error); ok {
+		r0 = rf(ctx, variant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,153 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package coin_request provides functionality for asking colleagues for coins, such as splitting the cost
+// of a shared gift, and the background job expiring unanswered requests.
+package coin_request
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// Config holds configuration settings for coin requests.
+type Config struct {
+	TTL          time.Duration `envconfig:"TTL" default:"72h"` // a request expires if not answered in time
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"1m"`
+}
+
+// DataBase interface defines methods for managing coin requests.
+type DataBase interface {
+	GetIDByUsername(ctx context.Context, username string) (int, error)
+	CreateCoinRequest(ctx context.Context, r *models.CoinRequest) error
+	GetIncomingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error)
+	GetOutgoingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error)
+	GetIncomingCoinRequestsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error)
+	GetOutgoingCoinRequestsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error)
+	GetCoinRequest(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)
+	ResolveCoinRequest(ctx context.Context, userID, requestID int, status string, rules *models.TransferRules) (*models.CoinRequest, error)
+	ExpireCoinRequests(ctx context.Context) (int, error)
+}
+
+// TransferRules provides the anti-fraud rules the payments are checked against.
+type TransferRules interface {
+	Rules() *models.TransferRules
+}
+
+// Service provides functionality for managing coin requests.
+type Service struct {
+	storage DataBase
+	rules   TransferRules
+	cfg     *Config
+	now     func() time.Time
+}
+
+// New creates a new instance of Service with the given storage, anti-fraud rules and configuration.
+func New(storage DataBase, rules TransferRules, cfg *Config) *Service {
+	return &Service{
+		storage: storage,
+		rules:   rules,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// CreateRequest asks the colleague for coins on behalf of the user, the request expires after the TTL.
+func (s *Service) CreateRequest(ctx context.Context, userID int, c *models.CoinRequestCreation) (*models.CoinRequest, error) {
+	payerID, err := s.storage.GetIDByUsername(ctx, c.User)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrPayerNotFound
+	} else if err != nil {
+		return nil, err
+	} else if payerID == userID {
+		return nil, models.ErrSelfPayer
+	}
+
+	request := &models.CoinRequest{
+		RequesterID: userID,
+		PayerID:     payerID,
+		Payer:       c.User,
+		Amount:      c.Amount,
+		Note:        c.Note,
+		ExpiresAt:   s.now().UTC().Add(s.cfg.TTL),
+	}
+	if err = s.storage.CreateCoinRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetRequests lists the pending requests the user is asked to pay and the user's own requests.
+func (s *Service) GetRequests(ctx context.Context, userID int) (*models.CoinRequests, error) {
+	incoming, err := s.storage.GetIncomingCoinRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	outgoing, err := s.storage.GetOutgoingCoinRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CoinRequests{Incoming: incoming, Outgoing: outgoing}, nil
+}
+
+// GetIncomingPage retrieves a page of the pending requests addressed to the user, the oldest first.
+func (s *Service) GetIncomingPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error) {
+	return s.storage.GetIncomingCoinRequestsPage(ctx, userID, limit, offset)
+}
+
+// GetOutgoingPage retrieves a page of the requests made by the user, the latest first.
+func (s *Service) GetOutgoingPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error) {
+	return s.storage.GetOutgoingCoinRequestsPage(ctx, userID, limit, offset)
+}
+
+// GetRequest retrieves the request made by or addressed to the user along with its history.
+func (s *Service) GetRequest(ctx context.Context, userID, requestID int) (*models.CoinRequest, error) {
+	return s.storage.GetCoinRequest(ctx, userID, requestID)
+}
+
+// Accept pays the coin request addressed to the user. The payment is checked against the anti-fraud rules
+// in the same transaction.
+func (s *Service) Accept(ctx context.Context, userID, requestID int) (*models.CoinRequest, error) {
+	request, err := s.storage.GetCoinRequest(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	} else if request.PayerID != userID || request.Status != models.CoinRequestPending {
+		return nil, models.ErrCoinRequestNotFound
+	}
+
+	return s.storage.ResolveCoinRequest(ctx, userID, requestID, models.CoinRequestAccepted, s.rules.Rules())
+}
+
+// Decline refuses the coin request addressed to the user.
+func (s *Service) Decline(ctx context.Context, userID, requestID int) (*models.CoinRequest, error) {
+	return s.storage.ResolveCoinRequest(ctx, userID, requestID, models.CoinRequestDeclined, nil)
+}
+
+// Cancel withdraws the user's coin request.
+func (s *Service) Cancel(ctx context.Context, userID, requestID int) (*models.CoinRequest, error) {
+	return s.storage.ResolveCoinRequest(ctx, userID, requestID, models.CoinRequestCancelled, nil)
+}
+
+// Run marks the expired requests every poll interval until the context is done.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if expired, err := s.storage.ExpireCoinRequests(ctx); err != nil {
+			logg.Error("coin_request.ExpireCoinRequests", "err", err.Error())
+		} else if expired > 0 {
+			logg.Info("coin requests expired", "count", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package coin_request
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/coin_request/mocks"
+)
+
+func TestService_CreateRequest(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		payer     string
+		payerID   int
+		lookupErr error
+		wantErr   error
+	}{
+		{
+			name:    "Request created",
+			payer:   "engineer-e2",
+			payerID: 2,
+		},
+		{
+			name:      "Payer not found",
+			payer:     "abracadabra",
+			lookupErr: sql.ErrNoRows,
+			wantErr:   models.ErrPayerNotFound,
+		},
+		{
+			name:    "Asking myself",
+			payer:   "engineer-e1",
+			payerID: 1,
+			wantErr: models.ErrSelfPayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, mocks.NewTransferRules(t), &Config{TTL: 72 * time.Hour})
+			service.now = func() time.Time { return now }
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetIDByUsername", mock.Anything, tt.payer).Return(tt.payerID, tt.lookupErr).Once()
+			if tt.wantErr == nil {
+				mockDB.On("CreateCoinRequest", mock.Anything, mock.MatchedBy(func(r *models.CoinRequest) bool {
+					return r.RequesterID == 1 && r.PayerID == tt.payerID && r.ExpiresAt.Equal(now.Add(72*time.Hour))
+				})).Return(nil).Once()
+			}
+
+			request, err := service.CreateRequest(ctx, 1, &models.CoinRequestCreation{User: tt.payer, Amount: 50})
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, 50, request.Amount)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Accept(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  models.CoinRequest
+		rulesErr error
+		wantErr  error
+	}{
+		{
+			name:    "Request paid",
+			request: models.CoinRequest{ID: 5, RequesterID: 2, PayerID: 1, Amount: 50, Status: models.CoinRequestPending},
+		},
+		{
+			name:    "Own request",
+			request: models.CoinRequest{ID: 5, RequesterID: 1, PayerID: 2, Amount: 50, Status: models.CoinRequestPending},
+			wantErr: models.ErrCoinRequestNotFound,
+		},
+		{
+			name:    "Already declined",
+			request: models.CoinRequest{ID: 5, RequesterID: 2, PayerID: 1, Amount: 50, Status: models.CoinRequestDeclined},
+			wantErr: models.ErrCoinRequestNotFound,
+		},
+		{
+			name:     "Blocked by anti-fraud rules",
+			request:  models.CoinRequest{ID: 5, RequesterID: 2, PayerID: 1, Amount: 50, Status: models.CoinRequestPending},
+			rulesErr: models.ErrTransferLimitExceeded,
+			wantErr:  models.ErrTransferLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			mockRules := new(mocks.TransferRules)
+			service := New(mockDB, mockRules, &Config{})
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			rules := &models.TransferRules{Window: time.Hour}
+			mockDB.On("GetCoinRequest", mock.Anything, 1, 5).Return(&tt.request, nil).Once()
+			if tt.request.PayerID == 1 && tt.request.Status == models.CoinRequestPending {
+				mockRules.On("Rules").Return(rules).Once()
+				accepted := tt.request
+				accepted.Status = models.CoinRequestAccepted
+				if tt.rulesErr != nil {
+					mockDB.On("ResolveCoinRequest", mock.Anything, 1, 5, models.CoinRequestAccepted, rules).Return(nil, tt.rulesErr).Once()
+				} else {
+					mockDB.On("ResolveCoinRequest", mock.Anything, 1, 5, models.CoinRequestAccepted, rules).Return(&accepted, nil).Once()
+				}
+			}
+
+			request, err := service.Accept(ctx, 1, 5)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, models.CoinRequestAccepted, request.Status)
+			}
+			mockDB.AssertExpectations(t)
+			mockRules.AssertExpectations(t)
+		})
+	}
+}
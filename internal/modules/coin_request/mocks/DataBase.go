@@ -0,0 +1,283 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// CreateCoinRequest provides a mock function with given fields: ctx, r
+func (_m *DataBase) CreateCoinRequest(ctx context.Context, r *models.CoinRequest) error {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCoinRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CoinRequest) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireCoinRequests provides a mock function with given fields: ctx
+func (_m *DataBase) ExpireCoinRequests(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireCoinRequests")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoinRequest provides a mock function with given fields: ctx, userID, requestID
+func (_m *DataBase) GetCoinRequest(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoinRequest")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIDByUsername provides a mock function with given fields: ctx, username
+func (_m *DataBase) GetIDByUsername(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIDByUsername")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncomingCoinRequests provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetIncomingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIncomingCoinRequests")
+	}
+
+	var r0 *[]models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.CoinRequest, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.CoinRequest); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncomingCoinRequestsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetIncomingCoinRequestsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.CoinRequest], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIncomingCoinRequestsPage")
+	}
+
+	var r0 *models.Page[models.CoinRequest]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.CoinRequest], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.CoinRequest]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.CoinRequest])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutgoingCoinRequests provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetOutgoingCoinRequests(ctx context.Context, userID int) (*[]models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingCoinRequests")
+	}
+
+	var r0 *[]models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.CoinRequest, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.CoinRequest); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutgoingCoinRequestsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetOutgoingCoinRequestsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.CoinRequest], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingCoinRequestsPage")
+	}
+
+	var r0 *models.Page[models.CoinRequest]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.CoinRequest], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.CoinRequest]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.CoinRequest])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveCoinRequest provides a mock function with given fields: ctx, userID, requestID, status, rules
+func (_m *DataBase) ResolveCoinRequest(ctx context.Context, userID int, requestID int, status string, rules *models.TransferRules) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID, status, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveCoinRequest")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, *models.TransferRules) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID, status, rules)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, *models.TransferRules) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID, status, rules)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, *models.TransferRules) error); ok {
+		r1 = rf(ctx, userID, requestID, status, rules)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TransferRules is an autogenerated mock type for the TransferRules type
+type TransferRules struct {
+	mock.Mock
+}
+
+// Rules provides a mock function with no fields
+func (_m *TransferRules) Rules() *models.TransferRules {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rules")
+	}
+
+	var r0 *models.TransferRules
+	if rf, ok := ret.Get(0).(func() *models.TransferRules); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferRules)
+		}
+	}
+
+	return r0
+}
+
+// NewTransferRules creates a new instance of TransferRules. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransferRules(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransferRules {
+	mock := &TransferRules{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,240 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package directory provides the bulk import of users and the sync with the HR directory export,
+// which creates the accounts of new hires before they log in and deactivates the accounts of the leavers.
+// The users are read from CSV with a header, the username and grant columns are used and the others ignored,
+// or from a JSON array of objects with the same fields.
+package directory
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// Formats of the import and the directory export.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// usernamePattern is what a username must look like to log in.
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9]{8,}$`)
+
+// Config holds configuration settings for the sync with the HR directory.
+type Config struct {
+	File         string        `envconfig:"FILE"` // the directory export, the sync is off if not set
+	SyncInterval time.Duration `envconfig:"SYNC_INTERVAL" default:"24h"`
+}
+
+// DataBase interface defines methods for reconciling the accounts with the HR directory.
+type DataBase interface {
+	SyncDirectory(ctx context.Context, users []models.DirectoryUser, full bool) (*models.DirectorySync, error)
+}
+
+// Service provides the bulk import of users and the sync with the HR directory.
+type Service struct {
+	storage DataBase
+	cfg     *Config
+}
+
+// New creates a new instance of Service with the given storage and configuration.
+func New(storage DataBase, cfg *Config) *Service {
+	return &Service{storage: storage, cfg: cfg}
+}
+
+// FormatOf returns the format of the file by its extension, CSV unless it's .json.
+func FormatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatCSV
+}
+
+// Import creates the accounts of the listed users who don't have one yet, the users not listed are left alone.
+// The result holds the claim tokens of the created accounts, the users need them to log in the first time.
+func (s *Service) Import(ctx context.Context, r io.Reader, format string) (*models.DirectorySync, error) {
+	users, err := parse(r, format)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(ctx, users, false)
+}
+
+// Sync reconciles the accounts with the full HR directory export: the new hires' accounts are created
+// and the accounts of the users who aren't listed anymore are deactivated. An empty export is refused,
+// as it would deactivate everyone. The result holds the claim tokens of the created accounts, as Import's does.
+func (s *Service) Sync(ctx context.Context, r io.Reader, format string) (*models.DirectorySync, error) {
+	users, err := parse(r, format)
+	if err != nil {
+		return nil, err
+	} else if len(users) == 0 {
+		return nil, models.ErrInvalidDirectory.With("reason", "the export lists no users")
+	}
+	return s.sync(ctx, users, true)
+}
+
+// sync reconciles the accounts with the users, each listed with a claim token for the account if it's created.
+func (s *Service) sync(ctx context.Context, users []models.DirectoryUser, full bool) (*models.DirectorySync, error) {
+	tokens := make(map[string]string, len(users))
+	for i := range users {
+		token, hash, err := hasher.NewToken()
+		if err != nil {
+			return nil, err
+		}
+		tokens[users[i].Username], users[i].ClaimTokenHash = token, hash
+	}
+
+	result, err := s.storage.SyncDirectory(ctx, users, full)
+	if err != nil {
+		return nil, err
+	}
+	result.ClaimTokens = make(map[string]string, len(result.Created))
+	for _, username := range result.Created {
+		result.ClaimTokens[username] = tokens[username]
+	}
+	return result, nil
+}
+
+// Run syncs the accounts with the configured directory export file periodically until the context is cancelled.
+// It does nothing if no file is configured.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	if s.cfg.File == "" {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.syncFile(ctx); err != nil {
+			logg.Error("directory.Sync", "file", s.cfg.File, "err", err.Error())
+		} else if len(result.Created)+len(result.Adopted)+len(result.Reactivated)+len(result.Deactivated) > 0 {
+			logg.Info("accounts synced with the directory", "created", len(result.Created), "adopted", len(result.Adopted),
+				"reactivated", len(result.Reactivated), "deactivated", len(result.Deactivated))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncFile syncs the accounts with the configured directory export file.
+func (s *Service) syncFile(ctx context.Context) (*models.DirectorySync, error) {
+	f, err := os.Open(s.cfg.File)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.Sync(ctx, f, FormatOf(s.cfg.File))
+}
+
+// parse reads the users in the format and checks them. Returns models.ErrInvalidDirectory, with the number
+// of the offending entry counted from 1, if the users can't be read or a user is invalid or listed twice.
+func parse(r io.Reader, format string) ([]models.DirectoryUser, error) {
+	var (
+		users []models.DirectoryUser
+		err   error
+	)
+	switch format {
+	case FormatCSV:
+		users, err = parseCSV(r)
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&users)
+		if err != nil {
+			err = models.ErrInvalidDirectory.With("reason", err.Error())
+		}
+	default:
+		return nil, models.ErrInvalidDirectory.With("reason", "the format must be csv or json")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(users))
+	for i, u := range users {
+		reason := ""
+		switch {
+		case !usernamePattern.MatchString(u.Username):
+			reason = "the username must be at least 8 letters or digits"
+		case seen[u.Username]:
+			reason = "the user is listed twice"
+		case u.Grant < 0:
+			reason = "the grant must not be negative"
+		}
+		if reason != "" {
+			return nil, models.ErrInvalidDirectory.With("reason", reason).With("entry", i+1)
+		}
+		seen[u.Username] = true
+	}
+	return users, nil
+}
+
+// parseCSV reads the users from CSV with a header naming the columns.
+func parseCSV(r io.Reader) ([]models.DirectoryUser, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	} else if err != nil {
+		return nil, models.ErrInvalidDirectory.With("reason", err.Error())
+	}
+	usernameCol, grantCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "username":
+			usernameCol = i
+		case "grant":
+			grantCol = i
+		}
+	}
+	if usernameCol < 0 {
+		return nil, models.ErrInvalidDirectory.With("reason", "the header has no username column")
+	}
+
+	var users []models.DirectoryUser
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		} else if err != nil {
+			return nil, models.ErrInvalidDirectory.With("reason", err.Error())
+		}
+
+		u := models.DirectoryUser{Username: strings.TrimSpace(field(record, usernameCol))}
+		if grant := strings.TrimSpace(field(record, grantCol)); grant != "" {
+			u.Grant, err = strconv.Atoi(grant)
+			if err != nil {
+				return nil, models.ErrInvalidDirectory.With("reason", "the grant must be a whole number").
+					With("entry", len(users)+1)
+			}
+		}
+		users = append(users, u)
+	}
+}
+
+// field returns the record's field in the column, empty if the record is short or there is no such column.
+func field(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return record[col]
+}
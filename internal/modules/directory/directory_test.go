@@ -0,0 +1,156 @@
+package directory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/hasher"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/directory/mocks"
+)
+
+func TestService_Import(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		format    string
+		want      []models.DirectoryUser
+		wantEntry int // the entry the import fails on, 0 if it doesn't fail
+	}{
+		{
+			name:   "CSV with extra columns",
+			input:  "Full Name,Username,Grant\nAnna Petrova,annapetrova,100\nIvan Ivanov, ivanivanov ,\n",
+			format: FormatCSV,
+			want:   []models.DirectoryUser{{Username: "annapetrova", Grant: 100}, {Username: "ivanivanov"}},
+		},
+		{
+			name:   "CSV without grants",
+			input:  "username\nannapetrova\n",
+			format: FormatCSV,
+			want:   []models.DirectoryUser{{Username: "annapetrova"}},
+		},
+		{
+			name:   "JSON",
+			input:  `[{"username":"annapetrova","grant":100,"department":"sales"},{"username":"ivanivanov"}]`,
+			format: FormatJSON,
+			want:   []models.DirectoryUser{{Username: "annapetrova", Grant: 100}, {Username: "ivanivanov"}},
+		},
+		{
+			name:      "Username too short",
+			input:     "username\nannapetrova\nivan\n",
+			format:    FormatCSV,
+			wantEntry: 2,
+		},
+		{
+			name:      "User listed twice",
+			input:     `[{"username":"annapetrova"},{"username":"annapetrova"}]`,
+			format:    FormatJSON,
+			wantEntry: 2,
+		},
+		{
+			name:      "Negative grant",
+			input:     "username,grant\nannapetrova,-5\n",
+			format:    FormatCSV,
+			wantEntry: 1,
+		},
+		{
+			name:      "Grant not a number",
+			input:     "username,grant\nannapetrova,100\nivanivanov,lots\n",
+			format:    FormatCSV,
+			wantEntry: 2,
+		},
+		{
+			name:   "No username column",
+			input:  "login\nannapetrova\n",
+			format: FormatCSV,
+		},
+		{
+			name:   "Unknown format",
+			input:  "annapetrova",
+			format: "xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDataBase(t)
+			service := New(mockDB, &Config{})
+			result := &models.DirectorySync{Created: []string{"annapetrova"}}
+			if tt.want != nil {
+				mockDB.On("SyncDirectory", mock.Anything, listed(tt.want), false).Return(result, nil).Once()
+			}
+
+			got, err := service.Import(context.Background(), strings.NewReader(tt.input), tt.format)
+			if tt.want != nil {
+				require.NoError(t, err)
+				require.Equal(t, result, got)
+				require.Len(t, got.ClaimTokens["annapetrova"], 32)
+				return
+			}
+
+			require.ErrorIs(t, err, models.ErrInvalidDirectory)
+			if tt.wantEntry > 0 {
+				require.Equal(t, tt.wantEntry, apperr.From(err).Extensions()["entry"])
+			}
+		})
+	}
+}
+
+func TestService_Sync(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB, &Config{})
+	users := []models.DirectoryUser{{Username: "annapetrova"}}
+	result := &models.DirectorySync{Deactivated: []string{"ivanivanov"}, Unchanged: 1}
+	mockDB.On("SyncDirectory", mock.Anything, listed(users), true).Return(result, nil).Once()
+
+	got, err := service.Sync(context.Background(), strings.NewReader("username\nannapetrova\n"), FormatCSV)
+	require.NoError(t, err)
+	require.Equal(t, result, got)
+
+	// an empty export would deactivate everyone
+	_, err = service.Sync(context.Background(), strings.NewReader("username\n"), FormatCSV)
+	require.ErrorIs(t, err, models.ErrInvalidDirectory)
+	_, err = service.Sync(context.Background(), strings.NewReader("[]"), FormatJSON)
+	require.ErrorIs(t, err, models.ErrInvalidDirectory)
+}
+
+func TestService_syncFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "employees.json")
+	require.NoError(t, os.WriteFile(file, []byte(`[{"username":"annapetrova","grant":50}]`), 0o600))
+
+	mockDB := mocks.NewDataBase(t)
+	service := New(mockDB, &Config{File: file})
+	result := &models.DirectorySync{Created: []string{"annapetrova"}}
+	var users []models.DirectoryUser
+	mockDB.On("SyncDirectory", mock.Anything, listed([]models.DirectoryUser{{Username: "annapetrova", Grant: 50}}), true).
+		Run(func(args mock.Arguments) { users = args.Get(1).([]models.DirectoryUser) }).
+		Return(result, nil).Once()
+
+	got, err := service.syncFile(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, result, got)
+	// only the hash of the token is stored
+	require.Equal(t, hasher.HashToken(got.ClaimTokens["annapetrova"]), users[0].ClaimTokenHash)
+}
+
+// listed matches the users passed to the storage, each listed with the hash of a claim token.
+func listed(want []models.DirectoryUser) any {
+	return mock.MatchedBy(func(users []models.DirectoryUser) bool {
+		if len(users) != len(want) {
+			return false
+		}
+		for i, u := range users {
+			if u.Username != want[i].Username || u.Grant != want[i].Grant || len(u.ClaimTokenHash) != 64 {
+				return false
+			}
+		}
+		return true
+	})
+}
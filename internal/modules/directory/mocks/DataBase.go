@@ -0,0 +1,60 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// SyncDirectory provides a mock function with given fields: ctx, users, full
+func (_m *DataBase) SyncDirectory(ctx context.Context, users []models.DirectoryUser, full bool) (*models.DirectorySync, error) {
+	ret := _m.Called(ctx, users, full)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncDirectory")
+	}
+
+	var r0 *models.DirectorySync
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.DirectoryUser, bool) (*models.DirectorySync, error)); ok {
+		return rf(ctx, users, full)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.DirectoryUser, bool) *models.DirectorySync); ok {
+		r0 = rf(ctx, users, full)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DirectorySync)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.DirectoryUser, bool) error); ok {
+		r1 = rf(ctx, users, full)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
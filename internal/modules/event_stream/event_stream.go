@@ -0,0 +1,174 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package event_stream provides functionality for streaming the changes of a user's balance, incoming transfers
+// and purchases. The storage notifies of new events on any replica, the open streams of the user are woken up
+// and read the events they haven't sent yet.
+package event_stream
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const eventsPage = 100 // number of events read at a time
+
+// Config holds configuration settings for the event streams.
+type Config struct {
+	Heartbeat     time.Duration `envconfig:"HEARTBEAT" default:"15s"`
+	MaxStreams    int           `envconfig:"MAX_STREAMS" default:"5"` // per user on each replica
+	Retention     time.Duration `envconfig:"RETENTION" default:"72h"`
+	RetryInterval time.Duration `envconfig:"RETRY_INTERVAL" default:"5s"`
+}
+
+// DataBase interface defines methods for reading and listening to the users' events.
+type DataBase interface {
+	GetUserEvents(ctx context.Context, userID int, afterID int64, limit int) (*[]models.UserEvent, error)
+	GetLastUserEventID(ctx context.Context, userID int) (int64, error)
+	DeleteUserEventsBefore(ctx context.Context, before time.Time) (int, error)
+	ListenUserEvents(ctx context.Context, notify func(userID int)) error
+}
+
+// Service provides functionality for streaming the users' events.
+type Service struct {
+	storage DataBase
+	cfg     *Config
+	now     func() time.Time
+
+	mu      sync.Mutex
+	streams map[int]map[chan struct{}]struct{} // wake-up channels of the open streams by user
+	closed  bool
+}
+
+// New creates a new instance of Service with the given storage and configuration.
+func New(storage DataBase, cfg *Config) *Service {
+	return &Service{
+		storage: storage,
+		cfg:     cfg,
+		now:     time.Now,
+		streams: make(map[int]map[chan struct{}]struct{}),
+	}
+}
+
+// Open opens a stream of the user's events. Returns the channel receiving a value when the user
+// may have new events, closed on shutdown, and the function closing the stream.
+func (s *Service) Open(userID int) (<-chan struct{}, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wake := make(chan struct{}, 1)
+	if s.closed {
+		close(wake)
+		return wake, func() {}, nil
+	}
+	if len(s.streams[userID]) >= s.cfg.MaxStreams {
+		return nil, nil, models.ErrTooManyStreams
+	}
+	if s.streams[userID] == nil {
+		s.streams[userID] = make(map[chan struct{}]struct{})
+	}
+	s.streams[userID][wake] = struct{}{}
+
+	return wake, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.streams[userID], wake)
+		if len(s.streams[userID]) == 0 {
+			delete(s.streams, userID)
+		}
+	}, nil
+}
+
+// Shutdown closes all the open streams and those opened later, so that the server can stop.
+func (s *Service) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for userID, streams := range s.streams {
+		for wake := range streams {
+			close(wake)
+		}
+		delete(s.streams, userID)
+	}
+}
+
+// GetEvents retrieves the user's next events following the given ID, the earliest first.
+func (s *Service) GetEvents(ctx context.Context, userID int, afterID int64) (*[]models.UserEvent, error) {
+	return s.storage.GetUserEvents(ctx, userID, afterID, eventsPage)
+}
+
+// LastEventID returns the ID of the user's latest event, a new stream starts after it.
+func (s *Service) LastEventID(ctx context.Context, userID int) (int64, error) {
+	return s.storage.GetLastUserEventID(ctx, userID)
+}
+
+// Heartbeat returns the interval of the heartbeats keeping the idle streams open.
+func (s *Service) Heartbeat() time.Duration {
+	return s.cfg.Heartbeat
+}
+
+// Run listens to the users' events and wakes up their streams until the context is done, listening again
+// after a failure. Removes the events older than the retention period every hour.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	// the clean-up is done by the time Run returns
+	var cleaning sync.WaitGroup
+	cleaning.Add(1)
+	go func() {
+		defer cleaning.Done()
+		s.cleanUp(ctx, logg)
+	}()
+	defer cleaning.Wait()
+
+	for {
+		if err := s.storage.ListenUserEvents(ctx, s.wake); err != nil && ctx.Err() == nil {
+			logg.Error("event_stream.ListenUserEvents", "err", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.RetryInterval):
+		}
+	}
+}
+
+// wake wakes up the user's open streams, all the streams if the user ID is zero.
+// A stream that hasn't read its events yet is already awake.
+func (s *Service) wake(userID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, streams := range s.streams {
+		if userID != 0 && id != userID {
+			continue
+		}
+		for wake := range streams {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// cleanUp removes the old events every hour until the context is done.
+func (s *Service) cleanUp(ctx context.Context, logg *slog.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.storage.DeleteUserEventsBefore(ctx, s.now().Add(-s.cfg.Retention)); err != nil && ctx.Err() == nil {
+			logg.Error("event_stream.DeleteUserEventsBefore", "err", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package event_stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/event_stream/mocks"
+)
+
+var cfg = &Config{Heartbeat: time.Second, MaxStreams: 2, Retention: time.Hour, RetryInterval: time.Second}
+
+// awake reports whether the stream has been woken up.
+func awake(wake <-chan struct{}) bool {
+	select {
+	case <-wake:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestService_Open(t *testing.T) {
+	service := New(new(mocks.DataBase), cfg)
+
+	first, closeFirst, err := service.Open(1)
+	require.NoError(t, err)
+	_, _, err = service.Open(1)
+	require.NoError(t, err)
+	_, _, err = service.Open(1)
+	require.ErrorIs(t, err, models.ErrTooManyStreams)
+	_, _, err = service.Open(2)
+	require.NoError(t, err, "the cap is per user")
+
+	// a closed stream frees its place and isn't woken up anymore
+	closeFirst()
+	_, _, err = service.Open(1)
+	require.NoError(t, err)
+	service.wake(1)
+	require.False(t, awake(first))
+}
+
+func TestService_Wake(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		wantFirst bool
+		wantOther bool
+	}{
+		{name: "User's streams", userID: 1, wantFirst: true},
+		{name: "All streams", userID: 0, wantFirst: true, wantOther: true},
+		{name: "User without streams", userID: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := New(new(mocks.DataBase), cfg)
+			first, _, err := service.Open(1)
+			require.NoError(t, err)
+			other, _, err := service.Open(2)
+			require.NoError(t, err)
+
+			// repeated notifications are coalesced
+			service.wake(tt.userID)
+			service.wake(tt.userID)
+
+			require.Equal(t, tt.wantFirst, awake(first))
+			require.False(t, awake(first))
+			require.Equal(t, tt.wantOther, awake(other))
+		})
+	}
+}
+
+func TestService_Shutdown(t *testing.T) {
+	service := New(new(mocks.DataBase), cfg)
+	open, closeOpen, err := service.Open(1)
+	require.NoError(t, err)
+
+	service.Shutdown()
+	closeOpen()
+
+	_, ok := <-open
+	require.False(t, ok)
+	late, _, err := service.Open(1)
+	require.NoError(t, err)
+	_, ok = <-late
+	require.False(t, ok)
+}
@@ -0,0 +1,136 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// DeleteUserEventsBefore provides a mock function with given fields: ctx, before
+func (_m *DataBase) DeleteUserEventsBefore(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserEventsBefore")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastUserEventID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetLastUserEventID(ctx context.Context, userID int) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastUserEventID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserEvents provides a mock function with given fields: ctx, userID, afterID, limit
+func (_m *DataBase) GetUserEvents(ctx context.Context, userID int, afterID int64, limit int) (*[]models.UserEvent, error) {
+	ret := _m.Called(ctx, userID, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserEvents")
+	}
+
+	var r0 *[]models.UserEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, int) (*[]models.UserEvent, error)); ok {
+		return rf(ctx, userID, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, int) *[]models.UserEvent); ok {
+		r0 = rf(ctx, userID, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.UserEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64, int) error); ok {
+		r1 = rf(ctx, userID, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListenUserEvents provides a mock function with given fields: ctx, notify
+func (_m *DataBase) ListenUserEvents(ctx context.Context, notify func(int)) error {
+	ret := _m.Called(ctx, notify)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListenUserEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(int)) error); ok {
+		r0 = rf(ctx, notify)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
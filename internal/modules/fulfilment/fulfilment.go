@@ -0,0 +1,90 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package fulfilment provides functionality for handing out purchased merch in the offices:
+// operators move purchases through the fulfilment statuses and see the pending work.
+package fulfilment
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// transitions lists for every status the statuses a purchase can be moved to it from,
+// all the known statuses are listed.
+var transitions = map[string][]string{
+	models.PurchaseReadyForPickup: {models.PurchasePending},
+	models.PurchaseHandedOver:     {models.PurchaseReadyForPickup},
+	models.PurchaseCancelled:      {models.PurchasePending, models.PurchaseReadyForPickup},
+	// back to the queue, e.g. the wrong size was prepared
+	models.PurchasePending: {models.PurchaseReadyForPickup},
+}
+
+// queue is the statuses of purchases the operators still have to deal with.
+var queue = []string{models.PurchasePending, models.PurchaseReadyForPickup}
+
+// DataBase interface defines methods for tracking the fulfilment of purchases.
+type DataBase interface {
+	GetPurchasesByStatus(ctx context.Context, statuses []string, office string) (*[]models.Purchase, error)
+	GetPurchasesPageByStatus(ctx context.Context, statuses []string, office string, limit, offset int) (*models.Page[models.Purchase], error)
+	ChangePurchaseStatus(ctx context.Context, purchaseID, operatorID int, from []string, to string) error
+}
+
+// Service provides functionality for tracking the fulfilment of purchases.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// GetQueue retrieves the purchases in the given status, or all the purchases not handed over yet
+// if the status is empty. If the office isn't empty, only purchases for that office are returned.
+func (s *Service) GetQueue(ctx context.Context, status, office string) (*[]models.Purchase, error) {
+	statuses, err := queueStatuses(status)
+	if err != nil {
+		return nil, err
+	}
+
+	purchases, err := s.storage.GetPurchasesByStatus(ctx, statuses, office)
+	if err != nil {
+		return nil, err
+	}
+	if purchases == nil {
+		return &[]models.Purchase{}, nil
+	}
+	return purchases, nil
+}
+
+// GetQueuePage retrieves a page of the purchases GetQueue does, the oldest first.
+func (s *Service) GetQueuePage(ctx context.Context, status, office string, limit, offset int) (*models.Page[models.Purchase], error) {
+	statuses, err := queueStatuses(status)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.GetPurchasesPageByStatus(ctx, statuses, office, limit, offset)
+}
+
+// queueStatuses returns the given status, or the statuses of the queue if it's empty.
+func queueStatuses(status string) ([]string, error) {
+	if status == "" {
+		return queue, nil
+	}
+	if _, ok := transitions[status]; !ok {
+		return nil, models.ErrInvalidPurchaseStatus
+	}
+	return []string{status}, nil
+}
+
+// ChangeStatus moves the purchase to the new status on behalf of the operator.
+// Cancelling a purchase refunds its price to the buyer.
+// Returns models.ErrInvalidStatusTransition if the purchase can't be moved from its current status.
+func (s *Service) ChangeStatus(ctx context.Context, operatorID, purchaseID int, status string) error {
+	from, ok := transitions[status]
+	if !ok {
+		return models.ErrInvalidPurchaseStatus
+	}
+	return s.storage.ChangePurchaseStatus(ctx, purchaseID, operatorID, from, status)
+}
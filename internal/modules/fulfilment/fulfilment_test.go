@@ -0,0 +1,150 @@
+package fulfilment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/fulfilment/mocks"
+)
+
+func TestService_GetQueue(t *testing.T) {
+	office := "moscow"
+	purchases := &[]models.Purchase{{ID: 1, User: "testUser1", Item: "hoody", Status: models.PurchasePending, Office: &office}}
+
+	tests := []struct {
+		name         string
+		status       string
+		office       string
+		wantStatuses []string
+		mockResult   *[]models.Purchase
+		mockError    error
+		want         *[]models.Purchase
+		wantErr      error
+	}{
+		{
+			name:         "Pending work by default",
+			office:       office,
+			wantStatuses: []string{models.PurchasePending, models.PurchaseReadyForPickup},
+			mockResult:   purchases,
+			want:         purchases,
+		},
+		{
+			name:         "Single status",
+			status:       models.PurchaseHandedOver,
+			wantStatuses: []string{models.PurchaseHandedOver},
+			want:         &[]models.Purchase{},
+		},
+		{
+			name:    "Unknown status",
+			status:  "lost",
+			wantErr: models.ErrInvalidPurchaseStatus,
+		},
+		{
+			name:         "Database error",
+			wantStatuses: []string{models.PurchasePending, models.PurchaseReadyForPickup},
+			mockError:    errors.New("database error"),
+			wantErr:      errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			if tt.wantStatuses != nil {
+				mockDB.On("GetPurchasesByStatus", mock.Anything, tt.wantStatuses, tt.office).
+					Return(tt.mockResult, tt.mockError).Once()
+			}
+
+			got, err := service.GetQueue(ctx, tt.status, tt.office)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetQueuePage(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	page := &models.Page[models.Purchase]{Items: []models.Purchase{{ID: 3}}, Total: 21, Limit: 10, Offset: 20}
+
+	mockDB.On("GetPurchasesPageByStatus", mock.Anything, []string{models.PurchasePending, models.PurchaseReadyForPickup}, "", 10, 20).
+		Return(page, nil).Once()
+
+	got, err := service.GetQueuePage(context.Background(), "", "", 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, page, got)
+
+	_, err = service.GetQueuePage(context.Background(), "lost", "", 10, 0)
+	require.Equal(t, models.ErrInvalidPurchaseStatus, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestService_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		wantFrom []string
+		dbErr    error
+		wantErr  error
+	}{
+		{
+			name:     "Ready for pickup",
+			status:   models.PurchaseReadyForPickup,
+			wantFrom: []string{models.PurchasePending},
+		},
+		{
+			name:     "Handed over",
+			status:   models.PurchaseHandedOver,
+			wantFrom: []string{models.PurchaseReadyForPickup},
+		},
+		{
+			name:     "Cancelled",
+			status:   models.PurchaseCancelled,
+			wantFrom: []string{models.PurchasePending, models.PurchaseReadyForPickup},
+		},
+		{
+			name:     "Handed over purchase can't be cancelled",
+			status:   models.PurchaseCancelled,
+			wantFrom: []string{models.PurchasePending, models.PurchaseReadyForPickup},
+			dbErr:    models.ErrInvalidStatusTransition,
+			wantErr:  models.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "Unknown status",
+			status:  "lost",
+			wantErr: models.ErrInvalidPurchaseStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			if tt.wantFrom != nil {
+				mockDB.On("ChangePurchaseStatus", mock.Anything, 10, 2, tt.wantFrom, tt.status).Return(tt.dbErr).Once()
+			}
+
+			err := service.ChangeStatus(ctx, 2, 10, tt.status)
+			require.ErrorIs(t, err, tt.wantErr)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// ChangePurchaseStatus provides a mock function with given fields: ctx, purchaseID, operatorID, from, to
+func (_m *DataBase) ChangePurchaseStatus(ctx context.Context, purchaseID int, operatorID int, from []string, to string) error {
+	ret := _m.Called(ctx, purchaseID, operatorID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePurchaseStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []string, string) error); ok {
+		r0 = rf(ctx, purchaseID, operatorID, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPurchasesByStatus provides a mock function with given fields: ctx, statuses, office
+func (_m *DataBase) GetPurchasesByStatus(ctx context.Context, statuses []string, office string) (*[]models.Purchase, error) {
+	ret := _m.Called(ctx, statuses, office)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchasesByStatus")
+	}
+
+	var r0 *[]models.Purchase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) (*[]models.Purchase, error)); ok {
+		return rf(ctx, statuses, office)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) *[]models.Purchase); ok {
+		r0 = rf(ctx, statuses, office)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Purchase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, statuses, office)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPurchasesPageByStatus provides a mock function with given fields: ctx, statuses, office, limit, offset
+func (_m *DataBase) GetPurchasesPageByStatus(ctx context.Context, statuses []string, office string, limit int, offset int) (*models.Page[models.Purchase], error) {
+	ret := _m.Called(ctx, statuses, office, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchasesPageByStatus")
+	}
+
+	var r0 *models.Page[models.Purchase]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, int, int) (*models.Page[models.Purchase], error)); ok {
+		return rf(ctx, statuses, office, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, int, int) *models.Page[models.Purchase]); ok {
+		r0 = rf(ctx, statuses, office, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Purchase])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string, int, int) error); ok {
+		r1 = rf(ctx, statuses, office, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,63 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package inventory provides functionality for managing items already owned by users,
+// such as returning them back to the shop or gifting them to other users.
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// Config holds configuration settings for the inventory module.
+type Config struct {
+	ReturnWindow time.Duration `envconfig:"RETURN_WINDOW" default:"336h"`
+}
+
+// DataBase interface defines methods for managing users' inventory.
+type DataBase interface {
+	ReturnItemByUserID(ctx context.Context, userID int, slug, sku string, window time.Duration) (int, error)
+	GetIDByUsername(ctx context.Context, username string) (int, error)
+	GiftItem(ctx context.Context, fromUserID, toUserID int, slug, sku string, quantity int) error
+}
+
+// Service provides functionality for managing users' inventory.
+type Service struct {
+	storage      DataBase
+	returnWindow time.Duration
+}
+
+// New creates a new instance of Service with the given storage and configuration.
+func New(storage DataBase, cfg *Config) *Service {
+	return &Service{
+		storage:      storage,
+		returnWindow: cfg.ReturnWindow,
+	}
+}
+
+// ReturnItem returns one unit of the item to the shop and refunds its purchase price.
+// If the SKU is empty, the latest purchased variant of the item is returned.
+// Returns the number of refunded coins.
+func (s *Service) ReturnItem(ctx context.Context, userID int, slug, sku string) (int, error) {
+	return s.storage.ReturnItemByUserID(ctx, userID, slug, sku, s.returnWindow)
+}
+
+// GiftItem moves the given quantity of the item's variant from the sender's inventory to the recipient's.
+// The default variant is gifted if the SKU is empty.
+func (s *Service) GiftItem(ctx context.Context, senderID int, recipient, slug, sku string, quantity int) error {
+	recipientID, err := s.storage.GetIDByUsername(ctx, recipient)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrRecipientNotFound
+	} else if err != nil {
+		return err
+	}
+	if recipientID == senderID {
+		return models.ErrSelfRecipient
+	}
+
+	return s.storage.GiftItem(ctx, senderID, recipientID, slug, sku, quantity)
+}
@@ -0,0 +1,154 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/inventory/mocks"
+)
+
+func TestService_ReturnItem(t *testing.T) {
+	cfg := &Config{ReturnWindow: 24 * time.Hour}
+
+	tests := []struct {
+		name       string
+		userID     int
+		slug       string
+		sku        string
+		refund     int
+		mockError  error
+		wantRefund int
+		wantErr    error
+	}{
+		{
+			name:       "Item returned",
+			userID:     1,
+			slug:       "hoody",
+			refund:     300,
+			wantRefund: 300,
+		},
+		{
+			name:       "Variant returned",
+			userID:     1,
+			slug:       "hoody",
+			sku:        "hoody-xl",
+			refund:     350,
+			wantRefund: 350,
+		},
+		{
+			name:      "Return window expired",
+			userID:    1,
+			slug:      "hoody",
+			mockError: models.ErrNoReturnablePurchase,
+			wantErr:   models.ErrNoReturnablePurchase,
+		},
+		{
+			name:      "Database error",
+			userID:    1,
+			slug:      "cup",
+			mockError: errors.New("database error"),
+			wantErr:   errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, cfg)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+
+			mockDB.On("ReturnItemByUserID", mock.Anything, tt.userID, tt.slug, tt.sku, cfg.ReturnWindow).
+				Return(tt.refund, tt.mockError).Once()
+
+			refund, err := service.ReturnItem(ctx, tt.userID, tt.slug, tt.sku)
+
+			require.Equal(t, tt.wantRefund, refund)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tt.wantErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			mockDB.AssertExpectations(t)
+			ctxCancel()
+		})
+	}
+}
+
+func TestService_GiftItem(t *testing.T) {
+	cfg := &Config{ReturnWindow: 24 * time.Hour}
+
+	tests := []struct {
+		name        string
+		senderID    int
+		recipient   string
+		recipientID int
+		lookupErr   error
+		giftErr     error
+		callGift    bool
+		wantErr     error
+	}{
+		{
+			name:        "Item gifted",
+			senderID:    1,
+			recipient:   "colleague",
+			recipientID: 2,
+			callGift:    true,
+		},
+		{
+			name:      "Recipient not found",
+			senderID:  1,
+			recipient: "nobody",
+			lookupErr: sql.ErrNoRows,
+			wantErr:   models.ErrRecipientNotFound,
+		},
+		{
+			name:        "Gift to yourself",
+			senderID:    1,
+			recipient:   "myself",
+			recipientID: 1,
+			wantErr:     models.ErrSelfRecipient,
+		},
+		{
+			name:        "Not enough items",
+			senderID:    1,
+			recipient:   "colleague",
+			recipientID: 2,
+			giftErr:     models.ErrNotEnoughItems,
+			callGift:    true,
+			wantErr:     models.ErrNotEnoughItems,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, cfg)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+
+			mockDB.On("GetIDByUsername", mock.Anything, tt.recipient).Return(tt.recipientID, tt.lookupErr).Once()
+			if tt.callGift {
+				mockDB.On("GiftItem", mock.Anything, tt.senderID, tt.recipientID, "cup", "", 2).Return(tt.giftErr).Once()
+			}
+
+			err := service.GiftItem(ctx, tt.senderID, tt.recipient, "cup", "", 2)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			mockDB.AssertExpectations(t)
+			ctxCancel()
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// GetIDByUsername provides a mock function with given fields: ctx, username
+func (_m *DataBase) GetIDByUsername(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIDByUsername")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GiftItem provides a mock function with given fields: ctx, fromUserID, toUserID, slug, sku, quantity
+func (_m *DataBase) GiftItem(ctx context.Context, fromUserID int, toUserID int, slug string, sku string, quantity int) error {
+	ret := _m.Called(ctx, fromUserID, toUserID, slug, sku, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GiftItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string, int) error); ok {
+		r0 = rf(ctx, fromUserID, toUserID, slug, sku, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReturnItemByUserID provides a mock function with given fields: ctx, userID, slug, sku, window
+func (_m *DataBase) ReturnItemByUserID(ctx context.Context, userID int, slug string, sku string, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, userID, slug, sku, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnItemByUserID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, time.Duration) (int, error)); ok {
+		return rf(ctx, userID, slug, sku, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, time.Duration) int); ok {
+		r0 = rf(ctx, userID, slug, sku, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, slug, sku, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package leaderboard provides functionality for ranking users by the coins received
+// from colleagues and by the items collected.
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	defaultLimit = 10  // number of places shown when the limit isn't given
+	maxLimit     = 100 // maximum number of places shown
+)
+
+// periods maps the leaderboard periods to their length, zero means all time.
+var periods = map[string]time.Duration{
+	models.PeriodWeek:    7 * 24 * time.Hour,
+	models.PeriodMonth:   30 * 24 * time.Hour,
+	models.PeriodAllTime: 0,
+}
+
+// DataBase interface defines methods for ranking users.
+type DataBase interface {
+	GetCoinsLeaderboard(ctx context.Context, period time.Duration, limit int) (*[]models.LeaderboardEntry, error)
+	GetItemsLeaderboard(ctx context.Context, limit int) (*[]models.LeaderboardEntry, error)
+	SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error
+}
+
+// Service provides functionality for ranking users.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// GetLeaderboard ranks users by the coins received within the period or by the items collected.
+// Items are ranked all-time only. A non-positive limit falls back to the default one,
+// the limit can't exceed 100 places.
+func (s *Service) GetLeaderboard(ctx context.Context, by, period string, limit int) (*[]models.LeaderboardEntry, error) {
+	length, ok := periods[period]
+	if !ok {
+		return nil, models.ErrInvalidLeaderboard
+	}
+
+	switch {
+	case limit <= 0:
+		limit = defaultLimit
+	case limit > maxLimit:
+		limit = maxLimit
+	}
+
+	var entries *[]models.LeaderboardEntry
+	var err error
+	switch by {
+	case models.LeaderboardByCoins:
+		entries, err = s.storage.GetCoinsLeaderboard(ctx, length, limit)
+	case models.LeaderboardByItems:
+		if period != models.PeriodAllTime {
+			return nil, models.ErrInvalidLeaderboard
+		}
+		entries, err = s.storage.GetItemsLeaderboard(ctx, limit)
+	default:
+		return nil, models.ErrInvalidLeaderboard
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		return &[]models.LeaderboardEntry{}, nil
+	}
+	return entries, nil
+}
+
+// SetVisibility shows the user on the leaderboards or hides them.
+func (s *Service) SetVisibility(ctx context.Context, userID int, visible bool) error {
+	return s.storage.SetLeaderboardOptOut(ctx, userID, !visible)
+}
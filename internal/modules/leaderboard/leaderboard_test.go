@@ -0,0 +1,124 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/leaderboard/mocks"
+)
+
+func TestService_GetLeaderboard(t *testing.T) {
+	entries := &[]models.LeaderboardEntry{
+		{Rank: 1, User: "alice", Value: 300},
+		{Rank: 2, User: "bob", Value: 150},
+	}
+
+	tests := []struct {
+		name       string
+		by         string
+		period     string
+		limit      int
+		mockMethod string
+		mockArgs   []any
+		mockData   *[]models.LeaderboardEntry
+		mockError  error
+		want       *[]models.LeaderboardEntry
+		wantErr    error
+	}{
+		{
+			name:       "Coins received this week",
+			by:         models.LeaderboardByCoins,
+			period:     models.PeriodWeek,
+			limit:      5,
+			mockMethod: "GetCoinsLeaderboard",
+			mockArgs:   []any{7 * 24 * time.Hour, 5},
+			mockData:   entries,
+			want:       entries,
+		},
+		{
+			name:       "Coins received all time, default limit",
+			by:         models.LeaderboardByCoins,
+			period:     models.PeriodAllTime,
+			mockMethod: "GetCoinsLeaderboard",
+			mockArgs:   []any{time.Duration(0), defaultLimit},
+			want:       &[]models.LeaderboardEntry{},
+		},
+		{
+			name:       "Items collected, limit capped",
+			by:         models.LeaderboardByItems,
+			period:     models.PeriodAllTime,
+			limit:      1000,
+			mockMethod: "GetItemsLeaderboard",
+			mockArgs:   []any{maxLimit},
+			mockData:   entries,
+			want:       entries,
+		},
+		{
+			name:    "Items collected per month",
+			by:      models.LeaderboardByItems,
+			period:  models.PeriodMonth,
+			wantErr: models.ErrInvalidLeaderboard,
+		},
+		{
+			name:    "Unknown ranking",
+			by:      "gifts",
+			period:  models.PeriodAllTime,
+			wantErr: models.ErrInvalidLeaderboard,
+		},
+		{
+			name:    "Unknown period",
+			by:      models.LeaderboardByCoins,
+			period:  "year",
+			wantErr: models.ErrInvalidLeaderboard,
+		},
+		{
+			name:       "Database error",
+			by:         models.LeaderboardByCoins,
+			period:     models.PeriodMonth,
+			mockMethod: "GetCoinsLeaderboard",
+			mockArgs:   []any{30 * 24 * time.Hour, defaultLimit},
+			mockError:  errors.New("database error"),
+			wantErr:    errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			if tt.mockMethod != "" {
+				args := append([]any{mock.Anything}, tt.mockArgs...)
+				mockDB.On(tt.mockMethod, args...).Return(tt.mockData, tt.mockError)
+			}
+
+			got, err := service.GetLeaderboard(ctx, tt.by, tt.period, tt.limit)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_SetVisibility(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+
+	mockDB.On("SetLeaderboardOptOut", mock.Anything, 1, true).Return(nil)
+
+	require.NoError(t, service.SetVisibility(context.Background(), 1, false))
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// GetCoinsLeaderboard provides a mock function with given fields: ctx, period, limit
+func (_m *DataBase) GetCoinsLeaderboard(ctx context.Context, period time.Duration, limit int) (*[]models.LeaderboardEntry, error) {
+	ret := _m.Called(ctx, period, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoinsLeaderboard")
+	}
+
+	var r0 *[]models.LeaderboardEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, int) (*[]models.LeaderboardEntry, error)); ok {
+		return rf(ctx, period, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, int) *[]models.LeaderboardEntry); ok {
+		r0 = rf(ctx, period, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.LeaderboardEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration, int) error); ok {
+		r1 = rf(ctx, period, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItemsLeaderboard provides a mock function with given fields: ctx, limit
+func (_m *DataBase) GetItemsLeaderboard(ctx context.Context, limit int) (*[]models.LeaderboardEntry, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItemsLeaderboard")
+	}
+
+	var r0 *[]models.LeaderboardEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.LeaderboardEntry, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.LeaderboardEntry); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.LeaderboardEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLeaderboardOptOut provides a mock function with given fields: ctx, userID, optOut
+func (_m *DataBase) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error {
+	ret := _m.Called(ctx, userID, optOut)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLeaderboardOptOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) error); ok {
+		r0 = rf(ctx, userID, optOut)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
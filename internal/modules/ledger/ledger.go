@@ -0,0 +1,103 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package ledger provides the finance export of the coin ledger: the transfers, refunds, grants, write-offs
+// and purchases made in a time range, with the running balance of each user, as CSV or NDJSON.
+// The ledger is streamed to the writer as it's read, so the export of any range takes the same memory.
+package ledger
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// Formats of the export.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// header is the first row of the CSV export.
+var header = []string{
+	"at", "source", "id", "kind", "user_id", "username", "counterparty", "amount", "balance", "reason", "item", "sku",
+}
+
+// DataBase interface defines methods for reading the coin ledger.
+type DataBase interface {
+	ExportLedger(ctx context.Context, from, to time.Time, emit func(*models.LedgerEntry) error) error
+}
+
+// Service provides the finance export of the coin ledger.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage: storage}
+}
+
+// Export writes the ledger of the range [from, to) to w in the given format. Nothing is written
+// if the range or the format is invalid. If the export fails midway, w is left with the entries written so far,
+// the CSV export writes nothing unless its buffer has been filled.
+func (s *Service) Export(ctx context.Context, from, to time.Time, format string, w io.Writer) error {
+	if !from.Before(to) {
+		return apperr.Invalid("the start of the range must be before its end")
+	}
+
+	switch format {
+	case FormatCSV:
+		return s.exportCSV(ctx, from, to, w)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		return s.storage.ExportLedger(ctx, from, to, func(e *models.LedgerEntry) error {
+			return enc.Encode(e)
+		})
+	default:
+		return apperr.Invalid("the format must be csv or ndjson")
+	}
+}
+
+// exportCSV writes the ledger as CSV with a header, the times are RFC 3339 in UTC and a missing value is empty.
+func (s *Service) exportCSV(ctx context.Context, from, to time.Time, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(header))
+	err := s.storage.ExportLedger(ctx, from, to, func(e *models.LedgerEntry) error {
+		record[0] = e.At.UTC().Format(time.RFC3339)
+		record[1] = e.Source
+		record[2] = strconv.Itoa(e.ID)
+		record[3] = e.Kind
+		record[4] = strconv.Itoa(e.UserID)
+		record[5] = e.Username
+		record[6] = orEmpty(e.Counterparty)
+		record[7] = strconv.Itoa(e.Amount)
+		record[8] = strconv.Itoa(e.Balance)
+		record[9] = orEmpty(e.Reason)
+		record[10] = orEmpty(e.Item)
+		record[11] = orEmpty(e.SKU)
+		return cw.Write(record)
+	})
+	// the buffered rows are dropped on a failure, so an export failing early writes nothing
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func orEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/ledger/mocks"
+)
+
+// entries is a ledger of a grant and a purchase by the same user.
+func entries() []models.LedgerEntry {
+	reason, item, sku := "hackathon, 1st place", "cup", "cup"
+	return []models.LedgerEntry{
+		{
+			At: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), Source: models.LedgerTransaction, ID: 7, Kind: "grant",
+			UserID: 1, Username: "testUser", Amount: 100, Balance: 1100, Reason: &reason,
+		},
+		{
+			At: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), Source: models.LedgerPurchase, ID: 3, Kind: "purchase",
+			UserID: 1, Username: "testUser", Amount: -20, Balance: 1080, Item: &item, SKU: &sku,
+		},
+	}
+}
+
+// emitting makes the mocked storage pass the entries to emit.
+func emitting(entries []models.LedgerEntry) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		emit := args.Get(3).(func(*models.LedgerEntry) error)
+		for i := range entries {
+			if emit(&entries[i]) != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		mockErr error
+	}{
+		{
+			name:   "CSV",
+			format: FormatCSV,
+			want: "at,source,id,kind,user_id,username,counterparty,amount,balance,reason,item,sku\n" +
+				"2025-03-01T09:00:00Z,transaction,7,grant,1,testUser,,100,1100,\"hackathon, 1st place\",,\n" +
+				"2025-03-02T09:00:00Z,purchase,3,purchase,1,testUser,,-20,1080,,cup,cup\n",
+		},
+		{
+			name:   "NDJSON",
+			format: FormatNDJSON,
+			want: `{"at":"2025-03-01T09:00:00Z","source":"transaction","id":7,"kind":"grant","userId":1,"username":"testUser","counterparty":null,"amount":100,"balance":1100,"reason":"hackathon, 1st place","item":null,"sku":null}` + "\n" +
+				`{"at":"2025-03-02T09:00:00Z","source":"purchase","id":3,"kind":"purchase","userId":1,"username":"testUser","counterparty":null,"amount":-20,"balance":1080,"reason":null,"item":"cup","sku":"cup"}` + "\n",
+		},
+		{
+			name:    "Database error",
+			format:  FormatCSV,
+			mockErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDataBase(t)
+			service := New(mockDB)
+
+			call := mockDB.On("ExportLedger", mock.Anything, from, to, mock.Anything).Return(tt.mockErr).Once()
+			if tt.mockErr == nil {
+				call.Run(emitting(entries()))
+			}
+
+			var buf bytes.Buffer
+			err := service.Export(context.Background(), from, to, tt.format, &buf)
+
+			if tt.mockErr != nil {
+				require.Equal(t, tt.mockErr, err)
+				require.Empty(t, buf.String())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestService_Export_Invalid(t *testing.T) {
+	service := New(mocks.NewDataBase(t))
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := service.Export(context.Background(), from, from, FormatCSV, &buf)
+	require.Equal(t, apperr.KindInvalid, apperr.From(err).Kind())
+
+	err = service.Export(context.Background(), from, from.AddDate(0, 1, 0), "xlsx", &buf)
+	require.Equal(t, apperr.KindInvalid, apperr.From(err).Kind())
+	require.Empty(t, buf.String())
+}
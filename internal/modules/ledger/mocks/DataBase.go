@@ -0,0 +1,50 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// ExportLedger provides a mock function with given fields: ctx, from, to, emit
+func (_m *DataBase) ExportLedger(ctx context.Context, from time.Time, to time.Time, emit func(*models.LedgerEntry) error) error {
+	ret := _m.Called(ctx, from, to, emit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportLedger")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, func(*models.LedgerEntry) error) error); ok {
+		r0 = rf(ctx, from, to, emit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
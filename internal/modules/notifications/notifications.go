@@ -0,0 +1,53 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package notifications provides functionality for reading the notifications generated for users,
+// such as wished items being back in stock or on sale.
+package notifications
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// limit is the maximum number of the latest notifications returned to the user.
+const limit = 100
+
+// DataBase interface defines methods for reading users' notifications.
+type DataBase interface {
+	GetNotificationsByUserID(ctx context.Context, userID, limit int) (*[]models.Notification, error)
+	GetNotificationsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Notification], error)
+	MarkNotificationsRead(ctx context.Context, userID int) error
+}
+
+// Service provides functionality for reading users' notifications.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// GetNotifications retrieves the latest notifications of the user, returning an empty list if none exists.
+func (s *Service) GetNotifications(ctx context.Context, userID int) (*[]models.Notification, error) {
+	notifications, err := s.storage.GetNotificationsByUserID(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		return &[]models.Notification{}, nil
+	}
+	return notifications, nil
+}
+
+// GetNotificationsPage retrieves a page of the user's notifications, the latest first.
+func (s *Service) GetNotificationsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Notification], error) {
+	return s.storage.GetNotificationsPageByUserID(ctx, userID, limit, offset)
+}
+
+// MarkRead marks all the user's notifications read.
+func (s *Service) MarkRead(ctx context.Context, userID int) error {
+	return s.storage.MarkNotificationsRead(ctx, userID)
+}
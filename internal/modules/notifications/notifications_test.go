@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/notifications/mocks"
+)
+
+func TestService_GetNotifications(t *testing.T) {
+	hoody := "pink-hoody"
+	notifications := &[]models.Notification{
+		{ID: 1, Kind: models.NotificationBackInStock, Item: &hoody, Message: "Pink Hoody is back in stock"},
+	}
+
+	tests := []struct {
+		name      string
+		mockData  *[]models.Notification
+		mockError error
+		want      *[]models.Notification
+		wantErr   bool
+	}{
+		{
+			name:     "Notifications",
+			mockData: notifications,
+			want:     notifications,
+		},
+		{
+			name: "No notifications",
+			want: &[]models.Notification{},
+		},
+		{
+			name:      "Database error",
+			mockError: errors.New("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetNotificationsByUserID", mock.Anything, 1, limit).Return(tt.mockData, tt.mockError)
+
+			got, err := service.GetNotifications(ctx, 1)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
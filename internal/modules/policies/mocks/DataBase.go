@@ -0,0 +1,121 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// ExecutePolicy provides a mock function with given fields: ctx, p, now
+func (_m *DataBase) ExecutePolicy(ctx context.Context, p *models.Policy, now time.Time) (*models.PolicyRun, error) {
+	ret := _m.Called(ctx, p, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecutePolicy")
+	}
+
+	var r0 *models.PolicyRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Policy, time.Time) (*models.PolicyRun, error)); ok {
+		return rf(ctx, p, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Policy, time.Time) *models.PolicyRun); ok {
+		r0 = rf(ctx, p, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PolicyRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Policy, time.Time) error); ok {
+		r1 = rf(ctx, p, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestPolicyRun provides a mock function with given fields: ctx, policy
+func (_m *DataBase) GetLatestPolicyRun(ctx context.Context, policy string) (*models.PolicyRun, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestPolicyRun")
+	}
+
+	var r0 *models.PolicyRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PolicyRun, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PolicyRun); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PolicyRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPolicyActions provides a mock function with given fields: ctx, p
+func (_m *DataBase) GetPolicyActions(ctx context.Context, p *models.Policy) (*[]models.PolicyAction, error) {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicyActions")
+	}
+
+	var r0 *[]models.PolicyAction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Policy) (*[]models.PolicyAction, error)); ok {
+		return rf(ctx, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Policy) *[]models.PolicyAction); ok {
+		r0 = rf(ctx, p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.PolicyAction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Policy) error); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,202 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package policies provides the balance policies run on a schedule: the coins granted too long ago
+// expire and the balances above a cap are clamped. The affected users are notified in advance.
+package policies
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/schedule"
+)
+
+// Config holds configuration settings for the balance policies.
+type Config struct {
+	PollInterval   time.Duration `envconfig:"POLL_INTERVAL" default:"1h"`
+	NoticePeriod   time.Duration `envconfig:"NOTICE_PERIOD" default:"720h"`
+	DryRun         bool          `envconfig:"DRY_RUN" default:"false"`             // only report the affected users
+	ExpiryMonths   int           `envconfig:"EXPIRY_MONTHS" default:"0"`           // 0 - coins don't expire
+	ExpirySchedule string        `envconfig:"EXPIRY_SCHEDULE" default:"0 0 1 1 *"` // at the end of the year
+	BalanceCap     int           `envconfig:"BALANCE_CAP" default:"0"`             // 0 - balances aren't capped
+	CapSchedule    string        `envconfig:"CAP_SCHEDULE" default:"0 0 1 * *"`    // at the end of the month
+}
+
+// DataBase interface defines methods for running balance policies.
+type DataBase interface {
+	GetPolicyActions(ctx context.Context, p *models.Policy) (*[]models.PolicyAction, error)
+	GetLatestPolicyRun(ctx context.Context, policy string) (*models.PolicyRun, error)
+	ExecutePolicy(ctx context.Context, p *models.Policy, now time.Time) (*models.PolicyRun, error)
+}
+
+// policy is an enabled balance policy with its schedule.
+type policy struct {
+	name     string
+	schedule *schedule.Schedule
+}
+
+// Service provides functionality for running balance policies.
+type Service struct {
+	storage  DataBase
+	cfg      *Config
+	policies []policy
+	now      func() time.Time
+}
+
+// New creates a new instance of Service with the given storage and configuration.
+// Returns an error if the schedule of an enabled policy is invalid.
+func New(storage DataBase, cfg *Config) (*Service, error) {
+	s := &Service{
+		storage: storage,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+
+	if cfg.ExpiryMonths > 0 {
+		sched, err := schedule.Parse(cfg.ExpirySchedule)
+		if err != nil {
+			return nil, fmt.Errorf("expiry schedule: %w", err)
+		}
+		s.policies = append(s.policies, policy{models.PolicyExpiry, sched})
+	}
+	if cfg.BalanceCap > 0 {
+		sched, err := schedule.Parse(cfg.CapSchedule)
+		if err != nil {
+			return nil, fmt.Errorf("balance cap schedule: %w", err)
+		}
+		s.policies = append(s.policies, policy{models.PolicyBalanceCap, sched})
+	}
+
+	return s, nil
+}
+
+// DryRun reports the users the coming run of the policy would affect if it ran now.
+func (s *Service) DryRun(ctx context.Context, name string) (*models.PolicyReport, error) {
+	pol, ok := s.lookup(name)
+	if !ok {
+		return nil, models.ErrPolicyNotFound
+	}
+
+	p, err := s.upcoming(ctx, pol)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := s.storage.GetPolicyActions(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if actions == nil {
+		actions = &[]models.PolicyAction{}
+	}
+
+	return &models.PolicyReport{
+		Policy:   p.Name,
+		RunAt:    p.RunAt,
+		Reason:   p.Reason,
+		Affected: actions,
+	}, nil
+}
+
+// Run moves the enabled policies forward every poll interval until the context is done.
+// Several replicas can run it at once, each policy run is handled by one of them.
+// In the dry-run mode the affected users are only logged.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	if len(s.policies) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, pol := range s.policies {
+			if err := s.execute(ctx, pol, logg); err != nil {
+				logg.Error("policies.execute", "policy", pol.name, "err", err.Error())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// execute moves the coming run of the policy forward, or reports it in the dry-run mode.
+func (s *Service) execute(ctx context.Context, pol policy, logg *slog.Logger) error {
+	if s.cfg.DryRun {
+		report, err := s.DryRun(ctx, pol.name)
+		if err != nil {
+			return err
+		}
+		coins := 0
+		for _, a := range *report.Affected {
+			coins += a.Amount
+		}
+		logg.Info("Balance policy dry run", "policy", report.Policy, "runAt", report.RunAt,
+			"affected", len(*report.Affected), "coins", coins)
+		return nil
+	}
+
+	p, err := s.upcoming(ctx, pol)
+	if err != nil {
+		return err
+	}
+	run, err := s.storage.ExecutePolicy(ctx, p, s.now())
+	if err != nil {
+		return err
+	}
+	if run != nil && run.AppliedAt != nil {
+		logg.Info("Balance policy applied", "policy", run.Policy, "runAt", run.RunAt,
+			"affected", run.Affected, "coins", run.Coins)
+	}
+	return nil
+}
+
+// upcoming works out the coming run of the policy: the pending one, the one following the latest applied run
+// or the first one from now on.
+func (s *Service) upcoming(ctx context.Context, pol policy) (*models.Policy, error) {
+	runAt := time.Time{}
+	latest, err := s.storage.GetLatestPolicyRun(ctx, pol.name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		runAt = pol.schedule.Next(s.now())
+	case err != nil:
+		return nil, err
+	case latest.AppliedAt == nil:
+		runAt = latest.RunAt
+	default:
+		runAt = pol.schedule.Next(latest.RunAt)
+	}
+
+	p := &models.Policy{
+		Name:     pol.name,
+		RunAt:    runAt,
+		NoticeAt: runAt.Add(-s.cfg.NoticePeriod),
+	}
+	switch pol.name {
+	case models.PolicyExpiry:
+		p.Cutoff = runAt.AddDate(0, -s.cfg.ExpiryMonths, 0)
+		p.Reason = fmt.Sprintf("coins received before %s expire", p.Cutoff.Format("2006-01-02"))
+	case models.PolicyBalanceCap:
+		p.Cap = s.cfg.BalanceCap
+		p.Reason = fmt.Sprintf("balance above %d coins", p.Cap)
+	}
+	return p, nil
+}
+
+// lookup finds the enabled policy by its name.
+func (s *Service) lookup(name string) (policy, bool) {
+	for _, pol := range s.policies {
+		if pol.name == name {
+			return pol, true
+		}
+	}
+	return policy{}, false
+}
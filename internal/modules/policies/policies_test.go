@@ -0,0 +1,151 @@
+package policies
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/policies/mocks"
+)
+
+var (
+	now     = time.Date(2025, 12, 10, 12, 0, 0, 0, time.UTC)
+	yearEnd = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	logg    = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+func newService(t *testing.T, storage DataBase, cfg *Config) *Service {
+	s, err := New(storage, cfg)
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		PollInterval:   time.Hour,
+		NoticePeriod:   30 * 24 * time.Hour,
+		ExpiryMonths:   12,
+		ExpirySchedule: "0 0 1 1 *",
+		BalanceCap:     5000,
+		CapSchedule:    "0 0 1 * *",
+	}
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(nil, &Config{})
+	require.NoError(t, err)
+	require.Empty(t, s.policies)
+
+	cfg := defaultConfig()
+	cfg.ExpirySchedule = "at the end of the year"
+	_, err = New(nil, cfg)
+	require.Error(t, err)
+}
+
+func TestService_DryRun(t *testing.T) {
+	applied := time.Date(2025, 11, 1, 0, 0, 3, 0, time.UTC)
+	actions := &[]models.PolicyAction{{UserID: 1, User: "testUser", Balance: 1200, Amount: 400}}
+
+	tests := []struct {
+		name       string
+		policy     string
+		latestRun  *models.PolicyRun
+		latestErr  error
+		wantPolicy *models.Policy
+		wantErr    error
+	}{
+		{
+			name:      "First expiry",
+			policy:    models.PolicyExpiry,
+			latestErr: sql.ErrNoRows,
+			wantPolicy: &models.Policy{
+				Name:     models.PolicyExpiry,
+				RunAt:    yearEnd,
+				NoticeAt: yearEnd.Add(-30 * 24 * time.Hour),
+				Cutoff:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				Reason:   "coins received before 2025-01-01 expire",
+			},
+		},
+		{
+			name:      "Balance cap after the applied run",
+			policy:    models.PolicyBalanceCap,
+			latestRun: &models.PolicyRun{RunAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), AppliedAt: &applied},
+			wantPolicy: &models.Policy{
+				Name:     models.PolicyBalanceCap,
+				RunAt:    time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+				NoticeAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+				Cap:      5000,
+				Reason:   "balance above 5000 coins",
+			},
+		},
+		{
+			name:      "Pending balance cap",
+			policy:    models.PolicyBalanceCap,
+			latestRun: &models.PolicyRun{RunAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+			wantPolicy: &models.Policy{
+				Name:     models.PolicyBalanceCap,
+				RunAt:    time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+				NoticeAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+				Cap:      5000,
+				Reason:   "balance above 5000 coins",
+			},
+		},
+		{
+			name:    "Unknown policy",
+			policy:  "inflation",
+			wantErr: models.ErrPolicyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := newService(t, mockDB, defaultConfig())
+
+			if tt.wantErr == nil {
+				mockDB.On("GetLatestPolicyRun", mock.Anything, tt.policy).Return(tt.latestRun, tt.latestErr)
+				mockDB.On("GetPolicyActions", mock.Anything, tt.wantPolicy).Return(actions, nil)
+			}
+
+			report, err := service.DryRun(context.Background(), tt.policy)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Nil(t, report)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantPolicy.RunAt, report.RunAt)
+				require.Equal(t, actions, report.Affected)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_execute(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		mockDB := new(mocks.DataBase)
+		cfg := defaultConfig()
+		cfg.DryRun = dryRun
+		service := newService(t, mockDB, cfg)
+
+		mockDB.On("GetLatestPolicyRun", mock.Anything, models.PolicyExpiry).Return(nil, sql.ErrNoRows)
+		if dryRun {
+			mockDB.On("GetPolicyActions", mock.Anything, mock.Anything).Return(&[]models.PolicyAction{}, nil)
+		} else {
+			mockDB.On("ExecutePolicy", mock.Anything, mock.MatchedBy(func(p *models.Policy) bool {
+				return p.RunAt.Equal(yearEnd)
+			}), now).Return(&models.PolicyRun{Policy: models.PolicyExpiry, RunAt: yearEnd, NoticedAt: &now}, nil)
+		}
+
+		require.NoError(t, service.execute(context.Background(), service.policies[0], logg))
+		mockDB.AssertExpectations(t)
+	}
+}
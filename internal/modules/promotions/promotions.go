@@ -0,0 +1,94 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package promotions provides functionality for managing time-boxed discounts
+// and promo codes that lower the prices of store items.
+package promotions
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase interface defines methods for storing discounts and promo codes.
+type DataBase interface {
+	GetDiscounts(ctx context.Context) (*[]models.Discount, error)
+	CreateDiscount(ctx context.Context, discount *models.Discount) error
+	DeleteDiscount(ctx context.Context, id int) error
+	GetPromoCodes(ctx context.Context) (*[]models.PromoCode, error)
+	CreatePromoCode(ctx context.Context, promo *models.PromoCode) error
+}
+
+// Service provides functionality for managing discounts and promo codes.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// GetDiscounts retrieves all the scheduled discounts, returning an empty list if none exists.
+func (s *Service) GetDiscounts(ctx context.Context) (*[]models.Discount, error) {
+	discounts, err := s.storage.GetDiscounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if discounts == nil {
+		return &[]models.Discount{}, nil
+	}
+	return discounts, nil
+}
+
+// CreateDiscount validates and saves a new discount limited to either an item or a category.
+func (s *Service) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	if (discount.ItemSlug == nil) == (discount.Category == nil) || !validValue(discount.Kind, discount.Value) {
+		return models.ErrInvalidDiscount
+	}
+
+	discount.StartsAt = discount.StartsAt.UTC()
+	discount.EndsAt = discount.EndsAt.UTC()
+	return s.storage.CreateDiscount(ctx, discount)
+}
+
+// DeleteDiscount deletes the discount by its ID.
+func (s *Service) DeleteDiscount(ctx context.Context, id int) error {
+	return s.storage.DeleteDiscount(ctx, id)
+}
+
+// GetPromoCodes retrieves all the promo codes, returning an empty list if none exists.
+func (s *Service) GetPromoCodes(ctx context.Context) (*[]models.PromoCode, error) {
+	promos, err := s.storage.GetPromoCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if promos == nil {
+		return &[]models.PromoCode{}, nil
+	}
+	return promos, nil
+}
+
+// CreatePromoCode validates and saves a new promo code. A promo code limited to neither
+// an item nor a category applies to the whole store.
+func (s *Service) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	if (promo.ItemSlug != nil && promo.Category != nil) || !validValue(promo.Kind, promo.Value) {
+		return models.ErrInvalidDiscount
+	}
+
+	promo.Used = 0
+	if promo.StartsAt != nil {
+		startsAt := promo.StartsAt.UTC()
+		promo.StartsAt = &startsAt
+	}
+	if promo.EndsAt != nil {
+		endsAt := promo.EndsAt.UTC()
+		promo.EndsAt = &endsAt
+	}
+	return s.storage.CreatePromoCode(ctx, promo)
+}
+
+// validValue checks that a percentage doesn't exceed 100.
+func validValue(kind string, value int) bool {
+	return value > 0 && (kind != models.DiscountPercent || value <= 100)
+}
@@ -0,0 +1,170 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/promotions/mocks"
+)
+
+func TestService_CreateDiscount(t *testing.T) {
+	item := "hoody"
+	category := "clothes"
+	startsAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name     string
+		discount *models.Discount
+		wantErr  error
+		callDB   bool
+	}{
+		{
+			name: "Item discount",
+			discount: &models.Discount{
+				ItemSlug: &item, Kind: models.DiscountPercent, Value: 20,
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour),
+			},
+			callDB: true,
+		},
+		{
+			name: "Category discount",
+			discount: &models.Discount{
+				Category: &category, Kind: models.DiscountFixed, Value: 50,
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour),
+			},
+			callDB: true,
+		},
+		{
+			name: "No scope",
+			discount: &models.Discount{
+				Kind: models.DiscountFixed, Value: 50,
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour),
+			},
+			wantErr: models.ErrInvalidDiscount,
+		},
+		{
+			name: "Both item and category",
+			discount: &models.Discount{
+				ItemSlug: &item, Category: &category, Kind: models.DiscountFixed, Value: 50,
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour),
+			},
+			wantErr: models.ErrInvalidDiscount,
+		},
+		{
+			name: "Percentage over 100",
+			discount: &models.Discount{
+				ItemSlug: &item, Kind: models.DiscountPercent, Value: 120,
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour),
+			},
+			wantErr: models.ErrInvalidDiscount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+
+			if tt.callDB {
+				mockDB.On("CreateDiscount", mock.Anything, tt.discount).Return(nil).Once()
+			}
+
+			err := service.CreateDiscount(ctx, tt.discount)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, time.UTC, tt.discount.StartsAt.Location())
+				require.True(t, startsAt.Equal(tt.discount.StartsAt))
+			}
+
+			mockDB.AssertExpectations(t)
+			ctxCancel()
+		})
+	}
+}
+
+func TestService_CreatePromoCode(t *testing.T) {
+	item := "hoody"
+	category := "clothes"
+	single := 1
+
+	tests := []struct {
+		name    string
+		promo   *models.PromoCode
+		dbErr   error
+		callDB  bool
+		wantErr error
+	}{
+		{
+			name:   "Single-use store-wide code",
+			promo:  &models.PromoCode{Code: "WELCOME", Kind: models.DiscountFixed, Value: 50, MaxUses: &single},
+			callDB: true,
+		},
+		{
+			name:   "Multi-use item code",
+			promo:  &models.PromoCode{Code: "HOODY10", Kind: models.DiscountPercent, Value: 10, ItemSlug: &item},
+			callDB: true,
+		},
+		{
+			name:    "Both item and category",
+			promo:   &models.PromoCode{Code: "BAD", Kind: models.DiscountPercent, Value: 10, ItemSlug: &item, Category: &category},
+			wantErr: models.ErrInvalidDiscount,
+		},
+		{
+			name:    "Duplicate code",
+			promo:   &models.PromoCode{Code: "WELCOME", Kind: models.DiscountFixed, Value: 50},
+			dbErr:   models.ErrPromoCodeExists,
+			callDB:  true,
+			wantErr: models.ErrPromoCodeExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+
+			if tt.callDB {
+				mockDB.On("CreatePromoCode", mock.Anything, tt.promo).Return(tt.dbErr).Once()
+			}
+
+			err := service.CreatePromoCode(ctx, tt.promo)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			mockDB.AssertExpectations(t)
+			ctxCancel()
+		})
+	}
+}
+
+func TestService_GetDiscounts(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mockDB.On("GetDiscounts", mock.Anything).Return(nil, nil).Once()
+	discounts, err := service.GetDiscounts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &[]models.Discount{}, discounts)
+
+	mockDB.On("GetDiscounts", mock.Anything).Return(nil, errors.New("database error")).Once()
+	discounts, err = service.GetDiscounts(ctx)
+	require.Error(t, err)
+	require.Nil(t, discounts)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,117 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// CloseSavingsGoal provides a mock function with given fields: ctx, userID, goalID
+func (_m *DataBase) CloseSavingsGoal(ctx context.Context, userID int, goalID int) (int, error) {
+	ret := _m.Called(ctx, userID, goalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseSavingsGoal")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int, error)); ok {
+		return rf(ctx, userID, goalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int); ok {
+		r0 = rf(ctx, userID, goalID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, goalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSavingsGoal provides a mock function with given fields: ctx, userID, slug
+func (_m *DataBase) CreateSavingsGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSavingsGoal")
+	}
+
+	var r0 *models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.SavingsGoal, error)); ok {
+		return rf(ctx, userID, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.SavingsGoal); ok {
+		r0 = rf(ctx, userID, slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DepositToSavingsGoal provides a mock function with given fields: ctx, userID, goalID, amount
+func (_m *DataBase) DepositToSavingsGoal(ctx context.Context, userID int, goalID int, amount int) (*models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID, goalID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DepositToSavingsGoal")
+	}
+
+	var r0 *models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.SavingsGoal, error)); ok {
+		return rf(ctx, userID, goalID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.SavingsGoal); ok {
+		r0 = rf(ctx, userID, goalID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, goalID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package savings provides functionality for users' savings goals. The coins set aside for an item
+// are held in escrow and can't be spent until the item is bought or the goal is closed.
+package savings
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase interface defines methods for managing users' savings goals.
+type DataBase interface {
+	CreateSavingsGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error)
+	DepositToSavingsGoal(ctx context.Context, userID, goalID, amount int) (*models.SavingsGoal, error)
+	CloseSavingsGoal(ctx context.Context, userID, goalID int) (int, error)
+}
+
+// Service provides functionality for managing users' savings goals.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// CreateGoal starts saving coins for the item.
+func (s *Service) CreateGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error) {
+	return s.storage.CreateSavingsGoal(ctx, userID, slug)
+}
+
+// Deposit moves the coins from the user's balance into the goal.
+func (s *Service) Deposit(ctx context.Context, userID, goalID, amount int) (*models.SavingsGoal, error) {
+	if amount < 1 {
+		return nil, models.ErrNotEnoughCoins
+	}
+	return s.storage.DepositToSavingsGoal(ctx, userID, goalID, amount)
+}
+
+// CloseGoal gives up the goal, the saved coins return to the user's balance.
+// Returns the number of released coins.
+func (s *Service) CloseGoal(ctx context.Context, userID, goalID int) (int, error) {
+	return s.storage.CloseSavingsGoal(ctx, userID, goalID)
+}
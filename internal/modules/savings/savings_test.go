@@ -0,0 +1,121 @@
+package savings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/savings/mocks"
+)
+
+func TestService_CreateGoal(t *testing.T) {
+	goal := &models.SavingsGoal{ID: 1, Item: "pink-hoody", Target: 500}
+
+	tests := []struct {
+		name      string
+		slug      string
+		mockGoal  *models.SavingsGoal
+		mockError error
+	}{
+		{name: "Goal created", slug: "pink-hoody", mockGoal: goal},
+		{name: "Item not found", slug: "unknown", mockError: models.ErrItemNotFound},
+		{name: "Goal exists", slug: "pink-hoody", mockError: models.ErrGoalExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+
+			mockDB.On("CreateSavingsGoal", mock.Anything, 1, tt.slug).Return(tt.mockGoal, tt.mockError)
+
+			got, err := service.CreateGoal(context.Background(), 1, tt.slug)
+			require.Equal(t, tt.mockError, err)
+			require.Equal(t, tt.mockGoal, got)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Deposit(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int
+		mockGoal  *models.SavingsGoal
+		mockError error
+		wantErr   error
+		wantCall  bool
+	}{
+		{
+			name:     "Coins deposited",
+			amount:   100,
+			mockGoal: &models.SavingsGoal{ID: 1, Item: "pink-hoody", Saved: 100, Target: 500},
+			wantCall: true,
+		},
+		{
+			name:      "Not enough coins",
+			amount:    5000,
+			mockError: models.ErrNotEnoughCoins,
+			wantErr:   models.ErrNotEnoughCoins,
+			wantCall:  true,
+		},
+		{
+			name:      "Goal not found",
+			amount:    10,
+			mockError: models.ErrGoalNotFound,
+			wantErr:   models.ErrGoalNotFound,
+			wantCall:  true,
+		},
+		{
+			name:    "Non-positive amount",
+			amount:  0,
+			wantErr: models.ErrNotEnoughCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+
+			if tt.wantCall {
+				mockDB.On("DepositToSavingsGoal", mock.Anything, 1, 1, tt.amount).Return(tt.mockGoal, tt.mockError)
+			}
+
+			got, err := service.Deposit(context.Background(), 1, 1, tt.amount)
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.mockGoal, got)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_CloseGoal(t *testing.T) {
+	tests := []struct {
+		name      string
+		released  int
+		mockError error
+	}{
+		{name: "Goal closed", released: 250},
+		{name: "Goal not found", mockError: models.ErrGoalNotFound},
+		{name: "Database error", mockError: errors.New("database error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+
+			mockDB.On("CloseSavingsGoal", mock.Anything, 1, 2).Return(tt.released, tt.mockError)
+
+			released, err := service.CloseGoal(context.Background(), 1, 2)
+			require.Equal(t, tt.mockError, err)
+			require.Equal(t, tt.released, released)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// CancelScheduledTransfer provides a mock function with given fields: ctx, userID, transferID
+func (_m *DataBase) CancelScheduledTransfer(ctx context.Context, userID int, transferID int) error {
+	ret := _m.Called(ctx, userID, transferID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, transferID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateScheduledTransfer provides a mock function with given fields: ctx, t
+func (_m *DataBase) CreateScheduledTransfer(ctx context.Context, t *models.ScheduledTransfer) error {
+	ret := _m.Called(ctx, t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduledTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ScheduledTransfer) error); ok {
+		r0 = rf(ctx, t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExecuteDueTransfer provides a mock function with given fields: ctx, rules, decide
+func (_m *DataBase) ExecuteDueTransfer(ctx context.Context, rules *models.TransferRules, decide func(*models.ScheduledTransfer, error) *models.TransferOutcome) (bool, error) {
+	ret := _m.Called(ctx, rules, decide)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteDueTransfer")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TransferRules, func(*models.ScheduledTransfer, error) *models.TransferOutcome) (bool, error)); ok {
+		return rf(ctx, rules, decide)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TransferRules, func(*models.ScheduledTransfer, error) *models.TransferOutcome) bool); ok {
+		r0 = rf(ctx, rules, decide)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.TransferRules, func(*models.ScheduledTransfer, error) *models.TransferOutcome) error); ok {
+		r1 = rf(ctx, rules, decide)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIDByUsername provides a mock function with given fields: ctx, username
+func (_m *DataBase) GetIDByUsername(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIDByUsername")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScheduledTransfersBySenderID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetScheduledTransfersBySenderID(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledTransfersBySenderID")
+	}
+
+	var r0 *[]models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.ScheduledTransfer, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.ScheduledTransfer); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,220 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package scheduled_transfer provides functionality for coin transfers made later or regularly,
+// such as monthly coins a team lead sends to each report, and the background worker running them.
+package scheduled_transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/schedule"
+)
+
+// Config holds configuration settings for the scheduled transfers worker.
+type Config struct {
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"30s"`
+	MaxAttempts  int           `envconfig:"MAX_ATTEMPTS" default:"5"`
+	RetryBackoff time.Duration `envconfig:"RETRY_BACKOFF" default:"1m"`
+}
+
+// DataBase interface defines methods for managing and running scheduled transfers.
+type DataBase interface {
+	GetIDByUsername(ctx context.Context, username string) (int, error)
+	CreateScheduledTransfer(ctx context.Context, t *models.ScheduledTransfer) error
+	GetScheduledTransfersBySenderID(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error)
+	CancelScheduledTransfer(ctx context.Context, userID, transferID int) error
+	ExecuteDueTransfer(
+		ctx context.Context, rules *models.TransferRules, decide func(*models.ScheduledTransfer, error) *models.TransferOutcome,
+	) (bool, error)
+}
+
+// TransferRules provides the anti-fraud rules the scheduled transfers are checked against.
+type TransferRules interface {
+	Rules() *models.TransferRules
+}
+
+// Service provides functionality for managing and running scheduled transfers.
+type Service struct {
+	storage DataBase
+	rules   TransferRules
+	cfg     *Config
+	now     func() time.Time
+}
+
+// New creates a new instance of Service with the given storage, anti-fraud rules and configuration.
+func New(storage DataBase, rules TransferRules, cfg *Config) *Service {
+	return &Service{
+		storage: storage,
+		rules:   rules,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// CreateTransfer schedules a transfer from the user, either a one-off at a future time or a recurring one
+// on a cron-like schedule.
+func (s *Service) CreateTransfer(ctx context.Context, senderID int, c *models.ScheduledTransferCreation) (*models.ScheduledTransfer, error) {
+	now := s.now()
+	transfer := &models.ScheduledTransfer{
+		SenderID:  senderID,
+		Recipient: c.User,
+		Amount:    c.Amount,
+		Schedule:  c.Schedule,
+	}
+
+	switch {
+	case c.RunAt != nil && c.Schedule == nil:
+		if !c.RunAt.After(now) {
+			return nil, models.ErrInvalidTransferSchedule
+		}
+		transfer.NextRunAt = c.RunAt.UTC()
+	case c.RunAt == nil && c.Schedule != nil:
+		sched, err := schedule.Parse(*c.Schedule)
+		if err != nil {
+			return nil, models.ErrInvalidTransferSchedule
+		}
+		transfer.NextRunAt = sched.Next(now)
+	default:
+		return nil, models.ErrInvalidTransferSchedule
+	}
+
+	receiverID, err := s.storage.GetIDByUsername(ctx, c.User)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrRecipientNotFound
+	} else if err != nil {
+		return nil, err
+	} else if receiverID == senderID {
+		return nil, models.ErrSelfRecipient
+	}
+	transfer.ReceiverID = receiverID
+
+	if err = s.storage.CreateScheduledTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// GetTransfers retrieves the user's scheduled transfers, returning an empty list if none exists.
+func (s *Service) GetTransfers(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error) {
+	transfers, err := s.storage.GetScheduledTransfersBySenderID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if transfers == nil {
+		return &[]models.ScheduledTransfer{}, nil
+	}
+	return transfers, nil
+}
+
+// CancelTransfer stops the user's scheduled transfer.
+func (s *Service) CancelTransfer(ctx context.Context, userID, transferID int) error {
+	return s.storage.CancelScheduledTransfer(ctx, userID, transferID)
+}
+
+// Run executes the due transfers every poll interval until the context is done.
+// Several replicas can run it at once, each transfer is executed by one of them.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.executeDue(ctx, logg)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeDue executes the due transfers one by one until none is left.
+func (s *Service) executeDue(ctx context.Context, logg *slog.Logger) {
+	for ctx.Err() == nil {
+		executed, err := s.storage.ExecuteDueTransfer(ctx, s.rules.Rules(), s.decide)
+		if err != nil {
+			logg.Error("scheduled_transfer.ExecuteDueTransfer", "err", err.Error())
+			return
+		}
+		if !executed {
+			return
+		}
+	}
+}
+
+// decide works out the state of the transfer after its run. A transfer short of coins is skipped and
+// the sender is notified, so is a transfer held for review by the anti-fraud rules, which the review makes
+// if approved. Other errors are retried with a growing backoff, the sender is notified
+// once the retries are exhausted. A recurring transfer moves on to its next run in any case, unless
+// the recipient has left: a transfer to a deactivated account fails at once and isn't run again.
+func (s *Service) decide(t *models.ScheduledTransfer, err error) *models.TransferOutcome {
+	now := s.now()
+	outcome := &models.TransferOutcome{Status: models.TransferActive}
+
+	switch {
+	case err == nil:
+		outcome.Status = models.TransferCompleted
+	case errors.Is(err, models.ErrNotEnoughCoins):
+		outcome.Status = models.TransferSkipped
+		outcome.LastError = ptr(err.Error())
+		outcome.Notification = &models.Notification{
+			Kind:    models.NotificationTransferSkipped,
+			Message: fmt.Sprintf("Scheduled transfer of %d coins to %s is skipped: not enough coins", t.Amount, t.Recipient),
+		}
+	case errors.Is(err, models.ErrTransferLimitExceeded), errors.Is(err, models.ErrTransferTooFrequent),
+		errors.Is(err, models.ErrFreshAccountTransfer):
+		outcome.Status = models.TransferSkipped
+		outcome.LastError = ptr(err.Error())
+		outcome.Notification = &models.Notification{
+			Kind:    models.NotificationTransferSkipped,
+			Message: fmt.Sprintf("Scheduled transfer of %d coins to %s is held for review: %s", t.Amount, t.Recipient, err),
+		}
+	case errors.Is(err, models.ErrRecipientInactive):
+		outcome.Status = models.TransferFailed
+		outcome.LastError = ptr(err.Error())
+		outcome.Notification = &models.Notification{
+			Kind:    models.NotificationTransferFailed,
+			Message: fmt.Sprintf("Scheduled transfer of %d coins to %s has failed: the recipient's account is deactivated", t.Amount, t.Recipient),
+		}
+		outcome.NextRunAt = t.NextRunAt
+		return outcome
+	case t.Attempts+1 < s.cfg.MaxAttempts:
+		outcome.Attempts = t.Attempts + 1
+		outcome.NextRunAt = now.Add(s.cfg.RetryBackoff * time.Duration(outcome.Attempts))
+		outcome.LastError = ptr(err.Error())
+		return outcome
+	default:
+		outcome.Status = models.TransferFailed
+		outcome.LastError = ptr(err.Error())
+		outcome.Notification = &models.Notification{
+			Kind:    models.NotificationTransferFailed,
+			Message: fmt.Sprintf("Scheduled transfer of %d coins to %s has failed", t.Amount, t.Recipient),
+		}
+	}
+
+	// A recurring transfer stays active until cancelled
+	if t.Schedule != nil {
+		sched, parseErr := schedule.Parse(*t.Schedule)
+		if parseErr != nil {
+			outcome.Status = models.TransferFailed
+			outcome.LastError = ptr(parseErr.Error())
+			return outcome
+		}
+		outcome.Status = models.TransferActive
+		outcome.NextRunAt = sched.Next(now)
+		return outcome
+	}
+
+	outcome.NextRunAt = t.NextRunAt
+	return outcome
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,221 @@
+package scheduled_transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/scheduled_transfer/mocks"
+)
+
+var (
+	now = time.Date(2025, 3, 14, 10, 20, 0, 0, time.UTC)
+	cfg = &Config{PollInterval: time.Second, MaxAttempts: 3, RetryBackoff: time.Minute}
+)
+
+func newService(storage DataBase) *Service {
+	rules := new(mocks.TransferRules)
+	rules.On("Rules").Return((*models.TransferRules)(nil)).Maybe()
+	s := New(storage, rules, cfg)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestService_CreateTransfer(t *testing.T) {
+	tests := []struct {
+		name       string
+		creation   *models.ScheduledTransferCreation
+		receiverID int
+		lookupErr  error
+		wantNext   time.Time
+		wantErr    error
+	}{
+		{
+			name:       "One-off transfer",
+			creation:   &models.ScheduledTransferCreation{User: "otherUser", Amount: 20, RunAt: ptr(now.Add(time.Hour))},
+			receiverID: 2,
+			wantNext:   now.Add(time.Hour),
+		},
+		{
+			name:       "Monthly transfer",
+			creation:   &models.ScheduledTransferCreation{User: "otherUser", Amount: 20, Schedule: ptr("0 9 1 * *")},
+			receiverID: 2,
+			wantNext:   time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Run time in the past",
+			creation: &models.ScheduledTransferCreation{User: "otherUser", Amount: 20, RunAt: ptr(now.Add(-time.Hour))},
+			wantErr:  models.ErrInvalidTransferSchedule,
+		},
+		{
+			name:     "Invalid schedule",
+			creation: &models.ScheduledTransferCreation{User: "otherUser", Amount: 20, Schedule: ptr("every month")},
+			wantErr:  models.ErrInvalidTransferSchedule,
+		},
+		{
+			name: "Both run time and schedule",
+			creation: &models.ScheduledTransferCreation{
+				User: "otherUser", Amount: 20, RunAt: ptr(now.Add(time.Hour)), Schedule: ptr("@daily"),
+			},
+			wantErr: models.ErrInvalidTransferSchedule,
+		},
+		{
+			name:      "Recipient not found",
+			creation:  &models.ScheduledTransferCreation{User: "nobody123", Amount: 20, Schedule: ptr("@daily")},
+			lookupErr: sql.ErrNoRows,
+			wantErr:   models.ErrRecipientNotFound,
+		},
+		{
+			name:       "Transfer to yourself",
+			creation:   &models.ScheduledTransferCreation{User: "testUser", Amount: 20, Schedule: ptr("@daily")},
+			receiverID: 1,
+			wantErr:    models.ErrSelfRecipient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := newService(mockDB)
+
+			if tt.receiverID != 0 || tt.lookupErr != nil {
+				mockDB.On("GetIDByUsername", mock.Anything, tt.creation.User).Return(tt.receiverID, tt.lookupErr)
+			}
+			if tt.wantErr == nil {
+				mockDB.On("CreateScheduledTransfer", mock.Anything, mock.MatchedBy(func(st *models.ScheduledTransfer) bool {
+					return st.SenderID == 1 && st.ReceiverID == tt.receiverID && st.NextRunAt.Equal(tt.wantNext)
+				})).Return(nil)
+			}
+
+			got, err := service.CreateTransfer(context.Background(), 1, tt.creation)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantNext, got.NextRunAt)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_decide(t *testing.T) {
+	monthly := ptr("0 9 1 * *")
+	nextMonth := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	transient := errors.New("connection reset")
+
+	tests := []struct {
+		name         string
+		transfer     *models.ScheduledTransfer
+		err          error
+		wantStatus   string
+		wantNext     time.Time
+		wantAttempts int
+		wantNotify   string
+	}{
+		{
+			name:       "One-off transfer made",
+			transfer:   &models.ScheduledTransfer{NextRunAt: now},
+			wantStatus: models.TransferCompleted,
+			wantNext:   now,
+		},
+		{
+			name:       "Recurring transfer made",
+			transfer:   &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now},
+			wantStatus: models.TransferActive,
+			wantNext:   nextMonth,
+		},
+		{
+			name:       "One-off transfer short of coins",
+			transfer:   &models.ScheduledTransfer{NextRunAt: now},
+			err:        models.ErrNotEnoughCoins,
+			wantStatus: models.TransferSkipped,
+			wantNext:   now,
+			wantNotify: models.NotificationTransferSkipped,
+		},
+		{
+			name:       "Recurring transfer short of coins",
+			transfer:   &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now},
+			err:        models.ErrNotEnoughCoins,
+			wantStatus: models.TransferActive,
+			wantNext:   nextMonth,
+			wantNotify: models.NotificationTransferSkipped,
+		},
+		{
+			name:       "Recurring transfer held for review",
+			transfer:   &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now},
+			err:        models.ErrTransferLimitExceeded,
+			wantStatus: models.TransferActive,
+			wantNext:   nextMonth,
+			wantNotify: models.NotificationTransferSkipped,
+		},
+		{
+			name:         "Transient error retried",
+			transfer:     &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now, Attempts: 1},
+			err:          transient,
+			wantStatus:   models.TransferActive,
+			wantNext:     now.Add(2 * time.Minute),
+			wantAttempts: 2,
+		},
+		{
+			name:       "One-off transfer out of retries",
+			transfer:   &models.ScheduledTransfer{NextRunAt: now, Attempts: 2},
+			err:        transient,
+			wantStatus: models.TransferFailed,
+			wantNext:   now,
+			wantNotify: models.NotificationTransferFailed,
+		},
+		{
+			name:       "Recurring transfer out of retries",
+			transfer:   &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now, Attempts: 2},
+			err:        transient,
+			wantStatus: models.TransferActive,
+			wantNext:   nextMonth,
+			wantNotify: models.NotificationTransferFailed,
+		},
+		{
+			name:       "Recurring transfer to a deactivated account",
+			transfer:   &models.ScheduledTransfer{Schedule: monthly, NextRunAt: now},
+			err:        models.ErrRecipientInactive,
+			wantStatus: models.TransferFailed,
+			wantNext:   now,
+			wantNotify: models.NotificationTransferFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newService(new(mocks.DataBase))
+
+			outcome := service.decide(tt.transfer, tt.err)
+			require.Equal(t, tt.wantStatus, outcome.Status)
+			require.Equal(t, tt.wantNext, outcome.NextRunAt)
+			require.Equal(t, tt.wantAttempts, outcome.Attempts)
+			if tt.wantNotify == "" {
+				require.Nil(t, outcome.Notification)
+			} else {
+				require.Equal(t, tt.wantNotify, outcome.Notification.Kind)
+			}
+			require.Equal(t, tt.err != nil, outcome.LastError != nil)
+		})
+	}
+}
+
+func TestService_executeDue(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := newService(mockDB)
+
+	// two due transfers, then none
+	mockDB.On("ExecuteDueTransfer", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Twice()
+	mockDB.On("ExecuteDueTransfer", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	service.executeDue(context.Background(), nil)
+	mockDB.AssertExpectations(t)
+}
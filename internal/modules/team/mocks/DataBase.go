@@ -0,0 +1,295 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// CreateTeam provides a mock function with given fields: ctx, _a1, ownerID
+func (_m *DataBase) CreateTeam(ctx context.Context, _a1 *models.Team, ownerID int) error {
+	ret := _m.Called(ctx, _a1, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTeam")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Team, int) error); ok {
+		r0 = rf(ctx, _a1, ownerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DepositToTeam provides a mock function with given fields: ctx, userID, teamID, coins, rules
+func (_m *DataBase) DepositToTeam(ctx context.Context, userID int, teamID int, coins int, rules *models.TransferRules) error {
+	ret := _m.Called(ctx, userID, teamID, coins, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DepositToTeam")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.TransferRules) error); ok {
+		r0 = rf(ctx, userID, teamID, coins, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetIDByUsername provides a mock function with given fields: ctx, username
+func (_m *DataBase) GetIDByUsername(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIDByUsername")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeam provides a mock function with given fields: ctx, userID, teamID
+func (_m *DataBase) GetTeam(ctx context.Context, userID int, teamID int) (*models.Team, error) {
+	ret := _m.Called(ctx, userID, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeam")
+	}
+
+	var r0 *models.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.Team, error)); ok {
+		return rf(ctx, userID, teamID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.Team); ok {
+		r0 = rf(ctx, userID, teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamRole provides a mock function with given fields: ctx, teamID, userID
+func (_m *DataBase) GetTeamRole(ctx context.Context, teamID int, userID int) (string, error) {
+	ret := _m.Called(ctx, teamID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamRole")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (string, error)); ok {
+		return rf(ctx, teamID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) string); ok {
+		r0 = rf(ctx, teamID, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, teamID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamSpends provides a mock function with given fields: ctx, teamID, status
+func (_m *DataBase) GetTeamSpends(ctx context.Context, teamID int, status string) (*[]models.TeamSpend, error) {
+	ret := _m.Called(ctx, teamID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamSpends")
+	}
+
+	var r0 *[]models.TeamSpend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*[]models.TeamSpend, error)); ok {
+		return rf(ctx, teamID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *[]models.TeamSpend); ok {
+		r0 = rf(ctx, teamID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.TeamSpend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, teamID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamsByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetTeamsByUserID(ctx context.Context, userID int) (*[]models.Team, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamsByUserID")
+	}
+
+	var r0 *[]models.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Team, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Team); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTeamMember provides a mock function with given fields: ctx, teamID, userID
+func (_m *DataBase) RemoveTeamMember(ctx context.Context, teamID int, userID int) error {
+	ret := _m.Called(ctx, teamID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTeamMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, teamID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveTeamSpend provides a mock function with given fields: ctx, teamID, spendID, ownerID, status, rules
+func (_m *DataBase) ResolveTeamSpend(ctx context.Context, teamID int, spendID int, ownerID int, status string, rules *models.TransferRules) (*models.TeamSpend, error) {
+	ret := _m.Called(ctx, teamID, spendID, ownerID, status, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveTeamSpend")
+	}
+
+	var r0 *models.TeamSpend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string, *models.TransferRules) (*models.TeamSpend, error)); ok {
+		return rf(ctx, teamID, spendID, ownerID, status, rules)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string, *models.TransferRules) *models.TeamSpend); ok {
+		r0 = rf(ctx, teamID, spendID, ownerID, status, rules)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TeamSpend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, string, *models.TransferRules) error); ok {
+		r1 = rf(ctx, teamID, spendID, ownerID, status, rules)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTeamMember provides a mock function with given fields: ctx, teamID, userID, m
+func (_m *DataBase) SetTeamMember(ctx context.Context, teamID int, userID int, m *models.TeamMembership) error {
+	ret := _m.Called(ctx, teamID, userID, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTeamMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.TeamMembership) error); ok {
+		r0 = rf(ctx, teamID, userID, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SpendFromTeam provides a mock function with given fields: ctx, spend, decide, rules
+func (_m *DataBase) SpendFromTeam(ctx context.Context, spend *models.TeamSpend, decide func(*models.Team, *models.TeamMember) (string, error), rules *models.TransferRules) error {
+	ret := _m.Called(ctx, spend, decide, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpendFromTeam")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TeamSpend, func(*models.Team, *models.TeamMember) (string, error), *models.TransferRules) error); ok {
+		r0 = rf(ctx, spend, decide, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
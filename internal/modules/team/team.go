@@ -0,0 +1,179 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package team provides functionality for managing teams and their shared wallets. Members spend from
+// the wallet within their spending limits, the spends above the team's threshold wait for an owner's approval.
+package team
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DataBase interface defines methods for managing teams and their wallets.
+type DataBase interface {
+	GetIDByUsername(ctx context.Context, username string) (int, error)
+	CreateTeam(ctx context.Context, team *models.Team, ownerID int) error
+	GetTeamsByUserID(ctx context.Context, userID int) (*[]models.Team, error)
+	GetTeam(ctx context.Context, userID, teamID int) (*models.Team, error)
+	GetTeamRole(ctx context.Context, teamID, userID int) (string, error)
+	SetTeamMember(ctx context.Context, teamID, userID int, m *models.TeamMembership) error
+	RemoveTeamMember(ctx context.Context, teamID, userID int) error
+	DepositToTeam(ctx context.Context, userID, teamID, coins int, rules *models.TransferRules) error
+	SpendFromTeam(
+		ctx context.Context, spend *models.TeamSpend, decide func(*models.Team, *models.TeamMember) (string, error), rules *models.TransferRules,
+	) error
+	GetTeamSpends(ctx context.Context, teamID int, status string) (*[]models.TeamSpend, error)
+	ResolveTeamSpend(ctx context.Context, teamID, spendID, ownerID int, status string, rules *models.TransferRules) (*models.TeamSpend, error)
+}
+
+// TransferRules provides the anti-fraud rules the deposits to and the spends from the wallets are checked against.
+type TransferRules interface {
+	Rules() *models.TransferRules
+}
+
+// Service provides functionality for managing teams.
+type Service struct {
+	storage DataBase
+	rules   TransferRules
+}
+
+// New creates a new instance of Service with the given storage and anti-fraud rules.
+func New(storage DataBase, rules TransferRules) *Service {
+	return &Service{storage: storage, rules: rules}
+}
+
+// CreateTeam creates the team with the user as its owner.
+func (s *Service) CreateTeam(ctx context.Context, userID int, c *models.TeamCreation) (*models.Team, error) {
+	team := &models.Team{Name: c.Name, ApprovalThreshold: c.ApprovalThreshold}
+	if err := s.storage.CreateTeam(ctx, team, userID); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// GetTeams lists the teams the user is a member of.
+func (s *Service) GetTeams(ctx context.Context, userID int) (*[]models.Team, error) {
+	return s.storage.GetTeamsByUserID(ctx, userID)
+}
+
+// GetTeam retrieves the user's team with its members.
+func (s *Service) GetTeam(ctx context.Context, userID, teamID int) (*models.Team, error) {
+	return s.storage.GetTeam(ctx, userID, teamID)
+}
+
+// SetMember adds the user to the team or changes the member's role and spending limit on behalf of the owner.
+func (s *Service) SetMember(ctx context.Context, ownerID, teamID int, username string, m *models.TeamMembership) error {
+	if err := s.checkOwner(ctx, teamID, ownerID); err != nil {
+		return err
+	}
+	userID, err := s.lookupUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	return s.storage.SetTeamMember(ctx, teamID, userID, m)
+}
+
+// RemoveMember removes the user from the team. The owners remove any member, a member only leaves the team.
+func (s *Service) RemoveMember(ctx context.Context, actorID, teamID int, username string) error {
+	role, err := s.storage.GetTeamRole(ctx, teamID, actorID)
+	if err != nil {
+		return err
+	}
+	userID, err := s.lookupUser(ctx, username)
+	if err != nil {
+		return err
+	} else if role != models.TeamRoleOwner && userID != actorID {
+		return models.ErrNotTeamOwner
+	}
+	return s.storage.RemoveTeamMember(ctx, teamID, userID)
+}
+
+// Deposit transfers coins from the user to the team wallet, anyone can chip in. The deposit is checked
+// against the anti-fraud rules, the one breaking a rule is held for review.
+func (s *Service) Deposit(ctx context.Context, userID, teamID, coins int) error {
+	return s.storage.DepositToTeam(ctx, userID, teamID, coins, s.rules.Rules())
+}
+
+// Spend transfers coins from the team wallet to the colleague on behalf of the member. The spend is made
+// right away within the member's limit and the team's threshold, above the threshold it waits for
+// an owner's approval. The owners spend without limits. The spend to be made is checked against the anti-fraud
+// rules, the one breaking a rule is held for review.
+func (s *Service) Spend(ctx context.Context, userID, teamID int, sp *models.TeamSpending) (*models.TeamSpend, error) {
+	receiverID, err := s.storage.GetIDByUsername(ctx, sp.User)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrRecipientNotFound
+	} else if err != nil {
+		return nil, err
+	} else if receiverID == userID {
+		return nil, models.ErrSelfRecipient
+	}
+
+	spend := &models.TeamSpend{
+		TeamID:     teamID,
+		MemberID:   userID,
+		ReceiverID: receiverID,
+		Recipient:  sp.User,
+		Amount:     sp.Amount,
+	}
+	err = s.storage.SpendFromTeam(ctx, spend, func(team *models.Team, member *models.TeamMember) (string, error) {
+		return decideSpend(team, member, sp.Amount)
+	}, s.rules.Rules())
+	if err != nil {
+		return nil, err
+	}
+	return spend, nil
+}
+
+// GetApprovals lists the team's spends waiting for the owner's approval.
+func (s *Service) GetApprovals(ctx context.Context, ownerID, teamID int) (*[]models.TeamSpend, error) {
+	if err := s.checkOwner(ctx, teamID, ownerID); err != nil {
+		return nil, err
+	}
+	return s.storage.GetTeamSpends(ctx, teamID, models.SpendPending)
+}
+
+// ResolveApproval approves or rejects the team's pending spend on behalf of the owner. The approved spend
+// breaking an anti-fraud rule is held for review.
+func (s *Service) ResolveApproval(ctx context.Context, ownerID, teamID, spendID int, status string) (*models.TeamSpend, error) {
+	if err := s.checkOwner(ctx, teamID, ownerID); err != nil {
+		return nil, err
+	}
+	return s.storage.ResolveTeamSpend(ctx, teamID, spendID, ownerID, status, s.rules.Rules())
+}
+
+// decideSpend decides whether the member's spend is made right away or waits for approval.
+func decideSpend(team *models.Team, member *models.TeamMember, coins int) (string, error) {
+	if member.Role == models.TeamRoleOwner {
+		return models.SpendSent, nil
+	}
+	if member.SpendLimit != nil && member.Spent+coins > *member.SpendLimit {
+		return "", models.ErrTeamSpendLimit
+	}
+	if team.ApprovalThreshold != nil && coins > *team.ApprovalThreshold {
+		return models.SpendPending, nil
+	}
+	return models.SpendSent, nil
+}
+
+// checkOwner makes sure the user owns the team.
+func (s *Service) checkOwner(ctx context.Context, teamID, userID int) error {
+	role, err := s.storage.GetTeamRole(ctx, teamID, userID)
+	if err != nil {
+		return err
+	} else if role != models.TeamRoleOwner {
+		return models.ErrNotTeamOwner
+	}
+	return nil
+}
+
+// lookupUser finds the ID of the user named in the request.
+func (s *Service) lookupUser(ctx context.Context, username string) (int, error) {
+	userID, err := s.storage.GetIDByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.ErrUserNotFound
+	}
+	return userID, err
+}
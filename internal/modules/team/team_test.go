@@ -0,0 +1,237 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/team/mocks"
+)
+
+func ptr(v int) *int { return &v }
+
+func TestDecideSpend(t *testing.T) {
+	tests := []struct {
+		name       string
+		team       models.Team
+		member     models.TeamMember
+		coins      int
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:       "Within the limit",
+			team:       models.Team{ApprovalThreshold: ptr(100)},
+			member:     models.TeamMember{Role: models.TeamRoleMember, SpendLimit: ptr(200), Spent: 100},
+			coins:      100,
+			wantStatus: models.SpendSent,
+		},
+		{
+			name:    "Over the limit",
+			team:    models.Team{ApprovalThreshold: ptr(100)},
+			member:  models.TeamMember{Role: models.TeamRoleMember, SpendLimit: ptr(200), Spent: 150},
+			coins:   60,
+			wantErr: models.ErrTeamSpendLimit,
+		},
+		{
+			name:       "Above the threshold",
+			team:       models.Team{ApprovalThreshold: ptr(100)},
+			member:     models.TeamMember{Role: models.TeamRoleMember},
+			coins:      101,
+			wantStatus: models.SpendPending,
+		},
+		{
+			name:       "No threshold",
+			member:     models.TeamMember{Role: models.TeamRoleMember},
+			coins:      1000,
+			wantStatus: models.SpendSent,
+		},
+		{
+			name:       "Owner spends without limits",
+			team:       models.Team{ApprovalThreshold: ptr(100)},
+			member:     models.TeamMember{Role: models.TeamRoleOwner, SpendLimit: ptr(10)},
+			coins:      1000,
+			wantStatus: models.SpendSent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := decideSpend(&tt.team, &tt.member, tt.coins)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestService_Spend(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipient  string
+		receiverID int
+		lookupErr  error
+		wantErr    error
+	}{
+		{
+			name:       "Spend made",
+			recipient:  "engineer-e2",
+			receiverID: 2,
+		},
+		{
+			name:      "Recipient not found",
+			recipient: "abracadabra",
+			lookupErr: sql.ErrNoRows,
+			wantErr:   models.ErrRecipientNotFound,
+		},
+		{
+			name:       "Spending to myself",
+			recipient:  "engineer-e1",
+			receiverID: 1,
+			wantErr:    models.ErrSelfRecipient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			mockRules := new(mocks.TransferRules)
+			service := New(mockDB, mockRules)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			rules := &models.TransferRules{Window: time.Hour}
+			mockDB.On("GetIDByUsername", mock.Anything, tt.recipient).Return(tt.receiverID, tt.lookupErr).Once()
+			if tt.wantErr == nil {
+				mockRules.On("Rules").Return(rules).Once()
+				mockDB.On("SpendFromTeam", mock.Anything, mock.MatchedBy(func(s *models.TeamSpend) bool {
+					return s.TeamID == 7 && s.MemberID == 1 && s.ReceiverID == tt.receiverID && s.Amount == 50
+				}), mock.Anything, rules).Return(nil).Once()
+			}
+
+			spend, err := service.Spend(ctx, 1, 7, &models.TeamSpending{User: tt.recipient, Amount: 50})
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, tt.recipient, spend.Recipient)
+			}
+			mockDB.AssertExpectations(t)
+			mockRules.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Deposit(t *testing.T) {
+	mockDB := mocks.NewDataBase(t)
+	mockRules := mocks.NewTransferRules(t)
+	service := New(mockDB, mockRules)
+
+	// anyone can chip in, the membership isn't checked
+	rules := &models.TransferRules{Window: time.Hour}
+	mockRules.On("Rules").Return(rules).Once()
+	mockDB.On("DepositToTeam", mock.Anything, 1, 7, 50, rules).Return(models.ErrTransferLimitExceeded).Once()
+
+	err := service.Deposit(context.Background(), 1, 7, 50)
+	require.ErrorIs(t, err, models.ErrTransferLimitExceeded)
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		userID  int
+		wantErr error
+	}{
+		{
+			name:   "Owner removes a member",
+			role:   models.TeamRoleOwner,
+			userID: 2,
+		},
+		{
+			name:   "Member leaves the team",
+			role:   models.TeamRoleMember,
+			userID: 1,
+		},
+		{
+			name:    "Member removes another member",
+			role:    models.TeamRoleMember,
+			userID:  2,
+			wantErr: models.ErrNotTeamOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, mocks.NewTransferRules(t))
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetTeamRole", mock.Anything, 7, 1).Return(tt.role, nil).Once()
+			mockDB.On("GetIDByUsername", mock.Anything, "engineer-e2").Return(tt.userID, nil).Once()
+			if tt.wantErr == nil {
+				mockDB.On("RemoveTeamMember", mock.Anything, 7, tt.userID).Return(nil).Once()
+			}
+
+			err := service.RemoveMember(ctx, 1, 7, "engineer-e2")
+
+			require.ErrorIs(t, err, tt.wantErr)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ResolveApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		roleErr error
+		wantErr error
+	}{
+		{
+			name: "Owner approves",
+			role: models.TeamRoleOwner,
+		},
+		{
+			name:    "Member approves",
+			role:    models.TeamRoleMember,
+			wantErr: models.ErrNotTeamOwner,
+		},
+		{
+			name:    "Not a member",
+			roleErr: models.ErrTeamNotFound,
+			wantErr: models.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			mockRules := new(mocks.TransferRules)
+			service := New(mockDB, mockRules)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetTeamRole", mock.Anything, 7, 1).Return(tt.role, tt.roleErr).Once()
+			if tt.wantErr == nil {
+				mockRules.On("Rules").Return((*models.TransferRules)(nil)).Once()
+				mockDB.On("ResolveTeamSpend", mock.Anything, 7, 3, 1, models.SpendApproved, (*models.TransferRules)(nil)).
+					Return(&models.TeamSpend{ID: 3, Status: models.SpendApproved}, nil).Once()
+			}
+
+			spend, err := service.ResolveApproval(ctx, 1, 7, 3, models.SpendApproved)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, models.SpendApproved, spend.Status)
+			}
+			mockDB.AssertExpectations(t)
+			mockRules.AssertExpectations(t)
+		})
+	}
+}
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -69,17 +70,107 @@ func (_m *DataBase) GetIDByUsername(ctx context.Context, username string) (int,
 	return r0, r1
 }
 
-// TransferCoins provides a mock function with given fields: ctx, fromUserID, toUserID, coins
-func (_m *DataBase) TransferCoins(ctx context.Context, fromUserID int, toUserID int, coins int) error {
-	ret := _m.Called(ctx, fromUserID, toUserID, coins)
+// GetTransferReviews provides a mock function with given fields: ctx, status
+func (_m *DataBase) GetTransferReviews(ctx context.Context, status string) (*[]models.TransferReview, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransferReviews")
+	}
+
+	var r0 *[]models.TransferReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]models.TransferReview, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]models.TransferReview); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.TransferReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransferReviewsPage provides a mock function with given fields: ctx, status, limit, offset
+func (_m *DataBase) GetTransferReviewsPage(ctx context.Context, status string, limit int, offset int) (*models.Page[models.TransferReview], error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransferReviewsPage")
+	}
+
+	var r0 *models.Page[models.TransferReview]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*models.Page[models.TransferReview], error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *models.Page[models.TransferReview]); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.TransferReview])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveTransferReview provides a mock function with given fields: ctx, reviewID, reviewerID, status
+func (_m *DataBase) ResolveTransferReview(ctx context.Context, reviewID int, reviewerID int, status string) (*models.TransferReview, error) {
+	ret := _m.Called(ctx, reviewID, reviewerID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveTransferReview")
+	}
+
+	var r0 *models.TransferReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.TransferReview, error)); ok {
+		return rf(ctx, reviewID, reviewerID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.TransferReview); ok {
+		r0 = rf(ctx, reviewID, reviewerID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, reviewID, reviewerID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferCoins provides a mock function with given fields: ctx, fromUserID, toUserID, coins, rules
+func (_m *DataBase) TransferCoins(ctx context.Context, fromUserID int, toUserID int, coins int, rules *models.TransferRules) error {
+	ret := _m.Called(ctx, fromUserID, toUserID, coins, rules)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TransferCoins")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
-		r0 = rf(ctx, fromUserID, toUserID, coins)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.TransferRules) error); ok {
+		r0 = rf(ctx, fromUserID, toUserID, coins, rules)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -87,6 +178,34 @@ func (_m *DataBase) TransferCoins(ctx context.Context, fromUserID int, toUserID
 	return r0
 }
 
+// TransferCoinsBatch provides a mock function with given fields: ctx, fromUserID, legs, rules
+func (_m *DataBase) TransferCoinsBatch(ctx context.Context, fromUserID int, legs []models.BatchTransfer, rules *models.TransferRules) (int, error) {
+	ret := _m.Called(ctx, fromUserID, legs, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferCoinsBatch")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.BatchTransfer, *models.TransferRules) (int, error)); ok {
+		return rf(ctx, fromUserID, legs, rules)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.BatchTransfer, *models.TransferRules) int); ok {
+		r0 = rf(ctx, fromUserID, legs, rules)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []models.BatchTransfer, *models.TransferRules) error); ok {
+		r1 = rf(ctx, fromUserID, legs, rules)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDataBase(t interface {
@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// rule is an anti-fraud rule checking the transfer of coins against the sender's recent transfers.
+type rule struct {
+	name  string
+	check func(stats *models.TransferStats, coins int, now time.Time) error
+}
+
+// newRules lists the rules turned on in the configuration.
+func newRules(cfg *Config) []rule {
+	var rules []rule
+	if cfg.DailyCap > 0 {
+		rules = append(rules, rule{models.RuleDailyCap, func(st *models.TransferStats, coins int, _ time.Time) error {
+			if st.SentDay+coins > cfg.DailyCap {
+				return fmt.Errorf("%w: at most %d coins a day, %d already sent", models.ErrTransferLimitExceeded, cfg.DailyCap, st.SentDay)
+			}
+			return nil
+		}})
+	}
+	if cfg.WeeklyCap > 0 {
+		rules = append(rules, rule{models.RuleWeeklyCap, func(st *models.TransferStats, coins int, _ time.Time) error {
+			if st.SentWeek+coins > cfg.WeeklyCap {
+				return fmt.Errorf("%w: at most %d coins a week, %d already sent", models.ErrTransferLimitExceeded, cfg.WeeklyCap, st.SentWeek)
+			}
+			return nil
+		}})
+	}
+	if cfg.RecipientCap > 0 {
+		rules = append(rules, rule{models.RuleRecipientCap, func(st *models.TransferStats, coins int, _ time.Time) error {
+			if st.SentToRecipient+coins > cfg.RecipientCap {
+				return fmt.Errorf("%w: at most %d coins a week to one user, %d already sent",
+					models.ErrTransferLimitExceeded, cfg.RecipientCap, st.SentToRecipient)
+			}
+			return nil
+		}})
+	}
+	if cfg.VelocityCount > 0 {
+		rules = append(rules, rule{models.RuleVelocity, func(st *models.TransferStats, _ int, _ time.Time) error {
+			if st.RecentTransfers >= cfg.VelocityCount {
+				return fmt.Errorf("%w: at most %d transfers in %s", models.ErrTransferTooFrequent, cfg.VelocityCount, cfg.VelocityWindow)
+			}
+			return nil
+		}})
+	}
+	if cfg.FreshAccount > 0 {
+		rules = append(rules, rule{models.RuleFreshAccounts, func(st *models.TransferStats, _ int, now time.Time) error {
+			freshSince := now.Add(-cfg.FreshAccount)
+			if st.SenderCreatedAt.After(freshSince) && st.RecipientCreatedAt.After(freshSince) {
+				return models.ErrFreshAccountTransfer
+			}
+			return nil
+		}})
+	}
+	return rules
+}
+
+// check runs the rules against the transfer. Returns the name and the error of the first broken rule.
+// It's run by the storage inside the transfer's transaction, see Rules.
+func (s *TransactService) check(stats *models.TransferStats, coins int) (string, error) {
+	now := s.now()
+	for _, r := range s.rules {
+		if err := r.check(stats, coins, now); err != nil {
+			return r.name, err
+		}
+	}
+	return "", nil
+}
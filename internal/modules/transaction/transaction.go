@@ -1,29 +1,55 @@
 //go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
 
 // Package transaction provides functionality for handling coin transactions between users.
+// Transfers are checked against the anti-fraud rules first, a blocked transfer is queued for review.
 package transaction
 
 import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
 )
 
+// Config holds the anti-fraud rules on coin transfers, a zero value turns the rule off.
+type Config struct {
+	DailyCap       int           `envconfig:"DAILY_CAP" default:"0"`     // coins sent within a day
+	WeeklyCap      int           `envconfig:"WEEKLY_CAP" default:"0"`    // coins sent within a week
+	RecipientCap   int           `envconfig:"RECIPIENT_CAP" default:"0"` // coins sent to one recipient within a week
+	VelocityCount  int           `envconfig:"VELOCITY_COUNT" default:"0"`
+	VelocityWindow time.Duration `envconfig:"VELOCITY_WINDOW" default:"1m"` // at most VelocityCount transfers within it
+	FreshAccount   time.Duration `envconfig:"FRESH_ACCOUNT" default:"0"`    // accounts registered more recently are fresh
+}
+
 // DataBase interface defines methods for handling coin transactions and user data.
 type DataBase interface {
 	GetIDByUsername(ctx context.Context, username string) (int, error)
 	GetCoinsByUserID(ctx context.Context, userID int) (int, error)
-	TransferCoins(ctx context.Context, fromUserID, toUserID, coins int) error
+	TransferCoins(ctx context.Context, fromUserID, toUserID, coins int, rules *models.TransferRules) error
+	TransferCoinsBatch(ctx context.Context, fromUserID int, legs []models.BatchTransfer, rules *models.TransferRules) (int, error)
+	GetTransferReviews(ctx context.Context, status string) (*[]models.TransferReview, error)
+	GetTransferReviewsPage(ctx context.Context, status string, limit, offset int) (*models.Page[models.TransferReview], error)
+	ResolveTransferReview(ctx context.Context, reviewID, reviewerID int, status string) (*models.TransferReview, error)
 }
 
 // TransactService provides functionality for handling coin transactions.
 type TransactService struct {
 	storage DataBase
+	cfg     *Config
+	rules   []rule
+	now     func() time.Time
 }
 
-// New creates a new instance of TransactService with the given storage.
-func New(storage DataBase) *TransactService {
-	return &TransactService{storage}
+// New creates a new instance of TransactService with the given storage and anti-fraud rules.
+func New(storage DataBase, cfg *Config) *TransactService {
+	return &TransactService{
+		storage: storage,
+		cfg:     cfg,
+		rules:   newRules(cfg),
+		now:     time.Now,
+	}
 }
 
 // GetIDRecipient retrieves the ID of a recipient by their username, handling DataBase errors.
@@ -44,7 +70,125 @@ func (s *TransactService) GetSenderCoins(ctx context.Context, userID int) (int,
 	return coins, nil
 }
 
-// SendCoinsToUser transfers coins from a sender to a recipient.
+// SendCoinsToUser transfers coins from a sender to a recipient. The transfer breaking an anti-fraud rule
+// isn't made, it's queued for review and the rule's error is returned.
 func (s *TransactService) SendCoinsToUser(ctx context.Context, senderID, recipientID int, coins int) error {
-	return s.storage.TransferCoins(ctx, senderID, recipientID, coins)
+	return s.storage.TransferCoins(ctx, senderID, recipientID, coins, s.Rules())
+}
+
+// Rules returns the anti-fraud rules the transfers, including the ones made elsewhere such as paying
+// a coin request, are checked against inside their transactions. Returns nil if no rule is turned on.
+func (s *TransactService) Rules() *models.TransferRules {
+	if len(s.rules) == 0 {
+		return nil
+	}
+	return &models.TransferRules{Window: s.cfg.VelocityWindow, Check: s.check}
+}
+
+// SendCoinsBatch transfers coins from a sender to several recipients at once, either all of them are sent
+// or none. The total is split evenly if given, the first recipients get the remainder. All the recipients
+// are resolved and each leg is checked against the anti-fraud rules before any coins move, the earlier legs
+// counting towards the caps of the next ones. The errors of
+// the legs stopping the batch are set in the results, the first one is returned along with them.
+func (s *TransactService) SendCoinsBatch(ctx context.Context, senderID int, batch *models.BatchSending) (*models.BatchResult, error) {
+	results, err := splitBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]models.BatchTransfer, len(results))
+	var recipientsErr error
+	for i := range results {
+		id, err := s.storage.GetIDByUsername(ctx, results[i].User)
+		var legErr error
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			legErr = models.ErrRecipientNotFound
+		case err != nil:
+			return nil, err
+		case id == senderID:
+			legErr = models.ErrSelfRecipient
+		}
+		if legErr != nil {
+			results[i].Error = legErr.Error()
+			if recipientsErr == nil {
+				recipientsErr = legErr
+			}
+		}
+		legs[i] = models.BatchTransfer{ReceiverID: id, Amount: results[i].Amount}
+	}
+	if recipientsErr != nil {
+		return &models.BatchResult{Results: results}, recipientsErr
+	}
+
+	batchID, err := s.storage.TransferCoinsBatch(ctx, senderID, legs, s.Rules())
+	var legErr *models.BatchLegError
+	if errors.As(err, &legErr) {
+		results[legErr.Leg].Error = legErr.Err.Error()
+		return &models.BatchResult{Results: results}, legErr.Err
+	} else if err != nil {
+		return nil, err
+	}
+	return &models.BatchResult{BatchID: batchID, Results: results}, nil
+}
+
+// splitBatch lists the legs of the batch with their amounts.
+func splitBatch(batch *models.BatchSending) ([]models.BatchLegResult, error) {
+	n := len(batch.Recipients)
+	if n == 0 || batch.Total > 0 && batch.Total < n {
+		return nil, models.ErrInvalidBatch
+	}
+
+	results := make([]models.BatchLegResult, n)
+	seen := make(map[string]struct{}, n)
+	for i, leg := range batch.Recipients {
+		if _, ok := seen[leg.User]; ok {
+			return nil, models.ErrInvalidBatch
+		}
+		seen[leg.User] = struct{}{}
+
+		amount := leg.Amount
+		switch {
+		case batch.Total > 0 && amount != 0, batch.Total == 0 && amount <= 0:
+			return nil, models.ErrInvalidBatch
+		case batch.Total > 0:
+			amount = batch.Total / n
+			if i < batch.Total%n {
+				amount++
+			}
+		}
+		results[i] = models.BatchLegResult{User: leg.User, Amount: amount}
+	}
+	return results, nil
+}
+
+// GetReviews lists the flagged transfers with the given status.
+func (s *TransactService) GetReviews(ctx context.Context, status string) (*[]models.TransferReview, error) {
+	if err := checkReviewStatus(status); err != nil {
+		return nil, err
+	}
+	return s.storage.GetTransferReviews(ctx, status)
+}
+
+// GetReviewsPage lists a page of the flagged transfers with the given status, the oldest first.
+func (s *TransactService) GetReviewsPage(ctx context.Context, status string, limit, offset int) (*models.Page[models.TransferReview], error) {
+	if err := checkReviewStatus(status); err != nil {
+		return nil, err
+	}
+	return s.storage.GetTransferReviewsPage(ctx, status, limit, offset)
+}
+
+// checkReviewStatus returns models.ErrInvalidReviewStatus unless the status is one of a review's statuses.
+func checkReviewStatus(status string) error {
+	switch status {
+	case models.ReviewOpen, models.ReviewApproved, models.ReviewRejected:
+		return nil
+	default:
+		return models.ErrInvalidReviewStatus
+	}
+}
+
+// ResolveReview approves or rejects the flagged transfer, an approved transfer is made.
+func (s *TransactService) ResolveReview(ctx context.Context, reviewerID, reviewID int, status string) (*models.TransferReview, error) {
+	return s.storage.ResolveTransferReview(ctx, reviewID, reviewerID, status)
 }
@@ -2,12 +2,15 @@ package transaction
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kk7453603/avito_2024_summer/internal/models"
 	"github.com/kk7453603/avito_2024_summer/internal/modules/transaction/mocks"
 )
 
@@ -35,7 +38,7 @@ func TestTransactService_GetIDRecipient(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDB := new(mocks.DataBase)
-			service := New(mockDB)
+			service := New(mockDB, &Config{})
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
 			mockDB.On("GetIDByUsername", mock.Anything, tt.username).Return(tt.wantUserID, tt.wantErr).Once()
@@ -58,7 +61,7 @@ func TestTransactService_GetIDRecipientError(t *testing.T) {
 	wantErr := errors.New("database error")
 
 	mockDB := new(mocks.DataBase)
-	service := New(mockDB)
+	service := New(mockDB, &Config{})
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	defer ctxCancel()
 
@@ -96,7 +99,7 @@ func TestTransactService_GetSenderCoins(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDB := new(mocks.DataBase)
-			service := New(mockDB)
+			service := New(mockDB, &Config{})
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
 			mockDB.On("GetCoinsByUserID", mock.Anything, tt.userID).Return(tt.wantCoins, tt.wantErr).Once()
@@ -119,7 +122,7 @@ func TestTransactService_GetSenderCoinsError(t *testing.T) {
 	wantErr := errors.New("database error")
 
 	mockDB := new(mocks.DataBase)
-	service := New(mockDB)
+	service := New(mockDB, &Config{})
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	defer ctxCancel()
 
@@ -188,10 +191,11 @@ func TestTransactService_SendCoinsToUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDB := new(mocks.DataBase)
-			service := New(mockDB)
+			service := New(mockDB, &Config{})
 			ctx, ctxCancel := context.WithCancel(context.Background())
 
-			mockDB.On("TransferCoins", mock.Anything, tt.senderID, tt.recipientID, tt.coins).Return(tt.expErr).Once()
+			mockDB.On("TransferCoins", mock.Anything, tt.senderID, tt.recipientID, tt.coins, (*models.TransferRules)(nil)).
+				Return(tt.expErr).Once()
 
 			err := service.SendCoinsToUser(ctx, tt.senderID, tt.recipientID, tt.coins)
 
@@ -207,3 +211,209 @@ func TestTransactService_SendCoinsToUser(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactService_SendCoinsToUserRules(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	oldAccount := now.Add(-30 * 24 * time.Hour)
+	cfg := &Config{
+		DailyCap:       500,
+		WeeklyCap:      1000,
+		RecipientCap:   300,
+		VelocityCount:  3,
+		VelocityWindow: time.Minute,
+		FreshAccount:   48 * time.Hour,
+	}
+	tests := []struct {
+		name     string
+		stats    models.TransferStats
+		coins    int
+		wantRule string
+		wantErr  error
+	}{
+		{
+			name:  "Within the limits",
+			stats: models.TransferStats{SentDay: 100, SentWeek: 400, SentToRecipient: 100, RecentTransfers: 2, SenderCreatedAt: oldAccount, RecipientCreatedAt: now},
+			coins: 200,
+		},
+		{
+			name:     "Daily cap",
+			stats:    models.TransferStats{SentDay: 400, SentWeek: 400, SenderCreatedAt: oldAccount, RecipientCreatedAt: oldAccount},
+			coins:    101,
+			wantRule: models.RuleDailyCap,
+			wantErr:  models.ErrTransferLimitExceeded,
+		},
+		{
+			name:     "Weekly cap",
+			stats:    models.TransferStats{SentDay: 0, SentWeek: 900, SenderCreatedAt: oldAccount, RecipientCreatedAt: oldAccount},
+			coins:    101,
+			wantRule: models.RuleWeeklyCap,
+			wantErr:  models.ErrTransferLimitExceeded,
+		},
+		{
+			name:     "Recipient cap",
+			stats:    models.TransferStats{SentDay: 250, SentWeek: 250, SentToRecipient: 250, SenderCreatedAt: oldAccount, RecipientCreatedAt: oldAccount},
+			coins:    51,
+			wantRule: models.RuleRecipientCap,
+			wantErr:  models.ErrTransferLimitExceeded,
+		},
+		{
+			name:     "Too many transfers",
+			stats:    models.TransferStats{SentDay: 30, SentWeek: 30, SentToRecipient: 10, RecentTransfers: 3, SenderCreatedAt: oldAccount, RecipientCreatedAt: oldAccount},
+			coins:    10,
+			wantRule: models.RuleVelocity,
+			wantErr:  models.ErrTransferTooFrequent,
+		},
+		{
+			name:     "Both accounts are fresh",
+			stats:    models.TransferStats{SenderCreatedAt: now.Add(-time.Hour), RecipientCreatedAt: now.Add(-47 * time.Hour)},
+			coins:    10,
+			wantRule: models.RuleFreshAccounts,
+			wantErr:  models.ErrFreshAccountTransfer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, cfg)
+			service.now = func() time.Time { return now }
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			// the storage checks the rules inside the transfer's transaction
+			mockDB.On("TransferCoins", mock.Anything, 1, 2, tt.coins, mock.AnythingOfType("*models.TransferRules")).
+				Return(func(_ context.Context, _, _, coins int, rules *models.TransferRules) error {
+					require.Equal(t, time.Minute, rules.Window)
+					rule, err := rules.Check(&tt.stats, coins)
+					require.Equal(t, tt.wantRule, rule)
+					return err
+				}).Once()
+
+			err := service.SendCoinsToUser(ctx, 1, 2, tt.coins)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransactService_SendCoinsBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		batch    models.BatchSending
+		users    map[string]int
+		wantLegs []models.BatchTransfer
+		wantErr  error
+	}{
+		{
+			name: "Amounts per recipient",
+			batch: models.BatchSending{Recipients: []models.BatchLeg{
+				{User: "engineer-e2", Amount: 30}, {User: "engineer-e3", Amount: 70},
+			}},
+			users:    map[string]int{"engineer-e2": 2, "engineer-e3": 3},
+			wantLegs: []models.BatchTransfer{{ReceiverID: 2, Amount: 30}, {ReceiverID: 3, Amount: 70}},
+		},
+		{
+			name: "Total split evenly",
+			batch: models.BatchSending{Total: 100, Recipients: []models.BatchLeg{
+				{User: "engineer-e2"}, {User: "engineer-e3"}, {User: "engineer-e4"},
+			}},
+			users:    map[string]int{"engineer-e2": 2, "engineer-e3": 3, "engineer-e4": 4},
+			wantLegs: []models.BatchTransfer{{ReceiverID: 2, Amount: 34}, {ReceiverID: 3, Amount: 33}, {ReceiverID: 4, Amount: 33}},
+		},
+		{
+			name: "Both total and amounts",
+			batch: models.BatchSending{Total: 100, Recipients: []models.BatchLeg{
+				{User: "engineer-e2", Amount: 50}, {User: "engineer-e3"},
+			}},
+			wantErr: models.ErrInvalidBatch,
+		},
+		{
+			name: "Repeated recipient",
+			batch: models.BatchSending{Recipients: []models.BatchLeg{
+				{User: "engineer-e2", Amount: 50}, {User: "engineer-e2", Amount: 50},
+			}},
+			wantErr: models.ErrInvalidBatch,
+		},
+		{
+			name: "Total less than recipients",
+			batch: models.BatchSending{Total: 1, Recipients: []models.BatchLeg{
+				{User: "engineer-e2"}, {User: "engineer-e3"},
+			}},
+			wantErr: models.ErrInvalidBatch,
+		},
+		{
+			name: "Unknown recipient",
+			batch: models.BatchSending{Recipients: []models.BatchLeg{
+				{User: "engineer-e2", Amount: 50}, {User: "abracadabra", Amount: 50},
+			}},
+			users:   map[string]int{"engineer-e2": 2},
+			wantErr: models.ErrRecipientNotFound,
+		},
+		{
+			name: "Sending to myself",
+			batch: models.BatchSending{Recipients: []models.BatchLeg{
+				{User: "engineer-e1", Amount: 50},
+			}},
+			users:   map[string]int{"engineer-e1": 1},
+			wantErr: models.ErrSelfRecipient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB, &Config{})
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			for _, leg := range tt.batch.Recipients {
+				if id, ok := tt.users[leg.User]; ok {
+					mockDB.On("GetIDByUsername", mock.Anything, leg.User).Return(id, nil).Maybe()
+				} else {
+					mockDB.On("GetIDByUsername", mock.Anything, leg.User).Return(0, sql.ErrNoRows).Maybe()
+				}
+			}
+			if tt.wantLegs != nil {
+				mockDB.On("TransferCoinsBatch", mock.Anything, 1, tt.wantLegs, (*models.TransferRules)(nil)).Return(7, nil).Once()
+			}
+
+			result, err := service.SendCoinsBatch(ctx, 1, &tt.batch)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, 7, result.BatchID)
+				require.Len(t, result.Results, len(tt.wantLegs))
+			} else if result != nil {
+				require.Zero(t, result.BatchID)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransactService_SendCoinsBatchRules(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB, &Config{DailyCap: 100, VelocityWindow: time.Minute})
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mockDB.On("GetIDByUsername", mock.Anything, "engineer-e2").Return(2, nil).Once()
+	mockDB.On("GetIDByUsername", mock.Anything, "engineer-e3").Return(3, nil).Once()
+	// 20 sent before and 50 to the first recipient leave 30 coins for the second one
+	legs := []models.BatchTransfer{{ReceiverID: 2, Amount: 50}, {ReceiverID: 3, Amount: 50}}
+	mockDB.On("TransferCoinsBatch", mock.Anything, 1, legs, mock.AnythingOfType("*models.TransferRules")).
+		Return(func(_ context.Context, _ int, legs []models.BatchTransfer, rules *models.TransferRules) (int, error) {
+			_, err := rules.Check(&models.TransferStats{SentDay: 20 + legs[0].Amount}, legs[1].Amount)
+			return 0, &models.BatchLegError{Leg: 1, Err: err}
+		}).Once()
+
+	result, err := service.SendCoinsBatch(ctx, 1, &models.BatchSending{Total: 100, Recipients: []models.BatchLeg{
+		{User: "engineer-e2"}, {User: "engineer-e3"},
+	}})
+
+	require.ErrorIs(t, err, models.ErrTransferLimitExceeded)
+	require.Empty(t, result.Results[0].Error)
+	require.NotEmpty(t, result.Results[1].Error)
+	mockDB.AssertExpectations(t)
+}
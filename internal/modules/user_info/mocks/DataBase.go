@@ -4,8 +4,8 @@ package mocks
 
 import (
 	context "context"
-	models "github.com/kk7453603/avito_2024_summer/internal/models"
 
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -72,6 +72,36 @@ func (_m *DataBase) GetCoinsByUserID(ctx context.Context, userID int) (int, erro
 	return r0, r1
 }
 
+// GetGiftHistoryByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetGiftHistoryByUserID(ctx context.Context, userID int) (*models.GiftHistory, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGiftHistoryByUserID")
+	}
+
+	var r0 *models.GiftHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.GiftHistory, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.GiftHistory); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GiftHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInventoryByUserID provides a mock function with given fields: ctx, userID
 func (_m *DataBase) GetInventoryByUserID(ctx context.Context, userID int) (*[]models.Merch, error) {
 	ret := _m.Called(ctx, userID)
@@ -102,6 +132,216 @@ func (_m *DataBase) GetInventoryByUserID(ctx context.Context, userID int) (*[]mo
 	return r0, r1
 }
 
+// GetPurchasesByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetPurchasesByUserID(ctx context.Context, userID int) (*[]models.Purchase, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchasesByUserID")
+	}
+
+	var r0 *[]models.Purchase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Purchase, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Purchase); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Purchase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPurchasesPageByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetPurchasesPageByUserID(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Purchase], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchasesPageByUserID")
+	}
+
+	var r0 *models.Page[models.Purchase]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Purchase], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Purchase]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Purchase])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceivedCoinsPageByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetReceivedCoinsPageByUserID(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Receiving], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceivedCoinsPageByUserID")
+	}
+
+	var r0 *models.Page[models.Receiving]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Receiving], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Receiving]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Receiving])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceivedGiftsPageByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetReceivedGiftsPageByUserID(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.GiftReceiving], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceivedGiftsPageByUserID")
+	}
+
+	var r0 *models.Page[models.GiftReceiving]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.GiftReceiving], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.GiftReceiving]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.GiftReceiving])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSavingsGoalsByUserID provides a mock function with given fields: ctx, userID
+func (_m *DataBase) GetSavingsGoalsByUserID(ctx context.Context, userID int) (*[]models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSavingsGoalsByUserID")
+	}
+
+	var r0 *[]models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.SavingsGoal, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.SavingsGoal); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentCoinsPageByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetSentCoinsPageByUserID(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Sending], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentCoinsPageByUserID")
+	}
+
+	var r0 *models.Page[models.Sending]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Sending], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Sending]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Sending])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentGiftsPageByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *DataBase) GetSentGiftsPageByUserID(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.GiftSending], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentGiftsPageByUserID")
+	}
+
+	var r0 *models.Page[models.GiftSending]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.GiftSending], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.GiftSending]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.GiftSending])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDataBase(t interface {
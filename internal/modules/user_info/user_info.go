@@ -1,7 +1,7 @@
 //go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
 
 // Package user_info provides functionality for retrieving user-related information
-// such as coin balance, inventory, and coin transaction history.
+// such as coin balance, savings goals, inventory, purchases, coin transaction and gift history.
 package user_info
 
 import (
@@ -17,6 +17,14 @@ type DataBase interface {
 	GetCoinsByUserID(ctx context.Context, userID int) (int, error)
 	GetInventoryByUserID(ctx context.Context, userID int) (*[]models.Merch, error)
 	GetCoinHistoryByUserID(ctx context.Context, userID int) (*models.CoinHistory, error)
+	GetGiftHistoryByUserID(ctx context.Context, userID int) (*models.GiftHistory, error)
+	GetPurchasesByUserID(ctx context.Context, userID int) (*[]models.Purchase, error)
+	GetReceivedCoinsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Receiving], error)
+	GetSentCoinsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Sending], error)
+	GetReceivedGiftsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftReceiving], error)
+	GetSentGiftsPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftSending], error)
+	GetPurchasesPageByUserID(ctx context.Context, userID, limit, offset int) (*models.Page[models.Purchase], error)
+	GetSavingsGoalsByUserID(ctx context.Context, userID int) (*[]models.SavingsGoal, error)
 }
 
 // UserInfoService provides functionality for retrieving user-related information.
@@ -71,3 +79,75 @@ func (s *UserInfoService) GetCoinHistory(ctx context.Context, userID int) (*mode
 
 	return coinHistory, nil
 }
+
+// GetGiftHistory retrieves the history of items gifted to and by a specific user, ensuring non-nil fields.
+func (s *UserInfoService) GetGiftHistory(ctx context.Context, userID int) (*models.GiftHistory, error) {
+	giftHistory, err := s.storage.GetGiftHistoryByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if giftHistory == nil {
+		giftHistory = &models.GiftHistory{}
+	}
+
+	if giftHistory.Receiving == nil {
+		giftHistory.Receiving = &[]models.GiftReceiving{}
+	}
+
+	if giftHistory.Sending == nil {
+		giftHistory.Sending = &[]models.GiftSending{}
+	}
+
+	return giftHistory, nil
+}
+
+// GetPurchases retrieves the purchases of a specific user with their fulfilment statuses,
+// returning an empty list if none exists.
+func (s *UserInfoService) GetPurchases(ctx context.Context, userID int) (*[]models.Purchase, error) {
+	purchases, err := s.storage.GetPurchasesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if purchases == nil {
+		return &[]models.Purchase{}, nil
+	}
+	return purchases, nil
+}
+
+// GetReceivedCoinsPage retrieves a page of the coins received by a specific user, the latest first.
+func (s *UserInfoService) GetReceivedCoinsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Receiving], error) {
+	return s.storage.GetReceivedCoinsPageByUserID(ctx, userID, limit, offset)
+}
+
+// GetSentCoinsPage retrieves a page of the coins sent by a specific user or written off their balance, the latest first.
+func (s *UserInfoService) GetSentCoinsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Sending], error) {
+	return s.storage.GetSentCoinsPageByUserID(ctx, userID, limit, offset)
+}
+
+// GetReceivedGiftsPage retrieves a page of the items gifted to a specific user, the latest first.
+func (s *UserInfoService) GetReceivedGiftsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftReceiving], error) {
+	return s.storage.GetReceivedGiftsPageByUserID(ctx, userID, limit, offset)
+}
+
+// GetSentGiftsPage retrieves a page of the items gifted by a specific user, the latest first.
+func (s *UserInfoService) GetSentGiftsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftSending], error) {
+	return s.storage.GetSentGiftsPageByUserID(ctx, userID, limit, offset)
+}
+
+// GetPurchasesPage retrieves a page of the purchases of a specific user with their fulfilment statuses, the latest first.
+func (s *UserInfoService) GetPurchasesPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Purchase], error) {
+	return s.storage.GetPurchasesPageByUserID(ctx, userID, limit, offset)
+}
+
+// GetGoals retrieves the savings goals of a specific user, returning an empty list if none exists.
+func (s *UserInfoService) GetGoals(ctx context.Context, userID int) (*[]models.SavingsGoal, error) {
+	goals, err := s.storage.GetSavingsGoalsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if goals == nil {
+		return &[]models.SavingsGoal{}, nil
+	}
+	return goals, nil
+}
@@ -233,3 +233,180 @@ func TestUserInfoService_GetCoinHistoryError(t *testing.T) {
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestUserInfoService_GetGiftHistory(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	validGiftHistory := models.GiftHistory{
+		Receiving: &[]models.GiftReceiving{
+			{User: "johnDoe", Item: "cup", Quantity: 2},
+		},
+		Sending: &[]models.GiftSending{
+			{User: "nickles-cage", Item: "socks", Quantity: 1},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		userID          int
+		mockGiftHistory *models.GiftHistory
+		expectedResult  *models.GiftHistory
+	}{
+		{
+			name:            "Successful retrieval of gift history with non-nil fields",
+			userID:          1,
+			mockGiftHistory: &validGiftHistory,
+			expectedResult:  &validGiftHistory,
+		},
+		{
+			name:            "Successful retrieval of gift history with invalid pointer",
+			userID:          2,
+			mockGiftHistory: nil,
+			expectedResult: &models.GiftHistory{
+				Receiving: &[]models.GiftReceiving{},
+				Sending:   &[]models.GiftSending{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB.On("GetGiftHistoryByUserID", mock.Anything, tt.userID).Return(tt.mockGiftHistory, nil)
+
+			result, err := service.GetGiftHistory(ctx, tt.userID)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, result)
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserInfoService_GetGiftHistoryError(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	userID := 0
+	wantErr := errors.New("database error")
+
+	mockDB.On("GetGiftHistoryByUserID", mock.Anything, userID).Return(nil, wantErr)
+
+	result, err := service.GetGiftHistory(ctx, userID)
+
+	require.Error(t, err)
+	require.Nil(t, result)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUserInfoService_GetPurchases(t *testing.T) {
+	office := "moscow"
+	purchases := &[]models.Purchase{
+		{ID: 2, Item: "hoody", Variant: "hoody-xl", Price: 350, Status: models.PurchaseReadyForPickup, Office: &office},
+		{ID: 1, Item: "cup", Variant: "cup", Price: 20, Status: models.PurchaseHandedOver},
+	}
+
+	tests := []struct {
+		name          string
+		userID        int
+		mockPurchases *[]models.Purchase
+		mockError     error
+		want          *[]models.Purchase
+		wantErr       bool
+	}{
+		{
+			name:          "Purchases with statuses",
+			userID:        1,
+			mockPurchases: purchases,
+			want:          purchases,
+		},
+		{
+			name:   "No purchases",
+			userID: 2,
+			want:   &[]models.Purchase{},
+		},
+		{
+			name:      "Database error",
+			userID:    3,
+			mockError: errors.New("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetPurchasesByUserID", mock.Anything, tt.userID).Return(tt.mockPurchases, tt.mockError)
+
+			result, err := service.GetPurchases(ctx, tt.userID)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, result)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserInfoService_GetGoals(t *testing.T) {
+	goals := &[]models.SavingsGoal{{ID: 1, Item: "pink-hoody", Saved: 200, Target: 500}}
+
+	tests := []struct {
+		name      string
+		mockGoals *[]models.SavingsGoal
+		mockError error
+		want      *[]models.SavingsGoal
+		wantErr   bool
+	}{
+		{
+			name:      "Goals",
+			mockGoals: goals,
+			want:      goals,
+		},
+		{
+			name: "No goals",
+			want: &[]models.SavingsGoal{},
+		},
+		{
+			name:      "Database error",
+			mockError: errors.New("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetSavingsGoalsByUserID", mock.Anything, 1).Return(tt.mockGoals, tt.mockError)
+
+			result, err := service.GetGoals(ctx, 1)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, result)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DataBase is an autogenerated mock type for the DataBase type
+type DataBase struct {
+	mock.Mock
+}
+
+// ClaimDueWebhook provides a mock function with given fields: ctx, lease
+func (_m *DataBase) ClaimDueWebhook(ctx context.Context, lease time.Duration) (*models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDueWebhook")
+	}
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (*models.WebhookDelivery, error)); ok {
+		return rf(ctx, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) *models.WebhookDelivery); ok {
+		r0 = rf(ctx, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhookSubscription provides a mock function with given fields: ctx, sub
+func (_m *DataBase) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	ret := _m.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscription) error); ok {
+		r0 = rf(ctx, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, id
+func (_m *DataBase) DeleteWebhookSubscription(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DispatchOutboxEvents provides a mock function with given fields: ctx, limit
+func (_m *DataBase) DispatchOutboxEvents(ctx context.Context, limit int) (int, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchOutboxEvents")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: ctx, status, subscriptionID, limit
+func (_m *DataBase) GetWebhookDeliveries(ctx context.Context, status string, subscriptionID int, limit int) (*[]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, status, subscriptionID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 *[]models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*[]models.WebhookDelivery, error)); ok {
+		return rf(ctx, status, subscriptionID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *[]models.WebhookDelivery); ok {
+		r0 = rf(ctx, status, subscriptionID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, status, subscriptionID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookDeliveriesPage provides a mock function with given fields: ctx, status, subscriptionID, limit, offset
+func (_m *DataBase) GetWebhookDeliveriesPage(ctx context.Context, status string, subscriptionID int, limit int, offset int) (*models.Page[models.WebhookDelivery], error) {
+	ret := _m.Called(ctx, status, subscriptionID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveriesPage")
+	}
+
+	var r0 *models.Page[models.WebhookDelivery]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) (*models.Page[models.WebhookDelivery], error)); ok {
+		return rf(ctx, status, subscriptionID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) *models.Page[models.WebhookDelivery]); ok {
+		r0 = rf(ctx, status, subscriptionID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.WebhookDelivery])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, int) error); ok {
+		r1 = rf(ctx, status, subscriptionID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookSubscriptions provides a mock function with given fields: ctx
+func (_m *DataBase) GetWebhookSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookSubscriptions")
+	}
+
+	var r0 *[]models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDeadDeliveries provides a mock function with given fields: ctx, subscriptionID
+func (_m *DataBase) ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadDeliveries")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayWebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *DataBase) ReplayWebhookDelivery(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveWebhookOutcome provides a mock function with given fields: ctx, d, outcome
+func (_m *DataBase) SaveWebhookOutcome(ctx context.Context, d *models.WebhookDelivery, outcome *models.DeliveryOutcome) (bool, error) {
+	ret := _m.Called(ctx, d, outcome)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhookOutcome")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookDelivery, *models.DeliveryOutcome) (bool, error)); ok {
+		return rf(ctx, d, outcome)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookDelivery, *models.DeliveryOutcome) bool); ok {
+		r0 = rf(ctx, d, outcome)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.WebhookDelivery, *models.DeliveryOutcome) error); ok {
+		r1 = rf(ctx, d, outcome)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhookSubscription provides a mock function with given fields: ctx, id, u
+func (_m *DataBase) UpdateWebhookSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, id, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhookSubscription")
+	}
+
+	var r0 *models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error)); ok {
+		return rf(ctx, id, u)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WebhookSubscriptionUpdate) *models.WebhookSubscription); ok {
+		r0 = rf(ctx, id, u)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.WebhookSubscriptionUpdate) error); ok {
+		r1 = rf(ctx, id, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDataBase creates a new instance of DataBase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDataBase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DataBase {
+	mock := &DataBase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,311 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package webhook provides functionality for managing the webhook subscriptions and the background worker
+// delivering the outbox events to them as signed HTTP requests.
+//
+// Each request carries the event as JSON and the headers:
+//
+//	X-Webhook-Event      type of the event, e.g. coins.received
+//	X-Webhook-ID         ID of the event, the same for all the attempts of a delivery
+//	X-Webhook-Timestamp  Unix time of the attempt
+//	X-Webhook-Signature  sha256=<hex HMAC-SHA256 of "<timestamp>.<body>" with the subscription's secret>
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	dispatchBatch  = 100 // number of outbox events dispatched at a time
+	deliveriesPage = 100 // number of deliveries listed
+	// time to save the outcome of an attempt once the request is made, on top of the request's timeout
+	leaseMargin = 30 * time.Second
+)
+
+// Config holds configuration settings for the webhook delivery worker.
+type Config struct {
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"5s"`
+	Timeout      time.Duration `envconfig:"TIMEOUT" default:"10s"`
+	MaxAttempts  int           `envconfig:"MAX_ATTEMPTS" default:"8"`
+	RetryBackoff time.Duration `envconfig:"RETRY_BACKOFF" default:"30s"`
+	MaxBackoff   time.Duration `envconfig:"MAX_BACKOFF" default:"1h"`
+}
+
+// DataBase interface defines methods for managing the subscriptions and delivering the events.
+type DataBase interface {
+	CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int) error
+	GetWebhookDeliveries(ctx context.Context, status string, subscriptionID, limit int) (*[]models.WebhookDelivery, error)
+	GetWebhookDeliveriesPage(ctx context.Context, status string, subscriptionID, limit, offset int) (*models.Page[models.WebhookDelivery], error)
+	ReplayWebhookDelivery(ctx context.Context, id int64) error
+	ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int, error)
+	DispatchOutboxEvents(ctx context.Context, limit int) (int, error)
+	ClaimDueWebhook(ctx context.Context, lease time.Duration) (*models.WebhookDelivery, error)
+	SaveWebhookOutcome(ctx context.Context, d *models.WebhookDelivery, outcome *models.DeliveryOutcome) (bool, error)
+}
+
+// Service provides functionality for managing the subscriptions and delivering the events.
+type Service struct {
+	storage DataBase
+	cfg     *Config
+	client  *http.Client
+	now     func() time.Time
+}
+
+// New creates a new instance of Service with the given storage and configuration.
+func New(storage DataBase, cfg *Config) *Service {
+	return &Service{
+		storage: storage,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		now:     time.Now,
+	}
+}
+
+// CreateSubscription registers the endpoint for the events, all of them if none is given.
+// A secret is generated unless given. The secret is returned only here.
+func (s *Service) CreateSubscription(ctx context.Context, c *models.WebhookSubscriptionCreation) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{
+		URL:    c.URL,
+		Secret: c.Secret,
+		Events: c.Events,
+	}
+	if sub.Events == nil {
+		sub.Events = []string{}
+	}
+	if sub.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		sub.Secret = hex.EncodeToString(b)
+	}
+
+	if err := s.storage.CreateWebhookSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetSubscriptions retrieves the subscriptions, returning an empty list if none exists.
+func (s *Service) GetSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error) {
+	subs, err := s.storage.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subs == nil {
+		return &[]models.WebhookSubscription{}, nil
+	}
+	return subs, nil
+}
+
+// UpdateSubscription changes the subscription. The deliveries of a paused subscription wait until it's resumed.
+func (s *Service) UpdateSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	if u.Events == nil {
+		u.Events = []string{}
+	}
+	return s.storage.UpdateWebhookSubscription(ctx, id, u)
+}
+
+// DeleteSubscription removes the subscription with its deliveries.
+func (s *Service) DeleteSubscription(ctx context.Context, id int) error {
+	return s.storage.DeleteWebhookSubscription(ctx, id)
+}
+
+// GetDeliveries lists the latest deliveries in the status, the dead ones if not given,
+// of the subscription or of all of them if the subscription ID is zero.
+func (s *Service) GetDeliveries(ctx context.Context, status string, subscriptionID int) (*[]models.WebhookDelivery, error) {
+	if status == "" {
+		status = models.DeliveryDead
+	}
+	deliveries, err := s.storage.GetWebhookDeliveries(ctx, status, subscriptionID, deliveriesPage)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		return &[]models.WebhookDelivery{}, nil
+	}
+	return deliveries, nil
+}
+
+// GetDeliveriesPage retrieves a page of the deliveries in the status, the dead ones if none is given,
+// the latest first. A zero subscription ID matches any subscription.
+func (s *Service) GetDeliveriesPage(ctx context.Context, status string, subscriptionID, limit, offset int) (*models.Page[models.WebhookDelivery], error) {
+	if status == "" {
+		status = models.DeliveryDead
+	}
+	return s.storage.GetWebhookDeliveriesPage(ctx, status, subscriptionID, limit, offset)
+}
+
+// ReplayDelivery sends the delivered or dead delivery again.
+func (s *Service) ReplayDelivery(ctx context.Context, id int64) error {
+	return s.storage.ReplayWebhookDelivery(ctx, id)
+}
+
+// ReplayDead sends again all the dead deliveries of the subscription. Returns the number of them.
+func (s *Service) ReplayDead(ctx context.Context, subscriptionID int) (int, error) {
+	return s.storage.ReplayDeadDeliveries(ctx, subscriptionID)
+}
+
+// Run dispatches the new outbox events and makes the due deliveries every poll interval until the context
+// is done. Several replicas can run it at once, each delivery attempt is made by one of them.
+func (s *Service) Run(ctx context.Context, logg *slog.Logger) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch(ctx, logg)
+		s.deliverDue(ctx, logg)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch creates the deliveries of the new outbox events until none is left.
+func (s *Service) dispatch(ctx context.Context, logg *slog.Logger) {
+	for ctx.Err() == nil {
+		n, err := s.storage.DispatchOutboxEvents(ctx, dispatchBatch)
+		if err != nil {
+			logg.Error("webhook.DispatchOutboxEvents", "err", err.Error())
+			return
+		}
+		if n < dispatchBatch {
+			return
+		}
+	}
+}
+
+// deliverDue makes the due deliveries one by one until none is left. Each delivery is leased while
+// it's sent, no transaction is held open during the request.
+func (s *Service) deliverDue(ctx context.Context, logg *slog.Logger) {
+	for ctx.Err() == nil {
+		d, err := s.storage.ClaimDueWebhook(ctx, s.cfg.Timeout+leaseMargin)
+		if err != nil {
+			logg.Error("webhook.ClaimDueWebhook", "err", err.Error())
+			return
+		}
+		if d == nil {
+			return
+		}
+
+		saved, err := s.storage.SaveWebhookOutcome(ctx, d, s.decide(d, s.send(ctx, d)))
+		if err != nil {
+			logg.Error("webhook.SaveWebhookOutcome", "err", err.Error())
+			return
+		}
+		if !saved {
+			logg.Warn("webhook delivery lease ended before the outcome was saved", "delivery", d.ID)
+		}
+	}
+}
+
+// send posts the event to the subscription's endpoint. Any response but 2xx is an error.
+func (s *Service) send(ctx context.Context, d *models.WebhookDelivery) error {
+	body, err := json.Marshal(struct {
+		ID        int64           `json:"id"`
+		Type      string          `json:"type"`
+		CreatedAt time.Time       `json:"createdAt"`
+		Data      json.RawMessage `json:"data"`
+	}{
+		ID:        d.EventID,
+		Type:      d.EventType,
+		CreatedAt: d.EventCreatedAt,
+		Data:      json.RawMessage(d.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(d.EventID, 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(d.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// decide works out the state of the delivery after an attempt. A failed delivery is retried
+// with an exponential backoff up to the maximum one, and turns dead once the attempts are exhausted.
+func (s *Service) decide(d *models.WebhookDelivery, err error) *models.DeliveryOutcome {
+	now := s.now()
+	outcome := &models.DeliveryOutcome{
+		Attempts:      d.Attempts + 1,
+		NextAttemptAt: d.NextAttemptAt,
+	}
+
+	switch {
+	case err == nil:
+		outcome.Status = models.DeliveryDelivered
+	case outcome.Attempts < s.cfg.MaxAttempts:
+		outcome.Status = models.DeliveryPending
+		outcome.NextAttemptAt = now.Add(s.backoff(outcome.Attempts))
+		outcome.LastError = ptr(err.Error())
+	default:
+		outcome.Status = models.DeliveryDead
+		outcome.LastError = ptr(err.Error())
+	}
+	return outcome
+}
+
+// backoff is the delay after the given number of failed attempts: the retry backoff doubled
+// after each of them, up to the maximum one.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.cfg.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.cfg.MaxBackoff {
+			return s.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Sign calculates the signature of the request body sent at the timestamp, so that the receiver holding
+// the secret can check the request came from the shop and wasn't replayed long after.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
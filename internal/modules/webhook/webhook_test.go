@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/webhook/mocks"
+)
+
+var (
+	now = time.Date(2025, 3, 14, 10, 20, 0, 0, time.UTC)
+	cfg = &Config{
+		PollInterval: time.Second,
+		Timeout:      time.Second,
+		MaxAttempts:  4,
+		RetryBackoff: time.Minute,
+		MaxBackoff:   3 * time.Minute,
+	}
+)
+
+func newService(storage DataBase) *Service {
+	s := New(storage, cfg)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestService_Decide(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     int
+		err          error
+		wantStatus   string
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{
+			name:         "Delivered",
+			wantStatus:   models.DeliveryDelivered,
+			wantAttempts: 1,
+			wantNext:     now,
+		},
+		{
+			name:         "First failure",
+			err:          errors.New("endpoint responded with 502 Bad Gateway"),
+			wantStatus:   models.DeliveryPending,
+			wantAttempts: 1,
+			wantNext:     now.Add(time.Minute),
+		},
+		{
+			name:         "Backoff doubled",
+			attempts:     1,
+			err:          errors.New("endpoint responded with 502 Bad Gateway"),
+			wantStatus:   models.DeliveryPending,
+			wantAttempts: 2,
+			wantNext:     now.Add(2 * time.Minute),
+		},
+		{
+			name:         "Backoff capped",
+			attempts:     2,
+			err:          errors.New("endpoint responded with 502 Bad Gateway"),
+			wantStatus:   models.DeliveryPending,
+			wantAttempts: 3,
+			wantNext:     now.Add(3 * time.Minute),
+		},
+		{
+			name:         "Attempts exhausted",
+			attempts:     3,
+			err:          errors.New("endpoint responded with 502 Bad Gateway"),
+			wantStatus:   models.DeliveryDead,
+			wantAttempts: 4,
+			wantNext:     now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newService(new(mocks.DataBase))
+
+			outcome := service.decide(&models.WebhookDelivery{Attempts: tt.attempts, NextAttemptAt: now}, tt.err)
+
+			require.Equal(t, tt.wantStatus, outcome.Status)
+			require.Equal(t, tt.wantAttempts, outcome.Attempts)
+			require.Equal(t, tt.wantNext, outcome.NextAttemptAt)
+			require.Equal(t, tt.err != nil, outcome.LastError != nil)
+		})
+	}
+}
+
+func TestService_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Accepted", status: http.StatusNoContent},
+		{name: "Rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			service := newService(new(mocks.DataBase))
+			delivery := &models.WebhookDelivery{
+				EventID:        7,
+				EventType:      models.EventCoinsReceived,
+				Payload:        `{"fromUser":"testUser","toUser":"otherUser","amount":20}`,
+				EventCreatedAt: now,
+				URL:            server.URL,
+				Secret:         "0123456789abcdef",
+			}
+
+			err := service.send(context.Background(), delivery)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, models.EventCoinsReceived, received.Header.Get("X-Webhook-Event"))
+			require.Equal(t, "7", received.Header.Get("X-Webhook-ID"))
+			require.Equal(t, Sign("0123456789abcdef", now.Unix(), body), received.Header.Get("X-Webhook-Signature"))
+
+			var event struct {
+				ID   int64 `json:"id"`
+				Data struct {
+					Amount int `json:"amount"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(body, &event))
+			require.Equal(t, int64(7), event.ID)
+			require.Equal(t, 20, event.Data.Amount)
+		})
+	}
+}
+
+func TestService_DeliverDue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mockDB := new(mocks.DataBase)
+	service := newService(mockDB)
+	lease := now.Add(cfg.Timeout + leaseMargin)
+	accepted := &models.WebhookDelivery{ID: 1, EventType: models.EventCoinsReceived, Payload: `{}`, URL: server.URL, NextAttemptAt: lease}
+	expired := &models.WebhookDelivery{ID: 2, EventType: models.EventCoinsReceived, Payload: `{}`, URL: server.URL, NextAttemptAt: lease}
+
+	// the deliveries are claimed for longer than the request may take, and their outcomes are saved after it's made
+	mockDB.On("ClaimDueWebhook", mock.Anything, cfg.Timeout+leaseMargin).Return(accepted, nil).Once()
+	mockDB.On("ClaimDueWebhook", mock.Anything, cfg.Timeout+leaseMargin).Return(expired, nil).Once()
+	mockDB.On("ClaimDueWebhook", mock.Anything, cfg.Timeout+leaseMargin).Return(nil, nil).Once()
+	mockDB.On("SaveWebhookOutcome", mock.Anything, accepted, mock.MatchedBy(func(o *models.DeliveryOutcome) bool {
+		return o.Status == models.DeliveryDelivered && o.Attempts == 1
+	})).Return(true, nil).Once()
+	// a delivery whose lease has ended isn't saved, the next one is made all the same
+	mockDB.On("SaveWebhookOutcome", mock.Anything, expired, mock.Anything).Return(false, nil).Once()
+
+	service.deliverDue(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestService_CreateSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantLen int
+	}{
+		{name: "Given secret", secret: "0123456789abcdef", wantLen: 16},
+		{name: "Generated secret", wantLen: 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := newService(mockDB)
+
+			mockDB.On("CreateWebhookSubscription", mock.Anything, mock.MatchedBy(func(sub *models.WebhookSubscription) bool {
+				return sub.Secret != "" && (tt.secret == "" || sub.Secret == tt.secret) && sub.Events != nil
+			})).Return(nil).Once()
+
+			sub, err := service.CreateSubscription(context.Background(),
+				&models.WebhookSubscriptionCreation{URL: "https://bot.example.com/hook", Secret: tt.secret})
+
+			require.NoError(t, err)
+			require.Len(t, sub.Secret, tt.wantLen)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --all --output=./mocks
+
+// Package wishlist provides functionality for managing the items users wish to buy later.
+// The users are notified when their wished items are back in stock or go on sale.
+package wishlist
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/pricing"
+)
+
+// DataBase interface defines methods for storing users' wishlists.
+type DataBase interface {
+	AddToWishlist(ctx context.Context, userID int, slug string) error
+	RemoveFromWishlist(ctx context.Context, userID int, slug string) error
+	GetWishlistByUserID(ctx context.Context, userID int) (*[]models.Item, error)
+	GetActiveDiscounts(ctx context.Context) (*[]models.Discount, error)
+}
+
+// Service provides functionality for managing users' wishlists.
+type Service struct {
+	storage DataBase
+}
+
+// New creates a new instance of Service with the given storage.
+func New(storage DataBase) *Service {
+	return &Service{storage}
+}
+
+// AddItem adds the item to the user's wishlist.
+// Returns models.ErrItemNotFound if the item doesn't exist.
+func (s *Service) AddItem(ctx context.Context, userID int, slug string) error {
+	return s.storage.AddToWishlist(ctx, userID, slug)
+}
+
+// RemoveItem removes the item from the user's wishlist.
+func (s *Service) RemoveItem(ctx context.Context, userID int, slug string) error {
+	return s.storage.RemoveFromWishlist(ctx, userID, slug)
+}
+
+// GetItems retrieves the items of the user's wishlist with their remaining stock and sale prices,
+// returning an empty list if none exists.
+func (s *Service) GetItems(ctx context.Context, userID int) (*[]models.Item, error) {
+	items, err := s.storage.GetWishlistByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil || len(*items) == 0 {
+		return &[]models.Item{}, nil
+	}
+
+	discounts, err := s.storage.GetActiveDiscounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *items {
+		(*items)[i].InWishlist = true
+		if discounts != nil {
+			pricing.SetSalePrice(&(*items)[i], *discounts)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,83 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/wishlist/mocks"
+)
+
+func TestService_AddItem(t *testing.T) {
+	mockDB := new(mocks.DataBase)
+	service := New(mockDB)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mockDB.On("AddToWishlist", mock.Anything, 1, "pink-hoody").Return(nil).Once()
+	mockDB.On("AddToWishlist", mock.Anything, 1, "unknown").Return(models.ErrItemNotFound).Once()
+
+	require.NoError(t, service.AddItem(ctx, 1, "pink-hoody"))
+	require.ErrorIs(t, service.AddItem(ctx, 1, "unknown"), models.ErrItemNotFound)
+	mockDB.AssertExpectations(t)
+}
+
+func TestService_GetItems(t *testing.T) {
+	hoody := "pink-hoody"
+	salePrice := 400
+	discounts := &[]models.Discount{{ItemSlug: &hoody, Kind: models.DiscountPercent, Value: 20}}
+
+	tests := []struct {
+		name      string
+		mockItems *[]models.Item
+		mockError error
+		want      *[]models.Item
+		wantErr   bool
+	}{
+		{
+			name:      "Wished items with sale prices",
+			mockItems: &[]models.Item{{Slug: hoody, Price: 500}, {Slug: "cup", Price: 20}},
+			want: &[]models.Item{
+				{Slug: hoody, Price: 500, SalePrice: &salePrice, InWishlist: true},
+				{Slug: "cup", Price: 20, InWishlist: true},
+			},
+		},
+		{
+			name: "Empty wishlist",
+			want: &[]models.Item{},
+		},
+		{
+			name:      "Database error",
+			mockError: errors.New("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DataBase)
+			service := New(mockDB)
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			mockDB.On("GetWishlistByUserID", mock.Anything, 1).Return(tt.mockItems, tt.mockError)
+			if tt.mockItems != nil {
+				mockDB.On("GetActiveDiscounts", mock.Anything).Return(discounts, nil)
+			}
+
+			items, err := service.GetItems(ctx, 1)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, items)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, items)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package pricing calculates the prices charged for store items
+// with scheduled discounts and promo codes applied.
+package pricing
+
+import (
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// DiscountAmount returns the number of coins the discount takes off the price, never more than the price itself.
+func DiscountAmount(price int, kind string, value int) int {
+	amount := 0
+	switch kind {
+	case models.DiscountPercent:
+		amount = price * value / 100
+	case models.DiscountFixed:
+		amount = value
+	}
+	return min(max(amount, 0), price)
+}
+
+// Applies reports whether a discount or promo code limited to the given item or category applies to the item.
+// A scope without an item and a category applies to every item.
+func Applies(itemSlug, category *string, item *models.Item) bool {
+	switch {
+	case itemSlug != nil:
+		return *itemSlug == item.Slug
+	case category != nil:
+		return item.Category != nil && *category == *item.Category
+	default:
+		return true
+	}
+}
+
+// PromoActive reports whether the promo code can be redeemed at the given moment.
+func PromoActive(promo *models.PromoCode, now time.Time) bool {
+	if promo.StartsAt != nil && now.Before(*promo.StartsAt) {
+		return false
+	}
+	if promo.EndsAt != nil && !now.Before(*promo.EndsAt) {
+		return false
+	}
+	return promo.MaxUses == nil || promo.Used < *promo.MaxUses
+}
+
+// BestDiscount returns the biggest amount the active discounts take off the item's price.
+// Discounts don't stack, only the most favourable one is applied.
+func BestDiscount(item *models.Item, discounts []models.Discount) int {
+	best := 0
+	for _, d := range discounts {
+		if !Applies(d.ItemSlug, d.Category, item) {
+			continue
+		}
+		best = max(best, DiscountAmount(item.Price, d.Kind, d.Value))
+	}
+	return best
+}
+
+// Quote calculates the price charged for the item. The best active discount is applied first,
+// then the promo code, if any, is applied to the discounted price.
+// The promo code must already be checked to apply to the item.
+func Quote(item *models.Item, discounts []models.Discount, promo *models.PromoCode) *models.Quote {
+	discount := BestDiscount(item, discounts)
+	q := &models.Quote{
+		ListPrice: item.Price,
+		Discount:  discount,
+		Price:     item.Price - discount,
+	}
+
+	if promo != nil {
+		promoDiscount := DiscountAmount(q.Price, promo.Kind, promo.Value)
+		q.Discount += promoDiscount
+		q.Price -= promoDiscount
+		q.PromoCode = &promo.Code
+	}
+
+	return q
+}
+
+// ForVariant returns a copy of the item priced as the variant: the variant's price override,
+// if set, replaces the item's price. Discounts and promo codes limited to the item apply to all its variants.
+func ForVariant(item *models.Item, variant *models.Variant) *models.Item {
+	priced := *item
+	if variant.PriceOverride != nil {
+		priced.Price = *variant.PriceOverride
+	}
+	return &priced
+}
+
+// SetSalePrice sets the sale price of the item and its variants if any of the discounts applies to them.
+func SetSalePrice(item *models.Item, discounts []models.Discount) {
+	if discount := BestDiscount(item, discounts); discount > 0 {
+		salePrice := item.Price - discount
+		item.SalePrice = &salePrice
+	}
+	for i := range item.Variants {
+		priced := ForVariant(item, &item.Variants[i])
+		if discount := BestDiscount(priced, discounts); discount > 0 {
+			salePrice := priced.Price - discount
+			item.Variants[i].SalePrice = &salePrice
+		}
+	}
+}
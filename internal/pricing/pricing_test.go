@@ -0,0 +1,117 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDiscountAmount(t *testing.T) {
+	tests := []struct {
+		name  string
+		price int
+		kind  string
+		value int
+		want  int
+	}{
+		{name: "Percent", price: 300, kind: models.DiscountPercent, value: 20, want: 60},
+		{name: "Percent rounds down", price: 10, kind: models.DiscountPercent, value: 15, want: 1},
+		{name: "Fixed", price: 300, kind: models.DiscountFixed, value: 50, want: 50},
+		{name: "Fixed capped at the price", price: 10, kind: models.DiscountFixed, value: 50, want: 10},
+		{name: "Unknown kind", price: 300, kind: "bogus", value: 50, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, DiscountAmount(tt.price, tt.kind, tt.value))
+		})
+	}
+}
+
+func TestApplies(t *testing.T) {
+	item := &models.Item{Slug: "hoody", Price: 300, Category: ptr("clothes")}
+
+	require.True(t, Applies(nil, nil, item))
+	require.True(t, Applies(ptr("hoody"), nil, item))
+	require.False(t, Applies(ptr("cup"), nil, item))
+	require.True(t, Applies(nil, ptr("clothes"), item))
+	require.False(t, Applies(nil, ptr("accessories"), item))
+	require.False(t, Applies(nil, ptr("clothes"), &models.Item{Slug: "pen"}))
+}
+
+func TestPromoActive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		promo *models.PromoCode
+		want  bool
+	}{
+		{name: "Unlimited", promo: &models.PromoCode{}, want: true},
+		{name: "Not started", promo: &models.PromoCode{StartsAt: ptr(now.Add(time.Hour))}, want: false},
+		{name: "Expired", promo: &models.PromoCode{EndsAt: ptr(now)}, want: false},
+		{name: "Single-use unused", promo: &models.PromoCode{MaxUses: ptr(1)}, want: true},
+		{name: "Single-use used", promo: &models.PromoCode{MaxUses: ptr(1), Used: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, PromoActive(tt.promo, now))
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	item := &models.Item{Slug: "hoody", Price: 300, Category: ptr("clothes")}
+	discounts := []models.Discount{
+		{ItemSlug: ptr("hoody"), Kind: models.DiscountFixed, Value: 50},
+		{Category: ptr("clothes"), Kind: models.DiscountPercent, Value: 10},
+		{ItemSlug: ptr("cup"), Kind: models.DiscountPercent, Value: 90},
+	}
+
+	t.Run("No discounts", func(t *testing.T) {
+		q := Quote(item, nil, nil)
+		require.Equal(t, &models.Quote{ListPrice: 300, Discount: 0, Price: 300}, q)
+	})
+
+	t.Run("Best discount only", func(t *testing.T) {
+		q := Quote(item, discounts, nil)
+		require.Equal(t, &models.Quote{ListPrice: 300, Discount: 50, Price: 250}, q)
+	})
+
+	t.Run("Promo code on top of the discount", func(t *testing.T) {
+		promo := &models.PromoCode{Code: "SPRING", Kind: models.DiscountPercent, Value: 20}
+		q := Quote(item, discounts, promo)
+		require.Equal(t, &models.Quote{ListPrice: 300, Discount: 100, Price: 200, PromoCode: ptr("SPRING")}, q)
+	})
+
+	t.Run("Price never goes below zero", func(t *testing.T) {
+		promo := &models.PromoCode{Code: "FREE", Kind: models.DiscountFixed, Value: 1000}
+		q := Quote(item, discounts, promo)
+		require.Equal(t, 0, q.Price)
+		require.Equal(t, 300, q.Discount)
+	})
+}
+
+func TestForVariant(t *testing.T) {
+	item := &models.Item{Slug: "hoody", Price: 300}
+
+	t.Run("Item price", func(t *testing.T) {
+		priced := ForVariant(item, &models.Variant{SKU: "hoody-m"})
+		require.Equal(t, 300, priced.Price)
+	})
+
+	t.Run("Price override", func(t *testing.T) {
+		priced := ForVariant(item, &models.Variant{SKU: "hoody-xxl", PriceOverride: ptr(350)})
+		require.Equal(t, 350, priced.Price)
+		require.Equal(t, 300, item.Price)
+		require.Equal(t, "hoody", priced.Slug)
+	})
+}
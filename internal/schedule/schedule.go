@@ -0,0 +1,169 @@
+// Package schedule parses cron-like schedules of recurring jobs and calculates their next run.
+//
+// A schedule has five space-separated fields: minute (0-59), hour (0-23), day of month (1-31),
+// month (1-12) and day of week (0-6, Sunday is 0 or 7). A field is `*`, a number, a range `a-b`
+// or a comma-separated list of them, any of which can have a step `/n`. The descriptors @hourly,
+// @daily, @weekly and @monthly are accepted as well. Schedules are evaluated in UTC.
+package schedule
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned when the schedule can't be parsed or never fires.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// horizon limits the search of the next run, a schedule firing less often never fires at all.
+const horizon = 5 * 366 * 24 * time.Hour
+
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// field describes the bounds of a schedule field.
+type field struct {
+	min, max int
+}
+
+var (
+	minutes    = field{0, 59}
+	hours      = field{0, 23}
+	daysOfMon  = field{1, 31}
+	months     = field{1, 12}
+	daysOfWeek = field{0, 7}
+)
+
+// Schedule is a parsed cron-like schedule. Each field is a bit set of the allowed values.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	anyDom, anyDow                bool // the field starts with *, the day must match both fields
+}
+
+// Parse parses the cron-like schedule.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := descriptors[expr]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, ErrInvalidSchedule
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], daysOfMon); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], daysOfWeek); err != nil {
+		return nil, err
+	}
+	// Sunday can be written as 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	// like in Vixie cron, a field starting with * is unrestricted even with a step, e.g. */1
+	s.anyDom = strings.HasPrefix(fields[2], "*")
+	s.anyDow = strings.HasPrefix(fields[4], "*")
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, ErrInvalidSchedule
+	}
+	return &s, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a bit set.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, ErrInvalidSchedule
+			}
+			rng = part[:i]
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, ErrInvalidSchedule
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, ErrInvalidSchedule
+			}
+			lo = v
+			// a single value with a step runs up to the end of the field, like in cron
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, ErrInvalidSchedule
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t matching the schedule, truncated to the minute.
+// Returns the zero time if the schedule doesn't fire within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(horizon)
+
+	for t.Before(end) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day fits the schedule. Like in cron, if both the day of month
+// and the day of week are restricted, a day matching either of them fits, otherwise the day must match both.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{"* * * * *", "0 9 1 * *", "*/15 9-18 * * 1-5", "0 0 29 2 *", "30 12 * * 7", "0,30 8 1,15 * *", "@monthly"}
+	for _, expr := range valid {
+		_, err := Parse(expr)
+		require.NoError(t, err, expr)
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "*/0 * * * *", "a * * * *", "0 0 30 2 *", "@yearly"}
+	for _, expr := range invalid {
+		_, err := Parse(expr)
+		require.ErrorIs(t, err, ErrInvalidSchedule, expr)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2025, 3, 14, 10, 20, 30, 0, time.UTC) // Friday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2025, 3, 14, 10, 21, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 9 1 * *", want: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * 1-5", want: time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{expr: "30 12 * * 7", want: time.Date(2025, 3, 16, 12, 30, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// both days restricted: either of them fits
+		{expr: "0 0 1 * 6", want: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		// a field starting with * is unrestricted, the day must match both
+		{expr: "0 9 */1 * 1", want: time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 9 1 * */1", want: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 */2 * 2", want: time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{expr: "@daily", want: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, s.Next(from))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// AuditLogHandlers provides admin HTTP handlers for the audit log.
+type AuditLogHandlers struct {
+	ctx      context.Context // Context for managing request-scoped values and cancellation.
+	auditSrv AuditLogService // Service for reading the audit log.
+}
+
+// NewAuditLogHandlers creates a new instance of AuditLogHandlers with the provided dependencies.
+func NewAuditLogHandlers(ctx context.Context, auditSrv AuditLogService) *AuditLogHandlers {
+	return &AuditLogHandlers{
+		ctx:      ctx,
+		auditSrv: auditSrv,
+	}
+}
+
+// ListAuditLogHandler returns the audit log entries, the latest first. The entries are filtered by
+// the `actor`, `action`, `target` and `requestId` query parameters and by the time range `from`-`to` (RFC 3339).
+// `before` pages through the log by the entry ID, `limit` is the number of entries.
+func (ah *AuditLogHandlers) ListAuditLogHandler(c *gin.Context) {
+	filter := models.AuditFilter{
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+		Target:    c.Query("target"),
+		RequestID: c.Query("requestId"),
+	}
+
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if raw := c.Query(param); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				_ = c.Error(apperr.Invalid("`" + param + "` must be an RFC 3339 time"))
+				return
+			}
+			t = t.UTC()
+			*dst = &t
+		}
+	}
+	if raw := c.Query("before"); raw != "" {
+		var err error
+		if filter.BeforeID, err = strconv.ParseInt(raw, 10, 64); err != nil || filter.BeforeID < 1 {
+			_ = c.Error(apperr.Invalid("`before` must be a positive integer"))
+			return
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(raw); err != nil || filter.Limit < 1 {
+			_ = c.Error(apperr.Invalid("`limit` must be a positive integer"))
+			return
+		}
+	}
+
+	entries, err := ah.auditSrv.GetEntries(ah.ctx, &filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// VerifyAuditLogHandler checks the hash chain of the audit log and returns the first broken entry, if any.
+func (ah *AuditLogHandlers) VerifyAuditLogHandler(c *gin.Context) {
+	result, err := ah.auditSrv.Verify(ah.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
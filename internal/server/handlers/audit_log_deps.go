@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// AuditLogService service
+type AuditLogService interface {
+	GetEntries(ctx context.Context, f *models.AuditFilter) (*[]models.AuditEntry, error)
+	Verify(ctx context.Context) (*models.AuditVerification, error)
+}
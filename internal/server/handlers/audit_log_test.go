@@ -0,0 +1,72 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+)
+
+// TestAuditLogHandlers_ListAuditLogHandler проверяет поиск по журналу аудита с фильтрами.
+func TestAuditLogHandlers_ListAuditLogHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		query    string
+		callSvc  bool
+		wantCode int
+	}{
+		{
+			name:     "Filtered by action and time",
+			query:    "?action=auth.login_failed&from=2025-03-01T00:00:00Z&limit=50",
+			callSvc:  true,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid time",
+			query:    "?from=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid page",
+			query:    "?before=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mAuditSvc := mocks.NewAuditLogService(t)
+			if tt.callSvc {
+				mAuditSvc.
+					On("GetEntries", mock.Anything, mock.MatchedBy(func(f *models.AuditFilter) bool {
+						return f.Action == models.AuditLoginFailed && f.From != nil && f.To == nil && f.Limit == 50
+					})).
+					Return(&[]models.AuditEntry{}, nil)
+			}
+
+			ah := NewAuditLogHandlers(context.Background(), mAuditSvc)
+			router.GET("/admin/audit", ah.ListAuditLogHandler)
+
+			req, err := http.NewRequest(http.MethodGet, "/admin/audit"+tt.query, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
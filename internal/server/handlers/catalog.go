@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CatalogHandlers provides HTTP handlers for managing the store's assortment by administrators.
+type CatalogHandlers struct {
+	ctx        context.Context // Context for managing request-scoped values and cancellation.
+	catalogSrv CatalogService  // Service for managing the store's assortment.
+}
+
+// NewCatalogHandlers creates a new instance of CatalogHandlers with the provided dependencies.
+func NewCatalogHandlers(ctx context.Context, catalogSrv CatalogService) *CatalogHandlers {
+	return &CatalogHandlers{
+		ctx:        ctx,
+		catalogSrv: catalogSrv,
+	}
+}
+
+// CreateVariantHandler adds a new variant to the item.
+func (ch *CatalogHandlers) CreateVariantHandler(c *gin.Context) {
+	var variant models.Variant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	err := ch.catalogSrv.CreateVariant(auditContext(ch.ctx, c), c.Param("item"), &variant)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateVariantHandler replaces the description, price override and stock of the variant.
+func (ch *CatalogHandlers) UpdateVariantHandler(c *gin.Context) {
+	// The SKU comes from the path, so the body doesn't have to repeat it
+	variant := models.Variant{SKU: c.Param("sku")}
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	err := ch.catalogSrv.UpdateVariant(auditContext(ch.ctx, c), c.Param("sku"), &variant)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// RestockVariantHandler adds the delivered quantity to the variant's stock.
+func (ch *CatalogHandlers) RestockVariantHandler(c *gin.Context) {
+	var restock models.Restock
+	if err := c.ShouldBindJSON(&restock); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	variant, err := ch.catalogSrv.RestockVariant(auditContext(ch.ctx, c), c.Param("sku"), restock.Quantity)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CatalogService service
+type CatalogService interface {
+	CreateVariant(ctx context.Context, slug string, variant *models.Variant) error
+	UpdateVariant(ctx context.Context, sku string, variant *models.Variant) error
+	RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error)
+}
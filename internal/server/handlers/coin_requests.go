@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CoinRequestHandlers provides HTTP handlers for asking colleagues for coins.
+type CoinRequestHandlers struct {
+	ctx        context.Context    // Context for managing request-scoped values and cancellation.
+	requestSrv CoinRequestService // Service for managing coin requests.
+}
+
+// NewCoinRequestHandlers creates a new instance of CoinRequestHandlers with the provided dependencies.
+func NewCoinRequestHandlers(ctx context.Context, requestSrv CoinRequestService) *CoinRequestHandlers {
+	return &CoinRequestHandlers{
+		ctx:        ctx,
+		requestSrv: requestSrv,
+	}
+}
+
+// CreateCoinRequestHandler asks the colleague `fromUser` for coins.
+func (rh *CoinRequestHandlers) CreateCoinRequestHandler(c *gin.Context) {
+	var creation models.CoinRequestCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	request, err := rh.requestSrv.CreateRequest(rh.ctx, userID, &creation)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// ListCoinRequestsHandler returns the pending requests the user is asked to pay and the user's own requests.
+func (rh *CoinRequestHandlers) ListCoinRequestsHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requests, err := rh.requestSrv.GetRequests(rh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetCoinRequestHandler returns the request made by or addressed to the user with the history of its statuses.
+func (rh *CoinRequestHandlers) GetCoinRequestHandler(c *gin.Context) {
+	rh.handleRequest(c, rh.requestSrv.GetRequest)
+}
+
+// AcceptCoinRequestHandler pays the coin request addressed to the user.
+func (rh *CoinRequestHandlers) AcceptCoinRequestHandler(c *gin.Context) {
+	rh.handleRequest(c, rh.requestSrv.Accept)
+}
+
+// DeclineCoinRequestHandler refuses the coin request addressed to the user.
+func (rh *CoinRequestHandlers) DeclineCoinRequestHandler(c *gin.Context) {
+	rh.handleRequest(c, rh.requestSrv.Decline)
+}
+
+// CancelCoinRequestHandler withdraws the user's coin request.
+func (rh *CoinRequestHandlers) CancelCoinRequestHandler(c *gin.Context) {
+	rh.handleRequest(c, rh.requestSrv.Cancel)
+}
+
+// handleRequest applies the action to the coin request from the path and responds with the request.
+func (rh *CoinRequestHandlers) handleRequest(c *gin.Context, action func(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid request id"))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	request, err := action(rh.ctx, userID, requestID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CoinRequestService service
+type CoinRequestService interface {
+	CreateRequest(ctx context.Context, userID int, c *models.CoinRequestCreation) (*models.CoinRequest, error)
+	GetRequests(ctx context.Context, userID int) (*models.CoinRequests, error)
+	GetIncomingPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error)
+	GetOutgoingPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.CoinRequest], error)
+	GetRequest(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)
+	Accept(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)
+	Decline(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)
+	Cancel(ctx context.Context, userID, requestID int) (*models.CoinRequest, error)
+}
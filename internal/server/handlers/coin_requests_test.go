@@ -0,0 +1,90 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestCoinRequestHandlers_AcceptCoinRequestHandler проверяет оплату запроса монет плательщиком.
+func TestCoinRequestHandlers_AcceptCoinRequestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		id        string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Request paid",
+			id:       "5",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Expired or answered request",
+			id:        "5",
+			mockError: models.ErrCoinRequestNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:      "Not enough coins",
+			id:        "5",
+			mockError: models.ErrNotEnoughCoins,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "Blocked by anti-fraud rules",
+			id:        "5",
+			mockError: models.ErrTransferTooFrequent,
+			wantCode:  http.StatusTooManyRequests,
+		},
+		{
+			name:     "Invalid request id",
+			id:       "abc",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mRequestSvc := mocks.NewCoinRequestService(t)
+			if tt.id == "5" {
+				mRequestSvc.
+					On("Accept", mock.Anything, 1, 5).
+					Return(&models.CoinRequest{ID: 5, Amount: 50, Status: models.CoinRequestAccepted}, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			rh := NewCoinRequestHandlers(context.Background(), mRequestSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/requests/:id/accept", rh.AcceptCoinRequestHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/requests/"+tt.id+"/accept", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+// ListCoinRequestsV2Handler returns a page of the pending requests the user is asked to pay,
+// or of the user's own requests if the `direction` query parameter is outgoing.
+func (rh *CoinRequestHandlers) ListCoinRequestsV2Handler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+	direction := c.DefaultQuery("direction", "incoming")
+	if direction != "incoming" && direction != "outgoing" {
+		_ = c.Error(apperr.Invalid("`direction` must be incoming or outgoing"))
+		return
+	}
+
+	getPage := rh.requestSrv.GetIncomingPage
+	if direction == "outgoing" {
+		getPage = rh.requestSrv.GetOutgoingPage
+	}
+	requests, err := getPage(rh.ctx, userID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/audit"
+)
+
+// errContextParsing is returned when the user data set by the JWT middleware can't be read.
+var errContextParsing = apperr.New(apperr.KindInternal, "context_parsing_failure", "context parsing failure")
+
+// userIDFromContext extracts the ID of the authorized user set by the JWT middleware.
+func userIDFromContext(c *gin.Context) (int, error) {
+	userIDStr, ok := c.Get("user_id")
+	if !ok {
+		return 0, errContextParsing
+	}
+	str, ok := userIDStr.(string)
+	if !ok {
+		return 0, errContextParsing
+	}
+	userID, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, errContextParsing
+	}
+	return userID, nil
+}
+
+// auditContext attaches the data of the request - the authorized user, if any, the client's IP and user agent
+// and the request ID - to the context, so that the changes made within the request are audited.
+func auditContext(ctx context.Context, c *gin.Context) context.Context {
+	r := audit.Request{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("request_id"),
+	}
+	if userID, err := userIDFromContext(c); err == nil {
+		r.ActorID = &userID
+		if username := c.GetString("username"); username != "" {
+			r.Actor = &username
+		}
+	}
+	return audit.WithRequest(ctx, r)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+// EventStreamHandlers provides HTTP handlers for the stream of the user's events.
+type EventStreamHandlers struct {
+	ctx       context.Context    // Context for managing request-scoped values and cancellation.
+	streamSrv EventStreamService // Service for streaming the users' events.
+}
+
+// NewEventStreamHandlers creates a new instance of EventStreamHandlers with the provided dependencies.
+func NewEventStreamHandlers(ctx context.Context, streamSrv EventStreamService) *EventStreamHandlers {
+	return &EventStreamHandlers{
+		ctx:       ctx,
+		streamSrv: streamSrv,
+	}
+}
+
+// StreamEventsHandler streams the changes of the user's balance, incoming transfers and purchases
+// as Server-Sent Events until the client disconnects. A reconnecting client sends the `Last-Event-ID` header
+// and receives the events it missed, a new stream starts with the next event.
+func (eh *EventStreamHandlers) StreamEventsHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var lastID int64
+	resume := c.GetHeader("Last-Event-ID")
+	if resume != "" {
+		if lastID, err = strconv.ParseInt(resume, 10, 64); err != nil || lastID < 0 {
+			_ = c.Error(apperr.Invalid("invalid Last-Event-ID"))
+			return
+		}
+	}
+
+	// The stream is opened before reading the last event, so that no event falls in between
+	wake, closeStream, err := eh.streamSrv.Open(userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer closeStream()
+
+	ctx := c.Request.Context()
+	if resume == "" {
+		if lastID, err = eh.streamSrv.LastEventID(ctx, userID); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eh.streamSrv.Heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		if lastID, err = eh.sendEvents(ctx, c.Writer, userID, lastID); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+		case <-heartbeat.C:
+			// the events are read on each heartbeat as well, in case a notification was missed
+			if _, err = io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// sendEvents writes the user's events following the given ID to the stream.
+// Returns the ID of the last event written.
+func (eh *EventStreamHandlers) sendEvents(ctx context.Context, w gin.ResponseWriter, userID int, lastID int64) (int64, error) {
+	for {
+		events, err := eh.streamSrv.GetEvents(ctx, userID, lastID)
+		if err != nil {
+			return lastID, err
+		}
+		if len(*events) == 0 {
+			return lastID, nil
+		}
+
+		for _, e := range *events {
+			if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Kind, e.Payload); err != nil {
+				return lastID, err
+			}
+			lastID = e.ID
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// EventStreamService service
+type EventStreamService interface {
+	Open(userID int) (<-chan struct{}, func(), error)
+	GetEvents(ctx context.Context, userID int, afterID int64) (*[]models.UserEvent, error)
+	LastEventID(ctx context.Context, userID int) (int64, error)
+	Heartbeat() time.Duration
+}
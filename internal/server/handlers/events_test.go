@@ -0,0 +1,108 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestEventStreamHandlers_StreamEventsHandler проверяет поток событий пользователя.
+func TestEventStreamHandlers_StreamEventsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		openErr     error
+		wantAfter   int64
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:      "New stream",
+			wantAfter: 40,
+			wantCode:  http.StatusOK,
+			wantBody:  "id: 41\nevent: transfer\ndata: {\"fromUser\":\"otherUser\",\"amount\":20}\n\n",
+		},
+		{
+			name:        "Resumed stream",
+			lastEventID: "12",
+			wantAfter:   12,
+			wantCode:    http.StatusOK,
+			wantBody:    "id: 41\nevent: transfer\ndata: {\"fromUser\":\"otherUser\",\"amount\":20}\n\n",
+		},
+		{
+			name:        "Invalid Last-Event-ID",
+			lastEventID: "last",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:     "Too many streams",
+			openErr:  models.ErrTooManyStreams,
+			wantCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mStreamSvc := mocks.NewEventStreamService(t)
+			if tt.wantAfter != 0 || tt.openErr != nil {
+				// the stream ends once woken up, as on shutdown
+				wake := make(chan struct{})
+				close(wake)
+				if tt.openErr != nil {
+					mStreamSvc.On("Open", 1).Return(nil, nil, tt.openErr)
+				} else {
+					mStreamSvc.On("Open", 1).Return((<-chan struct{})(wake), func() {}, nil)
+					mStreamSvc.On("Heartbeat").Return(time.Minute)
+					mStreamSvc.On("GetEvents", mock.Anything, 1, tt.wantAfter).Return(&[]models.UserEvent{
+						{ID: 41, Kind: models.UserEventTransfer, Payload: `{"fromUser":"otherUser","amount":20}`},
+					}, nil).Once()
+					mStreamSvc.On("GetEvents", mock.Anything, 1, int64(41)).Return(&[]models.UserEvent{}, nil).Once()
+				}
+				if tt.lastEventID == "" && tt.openErr == nil {
+					mStreamSvc.On("LastEventID", mock.Anything, 1).Return(int64(40), nil)
+				}
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			eh := NewEventStreamHandlers(context.Background(), mStreamSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.GET("/events", eh.StreamEventsHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/events", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+				require.Equal(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// FulfilmentHandlers provides HTTP handlers for operators handing out the purchased merch.
+type FulfilmentHandlers struct {
+	ctx           context.Context   // Context for managing request-scoped values and cancellation.
+	fulfilmentSrv FulfilmentService // Service for tracking the fulfilment of purchases.
+}
+
+// NewFulfilmentHandlers creates a new instance of FulfilmentHandlers with the provided dependencies.
+func NewFulfilmentHandlers(ctx context.Context, fulfilmentSrv FulfilmentService) *FulfilmentHandlers {
+	return &FulfilmentHandlers{
+		ctx:           ctx,
+		fulfilmentSrv: fulfilmentSrv,
+	}
+}
+
+// ListPurchasesHandler returns the purchases waiting to be handed out.
+// Optional `status` and `office` query parameters narrow the list down.
+func (fh *FulfilmentHandlers) ListPurchasesHandler(c *gin.Context) {
+	purchases, err := fh.fulfilmentSrv.GetQueue(fh.ctx, c.Query("status"), c.Query("office"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purchases": purchases})
+}
+
+// ChangePurchaseStatusHandler moves the purchase to the next fulfilment status.
+// Cancelling a purchase refunds its price to the buyer.
+func (fh *FulfilmentHandlers) ChangePurchaseStatusHandler(c *gin.Context) {
+	purchaseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid purchase id"))
+		return
+	}
+
+	var change models.PurchaseStatusChange
+	if err = c.ShouldBindJSON(&change); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	operatorID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = fh.fulfilmentSrv.ChangeStatus(auditContext(fh.ctx, c), operatorID, purchaseID, change.Status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// FulfilmentService service
+type FulfilmentService interface {
+	GetQueue(ctx context.Context, status, office string) (*[]models.Purchase, error)
+	GetQueuePage(ctx context.Context, status, office string, limit, offset int) (*models.Page[models.Purchase], error)
+	ChangeStatus(ctx context.Context, operatorID, purchaseID int, status string) error
+}
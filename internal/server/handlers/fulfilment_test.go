@@ -0,0 +1,101 @@
+//go:build integration
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestFulfilmentHandlers_ChangePurchaseStatusHandler проверяет смену статуса выдачи покупки оператором.
+func TestFulfilmentHandlers_ChangePurchaseStatusHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		id        string
+		body      string
+		status    string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Ready for pickup",
+			id:       "10",
+			body:     `{"status": "ready_for_pickup"}`,
+			status:   models.PurchaseReadyForPickup,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Handed over purchase can't be cancelled",
+			id:        "10",
+			body:      `{"status": "cancelled"}`,
+			status:    models.PurchaseCancelled,
+			mockError: models.ErrInvalidStatusTransition,
+			wantCode:  http.StatusConflict,
+		},
+		{
+			name:      "Purchase not found",
+			id:        "11",
+			body:      `{"status": "handed_over"}`,
+			status:    models.PurchaseHandedOver,
+			mockError: models.ErrPurchaseNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:     "Unknown status",
+			id:       "10",
+			body:     `{"status": "lost"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid purchase id",
+			id:       "abc",
+			body:     `{"status": "handed_over"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mFlfSvc := mocks.NewFulfilmentService(t)
+			if tt.status != "" {
+				mFlfSvc.
+					On("ChangeStatus", mock.Anything, 1, mock.AnythingOfType("int"), tt.status).
+					Return(tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			fh := NewFulfilmentHandlers(context.Background(), mFlfSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/operator/purchases/:id/status", fh.ChangePurchaseStatusHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/operator/purchases/"+tt.id+"/status", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
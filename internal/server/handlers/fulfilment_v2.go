@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPurchasesV2Handler returns a page of the purchases waiting to be handed out.
+// Optional `status` and `office` query parameters narrow the list down.
+func (fh *FulfilmentHandlers) ListPurchasesV2Handler(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	purchases, err := fh.fulfilmentSrv.GetQueuePage(fh.ctx, c.Query("status"), c.Query("office"), page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, purchases)
+}
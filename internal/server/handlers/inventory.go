@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// InventoryHandlers provides HTTP handlers for operations on items already owned by users.
+type InventoryHandlers struct {
+	ctx    context.Context  // Context for managing request-scoped values and cancellation.
+	invSrv InventoryService // Service for managing users' inventory.
+}
+
+// NewInventoryHandlers creates a new instance of InventoryHandlers with the provided dependencies.
+func NewInventoryHandlers(ctx context.Context, invSrv InventoryService) *InventoryHandlers {
+	return &InventoryHandlers{
+		ctx:    ctx,
+		invSrv: invSrv,
+	}
+}
+
+// ReturnItemHandler handles the return of a purchased item back to the shop.
+// An optional variant SKU is passed in the `variant` query parameter.
+func (ih *InventoryHandlers) ReturnItemHandler(c *gin.Context) {
+	itemSlug := c.Param("item")
+	sku := c.Query("variant")
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	refund, err := ih.invSrv.ReturnItem(ih.ctx, userID, itemSlug, sku)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"refunded": refund})
+}
+
+// GiftItemHandler handles gifting items from the user's inventory to another user.
+func (ih *InventoryHandlers) GiftItemHandler(c *gin.Context) {
+	var gift models.Gift
+	if err := c.ShouldBindJSON(&gift); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	senderID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = ih.invSrv.GiftItem(ih.ctx, senderID, gift.User, gift.Item, gift.Variant, gift.Quantity)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"context"
+)
+
+// InventoryService service
+type InventoryService interface {
+	ReturnItem(ctx context.Context, userID int, slug, sku string) (int, error)
+	GiftItem(ctx context.Context, senderID int, recipient, slug, sku string, quantity int) error
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestInventoryHandlers_ReturnItemHandler проверяет возврат товара и отказ при истёкшем окне возврата.
+func TestInventoryHandlers_ReturnItemHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		item      string
+		variant   string
+		refund    int
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Item returned",
+			item:     "hoody",
+			refund:   300,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Variant returned",
+			item:     "hoody",
+			variant:  "hoody-xl",
+			refund:   350,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Return window expired",
+			item:      "cup",
+			mockError: models.ErrNoReturnablePurchase,
+			wantCode:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mInvSvc := mocks.NewInventoryService(t)
+			mInvSvc.
+				On("ReturnItem", mock.Anything, 1, tt.item, tt.variant).
+				Return(tt.refund, tt.mockError)
+
+			dTokenMng := &dummyTokenManager{}
+			ih := NewInventoryHandlers(context.Background(), mInvSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/inventory/:item/return", ih.ReturnItemHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/inventory/"+tt.item+"/return?variant="+tt.variant, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
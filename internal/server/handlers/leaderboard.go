@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// LeaderboardHandlers provides HTTP handlers for the leaderboards of received coins and collected items.
+type LeaderboardHandlers struct {
+	ctx            context.Context    // Context for managing request-scoped values and cancellation.
+	leaderboardSrv LeaderboardService // Service for ranking users.
+}
+
+// NewLeaderboardHandlers creates a new instance of LeaderboardHandlers with the provided dependencies.
+func NewLeaderboardHandlers(ctx context.Context, leaderboardSrv LeaderboardService) *LeaderboardHandlers {
+	return &LeaderboardHandlers{
+		ctx:            ctx,
+		leaderboardSrv: leaderboardSrv,
+	}
+}
+
+// LeaderboardHandler returns the top users. The `by` query parameter chooses the ranking (coins by default),
+// `period` chooses the period of received coins (all time by default), `limit` - the number of places.
+func (lh *LeaderboardHandlers) LeaderboardHandler(c *gin.Context) {
+	by := c.DefaultQuery("by", models.LeaderboardByCoins)
+	period := c.DefaultQuery("period", models.PeriodAllTime)
+
+	var limit int
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			_ = c.Error(apperr.Invalid("`limit` must be a positive integer"))
+			return
+		}
+	}
+
+	entries, err := lh.leaderboardSrv.GetLeaderboard(lh.ctx, by, period, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"by": by, "period": period, "leaderboard": entries})
+}
+
+// SetVisibilityHandler shows the user on the leaderboards or hides them.
+func (lh *LeaderboardHandlers) SetVisibilityHandler(c *gin.Context) {
+	var visibility models.LeaderboardVisibility
+	if err := c.ShouldBindJSON(&visibility); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = lh.leaderboardSrv.SetVisibility(lh.ctx, userID, *visibility.Visible); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// LeaderboardService service
+type LeaderboardService interface {
+	GetLeaderboard(ctx context.Context, by, period string, limit int) (*[]models.LeaderboardEntry, error)
+	SetVisibility(ctx context.Context, userID int, visible bool) error
+}
@@ -0,0 +1,125 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestLeaderboardHandlers_LeaderboardHandler проверяет параметры рейтинга по умолчанию и отказ при неверных параметрах.
+func TestLeaderboardHandlers_LeaderboardHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		query     string
+		by        string
+		period    string
+		limit     int
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Default leaderboard",
+			by:       models.LeaderboardByCoins,
+			period:   models.PeriodAllTime,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Coins of the week",
+			query:    "?by=coins&period=week&limit=3",
+			by:       models.LeaderboardByCoins,
+			period:   models.PeriodWeek,
+			limit:    3,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Items per month",
+			query:     "?by=items&period=month",
+			by:        models.LeaderboardByItems,
+			period:    models.PeriodMonth,
+			mockError: models.ErrInvalidLeaderboard,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid limit",
+			query:    "?limit=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mLeaderboardSvc := mocks.NewLeaderboardService(t)
+			if tt.by != "" {
+				mLeaderboardSvc.
+					On("GetLeaderboard", mock.Anything, tt.by, tt.period, tt.limit).
+					Return(&[]models.LeaderboardEntry{}, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			lh := NewLeaderboardHandlers(context.Background(), mLeaderboardSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.GET("/leaderboard", lh.LeaderboardHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/leaderboard"+tt.query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+// TestLeaderboardHandlers_SetVisibilityHandler проверяет скрытие пользователя из рейтинга.
+func TestLeaderboardHandlers_SetVisibilityHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	mLeaderboardSvc := mocks.NewLeaderboardService(t)
+	mLeaderboardSvc.
+		On("SetVisibility", mock.Anything, 1, false).
+		Return(nil)
+
+	dTokenMng := &dummyTokenManager{}
+	lh := NewLeaderboardHandlers(context.Background(), mLeaderboardSvc)
+
+	meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+	authorized := router.Group("/", meddlers.JWTMiddleware())
+	{
+		authorized.PUT("/leaderboard/visibility", lh.SetVisibilityHandler)
+	}
+
+	// Без поля visible запрос отклоняется, сервис не вызывается.
+	for body, wantCode := range map[string]int{`{"visible": false}`: http.StatusOK, `{}`: http.StatusBadRequest} {
+		req, err := http.NewRequest(http.MethodPut, "/leaderboard/visibility", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+validToken)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, wantCode, w.Code, body)
+	}
+}
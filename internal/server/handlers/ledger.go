@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/modules/ledger"
+)
+
+// ledgerContentTypes are the media types of the export formats.
+var ledgerContentTypes = map[string]string{
+	ledger.FormatCSV:    "text/csv; charset=utf-8",
+	ledger.FormatNDJSON: "application/x-ndjson",
+}
+
+// LedgerHandlers provides admin HTTP handlers for the finance export of the coin ledger.
+type LedgerHandlers struct {
+	ctx       context.Context // Context for managing request-scoped values and cancellation.
+	ledgerSrv LedgerService   // Service for exporting the coin ledger.
+}
+
+// NewLedgerHandlers creates a new instance of LedgerHandlers with the provided dependencies.
+func NewLedgerHandlers(ctx context.Context, ledgerSrv LedgerService) *LedgerHandlers {
+	return &LedgerHandlers{
+		ctx:       ctx,
+		ledgerSrv: ledgerSrv,
+	}
+}
+
+// ExportLedgerHandler streams the transactions and purchases made in the time range `from`-`to` (RFC 3339,
+// `to` excluded) with the running balances of the users, as CSV or, if `format` is ndjson, as NDJSON.
+// An error occurring once the rows have been sent can't be reported, the connection is aborted instead,
+// so that the client doesn't take the cut short export for the whole ledger.
+func (lh *LedgerHandlers) ExportLedgerHandler(c *gin.Context) {
+	var from, to time.Time
+	for param, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		t, err := time.Parse(time.RFC3339, c.Query(param))
+		if err != nil {
+			_ = c.Error(apperr.Invalid("`" + param + "` must be an RFC 3339 time"))
+			return
+		}
+		*dst = t.UTC()
+	}
+	format := c.DefaultQuery("format", ledger.FormatCSV)
+	contentType, ok := ledgerContentTypes[format]
+	if !ok {
+		_ = c.Error(apperr.Invalid("`format` must be csv or ndjson"))
+		return
+	}
+	if !from.Before(to) {
+		_ = c.Error(apperr.Invalid("`from` must be before `to`"))
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="ledger-`+from.Format("20060102")+"-"+to.Format("20060102")+"."+format+`"`)
+	c.Status(http.StatusOK)
+	if err := lh.ledgerSrv.Export(c.Request.Context(), from, to, format, c.Writer); err != nil {
+		if c.Writer.Written() {
+			panic(http.ErrAbortHandler)
+		}
+		// the export failed before its first row, the error is rendered instead of the file
+		c.Writer.Header().Del("Content-Disposition")
+		_ = c.Error(err)
+	}
+}
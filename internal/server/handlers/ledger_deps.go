@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// LedgerService service
+type LedgerService interface {
+	Export(ctx context.Context, from, to time.Time, format string, w io.Writer) error
+}
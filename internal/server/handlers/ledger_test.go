@@ -0,0 +1,123 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+)
+
+// TestLedgerHandlers_ExportLedgerHandler проверяет выгрузку журнала монет в CSV и NDJSON и проверку диапазона.
+func TestLedgerHandlers_ExportLedgerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		query           string
+		format          string
+		exportErr       error
+		written         bool // выгрузка падает после отправки строк
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "CSV by default",
+			query:           "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z",
+			format:          "csv",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "exported",
+		},
+		{
+			name:            "NDJSON",
+			query:           "?from=2025-03-01T03:00:00%2B03:00&to=2025-04-01T00:00:00Z&format=ndjson",
+			format:          "ndjson",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "exported",
+		},
+		{
+			name:      "Failed before the first row",
+			query:     "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z",
+			format:    "csv",
+			exportErr: errors.New("database error"),
+			wantCode:  http.StatusInternalServerError,
+		},
+		{
+			name:      "Failed midway",
+			query:     "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z",
+			format:    "csv",
+			exportErr: errors.New("database error"),
+			written:   true,
+		},
+		{
+			name:     "Range reversed",
+			query:    "?from=2025-04-01T00:00:00Z&to=2025-03-01T00:00:00Z",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Range missing",
+			query:    "?from=2025-03-01T00:00:00Z",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown format",
+			query:    "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&format=xlsx",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mLedgerSvc := mocks.NewLedgerService(t)
+			if tt.format != "" {
+				call := mLedgerSvc.On("Export", mock.Anything, from, to, tt.format, mock.Anything).Return(tt.exportErr)
+				if tt.exportErr == nil || tt.written {
+					call.Run(func(args mock.Arguments) {
+						_, _ = io.WriteString(args.Get(4).(io.Writer), "exported")
+					})
+				}
+			}
+
+			lh := NewLedgerHandlers(context.Background(), mLedgerSvc)
+			router.GET("/admin/ledger", lh.ExportLedgerHandler)
+
+			req, err := http.NewRequest(http.MethodGet, "/admin/ledger"+tt.query, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			if tt.written {
+				// соединение обрывается, чтобы клиент не принял отправленные строки за всю выгрузку
+				require.PanicsWithValue(t, http.ErrAbortHandler, func() { router.ServeHTTP(w, req) })
+				return
+			}
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				require.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="ledger-20250301-20250401.`+tt.format+`"`, w.Header().Get("Content-Disposition"))
+				require.Equal(t, tt.wantBody, w.Body.String())
+			} else {
+				require.Empty(t, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
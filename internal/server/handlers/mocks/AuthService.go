@@ -15,6 +15,24 @@ type AuthService struct {
 	mock.Mock
 }
 
+// CheckAccount provides a mock function with given fields: ctx, userID
+func (_m *AuthService) CheckAccount(ctx context.Context, userID int) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ComparePassword provides a mock function with given fields: hashedPasswd, passwd
 func (_m *AuthService) ComparePassword(hashedPasswd string, passwd string) bool {
 	ret := _m.Called(hashedPasswd, passwd)
@@ -33,9 +51,9 @@ func (_m *AuthService) ComparePassword(hashedPasswd string, passwd string) bool
 	return r0
 }
 
-// GetOrRegUser provides a mock function with given fields: ctx, username, password
-func (_m *AuthService) GetOrRegUser(ctx context.Context, username string, password string) (*models.User, bool, error) {
-	ret := _m.Called(ctx, username, password)
+// GetOrRegUser provides a mock function with given fields: ctx, username, password, claimToken
+func (_m *AuthService) GetOrRegUser(ctx context.Context, username string, password string, claimToken string) (*models.User, bool, error) {
+	ret := _m.Called(ctx, username, password, claimToken)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrRegUser")
@@ -44,25 +62,25 @@ func (_m *AuthService) GetOrRegUser(ctx context.Context, username string, passwo
 	var r0 *models.User
 	var r1 bool
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.User, bool, error)); ok {
-		return rf(ctx, username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.User, bool, error)); ok {
+		return rf(ctx, username, password, claimToken)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.User); ok {
-		r0 = rf(ctx, username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.User); ok {
+		r0 = rf(ctx, username, password, claimToken)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) bool); ok {
-		r1 = rf(ctx, username, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) bool); ok {
+		r1 = rf(ctx, username, password, claimToken)
 	} else {
 		r1 = ret.Get(1).(bool)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
-		r2 = rf(ctx, username, password)
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(ctx, username, password, claimToken)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -70,6 +88,24 @@ func (_m *AuthService) GetOrRegUser(ctx context.Context, username string, passwo
 	return r0, r1, r2
 }
 
+// RecordAuthEvent provides a mock function with given fields: ctx, action, user
+func (_m *AuthService) RecordAuthEvent(ctx context.Context, action string, user *models.User) error {
+	ret := _m.Called(ctx, action, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordAuthEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.User) error); ok {
+		r0 = rf(ctx, action, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAuthService creates a new instance of AuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthService(t interface {
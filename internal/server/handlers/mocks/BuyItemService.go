@@ -15,45 +15,29 @@ type BuyItemService struct {
 	mock.Mock
 }
 
-// BuyItem provides a mock function with given fields: ctx, userID, item
-func (_m *BuyItemService) BuyItem(ctx context.Context, userID int, item *models.Item) error {
-	ret := _m.Called(ctx, userID, item)
+// BuyItem provides a mock function with given fields: ctx, userID, slug, order
+func (_m *BuyItemService) BuyItem(ctx context.Context, userID int, slug string, order *models.Order) (*models.Quote, error) {
+	ret := _m.Called(ctx, userID, slug, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BuyItem")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Item) error); ok {
-		r0 = rf(ctx, userID, item)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// GetBuyerCoins provides a mock function with given fields: ctx, userID
-func (_m *BuyItemService) GetBuyerCoins(ctx context.Context, userID int) (int, error) {
-	ret := _m.Called(ctx, userID)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetBuyerCoins")
-	}
-
-	var r0 int
+	var r0 *models.Quote
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *models.Order) (*models.Quote, error)); ok {
+		return rf(ctx, userID, slug, order)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *models.Order) *models.Quote); ok {
+		r0 = rf(ctx, userID, slug, order)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Quote)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, *models.Order) error); ok {
+		r1 = rf(ctx, userID, slug, order)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,29 +45,29 @@ func (_m *BuyItemService) GetBuyerCoins(ctx context.Context, userID int) (int, e
 	return r0, r1
 }
 
-// GetItem provides a mock function with given fields: ctx, slug
-func (_m *BuyItemService) GetItem(ctx context.Context, slug string) (*models.Item, error) {
-	ret := _m.Called(ctx, slug)
+// GetCatalog provides a mock function with given fields: ctx, userID
+func (_m *BuyItemService) GetCatalog(ctx context.Context, userID int) (*[]models.Item, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetItem")
+		panic("no return value specified for GetCatalog")
 	}
 
-	var r0 *models.Item
+	var r0 *[]models.Item
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Item, error)); ok {
-		return rf(ctx, slug)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Item, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Item); ok {
-		r0 = rf(ctx, slug)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Item); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Item)
+			r0 = ret.Get(0).(*[]models.Item)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, slug)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CatalogService is an autogenerated mock type for the CatalogService type
+type CatalogService struct {
+	mock.Mock
+}
+
+// CreateVariant provides a mock function with given fields: ctx, slug, variant
+func (_m *CatalogService) CreateVariant(ctx context.Context, slug string, variant *models.Variant) error {
+	ret := _m.Called(ctx, slug, variant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.Variant) error); ok {
+		r0 = rf(ctx, slug, variant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestockVariant provides a mock function with given fields: ctx, sku, quantity
+func (_m *CatalogService) RestockVariant(ctx context.Context, sku string, quantity int) (*models.Variant, error) {
+	ret := _m.Called(ctx, sku, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestockVariant")
+	}
+
+	var r0 *models.Variant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*models.Variant, error)); ok {
+		return rf(ctx, sku, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *models.Variant); ok {
+		r0 = rf(ctx, sku, quantity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Variant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, sku, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateVariant provides a mock function with given fields: ctx, sku, variant
+func (_m *CatalogService) UpdateVariant(ctx context.Context, sku string, variant *models.Variant) error {
+	ret := _m.Called(ctx, sku, variant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.Variant) error); ok {
+		r0 = rf(ctx, sku, variant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCatalogService creates a new instance of CatalogService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCatalogService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CatalogService {
+	mock := &CatalogService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,270 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// CoinRequestService is an autogenerated mock type for the CoinRequestService type
+type CoinRequestService struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: ctx, userID, requestID
+func (_m *CoinRequestService) Accept(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accept")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cancel provides a mock function with given fields: ctx, userID, requestID
+func (_m *CoinRequestService) Cancel(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRequest provides a mock function with given fields: ctx, userID, c
+func (_m *CoinRequestService) CreateRequest(ctx context.Context, userID int, c *models.CoinRequestCreation) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRequest")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.CoinRequestCreation) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.CoinRequestCreation) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.CoinRequestCreation) error); ok {
+		r1 = rf(ctx, userID, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Decline provides a mock function with given fields: ctx, userID, requestID
+func (_m *CoinRequestService) Decline(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decline")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncomingPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *CoinRequestService) GetIncomingPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.CoinRequest], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIncomingPage")
+	}
+
+	var r0 *models.Page[models.CoinRequest]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.CoinRequest], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.CoinRequest]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.CoinRequest])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutgoingPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *CoinRequestService) GetOutgoingPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.CoinRequest], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingPage")
+	}
+
+	var r0 *models.Page[models.CoinRequest]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.CoinRequest], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.CoinRequest]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.CoinRequest])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequest provides a mock function with given fields: ctx, userID, requestID
+func (_m *CoinRequestService) GetRequest(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequest")
+	}
+
+	var r0 *models.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.CoinRequest, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.CoinRequest); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequests provides a mock function with given fields: ctx, userID
+func (_m *CoinRequestService) GetRequests(ctx context.Context, userID int) (*models.CoinRequests, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequests")
+	}
+
+	var r0 *models.CoinRequests
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.CoinRequests, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.CoinRequests); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CoinRequests)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCoinRequestService creates a new instance of CoinRequestService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCoinRequestService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CoinRequestService {
+	mock := &CoinRequestService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
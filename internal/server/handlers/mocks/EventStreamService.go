@@ -0,0 +1,147 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+
+	time "time"
+)
+
+// EventStreamService is an autogenerated mock type for the EventStreamService type
+type EventStreamService struct {
+	mock.Mock
+}
+
+// GetEvents provides a mock function with given fields: ctx, userID, afterID
+func (_m *EventStreamService) GetEvents(ctx context.Context, userID int, afterID int64) (*[]models.UserEvent, error) {
+	ret := _m.Called(ctx, userID, afterID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEvents")
+	}
+
+	var r0 *[]models.UserEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) (*[]models.UserEvent, error)); ok {
+		return rf(ctx, userID, afterID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) *[]models.UserEvent); ok {
+		r0 = rf(ctx, userID, afterID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.UserEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64) error); ok {
+		r1 = rf(ctx, userID, afterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Heartbeat provides a mock function with no fields
+func (_m *EventStreamService) Heartbeat() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Heartbeat")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// LastEventID provides a mock function with given fields: ctx, userID
+func (_m *EventStreamService) LastEventID(ctx context.Context, userID int) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastEventID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: userID
+func (_m *EventStreamService) Open(userID int) (<-chan struct{}, func(), error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 <-chan struct{}
+	var r1 func()
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int) (<-chan struct{}, func(), error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) <-chan struct{}); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) func()); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(int) error); ok {
+		r2 = rf(userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewEventStreamService creates a new instance of EventStreamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventStreamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventStreamService {
+	mock := &EventStreamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
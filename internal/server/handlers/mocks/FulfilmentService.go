@@ -0,0 +1,108 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// FulfilmentService is an autogenerated mock type for the FulfilmentService type
+type FulfilmentService struct {
+	mock.Mock
+}
+
+// ChangeStatus provides a mock function with given fields: ctx, operatorID, purchaseID, status
+func (_m *FulfilmentService) ChangeStatus(ctx context.Context, operatorID int, purchaseID int, status string) error {
+	ret := _m.Called(ctx, operatorID, purchaseID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = rf(ctx, operatorID, purchaseID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetQueue provides a mock function with given fields: ctx, status, office
+func (_m *FulfilmentService) GetQueue(ctx context.Context, status string, office string) (*[]models.Purchase, error) {
+	ret := _m.Called(ctx, status, office)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueue")
+	}
+
+	var r0 *[]models.Purchase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*[]models.Purchase, error)); ok {
+		return rf(ctx, status, office)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *[]models.Purchase); ok {
+		r0 = rf(ctx, status, office)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Purchase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, status, office)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQueuePage provides a mock function with given fields: ctx, status, office, limit, offset
+func (_m *FulfilmentService) GetQueuePage(ctx context.Context, status string, office string, limit int, offset int) (*models.Page[models.Purchase], error) {
+	ret := _m.Called(ctx, status, office, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueuePage")
+	}
+
+	var r0 *models.Page[models.Purchase]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) (*models.Page[models.Purchase], error)); ok {
+		return rf(ctx, status, office, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) *models.Page[models.Purchase]); ok {
+		r0 = rf(ctx, status, office, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Purchase])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
+		r1 = rf(ctx, status, office, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFulfilmentService creates a new instance of FulfilmentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFulfilmentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FulfilmentService {
+	mock := &FulfilmentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
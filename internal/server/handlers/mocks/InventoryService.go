@@ -0,0 +1,74 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InventoryService is an autogenerated mock type for the InventoryService type
+type InventoryService struct {
+	mock.Mock
+}
+
+// GiftItem provides a mock function with given fields: ctx, senderID, recipient, slug, sku, quantity
+func (_m *InventoryService) GiftItem(ctx context.Context, senderID int, recipient string, slug string, sku string, quantity int) error {
+	ret := _m.Called(ctx, senderID, recipient, slug, sku, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GiftItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, int) error); ok {
+		r0 = rf(ctx, senderID, recipient, slug, sku, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReturnItem provides a mock function with given fields: ctx, userID, slug, sku
+func (_m *InventoryService) ReturnItem(ctx context.Context, userID int, slug string, sku string) (int, error) {
+	ret := _m.Called(ctx, userID, slug, sku)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnItem")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (int, error)); ok {
+		return rf(ctx, userID, slug, sku)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) int); ok {
+		r0 = rf(ctx, userID, slug, sku)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, slug, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewInventoryService creates a new instance of InventoryService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInventoryService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InventoryService {
+	mock := &InventoryService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
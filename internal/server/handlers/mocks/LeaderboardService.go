@@ -0,0 +1,78 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// LeaderboardService is an autogenerated mock type for the LeaderboardService type
+type LeaderboardService struct {
+	mock.Mock
+}
+
+// GetLeaderboard provides a mock function with given fields: ctx, by, period, limit
+func (_m *LeaderboardService) GetLeaderboard(ctx context.Context, by string, period string, limit int) (*[]models.LeaderboardEntry, error) {
+	ret := _m.Called(ctx, by, period, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeaderboard")
+	}
+
+	var r0 *[]models.LeaderboardEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*[]models.LeaderboardEntry, error)); ok {
+		return rf(ctx, by, period, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *[]models.LeaderboardEntry); ok {
+		r0 = rf(ctx, by, period, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.LeaderboardEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, by, period, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVisibility provides a mock function with given fields: ctx, userID, visible
+func (_m *LeaderboardService) SetVisibility(ctx context.Context, userID int, visible bool) error {
+	ret := _m.Called(ctx, userID, visible)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVisibility")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) error); ok {
+		r0 = rf(ctx, userID, visible)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLeaderboardService creates a new instance of LeaderboardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLeaderboardService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LeaderboardService {
+	mock := &LeaderboardService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
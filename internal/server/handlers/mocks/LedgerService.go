@@ -0,0 +1,50 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LedgerService is an autogenerated mock type for the LedgerService type
+type LedgerService struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, from, to, format, w
+func (_m *LedgerService) Export(ctx context.Context, from time.Time, to time.Time, format string, w io.Writer) error {
+	ret := _m.Called(ctx, from, to, format, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string, io.Writer) error); ok {
+		r0 = rf(ctx, from, to, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLedgerService creates a new instance of LedgerService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerService {
+	mock := &LedgerService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// PromotionService is an autogenerated mock type for the PromotionService type
+type PromotionService struct {
+	mock.Mock
+}
+
+// CreateDiscount provides a mock function with given fields: ctx, discount
+func (_m *PromotionService) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	ret := _m.Called(ctx, discount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDiscount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Discount) error); ok {
+		r0 = rf(ctx, discount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePromoCode provides a mock function with given fields: ctx, promo
+func (_m *PromotionService) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	ret := _m.Called(ctx, promo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePromoCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PromoCode) error); ok {
+		r0 = rf(ctx, promo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDiscount provides a mock function with given fields: ctx, id
+func (_m *PromotionService) DeleteDiscount(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDiscount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDiscounts provides a mock function with given fields: ctx
+func (_m *PromotionService) GetDiscounts(ctx context.Context) (*[]models.Discount, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDiscounts")
+	}
+
+	var r0 *[]models.Discount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.Discount, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.Discount); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Discount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromoCodes provides a mock function with given fields: ctx
+func (_m *PromotionService) GetPromoCodes(ctx context.Context) (*[]models.PromoCode, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromoCodes")
+	}
+
+	var r0 *[]models.PromoCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.PromoCode, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.PromoCode); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.PromoCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPromotionService creates a new instance of PromotionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPromotionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PromotionService {
+	mock := &PromotionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
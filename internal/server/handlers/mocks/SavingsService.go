@@ -0,0 +1,118 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// SavingsService is an autogenerated mock type for the SavingsService type
+type SavingsService struct {
+	mock.Mock
+}
+
+// CloseGoal provides a mock function with given fields: ctx, userID, goalID
+func (_m *SavingsService) CloseGoal(ctx context.Context, userID int, goalID int) (int, error) {
+	ret := _m.Called(ctx, userID, goalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseGoal")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int, error)); ok {
+		return rf(ctx, userID, goalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int); ok {
+		r0 = rf(ctx, userID, goalID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, goalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateGoal provides a mock function with given fields: ctx, userID, slug
+func (_m *SavingsService) CreateGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateGoal")
+	}
+
+	var r0 *models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.SavingsGoal, error)); ok {
+		return rf(ctx, userID, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.SavingsGoal); ok {
+		r0 = rf(ctx, userID, slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Deposit provides a mock function with given fields: ctx, userID, goalID, amount
+func (_m *SavingsService) Deposit(ctx context.Context, userID int, goalID int, amount int) (*models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID, goalID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deposit")
+	}
+
+	var r0 *models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.SavingsGoal, error)); ok {
+		return rf(ctx, userID, goalID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.SavingsGoal); ok {
+		r0 = rf(ctx, userID, goalID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, goalID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSavingsService creates a new instance of SavingsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSavingsService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SavingsService {
+	mock := &SavingsService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// ScheduledTransferService is an autogenerated mock type for the ScheduledTransferService type
+type ScheduledTransferService struct {
+	mock.Mock
+}
+
+// CancelTransfer provides a mock function with given fields: ctx, userID, transferID
+func (_m *ScheduledTransferService) CancelTransfer(ctx context.Context, userID int, transferID int) error {
+	ret := _m.Called(ctx, userID, transferID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, transferID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTransfer provides a mock function with given fields: ctx, senderID, c
+func (_m *ScheduledTransferService) CreateTransfer(ctx context.Context, senderID int, c *models.ScheduledTransferCreation) (*models.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, senderID, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransfer")
+	}
+
+	var r0 *models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.ScheduledTransferCreation) (*models.ScheduledTransfer, error)); ok {
+		return rf(ctx, senderID, c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.ScheduledTransferCreation) *models.ScheduledTransfer); ok {
+		r0 = rf(ctx, senderID, c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.ScheduledTransferCreation) error); ok {
+		r1 = rf(ctx, senderID, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransfers provides a mock function with given fields: ctx, userID
+func (_m *ScheduledTransferService) GetTransfers(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransfers")
+	}
+
+	var r0 *[]models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.ScheduledTransfer, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.ScheduledTransfer); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScheduledTransferService creates a new instance of ScheduledTransferService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduledTransferService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduledTransferService {
+	mock := &ScheduledTransferService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
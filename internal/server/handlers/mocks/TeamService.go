@@ -0,0 +1,264 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TeamService is an autogenerated mock type for the TeamService type
+type TeamService struct {
+	mock.Mock
+}
+
+// CreateTeam provides a mock function with given fields: ctx, userID, c
+func (_m *TeamService) CreateTeam(ctx context.Context, userID int, c *models.TeamCreation) (*models.Team, error) {
+	ret := _m.Called(ctx, userID, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTeam")
+	}
+
+	var r0 *models.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.TeamCreation) (*models.Team, error)); ok {
+		return rf(ctx, userID, c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.TeamCreation) *models.Team); ok {
+		r0 = rf(ctx, userID, c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.TeamCreation) error); ok {
+		r1 = rf(ctx, userID, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Deposit provides a mock function with given fields: ctx, userID, teamID, coins
+func (_m *TeamService) Deposit(ctx context.Context, userID int, teamID int, coins int) error {
+	ret := _m.Called(ctx, userID, teamID, coins)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deposit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = rf(ctx, userID, teamID, coins)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetApprovals provides a mock function with given fields: ctx, ownerID, teamID
+func (_m *TeamService) GetApprovals(ctx context.Context, ownerID int, teamID int) (*[]models.TeamSpend, error) {
+	ret := _m.Called(ctx, ownerID, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetApprovals")
+	}
+
+	var r0 *[]models.TeamSpend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*[]models.TeamSpend, error)); ok {
+		return rf(ctx, ownerID, teamID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *[]models.TeamSpend); ok {
+		r0 = rf(ctx, ownerID, teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.TeamSpend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, ownerID, teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeam provides a mock function with given fields: ctx, userID, teamID
+func (_m *TeamService) GetTeam(ctx context.Context, userID int, teamID int) (*models.Team, error) {
+	ret := _m.Called(ctx, userID, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeam")
+	}
+
+	var r0 *models.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.Team, error)); ok {
+		return rf(ctx, userID, teamID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.Team); ok {
+		r0 = rf(ctx, userID, teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeams provides a mock function with given fields: ctx, userID
+func (_m *TeamService) GetTeams(ctx context.Context, userID int) (*[]models.Team, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeams")
+	}
+
+	var r0 *[]models.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Team, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Team); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: ctx, actorID, teamID, username
+func (_m *TeamService) RemoveMember(ctx context.Context, actorID int, teamID int, username string) error {
+	ret := _m.Called(ctx, actorID, teamID, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = rf(ctx, actorID, teamID, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveApproval provides a mock function with given fields: ctx, ownerID, teamID, spendID, status
+func (_m *TeamService) ResolveApproval(ctx context.Context, ownerID int, teamID int, spendID int, status string) (*models.TeamSpend, error) {
+	ret := _m.Called(ctx, ownerID, teamID, spendID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveApproval")
+	}
+
+	var r0 *models.TeamSpend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string) (*models.TeamSpend, error)); ok {
+		return rf(ctx, ownerID, teamID, spendID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string) *models.TeamSpend); ok {
+		r0 = rf(ctx, ownerID, teamID, spendID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TeamSpend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, string) error); ok {
+		r1 = rf(ctx, ownerID, teamID, spendID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetMember provides a mock function with given fields: ctx, ownerID, teamID, username, m
+func (_m *TeamService) SetMember(ctx context.Context, ownerID int, teamID int, username string, m *models.TeamMembership) error {
+	ret := _m.Called(ctx, ownerID, teamID, username, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, *models.TeamMembership) error); ok {
+		r0 = rf(ctx, ownerID, teamID, username, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Spend provides a mock function with given fields: ctx, userID, teamID, sp
+func (_m *TeamService) Spend(ctx context.Context, userID int, teamID int, sp *models.TeamSpending) (*models.TeamSpend, error) {
+	ret := _m.Called(ctx, userID, teamID, sp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Spend")
+	}
+
+	var r0 *models.TeamSpend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.TeamSpending) (*models.TeamSpend, error)); ok {
+		return rf(ctx, userID, teamID, sp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.TeamSpending) *models.TeamSpend); ok {
+		r0 = rf(ctx, userID, teamID, sp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TeamSpend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.TeamSpending) error); ok {
+		r1 = rf(ctx, userID, teamID, sp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTeamService creates a new instance of TeamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTeamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TeamService {
+	mock := &TeamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
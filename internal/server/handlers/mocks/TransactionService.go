@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
 )
 
 // TransactionService is an autogenerated mock type for the TransactionService type
@@ -69,6 +71,36 @@ func (_m *TransactionService) GetSenderCoins(ctx context.Context, userID int) (i
 	return r0, r1
 }
 
+// SendCoinsBatch provides a mock function with given fields: ctx, senderID, batch
+func (_m *TransactionService) SendCoinsBatch(ctx context.Context, senderID int, batch *models.BatchSending) (*models.BatchResult, error) {
+	ret := _m.Called(ctx, senderID, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendCoinsBatch")
+	}
+
+	var r0 *models.BatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.BatchSending) (*models.BatchResult, error)); ok {
+		return rf(ctx, senderID, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.BatchSending) *models.BatchResult); ok {
+		r0 = rf(ctx, senderID, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.BatchSending) error); ok {
+		r1 = rf(ctx, senderID, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendCoinsToUser provides a mock function with given fields: ctx, senderID, recipientID, coins
 func (_m *TransactionService) SendCoinsToUser(ctx context.Context, senderID int, recipientID int, coins int) error {
 	ret := _m.Called(ctx, senderID, recipientID, coins)
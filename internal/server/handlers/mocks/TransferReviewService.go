@@ -0,0 +1,120 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TransferReviewService is an autogenerated mock type for the TransferReviewService type
+type TransferReviewService struct {
+	mock.Mock
+}
+
+// GetReviews provides a mock function with given fields: ctx, status
+func (_m *TransferReviewService) GetReviews(ctx context.Context, status string) (*[]models.TransferReview, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviews")
+	}
+
+	var r0 *[]models.TransferReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]models.TransferReview, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]models.TransferReview); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.TransferReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsPage provides a mock function with given fields: ctx, status, limit, offset
+func (_m *TransferReviewService) GetReviewsPage(ctx context.Context, status string, limit int, offset int) (*models.Page[models.TransferReview], error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsPage")
+	}
+
+	var r0 *models.Page[models.TransferReview]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*models.Page[models.TransferReview], error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *models.Page[models.TransferReview]); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.TransferReview])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveReview provides a mock function with given fields: ctx, reviewerID, reviewID, status
+func (_m *TransferReviewService) ResolveReview(ctx context.Context, reviewerID int, reviewID int, status string) (*models.TransferReview, error) {
+	ret := _m.Called(ctx, reviewerID, reviewID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveReview")
+	}
+
+	var r0 *models.TransferReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.TransferReview, error)); ok {
+		return rf(ctx, reviewerID, reviewID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.TransferReview); ok {
+		r0 = rf(ctx, reviewerID, reviewID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, reviewerID, reviewID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTransferReviewService creates a new instance of TransferReviewService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransferReviewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransferReviewService {
+	mock := &TransferReviewService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
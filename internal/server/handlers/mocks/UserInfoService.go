@@ -73,6 +73,66 @@ func (_m *UserInfoService) GetCoins(ctx context.Context, userID int) (int, error
 	return r0, r1
 }
 
+// GetGiftHistory provides a mock function with given fields: ctx, userID
+func (_m *UserInfoService) GetGiftHistory(ctx context.Context, userID int) (*models.GiftHistory, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGiftHistory")
+	}
+
+	var r0 *models.GiftHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.GiftHistory, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.GiftHistory); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GiftHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGoals provides a mock function with given fields: ctx, userID
+func (_m *UserInfoService) GetGoals(ctx context.Context, userID int) (*[]models.SavingsGoal, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGoals")
+	}
+
+	var r0 *[]models.SavingsGoal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.SavingsGoal, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.SavingsGoal); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.SavingsGoal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInventory provides a mock function with given fields: ctx, userID
 func (_m *UserInfoService) GetInventory(ctx context.Context, userID int) (*[]models.Merch, error) {
 	ret := _m.Called(ctx, userID)
@@ -103,6 +163,186 @@ func (_m *UserInfoService) GetInventory(ctx context.Context, userID int) (*[]mod
 	return r0, r1
 }
 
+// GetPurchases provides a mock function with given fields: ctx, userID
+func (_m *UserInfoService) GetPurchases(ctx context.Context, userID int) (*[]models.Purchase, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchases")
+	}
+
+	var r0 *[]models.Purchase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*[]models.Purchase, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *[]models.Purchase); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.Purchase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPurchasesPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *UserInfoService) GetPurchasesPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Purchase], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurchasesPage")
+	}
+
+	var r0 *models.Page[models.Purchase]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Purchase], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Purchase]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Purchase])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceivedCoinsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *UserInfoService) GetReceivedCoinsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Receiving], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceivedCoinsPage")
+	}
+
+	var r0 *models.Page[models.Receiving]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Receiving], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Receiving]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Receiving])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceivedGiftsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *UserInfoService) GetReceivedGiftsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.GiftReceiving], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceivedGiftsPage")
+	}
+
+	var r0 *models.Page[models.GiftReceiving]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.GiftReceiving], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.GiftReceiving]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.GiftReceiving])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentCoinsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *UserInfoService) GetSentCoinsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.Sending], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentCoinsPage")
+	}
+
+	var r0 *models.Page[models.Sending]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.Sending], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.Sending]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.Sending])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentGiftsPage provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *UserInfoService) GetSentGiftsPage(ctx context.Context, userID int, limit int, offset int) (*models.Page[models.GiftSending], error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentGiftsPage")
+	}
+
+	var r0 *models.Page[models.GiftSending]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.Page[models.GiftSending], error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.Page[models.GiftSending]); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.GiftSending])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserInfoService creates a new instance of UserInfoService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserInfoService(t interface {
@@ -0,0 +1,244 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// WebhookService is an autogenerated mock type for the WebhookService type
+type WebhookService struct {
+	mock.Mock
+}
+
+// CreateSubscription provides a mock function with given fields: ctx, c
+func (_m *WebhookService) CreateSubscription(ctx context.Context, c *models.WebhookSubscriptionCreation) (*models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubscription")
+	}
+
+	var r0 *models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscriptionCreation) (*models.WebhookSubscription, error)); ok {
+		return rf(ctx, c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscriptionCreation) *models.WebhookSubscription); ok {
+		r0 = rf(ctx, c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.WebhookSubscriptionCreation) error); ok {
+		r1 = rf(ctx, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSubscription provides a mock function with given fields: ctx, id
+func (_m *WebhookService) DeleteSubscription(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveries provides a mock function with given fields: ctx, status, subscriptionID
+func (_m *WebhookService) GetDeliveries(ctx context.Context, status string, subscriptionID int) (*[]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, status, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 *[]models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*[]models.WebhookDelivery, error)); ok {
+		return rf(ctx, status, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *[]models.WebhookDelivery); ok {
+		r0 = rf(ctx, status, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, status, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeliveriesPage provides a mock function with given fields: ctx, status, subscriptionID, limit, offset
+func (_m *WebhookService) GetDeliveriesPage(ctx context.Context, status string, subscriptionID int, limit int, offset int) (*models.Page[models.WebhookDelivery], error) {
+	ret := _m.Called(ctx, status, subscriptionID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveriesPage")
+	}
+
+	var r0 *models.Page[models.WebhookDelivery]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) (*models.Page[models.WebhookDelivery], error)); ok {
+		return rf(ctx, status, subscriptionID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) *models.Page[models.WebhookDelivery]); ok {
+		r0 = rf(ctx, status, subscriptionID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page[models.WebhookDelivery])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, int) error); ok {
+		r1 = rf(ctx, status, subscriptionID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx
+func (_m *WebhookService) GetSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptions")
+	}
+
+	var r0 *[]models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]models.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]models.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDead provides a mock function with given fields: ctx, subscriptionID
+func (_m *WebhookService) ReplayDead(ctx context.Context, subscriptionID int) (int, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDead")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDelivery provides a mock function with given fields: ctx, id
+func (_m *WebhookService) ReplayDelivery(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSubscription provides a mock function with given fields: ctx, id, u
+func (_m *WebhookService) UpdateSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, id, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSubscription")
+	}
+
+	var r0 *models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error)); ok {
+		return rf(ctx, id, u)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WebhookSubscriptionUpdate) *models.WebhookSubscription); ok {
+		r0 = rf(ctx, id, u)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.WebhookSubscriptionUpdate) error); ok {
+		r1 = rf(ctx, id, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookService creates a new instance of WebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookService {
+	mock := &WebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
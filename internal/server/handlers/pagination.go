@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+const (
+	defaultPageLimit = 20  // page size if `limit` isn't given
+	maxPageLimit     = 100 // the largest page
+)
+
+// pageRequest is the part of a collection requested by the `limit` and `offset` query parameters.
+type pageRequest struct {
+	limit  int
+	offset int
+}
+
+// pageFromQuery reads the requested page, `limit` is 20 by default and 100 at most.
+func pageFromQuery(c *gin.Context) (pageRequest, error) {
+	p := pageRequest{limit: defaultPageLimit}
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if p.limit, err = strconv.Atoi(raw); err != nil || p.limit < 1 || p.limit > maxPageLimit {
+			return p, apperr.Invalid("`limit` must be an integer from 1 to 100")
+		}
+	}
+	if raw := c.Query("offset"); raw != "" {
+		var err error
+		if p.offset, err = strconv.Atoi(raw); err != nil || p.offset < 0 {
+			return p, apperr.Invalid("`offset` must be a non-negative integer")
+		}
+	}
+	return p, nil
+}
+
+// paginate cuts the requested page out of the collection, a missing collection is empty.
+// It's for the collections bounded by the catalog or the settings, e.g. the inventory or the discounts,
+// that are small enough to be paged in memory; the growing ones are paged by the storage.
+func paginate[T any](items *[]T, p pageRequest) models.Page[T] {
+	var all []T
+	if items != nil {
+		all = *items
+	}
+
+	start := min(p.offset, len(all))
+	end := min(start+p.limit, len(all))
+	page := make([]T, end-start)
+	copy(page, all[start:end])
+
+	return models.Page[T]{
+		Items:  page,
+		Total:  len(all),
+		Limit:  p.limit,
+		Offset: p.offset,
+	}
+}
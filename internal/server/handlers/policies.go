@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyHandlers provides admin HTTP handlers for the balance policies.
+type PolicyHandlers struct {
+	ctx       context.Context // Context for managing request-scoped values and cancellation.
+	policySrv PolicyService   // Service for running balance policies.
+}
+
+// NewPolicyHandlers creates a new instance of PolicyHandlers with the provided dependencies.
+func NewPolicyHandlers(ctx context.Context, policySrv PolicyService) *PolicyHandlers {
+	return &PolicyHandlers{
+		ctx:       ctx,
+		policySrv: policySrv,
+	}
+}
+
+// DryRunPolicyHandler reports the users the coming run of the policy would affect, nothing is changed.
+func (ph *PolicyHandlers) DryRunPolicyHandler(c *gin.Context) {
+	report, err := ph.policySrv.DryRun(ph.ctx, c.Param("policy"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
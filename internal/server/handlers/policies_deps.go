@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// PolicyService service
+type PolicyService interface {
+	DryRun(ctx context.Context, name string) (*models.PolicyReport, error)
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+)
+
+// TestPolicyHandlers_DryRunPolicyHandler проверяет отчёт о пользователях, затронутых политикой, и отказ для выключенной политики.
+func TestPolicyHandlers_DryRunPolicyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		policy    string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Expiry dry run",
+			policy:   models.PolicyExpiry,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Disabled policy",
+			policy:    models.PolicyBalanceCap,
+			mockError: models.ErrPolicyNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mPolicySvc := mocks.NewPolicyService(t)
+			mPolicySvc.
+				On("DryRun", mock.Anything, tt.policy).
+				Return(&models.PolicyReport{
+					Policy:   tt.policy,
+					RunAt:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+					Affected: &[]models.PolicyAction{{User: "testUser", Balance: 900, Amount: 700}},
+				}, tt.mockError)
+
+			ph := NewPolicyHandlers(context.Background(), mPolicySvc)
+			router.GET("/admin/policies/:policy/dry-run", ph.DryRunPolicyHandler)
+
+			req, err := http.NewRequest(http.MethodGet, "/admin/policies/"+tt.policy+"/dry-run", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// PromotionHandlers provides HTTP handlers for managing discounts and promo codes by administrators.
+type PromotionHandlers struct {
+	ctx      context.Context  // Context for managing request-scoped values and cancellation.
+	promoSrv PromotionService // Service for managing discounts and promo codes.
+}
+
+// NewPromotionHandlers creates a new instance of PromotionHandlers with the provided dependencies.
+func NewPromotionHandlers(ctx context.Context, promoSrv PromotionService) *PromotionHandlers {
+	return &PromotionHandlers{
+		ctx:      ctx,
+		promoSrv: promoSrv,
+	}
+}
+
+// ListDiscountsHandler returns all the scheduled discounts.
+func (ph *PromotionHandlers) ListDiscountsHandler(c *gin.Context) {
+	discounts, err := ph.promoSrv.GetDiscounts(ph.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"discounts": discounts})
+}
+
+// CreateDiscountHandler schedules a new discount for an item or a category.
+func (ph *PromotionHandlers) CreateDiscountHandler(c *gin.Context) {
+	var discount models.Discount
+	if err := c.ShouldBindJSON(&discount); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	err := ph.promoSrv.CreateDiscount(auditContext(ph.ctx, c), &discount)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, discount)
+}
+
+// DeleteDiscountHandler cancels the discount.
+func (ph *PromotionHandlers) DeleteDiscountHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid discount id"))
+		return
+	}
+
+	err = ph.promoSrv.DeleteDiscount(auditContext(ph.ctx, c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// ListPromoCodesHandler returns all the promo codes with their usage.
+func (ph *PromotionHandlers) ListPromoCodesHandler(c *gin.Context) {
+	promos, err := ph.promoSrv.GetPromoCodes(ph.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promoCodes": promos})
+}
+
+// CreatePromoCodeHandler creates a new single-use or multi-use promo code.
+func (ph *PromotionHandlers) CreatePromoCodeHandler(c *gin.Context) {
+	var promo models.PromoCode
+	if err := c.ShouldBindJSON(&promo); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	err := ph.promoSrv.CreatePromoCode(auditContext(ph.ctx, c), &promo)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
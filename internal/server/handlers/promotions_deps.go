@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// PromotionService service
+type PromotionService interface {
+	GetDiscounts(ctx context.Context) (*[]models.Discount, error)
+	CreateDiscount(ctx context.Context, discount *models.Discount) error
+	DeleteDiscount(ctx context.Context, id int) error
+	GetPromoCodes(ctx context.Context) (*[]models.PromoCode, error)
+	CreatePromoCode(ctx context.Context, promo *models.PromoCode) error
+}
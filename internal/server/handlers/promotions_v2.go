@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDiscountsV2Handler returns a page of the discounts.
+func (ph *PromotionHandlers) ListDiscountsV2Handler(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	discounts, err := ph.promoSrv.GetDiscounts(ph.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(discounts, page))
+}
+
+// ListPromoCodesV2Handler returns a page of the promo codes.
+func (ph *PromotionHandlers) ListPromoCodesV2Handler(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	promos, err := ph.promoSrv.GetPromoCodes(ph.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(promos, page))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// SavingsHandlers provides HTTP handlers for users' savings goals.
+type SavingsHandlers struct {
+	ctx        context.Context // Context for managing request-scoped values and cancellation.
+	savingsSrv SavingsService  // Service for managing users' savings goals.
+}
+
+// NewSavingsHandlers creates a new instance of SavingsHandlers with the provided dependencies.
+func NewSavingsHandlers(ctx context.Context, savingsSrv SavingsService) *SavingsHandlers {
+	return &SavingsHandlers{
+		ctx:        ctx,
+		savingsSrv: savingsSrv,
+	}
+}
+
+// CreateGoalHandler starts saving coins for the item.
+func (sh *SavingsHandlers) CreateGoalHandler(c *gin.Context) {
+	var creation models.SavingsGoalCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	goal, err := sh.savingsSrv.CreateGoal(sh.ctx, userID, creation.Item)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// DepositHandler moves coins from the user's balance into the savings goal.
+func (sh *SavingsHandlers) DepositHandler(c *gin.Context) {
+	goalID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid goal id"))
+		return
+	}
+
+	var deposit models.SavingsDeposit
+	if err = c.ShouldBindJSON(&deposit); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	goal, err := sh.savingsSrv.Deposit(sh.ctx, userID, goalID, deposit.Amount)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// CloseGoalHandler gives up the savings goal and releases its coins back to the user's balance.
+func (sh *SavingsHandlers) CloseGoalHandler(c *gin.Context) {
+	goalID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid goal id"))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	released, err := sh.savingsSrv.CloseGoal(sh.ctx, userID, goalID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"released": released})
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// SavingsService service
+type SavingsService interface {
+	CreateGoal(ctx context.Context, userID int, slug string) (*models.SavingsGoal, error)
+	Deposit(ctx context.Context, userID, goalID, amount int) (*models.SavingsGoal, error)
+	CloseGoal(ctx context.Context, userID, goalID int) (int, error)
+}
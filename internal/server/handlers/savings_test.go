@@ -0,0 +1,81 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestSavingsHandlers_DepositHandler проверяет пополнение копилки и отказы при нехватке монет и чужой копилке.
+func TestSavingsHandlers_DepositHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		amount    int
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Coins deposited",
+			amount:   100,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Not enough coins",
+			amount:    5000,
+			mockError: models.ErrNotEnoughCoins,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "Goal not found",
+			amount:    10,
+			mockError: models.ErrGoalNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mSavingsSvc := mocks.NewSavingsService(t)
+			mSavingsSvc.
+				On("Deposit", mock.Anything, 1, 7, tt.amount).
+				Return(&models.SavingsGoal{ID: 7, Item: "pink-hoody", Saved: tt.amount, Target: 500}, tt.mockError)
+
+			dTokenMng := &dummyTokenManager{}
+			sh := NewSavingsHandlers(context.Background(), mSavingsSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/goals/:id/deposit", sh.DepositHandler)
+			}
+
+			body := fmt.Sprintf(`{"amount": %d}`, tt.amount)
+			req, err := http.NewRequest(http.MethodPost, "/goals/7/deposit", strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// ScheduledTransferHandlers provides HTTP handlers for coin transfers made later or regularly.
+type ScheduledTransferHandlers struct {
+	ctx          context.Context          // Context for managing request-scoped values and cancellation.
+	scheduledSrv ScheduledTransferService // Service for managing scheduled transfers.
+}
+
+// NewScheduledTransferHandlers creates a new instance of ScheduledTransferHandlers with the provided dependencies.
+func NewScheduledTransferHandlers(ctx context.Context, scheduledSrv ScheduledTransferService) *ScheduledTransferHandlers {
+	return &ScheduledTransferHandlers{
+		ctx:          ctx,
+		scheduledSrv: scheduledSrv,
+	}
+}
+
+// ListScheduledTransfersHandler returns the user's scheduled transfers with their statuses.
+func (sh *ScheduledTransferHandlers) ListScheduledTransfersHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	transfers, err := sh.scheduledSrv.GetTransfers(sh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+// CreateScheduledTransferHandler schedules a one-off transfer at `runAt` or a recurring one on the cron-like `schedule`.
+func (sh *ScheduledTransferHandlers) CreateScheduledTransferHandler(c *gin.Context) {
+	var creation models.ScheduledTransferCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	transfer, err := sh.scheduledSrv.CreateTransfer(sh.ctx, userID, &creation)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// CancelScheduledTransferHandler stops the user's scheduled transfer.
+func (sh *ScheduledTransferHandlers) CancelScheduledTransferHandler(c *gin.Context) {
+	transferID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid transfer id"))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = sh.scheduledSrv.CancelTransfer(sh.ctx, userID, transferID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// ScheduledTransferService service
+type ScheduledTransferService interface {
+	CreateTransfer(ctx context.Context, senderID int, c *models.ScheduledTransferCreation) (*models.ScheduledTransfer, error)
+	GetTransfers(ctx context.Context, userID int) (*[]models.ScheduledTransfer, error)
+	CancelTransfer(ctx context.Context, userID, transferID int) error
+}
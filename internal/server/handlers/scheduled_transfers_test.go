@@ -0,0 +1,83 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestScheduledTransferHandlers_CreateScheduledTransferHandler проверяет создание перевода по расписанию и отказ при неверном расписании.
+func TestScheduledTransferHandlers_CreateScheduledTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		body      string
+		mockError error
+		wantCall  bool
+		wantCode  int
+	}{
+		{
+			name:     "Monthly transfer",
+			body:     `{"toUser": "otherUser", "amount": 20, "schedule": "0 9 1 * *"}`,
+			wantCall: true,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:      "Invalid schedule",
+			body:      `{"toUser": "otherUser", "amount": 20, "schedule": "monthly"}`,
+			mockError: models.ErrInvalidTransferSchedule,
+			wantCall:  true,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "No amount",
+			body:     `{"toUser": "otherUser", "schedule": "0 9 1 * *"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mScheduledSvc := mocks.NewScheduledTransferService(t)
+			if tt.wantCall {
+				mScheduledSvc.
+					On("CreateTransfer", mock.Anything, 1, mock.AnythingOfType("*models.ScheduledTransferCreation")).
+					Return(&models.ScheduledTransfer{ID: 1}, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			sh := NewScheduledTransferHandlers(context.Background(), mScheduledSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/transfers/scheduled", sh.CreateScheduledTransferHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/transfers/scheduled", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
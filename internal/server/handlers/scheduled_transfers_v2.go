@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListScheduledTransfersV2Handler returns a page of the user's scheduled transfers.
+func (sh *ScheduledTransferHandlers) ListScheduledTransfersV2Handler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	transfers, err := sh.scheduledSrv.GetTransfers(sh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(transfers, page))
+}
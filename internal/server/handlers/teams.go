@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TeamHandlers provides HTTP handlers for teams and their shared wallets.
+type TeamHandlers struct {
+	ctx     context.Context // Context for managing request-scoped values and cancellation.
+	teamSrv TeamService     // Service for managing teams.
+}
+
+// NewTeamHandlers creates a new instance of TeamHandlers with the provided dependencies.
+func NewTeamHandlers(ctx context.Context, teamSrv TeamService) *TeamHandlers {
+	return &TeamHandlers{
+		ctx:     ctx,
+		teamSrv: teamSrv,
+	}
+}
+
+// CreateTeamHandler creates the team with the user as its owner.
+func (th *TeamHandlers) CreateTeamHandler(c *gin.Context) {
+	var creation models.TeamCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	team, err := th.teamSrv.CreateTeam(th.ctx, userID, &creation)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, team)
+}
+
+// ListTeamsHandler returns the teams the user is a member of.
+func (th *TeamHandlers) ListTeamsHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	teams, err := th.teamSrv.GetTeams(th.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// GetTeamHandler returns the user's team with its members and their spends within 30 days.
+func (th *TeamHandlers) GetTeamHandler(c *gin.Context) {
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	team, err := th.teamSrv.GetTeam(th.ctx, userID, teamID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// SetTeamMemberHandler adds the user to the team or changes the member's role and spending limit.
+func (th *TeamHandlers) SetTeamMemberHandler(c *gin.Context) {
+	var membership models.TeamMembership
+	if err := c.ShouldBindJSON(&membership); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := th.teamSrv.SetMember(th.ctx, userID, teamID, c.Param("user"), &membership); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// RemoveTeamMemberHandler removes the user from the team, a member may remove only themselves.
+func (th *TeamHandlers) RemoveTeamMemberHandler(c *gin.Context) {
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := th.teamSrv.RemoveMember(th.ctx, userID, teamID, c.Param("user")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DepositToTeamHandler transfers the user's coins to the team wallet.
+func (th *TeamHandlers) DepositToTeamHandler(c *gin.Context) {
+	var deposit models.TeamDeposit
+	if err := c.ShouldBindJSON(&deposit); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := th.teamSrv.Deposit(th.ctx, userID, teamID, deposit.Amount); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// SpendFromTeamHandler transfers coins from the team wallet to the colleague `toUser`.
+// Responds with 202 if the spend waits for an owner's approval.
+func (th *TeamHandlers) SpendFromTeamHandler(c *gin.Context) {
+	var spending models.TeamSpending
+	if err := c.ShouldBindJSON(&spending); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	spend, err := th.teamSrv.Spend(th.ctx, userID, teamID, &spending)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if spend.Status == models.SpendPending {
+		c.JSON(http.StatusAccepted, spend)
+		return
+	}
+	c.JSON(http.StatusOK, spend)
+}
+
+// ListTeamApprovalsHandler returns the team's spends waiting for the owner's approval.
+func (th *TeamHandlers) ListTeamApprovalsHandler(c *gin.Context) {
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	spends, err := th.teamSrv.GetApprovals(th.ctx, userID, teamID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, spends)
+}
+
+// ResolveTeamApprovalHandler approves or rejects the team's pending spend.
+func (th *TeamHandlers) ResolveTeamApprovalHandler(c *gin.Context) {
+	spendID, err := strconv.Atoi(c.Param("spendId"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid spend id"))
+		return
+	}
+
+	var decision models.TeamSpendDecision
+	if err = c.ShouldBindJSON(&decision); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+
+	spend, err := th.teamSrv.ResolveApproval(th.ctx, userID, teamID, spendID, decision.Status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, spend)
+}
+
+// teamFromRequest reads the user and the team from the path, adding the error to the context if they are missing.
+func teamFromRequest(c *gin.Context) (int, int, bool) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid team id"))
+		return 0, 0, false
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return 0, 0, false
+	}
+	return userID, teamID, true
+}
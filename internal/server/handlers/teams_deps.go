@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TeamService service
+type TeamService interface {
+	CreateTeam(ctx context.Context, userID int, c *models.TeamCreation) (*models.Team, error)
+	GetTeams(ctx context.Context, userID int) (*[]models.Team, error)
+	GetTeam(ctx context.Context, userID, teamID int) (*models.Team, error)
+	SetMember(ctx context.Context, ownerID, teamID int, username string, m *models.TeamMembership) error
+	RemoveMember(ctx context.Context, actorID, teamID int, username string) error
+	Deposit(ctx context.Context, userID, teamID, coins int) error
+	Spend(ctx context.Context, userID, teamID int, sp *models.TeamSpending) (*models.TeamSpend, error)
+	GetApprovals(ctx context.Context, ownerID, teamID int) (*[]models.TeamSpend, error)
+	ResolveApproval(ctx context.Context, ownerID, teamID, spendID int, status string) (*models.TeamSpend, error)
+}
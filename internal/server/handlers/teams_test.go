@@ -0,0 +1,168 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestTeamHandlers_SpendFromTeamHandler проверяет трату из кошелька команды участником.
+func TestTeamHandlers_SpendFromTeamHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		body       string
+		mockStatus string
+		mockError  error
+		callSvc    bool
+		wantCode   int
+	}{
+		{
+			name:       "Spend made",
+			body:       `{"toUser": "engineer22", "amount": 50}`,
+			mockStatus: models.SpendSent,
+			callSvc:    true,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "Spend waits for approval",
+			body:       `{"toUser": "engineer22", "amount": 500}`,
+			mockStatus: models.SpendPending,
+			callSvc:    true,
+			wantCode:   http.StatusAccepted,
+		},
+		{
+			name:      "Over the spending limit",
+			body:      `{"toUser": "engineer22", "amount": 50}`,
+			mockError: models.ErrTeamSpendLimit,
+			callSvc:   true,
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Team wallet is short of coins",
+			body:      `{"toUser": "engineer22", "amount": 50}`,
+			mockError: models.ErrNotEnoughTeamCoins,
+			callSvc:   true,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "Not a member",
+			body:      `{"toUser": "engineer22", "amount": 50}`,
+			mockError: models.ErrTeamNotFound,
+			callSvc:   true,
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:     "Zero amount",
+			body:     `{"toUser": "engineer22", "amount": 0}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mTeamSvc := mocks.NewTeamService(t)
+			if tt.callSvc {
+				var spend *models.TeamSpend
+				if tt.mockError == nil {
+					spend = &models.TeamSpend{ID: 3, Recipient: "engineer22", Status: tt.mockStatus}
+				}
+				mTeamSvc.
+					On("Spend", mock.Anything, 1, 7, mock.AnythingOfType("*models.TeamSpending")).
+					Return(spend, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			th := NewTeamHandlers(context.Background(), mTeamSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/teams/:id/spend", th.SpendFromTeamHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/teams/7/spend", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+// TestTeamHandlers_RemoveTeamMemberHandler проверяет удаление участника из команды.
+func TestTeamHandlers_RemoveTeamMemberHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Member removed",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Not an owner",
+			mockError: models.ErrNotTeamOwner,
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Last owner",
+			mockError: models.ErrLastTeamOwner,
+			wantCode:  http.StatusConflict,
+		},
+		{
+			name:      "Not a member",
+			mockError: models.ErrTeamMemberNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mTeamSvc := mocks.NewTeamService(t)
+			mTeamSvc.On("RemoveMember", mock.Anything, 1, 7, "engineer-e2").Return(tt.mockError)
+
+			dTokenMng := &dummyTokenManager{}
+			th := NewTeamHandlers(context.Background(), mTeamSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.DELETE("/teams/:id/members/:user", th.RemoveTeamMemberHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodDelete, "/teams/7/members/engineer-e2", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
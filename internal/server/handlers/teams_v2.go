@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTeamsV2Handler returns a page of the teams the user is a member of.
+func (th *TeamHandlers) ListTeamsV2Handler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	teams, err := th.teamSrv.GetTeams(th.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(teams, page))
+}
+
+// ListTeamApprovalsV2Handler returns a page of the team's spends waiting for the owner's approval.
+func (th *TeamHandlers) ListTeamApprovalsV2Handler(c *gin.Context) {
+	userID, teamID, ok := teamFromRequest(c)
+	if !ok {
+		return
+	}
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	spends, err := th.teamSrv.GetApprovals(th.ctx, userID, teamID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(spends, page))
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TransferReviewHandlers provides admin HTTP handlers for the transfers flagged by the anti-fraud rules.
+type TransferReviewHandlers struct {
+	ctx       context.Context       // Context for managing request-scoped values and cancellation.
+	reviewSrv TransferReviewService // Service for reviewing flagged transfers.
+}
+
+// NewTransferReviewHandlers creates a new instance of TransferReviewHandlers with the provided dependencies.
+func NewTransferReviewHandlers(ctx context.Context, reviewSrv TransferReviewService) *TransferReviewHandlers {
+	return &TransferReviewHandlers{
+		ctx:       ctx,
+		reviewSrv: reviewSrv,
+	}
+}
+
+// ListTransferReviewsHandler returns the flagged transfers, the open ones unless the `status` query parameter is given.
+func (th *TransferReviewHandlers) ListTransferReviewsHandler(c *gin.Context) {
+	reviews, err := th.reviewSrv.GetReviews(th.ctx, c.DefaultQuery("status", models.ReviewOpen))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// ResolveTransferReviewHandler approves or rejects the flagged transfer, an approved transfer is made.
+func (th *TransferReviewHandlers) ResolveTransferReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid review id"))
+		return
+	}
+
+	var decision models.TransferReviewDecision
+	if err = c.ShouldBindJSON(&decision); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	reviewerID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	review, err := th.reviewSrv.ResolveReview(auditContext(th.ctx, c), reviewerID, reviewID, decision.Status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
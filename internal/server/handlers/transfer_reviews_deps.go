@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TransferReviewService service
+type TransferReviewService interface {
+	GetReviews(ctx context.Context, status string) (*[]models.TransferReview, error)
+	GetReviewsPage(ctx context.Context, status string, limit, offset int) (*models.Page[models.TransferReview], error)
+	ResolveReview(ctx context.Context, reviewerID, reviewID int, status string) (*models.TransferReview, error)
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestTransferReviewHandlers_ResolveTransferReviewHandler проверяет одобрение и отклонение перевода, задержанного антифрод-правилами.
+func TestTransferReviewHandlers_ResolveTransferReviewHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		id        string
+		body      string
+		status    string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Approved",
+			id:       "1",
+			body:     `{"status": "approved"}`,
+			status:   models.ReviewApproved,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Already resolved",
+			id:        "1",
+			body:      `{"status": "rejected"}`,
+			status:    models.ReviewRejected,
+			mockError: models.ErrTransferReviewNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:      "Sender spent the coins",
+			id:        "1",
+			body:      `{"status": "approved"}`,
+			status:    models.ReviewApproved,
+			mockError: models.ErrNotEnoughCoins,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown decision",
+			id:       "1",
+			body:     `{"status": "open"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid review id",
+			id:       "abc",
+			body:     `{"status": "approved"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mReviewSvc := mocks.NewTransferReviewService(t)
+			if tt.status != "" {
+				mReviewSvc.
+					On("ResolveReview", mock.Anything, 1, 1, tt.status).
+					Return(&models.TransferReview{ID: 1, Status: tt.status}, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			th := NewTransferReviewHandlers(context.Background(), mReviewSvc)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/admin/transfers/reviews/:id", th.ResolveTransferReviewHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/transfers/reviews/"+tt.id, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
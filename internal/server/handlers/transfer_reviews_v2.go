@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// ListTransferReviewsV2Handler returns a page of the reviews in the `status` query parameter, the open ones by default.
+func (th *TransferReviewHandlers) ListTransferReviewsV2Handler(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	reviews, err := th.reviewSrv.GetReviewsPage(th.ctx, c.DefaultQuery("status", models.ReviewOpen), page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
@@ -3,6 +3,7 @@
 // Each handler interacts with the appropriate service layer to perform its tasks and returns HTTP responses
 // in JSON format. The package uses the gin framework for routing and request handling,
 // ensuring a clean and modular structure for handling user requests.
+// The errors are added to the context with c.Error and rendered by the error middleware.
 package handlers
 
 import (
@@ -13,11 +14,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
 	"github.com/kk7453603/avito_2024_summer/internal/models"
 )
 
-// ErrInDB is a common error message for database-related issues.
-var ErrInDB = errors.New("something happened to the database")
+// errTokenGeneration is returned when the token can't be issued.
+var errTokenGeneration = apperr.New(apperr.KindInternal, "token_generation_failure", "token generation failure")
 
 // UserHandlers provides HTTP handlers for user-related operations.
 type UserHandlers struct {
@@ -55,31 +57,64 @@ func (uh *UserHandlers) AuthHandler(c *gin.Context) {
 
 	var login models.Login
 	if err := c.ShouldBindJSON(&login); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(apperr.Invalid(err.Error()))
 		return
 	}
 
-	user, ok, err := uh.authSrv.GetOrRegUser(uh.ctx, login.Username, login.Password)
+	ctx := auditContext(uh.ctx, c)
+	user, ok, err := uh.authSrv.GetOrRegUser(ctx, login.Username, login.Password, login.ClaimToken)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+		_ = c.Error(err)
 		return
 	} else if ok {
 		if !uh.authSrv.ComparePassword(user.Password, login.Password) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid password"})
+			if err = uh.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				_ = c.Error(err)
+				return
+			}
+			_ = c.Error(models.ErrInvalidPassword)
+			return
+		}
+		// a locked account can't log in, the tokens issued before it was locked are rejected by the JWT middleware
+		if user.LockedAt != nil {
+			if err = uh.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				_ = c.Error(err)
+				return
+			}
+			_ = c.Error(models.ErrAccountLocked)
+			return
+		}
+		// the user has left, the account is kept for the history but can't be used anymore
+		if user.DeactivatedAt != nil {
+			if err = uh.authSrv.RecordAuthEvent(ctx, models.AuditLoginFailed, user); err != nil {
+				_ = c.Error(err)
+				return
+			}
+			_ = c.Error(models.ErrAccountDeactivated)
+			return
+		}
+		if err = uh.authSrv.RecordAuthEvent(ctx, models.AuditLogin, user); err != nil {
+			_ = c.Error(err)
 			return
 		}
 	}
 
 	tokenString, err := uh.tknMng.NewToken(strconv.Itoa(user.ID), user.Username)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "token generation failure"})
+		_ = c.Error(errTokenGeneration)
+		return
+	}
+	// the token isn't handed out unless its issuance is audited
+	if err = uh.authSrv.RecordAuthEvent(ctx, models.AuditTokenIssued, user); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"token": tokenString})
 }
 
-// InfoHandler retrieves and returns user information, including coins, inventory, and coin history.
+// InfoHandler retrieves and returns user information, including coins, inventory, purchases with their
+// fulfilment statuses, coin and gift history.
 func (uh *UserHandlers) InfoHandler(c *gin.Context) {
 	// switch c.GetHeader("Accept") {
 	// case "application/json":
@@ -91,38 +126,62 @@ func (uh *UserHandlers) InfoHandler(c *gin.Context) {
 	userIDStr, _ := c.Get("user_id")
 	userID, err := strconv.Atoi(userIDStr.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "context parsing failure"})
+		_ = c.Error(errContextParsing)
 		return
 	}
 
 	coins, err := uh.usrInfSrv.GetCoins(uh.ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	inventory, err := uh.usrInfSrv.GetInventory(uh.ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	coinHistory, err := uh.usrInfSrv.GetCoinHistory(uh.ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+		_ = c.Error(err)
+		return
+	}
+
+	giftHistory, err := uh.usrInfSrv.GetGiftHistory(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	purchases, err := uh.usrInfSrv.GetPurchases(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	goals, err := uh.usrInfSrv.GetGoals(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	type Response struct {
-		Coins       int                 `json:"coins"`
-		Inventory   *[]models.Merch     `json:"inventory"`
-		CoinHistory *models.CoinHistory `json:"coinHistory"`
+		Coins       int                   `json:"coins"`
+		Goals       *[]models.SavingsGoal `json:"goals"`
+		Inventory   *[]models.Merch       `json:"inventory"`
+		Purchases   *[]models.Purchase    `json:"purchases"`
+		CoinHistory *models.CoinHistory   `json:"coinHistory"`
+		GiftHistory *models.GiftHistory   `json:"giftHistory"`
 	}
 
 	c.JSON(http.StatusOK, Response{
 		Coins:       coins,
+		Goals:       goals,
 		Inventory:   inventory,
+		Purchases:   purchases,
 		CoinHistory: coinHistory,
+		GiftHistory: giftHistory,
 	})
 }
 
@@ -130,7 +189,7 @@ func (uh *UserHandlers) InfoHandler(c *gin.Context) {
 func (uh *UserHandlers) SendCoinsHandler(c *gin.Context) {
 	var send models.Sending
 	if err := c.ShouldBindJSON(&send); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(apperr.Invalid(err.Error()))
 		return
 	}
 	// if send.User == "" {
@@ -141,69 +200,117 @@ func (uh *UserHandlers) SendCoinsHandler(c *gin.Context) {
 	// 	return
 	// }
 
-	recipientID, err := uh.txSrv.GetIDRecipient(uh.ctx, send.User)
+	senderIDStr, _ := c.Get("user_id")
+	senderID, err := strconv.Atoi(senderIDStr.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
-		return
-	} else if recipientID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "`toUser` is not found"})
+		_ = c.Error(errContextParsing)
 		return
 	}
 
-	senderIDStr, _ := c.Get("user_id")
-	senderID, err := strconv.Atoi(senderIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "context parsing failure"})
+	if err = uh.send(c, senderID, &send); err != nil {
+		_ = c.Error(err)
 		return
 	}
-	if senderCoins, err := uh.txSrv.GetSenderCoins(uh.ctx, senderID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+
+	c.Status(http.StatusOK)
+}
+
+// SendCoinsBatchHandler handles the transfer of coins to several users at once, either all of them are sent or none.
+// The results list each recipient with the amount, the recipients stopping the batch have an error.
+func (uh *UserHandlers) SendCoinsBatchHandler(c *gin.Context) {
+	var batch models.BatchSending
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
 		return
-	} else if senderCoins < send.Amount {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "you don't have enough coins"})
+	}
+
+	senderID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	if err = uh.txSrv.SendCoinsToUser(uh.ctx, senderID, recipientID, send.Amount); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+	result, err := uh.sendBatch(c, senderID, &batch)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, result)
+}
+
+// send transfers the coins to the recipient after checking the sender's balance.
+func (uh *UserHandlers) send(c *gin.Context, senderID int, send *models.Sending) error {
+	recipientID, err := uh.txSrv.GetIDRecipient(uh.ctx, send.User)
+	if err != nil {
+		return err
+	} else if recipientID == 0 {
+		return models.ErrRecipientNotFound
+	}
+
+	if senderCoins, err := uh.txSrv.GetSenderCoins(uh.ctx, senderID); err != nil {
+		return err
+	} else if senderCoins < send.Amount {
+		return models.ErrNotEnoughCoins
+	}
+
+	return uh.txSrv.SendCoinsToUser(auditContext(uh.ctx, c), senderID, recipientID, send.Amount)
+}
+
+// sendBatch makes the batch transfer, the error of a failed batch carries the results
+// showing the recipients stopping it.
+func (uh *UserHandlers) sendBatch(c *gin.Context, senderID int, batch *models.BatchSending) (*models.BatchResult, error) {
+	result, err := uh.txSrv.SendCoinsBatch(auditContext(uh.ctx, c), senderID, batch)
+	if err != nil {
+		var appErr *apperr.Error
+		if result != nil && errors.As(err, &appErr) {
+			err = appErr.With("results", result.Results)
+		}
+		return nil, err
+	}
+	return result, nil
 }
 
 // BuyItemHandler handles the purchase of an item by a user.
+// An optional variant SKU, promo code and pickup office are passed in the `variant`, `promo` and `office`
+// query parameters, the default variant of the item is bought if no variant is given.
 func (uh *UserHandlers) BuyItemHandler(c *gin.Context) {
 	itemSlug := c.Param("item")
+	order := &models.Order{
+		Variant:   c.Query("variant"),
+		PromoCode: c.Query("promo"),
+		Office:    c.Query("office"),
+	}
 	userIDStr, _ := c.Get("user_id")
 	userID, err := strconv.Atoi(userIDStr.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "context parsing failure"})
+		_ = c.Error(errContextParsing)
 		return
 	}
 
-	item, err := uh.buyItmSrv.GetItem(uh.ctx, itemSlug)
+	quote, err := uh.buyItmSrv.BuyItem(auditContext(uh.ctx, c), userID, itemSlug, order)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
-		return
-	} else if item == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "item not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	buyerCoins, err := uh.buyItmSrv.GetBuyerCoins(uh.ctx, userID)
+	c.JSON(http.StatusOK, quote)
+}
+
+// CatalogHandler returns the items of the store with their prices and remaining stock,
+// marking the items in the user's wishlist.
+func (uh *UserHandlers) CatalogHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
-		return
-	} else if buyerCoins < item.Price {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "you don't have enough coins"})
+		_ = c.Error(err)
 		return
 	}
 
-	if err = uh.buyItmSrv.BuyItem(uh.ctx, userID, item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInDB.Error()})
+	items, err := uh.buyItmSrv.GetCatalog(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, gin.H{"items": items})
 }
@@ -10,8 +10,10 @@ import (
 
 // AuthService service
 type AuthService interface {
-	GetOrRegUser(ctx context.Context, username, password string) (*models.User, bool, error)
+	GetOrRegUser(ctx context.Context, username, password, claimToken string) (*models.User, bool, error)
 	ComparePassword(hashedPasswd, passwd string) bool
+	RecordAuthEvent(ctx context.Context, action string, user *models.User) error
+	CheckAccount(ctx context.Context, userID int) error
 }
 
 // TokenManager service
@@ -24,6 +26,14 @@ type UserInfoService interface {
 	GetCoins(ctx context.Context, userID int) (int, error)
 	GetInventory(ctx context.Context, userID int) (*[]models.Merch, error)
 	GetCoinHistory(ctx context.Context, userID int) (*models.CoinHistory, error)
+	GetGiftHistory(ctx context.Context, userID int) (*models.GiftHistory, error)
+	GetPurchases(ctx context.Context, userID int) (*[]models.Purchase, error)
+	GetReceivedCoinsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Receiving], error)
+	GetSentCoinsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Sending], error)
+	GetReceivedGiftsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftReceiving], error)
+	GetSentGiftsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.GiftSending], error)
+	GetPurchasesPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Purchase], error)
+	GetGoals(ctx context.Context, userID int) (*[]models.SavingsGoal, error)
 }
 
 // TransactionService service
@@ -31,11 +41,11 @@ type TransactionService interface {
 	GetIDRecipient(ctx context.Context, username string) (int, error)
 	GetSenderCoins(ctx context.Context, userID int) (int, error)
 	SendCoinsToUser(ctx context.Context, senderID, recipientID int, coins int) error
+	SendCoinsBatch(ctx context.Context, senderID int, batch *models.BatchSending) (*models.BatchResult, error)
 }
 
 // BuyItemService service
 type BuyItemService interface {
-	GetItem(ctx context.Context, slug string) (*models.Item, error)
-	GetBuyerCoins(ctx context.Context, userID int) (int, error)
-	BuyItem(ctx context.Context, userID int, item *models.Item) error
+	BuyItem(ctx context.Context, userID int, slug string, order *models.Order) (*models.Quote, error)
+	GetCatalog(ctx context.Context, userID int) (*[]models.Item, error)
 }
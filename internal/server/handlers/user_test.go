@@ -42,10 +42,24 @@ func (d *dummyTokenManager) ParseClaims(token string) (*jwt.MapClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// dummyAccountChecker – реализация проверки аккаунтов, для которой все аккаунты активны.
+type dummyAccountChecker struct{}
+
+func (d *dummyAccountChecker) CheckAccount(ctx context.Context, userID int) error {
+	return nil
+}
+
+// newTestRouter создаёт роутер с middleware, выводящим ошибки обработчиков.
+func newTestRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(middlewares.NewMiddlewares(&dummyTokenManager{}, nil, &dummyAccountChecker{}).ErrorMiddleware())
+	return router
+}
+
 // TestUserHandlers_SendCoinsHandler проверяет сценарий успешной передачи монеток другому сотруднику.
 func TestUserHandlers_SendCoinsHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newTestRouter()
 
 	recipientUser := models.User{
 		ID:       2,
@@ -81,7 +95,7 @@ func TestUserHandlers_SendCoinsHandler(t *testing.T) {
 	// Создаём обработчики, передавая TransactionService в соответствующий параметр.
 	uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, mTxSvc, nil)
 
-	meddlers := middlewares.NewMiddlewares(dTokenMng)
+	meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
 	authorized := router.Group("/", meddlers.JWTMiddleware())
 	{
 		authorized.POST("/sendCoin", uh.SendCoinsHandler)
@@ -103,10 +117,140 @@ func TestUserHandlers_SendCoinsHandler(t *testing.T) {
 	require.Equal(t, http.StatusOK, w.Code)
 }
 
+// TestUserHandlers_SendCoinsHandlerRules проверяет коды ответа для переводов, заблокированных антифрод-правилами.
+func TestUserHandlers_SendCoinsHandlerRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:      "Daily cap exceeded",
+			mockError: fmt.Errorf("%w: at most 500 coins a day", models.ErrTransferLimitExceeded),
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Fresh accounts",
+			mockError: models.ErrFreshAccountTransfer,
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Too many transfers",
+			mockError: models.ErrTransferTooFrequent,
+			wantCode:  http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mTxSvc := mocks.NewTransactionService(t)
+			mTxSvc.On("GetIDRecipient", mock.Anything, "otherUser").Return(2, nil)
+			mTxSvc.On("GetSenderCoins", mock.Anything, 1).Return(1000, nil)
+			mTxSvc.On("SendCoinsToUser", mock.Anything, 1, 2, 50).Return(tt.mockError)
+
+			dTokenMng := &dummyTokenManager{}
+			uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, mTxSvc, nil)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/sendCoin", uh.SendCoinsHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(`{"toUser": "otherUser", "amount": 50}`))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+// TestUserHandlers_SendCoinsBatchHandler проверяет перевод монет нескольким сотрудникам одной транзакцией.
+func TestUserHandlers_SendCoinsBatchHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		body      string
+		callSvc   bool
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Total split between recipients",
+			body:     `{"total": 100, "recipients": [{"toUser": "otherUser1"}, {"toUser": "otherUser2"}]}`,
+			callSvc:  true,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Unknown recipient",
+			body:      `{"recipients": [{"toUser": "otherUser1", "amount": 10}, {"toUser": "abracadabra", "amount": 10}]}`,
+			callSvc:   true,
+			mockError: models.ErrRecipientNotFound,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "Not enough coins",
+			body:      `{"total": 5000, "recipients": [{"toUser": "otherUser1"}]}`,
+			callSvc:   true,
+			mockError: models.ErrNotEnoughCoins,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:     "No recipients",
+			body:     `{"total": 100, "recipients": []}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mTxSvc := mocks.NewTransactionService(t)
+			if tt.callSvc {
+				result := &models.BatchResult{BatchID: 1, Results: []models.BatchLegResult{{User: "otherUser1", Amount: 50}}}
+				if tt.mockError != nil {
+					result = &models.BatchResult{Results: []models.BatchLegResult{{User: "abracadabra", Amount: 10, Error: tt.mockError.Error()}}}
+				}
+				mTxSvc.On("SendCoinsBatch", mock.Anything, 1, mock.AnythingOfType("*models.BatchSending")).Return(result, tt.mockError)
+			}
+
+			dTokenMng := &dummyTokenManager{}
+			uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, mTxSvc, nil)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/sendCoin/batch", uh.SendCoinsBatchHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/sendCoin/batch", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
 // TestUserHandlers_BuyItemHandler проверяет сценарий успешной покупки мерча.
 func TestUserHandlers_BuyItemHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newTestRouter()
 
 	user := models.User{
 		ID:       1,
@@ -120,17 +264,17 @@ func TestUserHandlers_BuyItemHandler(t *testing.T) {
 	}
 
 	mBuyItemSvc := mocks.NewBuyItemService(t)
+	// При покупке варианта по умолчанию возвращаем цену товара (успех).
 	mBuyItemSvc.
-		On("GetItem", mock.Anything, item.Slug).
-		Return(item, nil)
-	// При запросе количества монет для покупателя (user_id = 1) возвращаем 150.
+		On("BuyItem", mock.Anything, user.ID, item.Slug, &models.Order{Office: "moscow"}).
+		Return(&models.Quote{ListPrice: item.Price, Price: item.Price}, nil)
+	// Вариант дороже баланса и несуществующий вариант отклоняются сервисом.
 	mBuyItemSvc.
-		On("GetBuyerCoins", mock.Anything, user.ID).
-		Return(user.Coins, nil)
-	// При вызове BuyItem возвращаем nil (успех).
+		On("BuyItem", mock.Anything, user.ID, item.Slug, &models.Order{Variant: "merch123-xl"}).
+		Return(nil, models.ErrNotEnoughCoins)
 	mBuyItemSvc.
-		On("BuyItem", mock.Anything, user.ID, item).
-		Return(nil)
+		On("BuyItem", mock.Anything, user.ID, item.Slug, &models.Order{Variant: "merch123-xxs"}).
+		Return(nil, models.ErrVariantNotFound)
 
 	dTokenMng := &dummyTokenManager{}
 
@@ -139,14 +283,14 @@ func TestUserHandlers_BuyItemHandler(t *testing.T) {
 	uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, nil, mBuyItemSvc)
 
 	// Настраиваем группу маршрутов с JWT-мидлваром.
-	meddlers := middlewares.NewMiddlewares(dTokenMng)
+	meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
 	authorized := router.Group("/", meddlers.JWTMiddleware())
 	{
 		authorized.GET("/buy/:item", uh.BuyItemHandler)
 	}
 
 	// Формируем HTTP‑запрос на покупку мерча с корректным токеном.
-	req, err := http.NewRequest(http.MethodGet, "/buy/merch123", nil)
+	req, err := http.NewRequest(http.MethodGet, "/buy/merch123?office=moscow", nil)
 	require.NoError(t, err)
 	req.Header.Set("Authorization", "Bearer "+validToken)
 	req.Header.Set("Accept", "application/json")
@@ -157,4 +301,23 @@ func TestUserHandlers_BuyItemHandler(t *testing.T) {
 
 	// Проверяем, что статус ответа 200 OK.
 	require.Equal(t, http.StatusOK, w.Code)
+
+	// Ошибки сервиса возвращаются как отчёты об ошибках.
+	for url, want := range map[string]struct {
+		status int
+		code   string
+	}{
+		"/buy/merch123?variant=merch123-xl":  {http.StatusBadRequest, "not_enough_coins"},
+		"/buy/merch123?variant=merch123-xxs": {http.StatusNotFound, "variant_not_found"},
+	} {
+		req, err = http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+validToken)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, want.status, w.Code, url)
+		require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"), url)
+		require.Contains(t, w.Body.String(), `"code":"`+want.code+`"`, url)
+	}
 }
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// Directions of the histories in API v2.
+const (
+	directionReceived = "received"
+	directionSent     = "sent"
+)
+
+// errInvalidDirection is returned when the `direction` query parameter of a history is unknown.
+var errInvalidDirection = apperr.Invalid("`direction` must be received or sent")
+
+// MeHandler returns the user's name and balance, the collections of API v1 /info are paged separately.
+func (uh *UserHandlers) MeHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	coins, err := uh.usrInfSrv.GetCoins(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Profile{
+		Username: c.GetString("username"),
+		Coins:    coins,
+	})
+}
+
+// ListMyInventoryHandler returns a page of the user's inventory.
+func (uh *UserHandlers) ListMyInventoryHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	inventory, err := uh.usrInfSrv.GetInventory(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(inventory, page))
+}
+
+// ListMyPurchasesHandler returns a page of the user's purchases with their fulfilment statuses.
+func (uh *UserHandlers) ListMyPurchasesHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	purchases, err := uh.usrInfSrv.GetPurchasesPage(uh.ctx, userID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, purchases)
+}
+
+// ListMyGoalsHandler returns a page of the user's savings goals.
+func (uh *UserHandlers) ListMyGoalsHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	goals, err := uh.usrInfSrv.GetGoals(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(goals, page))
+}
+
+// ListMyTransfersHandler returns a page of the coins received by the user, or sent by the user
+// if the `direction` query parameter is sent.
+func (uh *UserHandlers) ListMyTransfersHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+	direction := c.DefaultQuery("direction", directionReceived)
+	if direction != directionReceived && direction != directionSent {
+		_ = c.Error(errInvalidDirection)
+		return
+	}
+
+	if direction == directionSent {
+		sent, err := uh.usrInfSrv.GetSentCoinsPage(uh.ctx, userID, page.limit, page.offset)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, sent)
+		return
+	}
+
+	received, err := uh.usrInfSrv.GetReceivedCoinsPage(uh.ctx, userID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, received)
+}
+
+// ListMyGiftsHandler returns a page of the gifts received by the user, or sent by the user
+// if the `direction` query parameter is sent.
+func (uh *UserHandlers) ListMyGiftsHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+	direction := c.DefaultQuery("direction", directionReceived)
+	if direction != directionReceived && direction != directionSent {
+		_ = c.Error(errInvalidDirection)
+		return
+	}
+
+	if direction == directionSent {
+		sent, err := uh.usrInfSrv.GetSentGiftsPage(uh.ctx, userID, page.limit, page.offset)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, sent)
+		return
+	}
+
+	received, err := uh.usrInfSrv.GetReceivedGiftsPage(uh.ctx, userID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, received)
+}
+
+// CreateTransferHandler transfers the coins to another user and returns the transfer.
+func (uh *UserHandlers) CreateTransferHandler(c *gin.Context) {
+	var send models.Sending
+	if err := c.ShouldBindJSON(&send); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+	senderID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = uh.send(c, senderID, &send); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, send)
+}
+
+// CreateTransferBatchHandler transfers the coins to several users at once, all or nothing.
+func (uh *UserHandlers) CreateTransferBatchHandler(c *gin.Context) {
+	var batch models.BatchSending
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+	senderID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	result, err := uh.sendBatch(c, senderID, &batch)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// CreatePurchaseHandler buys the item described in the request body and returns the price paid.
+// The default variant of the item is bought if no variant is given.
+func (uh *UserHandlers) CreatePurchaseHandler(c *gin.Context) {
+	var creation models.PurchaseCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	quote, err := uh.buyItmSrv.BuyItem(auditContext(uh.ctx, c), userID, creation.Item, &models.Order{
+		Variant:   creation.Variant,
+		PromoCode: creation.PromoCode,
+		Office:    creation.Office,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// ListItemsHandler returns a page of the store's items with their prices and remaining stock,
+// marking the items in the user's wishlist.
+func (uh *UserHandlers) ListItemsHandler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	items, err := uh.buyItmSrv.GetCatalog(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(items, page))
+}
+
+// GetItemHandler returns the item of the store as it's shown in the catalog.
+func (uh *UserHandlers) GetItemHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	items, err := uh.buyItmSrv.GetCatalog(uh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if items != nil {
+		for _, item := range *items {
+			if item.Slug == c.Param("item") {
+				c.JSON(http.StatusOK, item)
+				return
+			}
+		}
+	}
+
+	_ = c.Error(models.ErrItemNotFound)
+}
+
+// userPageFromRequest extracts the ID of the authorized user and the requested page.
+// If the request is invalid, the error is added to the context and false is returned.
+func userPageFromRequest(c *gin.Context) (int, pageRequest, bool) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return 0, pageRequest{}, false
+	}
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return 0, pageRequest{}, false
+	}
+	return userID, page, true
+}
@@ -0,0 +1,190 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// serveAuthorized выполняет запрос с корректным токеном.
+func serveAuthorized(router *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+validToken)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestUserHandlers_CreatePurchaseHandler проверяет покупку через POST /purchases API v2:
+// успешная покупка отвечает 201, покупка несуществующего товара - 404.
+func TestUserHandlers_CreatePurchaseHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	mBuyItemSvc := mocks.NewBuyItemService(t)
+	mBuyItemSvc.
+		On("BuyItem", mock.Anything, 1, "merch123", &models.Order{PromoCode: "SPRING", Office: "moscow"}).
+		Return(&models.Quote{ListPrice: 100, Discount: 10, Price: 90}, nil)
+	mBuyItemSvc.On("BuyItem", mock.Anything, 1, "unknown", &models.Order{}).Return(nil, models.ErrItemNotFound)
+
+	dTokenMng := &dummyTokenManager{}
+	uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, nil, mBuyItemSvc)
+
+	authorized := router.Group("/", middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{}).JWTMiddleware())
+	{
+		authorized.POST("/purchases", uh.CreatePurchaseHandler)
+	}
+
+	w := serveAuthorized(router, http.MethodPost, "/purchases", `{"item": "merch123", "promoCode": "SPRING", "office": "moscow"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"listPrice": 100, "discount": 10, "price": 90}`, w.Body.String())
+
+	w = serveAuthorized(router, http.MethodPost, "/purchases", `{"item": "unknown"}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"item_not_found"`)
+
+	// Товар обязателен.
+	w = serveAuthorized(router, http.MethodPost, "/purchases", `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestUserHandlers_CreateTransferHandler проверяет, что перевод через POST /transfers API v2 отвечает 201.
+func TestUserHandlers_CreateTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	mTxSvc := mocks.NewTransactionService(t)
+	mTxSvc.On("GetIDRecipient", mock.Anything, "otherUser").Return(2, nil)
+	mTxSvc.On("GetSenderCoins", mock.Anything, 1).Return(250, nil)
+	mTxSvc.On("SendCoinsToUser", mock.Anything, 1, 2, 50).Return(nil)
+
+	dTokenMng := &dummyTokenManager{}
+	uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, mTxSvc, nil)
+
+	authorized := router.Group("/", middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{}).JWTMiddleware())
+	{
+		authorized.POST("/transfers", uh.CreateTransferHandler)
+	}
+
+	w := serveAuthorized(router, http.MethodPost, "/transfers", `{"toUser": "otherUser", "amount": 50}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"toUser": "otherUser", "amount": 50}`, w.Body.String())
+}
+
+// TestUserHandlers_ListMyInventoryHandler проверяет постраничную выдачу инвентаря и проверку `limit` и `offset`.
+func TestUserHandlers_ListMyInventoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	inventory := []models.Merch{
+		{Type: "cup", Variant: "cup", Quantity: 1},
+		{Type: "pen", Variant: "pen", Quantity: 2},
+		{Type: "hoody", Variant: "hoody-m", Quantity: 1},
+	}
+
+	mUsrInfSvc := mocks.NewUserInfoService(t)
+	mUsrInfSvc.On("GetInventory", mock.Anything, 1).Return(&inventory, nil)
+
+	dTokenMng := &dummyTokenManager{}
+	uh := NewUserHandlers(context.Background(), nil, dTokenMng, mUsrInfSvc, nil, nil)
+
+	authorized := router.Group("/", middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{}).JWTMiddleware())
+	{
+		authorized.GET("/me/inventory", uh.ListMyInventoryHandler)
+	}
+
+	w := serveAuthorized(router, http.MethodGet, "/me/inventory?limit=2&offset=1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page models.Page[models.Merch]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Equal(t, models.Page[models.Merch]{Items: inventory[1:3], Total: 3, Limit: 2, Offset: 1}, page)
+
+	// Смещение за концом коллекции даёт пустую страницу, а не null.
+	w = serveAuthorized(router, http.MethodGet, "/me/inventory?offset=10", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"items": [], "total": 3, "limit": 20, "offset": 10}`, w.Body.String())
+
+	for _, url := range []string{"/me/inventory?limit=0", "/me/inventory?limit=101", "/me/inventory?offset=-1"} {
+		w = serveAuthorized(router, http.MethodGet, url, "")
+		require.Equal(t, http.StatusBadRequest, w.Code, url)
+		require.Contains(t, w.Body.String(), `"code":"invalid_request"`, url)
+	}
+}
+
+// TestUserHandlers_ListMyTransfersHandler проверяет выбор полученных и отправленных переводов параметром `direction`.
+func TestUserHandlers_ListMyTransfersHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	// the pages are cut by the storage, the total counts the whole collection
+	received := &models.Page[models.Receiving]{
+		Items: []models.Receiving{{User: "otherUser", Amount: 10, Kind: "transfer"}}, Total: 1, Limit: 20,
+	}
+	sent := &models.Page[models.Sending]{Items: []models.Sending{{User: "otherUser", Amount: 20}}, Total: 2, Limit: 1}
+
+	mUsrInfSvc := mocks.NewUserInfoService(t)
+	mUsrInfSvc.On("GetReceivedCoinsPage", mock.Anything, 1, 20, 0).Return(received, nil)
+	mUsrInfSvc.On("GetSentCoinsPage", mock.Anything, 1, 1, 0).Return(sent, nil)
+
+	dTokenMng := &dummyTokenManager{}
+	uh := NewUserHandlers(context.Background(), nil, dTokenMng, mUsrInfSvc, nil, nil)
+
+	authorized := router.Group("/", middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{}).JWTMiddleware())
+	{
+		authorized.GET("/me/transfers", uh.ListMyTransfersHandler)
+	}
+
+	w := serveAuthorized(router, http.MethodGet, "/me/transfers", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"items": [{"fromUser": "otherUser", "amount": 10, "type": "transfer"}], "total": 1, "limit": 20, "offset": 0}`, w.Body.String())
+
+	w = serveAuthorized(router, http.MethodGet, "/me/transfers?direction=sent&limit=1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"items": [{"toUser": "otherUser", "amount": 20}], "total": 2, "limit": 1, "offset": 0}`, w.Body.String())
+
+	w = serveAuthorized(router, http.MethodGet, "/me/transfers?direction=both", "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestUserHandlers_GetItemHandler проверяет выдачу товара каталога и 404 для несуществующего товара.
+func TestUserHandlers_GetItemHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter()
+
+	catalog := []models.Item{{Slug: "cup", Title: "Cup", Price: 20}}
+
+	mBuyItemSvc := mocks.NewBuyItemService(t)
+	mBuyItemSvc.On("GetCatalog", mock.Anything, 1).Return(&catalog, nil)
+
+	dTokenMng := &dummyTokenManager{}
+	uh := NewUserHandlers(context.Background(), nil, dTokenMng, nil, nil, mBuyItemSvc)
+
+	authorized := router.Group("/", middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{}).JWTMiddleware())
+	{
+		authorized.GET("/items/:item", uh.GetItemHandler)
+	}
+
+	w := serveAuthorized(router, http.MethodGet, "/items/cup", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"slug":"cup"`)
+
+	w = serveAuthorized(router, http.MethodGet, "/items/pen", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"item_not_found"`)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// WebhookHandlers provides admin HTTP handlers for the webhook subscriptions and deliveries.
+type WebhookHandlers struct {
+	ctx        context.Context // Context for managing request-scoped values and cancellation.
+	webhookSrv WebhookService  // Service for managing the webhooks.
+}
+
+// NewWebhookHandlers creates a new instance of WebhookHandlers with the provided dependencies.
+func NewWebhookHandlers(ctx context.Context, webhookSrv WebhookService) *WebhookHandlers {
+	return &WebhookHandlers{
+		ctx:        ctx,
+		webhookSrv: webhookSrv,
+	}
+}
+
+// ListWebhooksHandler returns the webhook subscriptions without their secrets.
+func (wh *WebhookHandlers) ListWebhooksHandler(c *gin.Context) {
+	subs, err := wh.webhookSrv.GetSubscriptions(wh.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subs})
+}
+
+// CreateWebhookHandler registers an endpoint for the events and returns the subscription with its secret,
+// which isn't shown again.
+func (wh *WebhookHandlers) CreateWebhookHandler(c *gin.Context) {
+	var creation models.WebhookSubscriptionCreation
+	if err := c.ShouldBindJSON(&creation); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	sub, err := wh.webhookSrv.CreateSubscription(wh.ctx, &creation)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// UpdateWebhookHandler changes the subscription's URL and events, or pauses and resumes it.
+func (wh *WebhookHandlers) UpdateWebhookHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid webhook id"))
+		return
+	}
+
+	var update models.WebhookSubscriptionUpdate
+	if err = c.ShouldBindJSON(&update); err != nil {
+		_ = c.Error(apperr.Invalid(err.Error()))
+		return
+	}
+
+	sub, err := wh.webhookSrv.UpdateSubscription(wh.ctx, id, &update)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhookHandler removes the subscription with its deliveries.
+func (wh *WebhookHandlers) DeleteWebhookHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid webhook id"))
+		return
+	}
+
+	err = wh.webhookSrv.DeleteSubscription(wh.ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveriesHandler returns the latest deliveries in the `status` query parameter, the dead ones
+// by default, optionally of the `subscription` only.
+func (wh *WebhookHandlers) ListWebhookDeliveriesHandler(c *gin.Context) {
+	status, subscriptionID, err := deliveryFilterFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	deliveries, err := wh.webhookSrv.GetDeliveries(wh.ctx, status, subscriptionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// deliveryFilterFromQuery reads the `status` and `subscription` query parameters narrowing the deliveries down.
+func deliveryFilterFromQuery(c *gin.Context) (string, int, error) {
+	status := c.Query("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return "", 0, apperr.Invalid("`status` must be one of pending, delivered, dead")
+	}
+	subscriptionID := 0
+	if raw := c.Query("subscription"); raw != "" {
+		var err error
+		if subscriptionID, err = strconv.Atoi(raw); err != nil || subscriptionID < 1 {
+			return "", 0, apperr.Invalid("`subscription` must be a positive integer")
+		}
+	}
+	return status, subscriptionID, nil
+}
+
+// ReplayWebhookDeliveryHandler sends the delivered or dead delivery again.
+func (wh *WebhookHandlers) ReplayWebhookDeliveryHandler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid delivery id"))
+		return
+	}
+
+	err = wh.webhookSrv.ReplayDelivery(wh.ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ReplayDeadWebhooksHandler sends again all the dead deliveries of the subscription,
+// e.g. once its endpoint is fixed. Returns the number of them.
+func (wh *WebhookHandlers) ReplayDeadWebhooksHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperr.Invalid("invalid webhook id"))
+		return
+	}
+
+	replayed, err := wh.webhookSrv.ReplayDead(wh.ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"replayed": replayed})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// WebhookService service
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, c *models.WebhookSubscriptionCreation) (*models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) (*[]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id int, u *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, status string, subscriptionID int) (*[]models.WebhookDelivery, error)
+	GetDeliveriesPage(ctx context.Context, status string, subscriptionID, limit, offset int) (*models.Page[models.WebhookDelivery], error)
+	ReplayDelivery(ctx context.Context, id int64) error
+	ReplayDead(ctx context.Context, subscriptionID int) (int, error)
+}
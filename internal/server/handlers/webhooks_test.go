@@ -0,0 +1,126 @@
+//go:build integration
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+)
+
+// TestWebhookHandlers_CreateWebhookHandler проверяет регистрацию подписки на события.
+func TestWebhookHandlers_CreateWebhookHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		callSvc  bool
+		wantCode int
+	}{
+		{
+			name:     "Subscription to purchases",
+			body:     `{"url":"https://bot.example.com/hook","events":["merch.purchased"]}`,
+			callSvc:  true,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Unknown event",
+			body:     `{"url":"https://bot.example.com/hook","events":["user.deleted"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid URL",
+			body:     `{"url":"bot"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mWebhookSvc := mocks.NewWebhookService(t)
+			if tt.callSvc {
+				mWebhookSvc.
+					On("CreateSubscription", mock.Anything, mock.AnythingOfType("*models.WebhookSubscriptionCreation")).
+					Return(&models.WebhookSubscription{ID: 1, Secret: "generated"}, nil)
+			}
+
+			wh := NewWebhookHandlers(context.Background(), mWebhookSvc)
+			router.POST("/admin/webhooks", wh.CreateWebhookHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+// TestWebhookHandlers_ReplayWebhookDeliveryHandler проверяет повторную отправку доставки.
+func TestWebhookHandlers_ReplayWebhookDeliveryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		id       string
+		svcErr   error
+		callSvc  bool
+		wantCode int
+	}{
+		{
+			name:     "Dead delivery",
+			id:       "42",
+			callSvc:  true,
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:     "Pending delivery",
+			id:       "43",
+			svcErr:   models.ErrWebhookDeliveryNotFound,
+			callSvc:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid id",
+			id:       "first",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mWebhookSvc := mocks.NewWebhookService(t)
+			if tt.callSvc {
+				mWebhookSvc.On("ReplayDelivery", mock.Anything, mock.AnythingOfType("int64")).Return(tt.svcErr)
+			}
+
+			wh := NewWebhookHandlers(context.Background(), mWebhookSvc)
+			router.POST("/admin/webhooks/deliveries/:id/replay", wh.ReplayWebhookDeliveryHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/webhooks/deliveries/"+tt.id+"/replay", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
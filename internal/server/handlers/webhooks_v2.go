@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListWebhooksV2Handler returns a page of the webhook subscriptions.
+func (wh *WebhookHandlers) ListWebhooksV2Handler(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	subs, err := wh.webhookSrv.GetSubscriptions(wh.ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(subs, page))
+}
+
+// ListWebhookDeliveriesV2Handler returns a page of the latest deliveries in the `status` query parameter,
+// the dead ones by default, optionally of the `subscription` only.
+func (wh *WebhookHandlers) ListWebhookDeliveriesV2Handler(c *gin.Context) {
+	status, subscriptionID, err := deliveryFilterFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	deliveries, err := wh.webhookSrv.GetDeliveriesPage(wh.ctx, status, subscriptionID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WishlistHandlers provides HTTP handlers for users' wishlists and the notifications about wished items.
+type WishlistHandlers struct {
+	ctx         context.Context     // Context for managing request-scoped values and cancellation.
+	wishlistSrv WishlistService     // Service for managing users' wishlists.
+	notifySrv   NotificationService // Service for reading users' notifications.
+}
+
+// NewWishlistHandlers creates a new instance of WishlistHandlers with the provided dependencies.
+func NewWishlistHandlers(ctx context.Context, wishlistSrv WishlistService, notifySrv NotificationService) *WishlistHandlers {
+	return &WishlistHandlers{
+		ctx:         ctx,
+		wishlistSrv: wishlistSrv,
+		notifySrv:   notifySrv,
+	}
+}
+
+// ListWishlistHandler returns the items of the user's wishlist with their prices and remaining stock.
+func (wh *WishlistHandlers) ListWishlistHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	items, err := wh.wishlistSrv.GetItems(wh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// AddToWishlistHandler adds the item to the user's wishlist.
+func (wh *WishlistHandlers) AddToWishlistHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = wh.wishlistSrv.AddItem(wh.ctx, userID, c.Param("item"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// RemoveFromWishlistHandler removes the item from the user's wishlist.
+func (wh *WishlistHandlers) RemoveFromWishlistHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = wh.wishlistSrv.RemoveItem(wh.ctx, userID, c.Param("item"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// ListNotificationsHandler returns the latest notifications of the user.
+func (wh *WishlistHandlers) ListNotificationsHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	notifications, err := wh.notifySrv.GetNotifications(wh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+// MarkNotificationsReadHandler marks all the user's notifications read.
+func (wh *WishlistHandlers) MarkNotificationsReadHandler(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = wh.notifySrv.MarkRead(wh.ctx, userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
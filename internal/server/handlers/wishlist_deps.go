@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// WishlistService service
+type WishlistService interface {
+	AddItem(ctx context.Context, userID int, slug string) error
+	RemoveItem(ctx context.Context, userID int, slug string) error
+	GetItems(ctx context.Context, userID int) (*[]models.Item, error)
+}
+
+// NotificationService service
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID int) (*[]models.Notification, error)
+	GetNotificationsPage(ctx context.Context, userID, limit, offset int) (*models.Page[models.Notification], error)
+	MarkRead(ctx context.Context, userID int) error
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/handlers/mocks"
+	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+)
+
+// TestWishlistHandlers_AddToWishlistHandler проверяет добавление товара в список желаний.
+func TestWishlistHandlers_AddToWishlistHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		item      string
+		mockError error
+		wantCode  int
+	}{
+		{
+			name:     "Item added",
+			item:     "pink-hoody",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "Item not found",
+			item:      "unknown",
+			mockError: models.ErrItemNotFound,
+			wantCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			mWishlistSvc := mocks.NewWishlistService(t)
+			mWishlistSvc.
+				On("AddItem", mock.Anything, 1, tt.item).
+				Return(tt.mockError)
+
+			dTokenMng := &dummyTokenManager{}
+			wh := NewWishlistHandlers(context.Background(), mWishlistSvc, nil)
+
+			meddlers := middlewares.NewMiddlewares(dTokenMng, nil, &dummyAccountChecker{})
+			authorized := router.Group("/", meddlers.JWTMiddleware())
+			{
+				authorized.POST("/wishlist/:item", wh.AddToWishlistHandler)
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/wishlist/"+tt.item, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+validToken)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
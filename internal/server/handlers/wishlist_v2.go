@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListWishlistV2Handler returns a page of the user's wishlist.
+func (wh *WishlistHandlers) ListWishlistV2Handler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	items, err := wh.wishlistSrv.GetItems(wh.ctx, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginate(items, page))
+}
+
+// ListNotificationsV2Handler returns a page of the user's notifications.
+func (wh *WishlistHandlers) ListNotificationsV2Handler(c *gin.Context) {
+	userID, page, ok := userPageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	notifications, err := wh.notifySrv.GetNotificationsPage(wh.ctx, userID, page.limit, page.offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
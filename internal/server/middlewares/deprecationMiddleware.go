@@ -0,0 +1,27 @@
+// Package middlewares provides functionality for handling JWT-based authentication in HTTP requests.
+// It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationMiddleware is a middleware function that marks the responses of a deprecated version of the API.
+// The "Deprecation" header (RFC 9745) gives the time the version was deprecated at, the "Sunset" header (RFC 8594)
+// the time it stops being served, and the "Link" header points to the successor version.
+func (m *Middlewares) DeprecationMiddleware(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", link)
+		c.Next() // Proceed to the next handler.
+	}
+}
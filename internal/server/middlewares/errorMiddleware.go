@@ -0,0 +1,66 @@
+// Package middlewares provides functionality for handling JWT-based authentication in HTTP requests.
+// It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+// problemContentType is the media type of the error reports (RFC 7807).
+const problemContentType = "application/problem+json"
+
+// ErrorMiddleware is a middleware function that renders the error added to the context by the handlers
+// and the middlewares following it as an RFC 7807 problem. The errors are added with c.Error,
+// so that gin's logger prints them along with the causes of the internal failures.
+func (m *Middlewares) ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next() // Proceed to the next handler.
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		renderError(c, c.Errors.Last().Err)
+	}
+}
+
+// abortWithError aborts the request in a middleware with the problem describing the error.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	renderError(c, err)
+}
+
+// renderError responds with the problem describing the error and aborts the request.
+// The errors without a type are reported as failures of the database, their messages aren't disclosed.
+// The message of a wrapped domain error is reported whole, with the context added to it, e.g. the limits.
+//
+// The problem's `type` is about:blank, the kind of the error is identified by its stable `code`.
+func renderError(c *gin.Context, err error) {
+	appErr := apperr.From(err)
+	status := appErr.Kind().HTTPStatus()
+	detail := appErr.Error()
+	if appErr != apperr.ErrDatabase {
+		detail = err.Error()
+	}
+
+	problem := gin.H{
+		"type":     "about:blank",
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   detail,
+		"code":     appErr.Code(),
+		"instance": c.Request.URL.Path,
+	}
+	if requestID := c.GetString("request_id"); requestID != "" {
+		problem["requestId"] = requestID
+	}
+	for key, value := range appErr.Extensions() {
+		problem[key] = value
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// TestMiddlewares_ErrorMiddleware проверяет вывод ошибок обработчиков в формате RFC 7807.
+func TestMiddlewares_ErrorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		handler     gin.HandlerFunc
+		wantStatus  int
+		wantProblem map[string]any
+	}{
+		{
+			name:       "Доменная ошибка",
+			handler:    func(c *gin.Context) { _ = c.Error(models.ErrItemNotFound) },
+			wantStatus: http.StatusNotFound,
+			wantProblem: map[string]any{
+				"type":     "about:blank",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "item not found",
+				"code":     "item_not_found",
+				"instance": "/test",
+			},
+		},
+		{
+			name: "Ошибка с дополнительным полем",
+			handler: func(c *gin.Context) {
+				_ = c.Error(models.ErrTransferTooFrequent.With("results", []string{"testUser2"}))
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantProblem: map[string]any{
+				"code":    "transfer_too_frequent",
+				"results": []any{"testUser2"},
+			},
+		},
+		{
+			name: "Обёрнутая доменная ошибка сохраняет контекст",
+			handler: func(c *gin.Context) {
+				_ = c.Error(fmt.Errorf("%w: at most %d coins a day, %d already sent", models.ErrTransferLimitExceeded, 500, 450))
+			},
+			wantStatus: http.StatusForbidden,
+			wantProblem: map[string]any{
+				"detail": models.ErrTransferLimitExceeded.Error() + ": at most 500 coins a day, 450 already sent",
+				"code":   "transfer_limit_exceeded",
+			},
+		},
+		{
+			name:       "Ошибка без типа не раскрывается",
+			handler:    func(c *gin.Context) { _ = c.Error(errors.New("connection refused")) },
+			wantStatus: http.StatusInternalServerError,
+			wantProblem: map[string]any{
+				"detail": "something happened to the database",
+				"code":   "database_error",
+			},
+		},
+		{
+			name: "Ответ уже отправлен",
+			handler: func(c *gin.Context) {
+				_ = c.Error(models.ErrItemNotFound)
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(NewMiddlewares(nil, nil, nil).ErrorMiddleware())
+			router.GET("/test", tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantProblem == nil {
+				require.NotContains(t, w.Body.String(), "code")
+				return
+			}
+			require.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			var problem map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			for key, want := range tt.wantProblem {
+				require.Equal(t, want, problem[key], key)
+			}
+		})
+	}
+}
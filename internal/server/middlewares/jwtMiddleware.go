@@ -3,39 +3,63 @@
 package middlewares
 
 import (
-	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+var (
+	// errMissingToken is returned when the request has no token.
+	errMissingToken = apperr.New(apperr.KindUnauthorized, "missing_token", "the 'Authorization' header is missing")
+	// errTokenFormat is returned when the 'Authorization' header doesn't hold a Bearer token.
+	errTokenFormat = apperr.New(apperr.KindUnauthorized, "invalid_token", "invalid token format")
+	// errEmptyToken is returned when the Bearer token is empty.
+	errEmptyToken = apperr.New(apperr.KindUnauthorized, "invalid_token", "token is empty")
 )
 
 // JWTMiddleware is a middleware function that validates JWT tokens in incoming requests.
 // It ensures that the request contains a valid "Authorization" header with a Bearer token.
 // If the token is valid, it extracts the user ID and username from the token claims and sets them in the context.
+// The tokens of the users whose accounts have been locked or deactivated since they were issued are rejected.
 func (m *Middlewares) JWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Retrieve the "Authorization" header from the request.
 		header := c.GetHeader(authHeader)
 		if header == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": "the 'Authorization' header is missing"})
+			abortWithError(c, errMissingToken)
 			return
 		}
 
 		// Split the header into parts to extract the token.
 		parts := strings.Split(header, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": "invalid token format"})
+			abortWithError(c, errTokenFormat)
 			return
 		}
 		if len(parts[1]) == 0 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": "token is empty"})
+			abortWithError(c, errEmptyToken)
 			return
 		}
 
 		// Parse and validate the token claims.
 		claims, err := m.tknMng.ParseClaims(parts[1])
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": err.Error()})
+			abortWithError(c, apperr.New(apperr.KindUnauthorized, "invalid_token", err.Error()))
+			return
+		}
+
+		// The account may have been locked or deactivated after the token was issued.
+		sub, _ := (*claims)["sub"].(string)
+		userID, err := strconv.Atoi(sub)
+		if err != nil {
+			abortWithError(c, errNoUser)
+			return
+		}
+		if err = m.accounts.CheckAccount(c.Request.Context(), userID); err != nil {
+			abortWithError(c, err)
 			return
 		}
 
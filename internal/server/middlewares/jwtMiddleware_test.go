@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+)
+
+// stubTokenManager принимает токен "validToken" пользователя с ID 1.
+type stubTokenManager struct{}
+
+func (stubTokenManager) ParseClaims(token string) (*jwt.MapClaims, error) {
+	if token != "validToken" {
+		return nil, errors.New("invalid token")
+	}
+	return &jwt.MapClaims{"sub": "1", "username": "testUser"}, nil
+}
+
+// stubAccountChecker возвращает заданную ошибку для любого пользователя.
+type stubAccountChecker struct {
+	err error
+}
+
+func (s stubAccountChecker) CheckAccount(ctx context.Context, userID int) error {
+	return s.err
+}
+
+// TestMiddlewares_JWTMiddleware проверяет, что токены заблокированных и деактивированных пользователей отклоняются,
+// даже если они были выданы до блокировки.
+func TestMiddlewares_JWTMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		token      string
+		accountErr error
+		wantStatus int
+		wantError  string
+	}{
+		{name: "Активный аккаунт", token: "validToken", wantStatus: http.StatusOK},
+		{name: "Неверный токен", token: "invalidToken", wantStatus: http.StatusUnauthorized, wantError: "invalid_token"},
+		{name: "Заблокированный аккаунт", token: "validToken", accountErr: models.ErrAccountLocked, wantStatus: http.StatusForbidden, wantError: "account_locked"},
+		{name: "Деактивированный аккаунт", token: "validToken", accountErr: models.ErrAccountDeactivated, wantStatus: http.StatusForbidden, wantError: "account_deactivated"},
+		{name: "Ошибка БД", token: "validToken", accountErr: errors.New("db is down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			m := NewMiddlewares(stubTokenManager{}, nil, stubAccountChecker{err: tt.accountErr})
+			router.Use(m.ErrorMiddleware(), m.JWTMiddleware())
+			router.GET("/api/info", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			require.Contains(t, w.Body.String(), tt.wantError)
+		})
+	}
+}
@@ -2,7 +2,11 @@
 // It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
 package middlewares
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 // authHeader is the key used to extract the JWT token from the HTTP request header.
 const authHeader = "Authorization"
@@ -12,12 +16,28 @@ type tokenManager interface {
 	ParseClaims(string) (*jwt.MapClaims, error)
 }
 
-// Middlewares provides middleware functionality for handling JWT-based authentication.
+// roleProvider defines the interface for retrieving the roles of users.
+type roleProvider interface {
+	GetRoleByUserID(ctx context.Context, userID int) (string, error)
+}
+
+// accountChecker defines the interface for checking that the users' accounts are neither locked nor deactivated.
+type accountChecker interface {
+	CheckAccount(ctx context.Context, userID int) error
+}
+
+// Middlewares provides middleware functionality for handling JWT-based authentication and role checks.
 type Middlewares struct {
-	tknMng tokenManager
+	tknMng   tokenManager
+	roles    roleProvider
+	accounts accountChecker
 }
 
-// NewMiddlewares creates a new instance of Middlewares with the provided tokenManager.
-func NewMiddlewares(tokenManager tokenManager) *Middlewares {
-	return &Middlewares{tknMng: tokenManager}
+// NewMiddlewares creates a new instance of Middlewares with the provided tokenManager, roleProvider and accountChecker.
+func NewMiddlewares(tokenManager tokenManager, roles roleProvider, accounts accountChecker) *Middlewares {
+	return &Middlewares{
+		tknMng:   tokenManager,
+		roles:    roles,
+		accounts: accounts,
+	}
 }
@@ -0,0 +1,35 @@
+// Package middlewares provides functionality for handling JWT-based authentication in HTTP requests.
+// It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDHeader is the header carrying the ID of the request, it's set by a proxy or generated.
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID limits the request IDs accepted from clients, so that they are safe to store and log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware is a middleware function that assigns an ID to each request.
+// The ID from the "X-Request-ID" header is kept if it's valid, otherwise a new one is generated.
+// The ID is set in the context for the audit log and returned in the response header.
+func (m *Middlewares) RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next() // Proceed to the next handler.
+	}
+}
@@ -0,0 +1,48 @@
+// Package middlewares provides functionality for handling JWT-based authentication in HTTP requests.
+// It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
+package middlewares
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+var (
+	// errNoUser is returned when the user set by the JWT middleware can't be read.
+	errNoUser = apperr.New(apperr.KindUnauthorized, "invalid_token", "context parsing failure")
+	// errRoleRetrieval is returned when the user's role can't be read from the storage.
+	errRoleRetrieval = apperr.New(apperr.KindInternal, "role_retrieval_failure", "role retrieval failure")
+	// errNotEnoughRights is returned when the user's role isn't allowed the request.
+	errNotEnoughRights = apperr.New(apperr.KindForbidden, "not_enough_rights", "not enough rights")
+)
+
+// RequireRole is a middleware function that allows the request only for users with one of the given roles.
+// It must be used after JWTMiddleware, which sets the user ID in the context.
+// The role is read from the storage on every request, so that role changes take effect immediately.
+func (m *Middlewares) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDStr, _ := c.Get("user_id")
+		str, _ := userIDStr.(string)
+		userID, err := strconv.Atoi(str)
+		if err != nil {
+			abortWithError(c, errNoUser)
+			return
+		}
+
+		role, err := m.roles.GetRoleByUserID(c.Request.Context(), userID)
+		if err != nil {
+			abortWithError(c, errRoleRetrieval)
+			return
+		}
+		if !slices.Contains(roles, role) {
+			abortWithError(c, errNotEnoughRights)
+			return
+		}
+
+		c.Next() // Proceed to the next handler.
+	}
+}
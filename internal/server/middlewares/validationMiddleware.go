@@ -0,0 +1,145 @@
+// Package middlewares provides functionality for handling JWT-based authentication in HTTP requests.
+// It includes a Middlewares struct that uses a tokenManager to parse and validate JWT tokens.
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/apperr"
+)
+
+// specRouter defines the interface for matching requests to the operations of the OpenAPI specification.
+type specRouter interface {
+	FindRoute(req *http.Request) (*routers.Route, map[string]string, error)
+}
+
+// ValidationMiddleware is a middleware function that validates requests against the OpenAPI specification.
+// An invalid request is rejected with 400, the requests to the routes missing from the specification are passed as is.
+// The token is checked by the JWT middleware, not here.
+// In gin's test mode the responses are validated as well: a response not matching the specification
+// is replaced with 500 describing the mismatch, so that tests catch the handlers drifting from the specification.
+func (m *Middlewares) ValidationMiddleware(spec specRouter) gin.HandlerFunc {
+	validateResponses := gin.Mode() == gin.TestMode
+
+	return func(c *gin.Context) {
+		route, pathParams, err := spec.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+		options.WithCustomSchemaErrorFunc(schemaErrorMessage)
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err = openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			abortWithError(c, apperr.Invalid(err.Error()))
+			return
+		}
+
+		// The streams are never finished or too large, so they can't be buffered
+		if !validateResponses || streamed(route) {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		options = &openapi3filter.Options{IncludeResponseStatus: true}
+		options.WithCustomSchemaErrorFunc(schemaErrorMessage)
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 w.status,
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(w.body.Bytes())),
+			Options:                options,
+		})
+		if err != nil {
+			w.Header().Del("Content-Length")
+			renderError(c, apperr.New(apperr.KindInternal, "response_mismatch", "the response doesn't match the OpenAPI specification: "+err.Error()))
+			return
+		}
+
+		w.ResponseWriter.WriteHeader(w.status)
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// streamed reports whether the operation streams its response: Server-Sent Events or an export.
+func streamed(route *routers.Route) bool {
+	if route.Operation == nil {
+		return false
+	}
+	resp := route.Operation.Responses.Status(http.StatusOK)
+	if resp == nil || resp.Value == nil {
+		return false
+	}
+	for _, mediaType := range []string{"text/event-stream", "text/csv", "application/x-ndjson"} {
+		if resp.Value.Content.Get(mediaType) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaErrorMessage shortens the schema errors to the invalid field and the reason,
+// without dumping the schema and the value.
+func schemaErrorMessage(err *openapi3.SchemaError) string {
+	if err.Reason == "" {
+		return ""
+	}
+	if field := err.JSONPointer(); len(field) > 0 {
+		return "`" + strings.Join(field, ".") + "` " + err.Reason
+	}
+	return err.Reason
+}
+
+// bufferedWriter holds the response back until it's validated.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
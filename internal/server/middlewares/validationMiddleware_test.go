@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
+	"github.com/kk7453603/avito_2024_summer/internal/server/openapi"
+)
+
+// TestMiddlewares_ValidationMiddleware проверяет отклонение запросов и, в тестовом режиме gin,
+// ответов, не соответствующих спецификации OpenAPI.
+func TestMiddlewares_ValidationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:   "Корректный запрос и ответ",
+			method: http.MethodPost, path: "/api/auth",
+			body:       `{"username": "testUser1", "password": "password"}`,
+			handler:    func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"token": "token"}) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Короткое имя пользователя",
+			method: http.MethodPost, path: "/api/auth",
+			body:       `{"username": "user", "password": "password"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "`username` minimum string length is 8",
+		},
+		{
+			name:   "Нечисловой параметр пути",
+			method: http.MethodDelete, path: "/api/goals/abc",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "an invalid integer",
+		},
+		{
+			name:   "Ответ с полем неверного типа",
+			method: http.MethodPost, path: "/api/auth",
+			body:       `{"username": "testUser1", "password": "password"}`,
+			handler:    func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"token": 42}) },
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "the response doesn't match the OpenAPI specification",
+		},
+		{
+			name:   "Ответ с неописанным статусом",
+			method: http.MethodPost, path: "/api/auth",
+			body:       `{"username": "testUser1", "password": "password"}`,
+			handler:    func(c *gin.Context) { c.Status(http.StatusTeapot) },
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "the response doesn't match the OpenAPI specification",
+		},
+		{
+			name:   "Ошибка обработчика в формате спецификации",
+			method: http.MethodPost, path: "/api/sendCoin",
+			body:       `{"toUser": "testUser2", "amount": 1000}`,
+			handler:    func(c *gin.Context) { _ = c.Error(models.ErrNotEnoughCoins) },
+			wantStatus: http.StatusBadRequest,
+			wantError:  `"code":"not_enough_coins"`,
+		},
+		{
+			name:   "Маршрут вне спецификации",
+			method: http.MethodGet, path: "/api/unknown",
+			handler:    func(c *gin.Context) { c.String(http.StatusOK, "ok") },
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.handler
+			if handler == nil {
+				handler = func(c *gin.Context) { t.Fatal("the invalid request has reached the handler") }
+			}
+			router := gin.New()
+			m := NewMiddlewares(nil, nil, nil)
+			router.Use(m.ValidationMiddleware(openapi.MustLoad()), m.ErrorMiddleware())
+			router.Handle(tt.method, strings.NewReplacer("abc", ":id").Replace(tt.path), handler)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			require.Contains(t, w.Body.String(), tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package openapi contains the OpenAPI specification of the HTTP API with the handlers serving it
+// and its documentation page. The specification is also used to validate the requests and, in tests, the responses.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// Spec is the OpenAPI specification of the HTTP API.
+type Spec struct {
+	doc    *openapi3.T
+	router routers.Router // matches the requests to the operations
+	json   []byte
+}
+
+// Load parses and validates the specification.
+func Load() (*Spec, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	json, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Spec{
+		doc:    doc,
+		router: router,
+		json:   json,
+	}, nil
+}
+
+// MustLoad loads the specification, it panics if the specification is broken.
+// The specification is embedded, so it either always loads or never does.
+func MustLoad() *Spec {
+	spec, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// Doc returns the specification's document.
+func (s *Spec) Doc() *openapi3.T {
+	return s.doc
+}
+
+// FindRoute returns the operation matching the request with the values of its path parameters.
+func (s *Spec) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	return s.router.FindRoute(req)
+}
+
+// SpecHandler returns the specification as JSON.
+func (s *Spec) SpecHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", s.json)
+}
+
+// DocsHandler returns the documentation page rendered from the specification.
+func (s *Spec) DocsHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsHTML)
+}
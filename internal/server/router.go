@@ -3,21 +3,197 @@
 package server
 
 import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kk7453603/avito_2024_summer/internal/models"
 	"github.com/kk7453603/avito_2024_summer/internal/server/middlewares"
+	"github.com/kk7453603/avito_2024_summer/internal/server/openapi"
 )
 
 // configureRouter sets up the HTTP route handlers.
+// Every route is described in the OpenAPI specification, the requests are validated against it
+// once the client is authenticated and its role is checked, so that the specification's constraints
+// aren't disclosed to anyone else. The errors are rendered by the error middleware inside the validation,
+// so that they are validated in tests too.
+//
+// API v1 under /api is deprecated in favour of API v2 under /api/v2, both versions are served
+// by the same handlers where their semantics match.
 func (as *APIServer) configureRouter() {
-	api := as.router.Group("/api")
+	meddlers := middlewares.NewMiddlewares(as.tknMng, as.roles, as.accounts)
+	spec := openapi.MustLoad()
+	requestID := meddlers.RequestIDMiddleware()
+	validation := []gin.HandlerFunc{meddlers.ValidationMiddleware(spec), meddlers.ErrorMiddleware()}
+
+	docs := as.router.Group("/api", requestID)
+	docs.Use(validation...)
+	docs.GET("/openapi.json", spec.SpecHandler)
+	docs.GET("/docs", spec.DocsHandler)
+
+	// the responses of API v1 are marked as deprecated even if the request fails the validation
+	deprecation := meddlers.DeprecationMiddleware(as.cfg.V1DeprecatedAt, as.cfg.V1Sunset, "/api/v2")
+	as.configureV1(as.router.Group("/api", requestID, deprecation), meddlers, validation)
+	as.configureV2(as.router.Group("/api/v2", requestID), meddlers, validation)
+}
+
+// configureV1 sets up the routes of API v1, validating the requests after the authentication.
+func (as *APIServer) configureV1(api *gin.RouterGroup, meddlers *middlewares.Middlewares, validation []gin.HandlerFunc) {
+	api.Group("/", validation...).POST("/auth", as.usrHandlers.AuthHandler)
+
+	authenticated := api.Group("/", meddlers.JWTMiddleware())
+	authorized := authenticated.Group("/", validation...)
+	{
+		authorized.GET("/info", as.usrHandlers.InfoHandler)
+		authorized.GET("/events", as.evtHandlers.StreamEventsHandler)
+		authorized.POST("/sendCoin", as.usrHandlers.SendCoinsHandler)
+		authorized.POST("/sendCoin/batch", as.usrHandlers.SendCoinsBatchHandler)
+		authorized.GET("/buy/:item", as.usrHandlers.BuyItemHandler)
+		authorized.GET("/catalog", as.usrHandlers.CatalogHandler)
+		authorized.POST("/inventory/:item/return", as.invHandlers.ReturnItemHandler)
+		authorized.POST("/inventory/gift", as.invHandlers.GiftItemHandler)
+		authorized.GET("/wishlist", as.wshHandlers.ListWishlistHandler)
+		authorized.POST("/wishlist/:item", as.wshHandlers.AddToWishlistHandler)
+		authorized.DELETE("/wishlist/:item", as.wshHandlers.RemoveFromWishlistHandler)
+		authorized.GET("/notifications", as.wshHandlers.ListNotificationsHandler)
+		authorized.POST("/notifications/read", as.wshHandlers.MarkNotificationsReadHandler)
+		authorized.GET("/leaderboard", as.ldbHandlers.LeaderboardHandler)
+		authorized.PUT("/leaderboard/visibility", as.ldbHandlers.SetVisibilityHandler)
+		authorized.POST("/goals", as.svgHandlers.CreateGoalHandler)
+		authorized.POST("/goals/:id/deposit", as.svgHandlers.DepositHandler)
+		authorized.DELETE("/goals/:id", as.svgHandlers.CloseGoalHandler)
+		authorized.GET("/transfers/scheduled", as.schHandlers.ListScheduledTransfersHandler)
+		authorized.POST("/transfers/scheduled", as.schHandlers.CreateScheduledTransferHandler)
+		authorized.DELETE("/transfers/scheduled/:id", as.schHandlers.CancelScheduledTransferHandler)
+		authorized.GET("/requests", as.reqHandlers.ListCoinRequestsHandler)
+		authorized.POST("/requests", as.reqHandlers.CreateCoinRequestHandler)
+		authorized.GET("/requests/:id", as.reqHandlers.GetCoinRequestHandler)
+		authorized.POST("/requests/:id/accept", as.reqHandlers.AcceptCoinRequestHandler)
+		authorized.POST("/requests/:id/decline", as.reqHandlers.DeclineCoinRequestHandler)
+		authorized.DELETE("/requests/:id", as.reqHandlers.CancelCoinRequestHandler)
+		authorized.GET("/teams", as.tmsHandlers.ListTeamsHandler)
+		authorized.POST("/teams", as.tmsHandlers.CreateTeamHandler)
+		authorized.GET("/teams/:id", as.tmsHandlers.GetTeamHandler)
+		authorized.PUT("/teams/:id/members/:user", as.tmsHandlers.SetTeamMemberHandler)
+		authorized.DELETE("/teams/:id/members/:user", as.tmsHandlers.RemoveTeamMemberHandler)
+		authorized.POST("/teams/:id/deposit", as.tmsHandlers.DepositToTeamHandler)
+		authorized.POST("/teams/:id/spend", as.tmsHandlers.SpendFromTeamHandler)
+		authorized.GET("/teams/:id/approvals", as.tmsHandlers.ListTeamApprovalsHandler)
+		authorized.POST("/teams/:id/approvals/:spendId", as.tmsHandlers.ResolveTeamApprovalHandler)
+
+		operator := authenticated.Group("/operator", meddlers.RequireRole(models.RoleOperator, models.RoleAdmin))
+		operator.Use(validation...)
+		{
+			operator.GET("/purchases", as.flfHandlers.ListPurchasesHandler)
+			operator.POST("/purchases/:id/status", as.flfHandlers.ChangePurchaseStatusHandler)
+		}
+
+		admin := authenticated.Group("/admin", meddlers.RequireRole(models.RoleAdmin))
+		admin.Use(validation...)
+		{
+			admin.GET("/discounts", as.prmHandlers.ListDiscountsHandler)
+			admin.POST("/discounts", as.prmHandlers.CreateDiscountHandler)
+			admin.DELETE("/discounts/:id", as.prmHandlers.DeleteDiscountHandler)
+			admin.GET("/promocodes", as.prmHandlers.ListPromoCodesHandler)
+			admin.POST("/promocodes", as.prmHandlers.CreatePromoCodeHandler)
+			admin.POST("/items/:item/variants", as.ctlHandlers.CreateVariantHandler)
+			admin.PUT("/variants/:sku", as.ctlHandlers.UpdateVariantHandler)
+			admin.POST("/variants/:sku/restock", as.ctlHandlers.RestockVariantHandler)
+			admin.GET("/policies/:policy/dry-run", as.plcHandlers.DryRunPolicyHandler)
+			admin.GET("/transfers/reviews", as.rvwHandlers.ListTransferReviewsHandler)
+			admin.POST("/transfers/reviews/:id", as.rvwHandlers.ResolveTransferReviewHandler)
+			admin.GET("/audit", as.adtHandlers.ListAuditLogHandler)
+			admin.GET("/audit/verify", as.adtHandlers.VerifyAuditLogHandler)
+			admin.GET("/webhooks", as.whkHandlers.ListWebhooksHandler)
+			admin.POST("/webhooks", as.whkHandlers.CreateWebhookHandler)
+			admin.PUT("/webhooks/:id", as.whkHandlers.UpdateWebhookHandler)
+			admin.DELETE("/webhooks/:id", as.whkHandlers.DeleteWebhookHandler)
+			admin.POST("/webhooks/:id/replay", as.whkHandlers.ReplayDeadWebhooksHandler)
+			admin.GET("/webhooks/deliveries", as.whkHandlers.ListWebhookDeliveriesHandler)
+			admin.POST("/webhooks/deliveries/:id/replay", as.whkHandlers.ReplayWebhookDeliveryHandler)
+		}
+	}
+}
+
+// configureV2 sets up the routes of API v2, validating the requests after the authentication.
+func (as *APIServer) configureV2(api *gin.RouterGroup, meddlers *middlewares.Middlewares, validation []gin.HandlerFunc) {
+	api.Group("/", validation...).POST("/auth", as.usrHandlers.AuthHandler)
+
+	authenticated := api.Group("/", meddlers.JWTMiddleware())
+	authorized := authenticated.Group("/", validation...)
 	{
-		api.POST("/auth", as.usrHandlers.AuthHandler)
+		authorized.GET("/me", as.usrHandlers.MeHandler)
+		authorized.GET("/me/inventory", as.usrHandlers.ListMyInventoryHandler)
+		authorized.GET("/me/purchases", as.usrHandlers.ListMyPurchasesHandler)
+		authorized.GET("/me/transfers", as.usrHandlers.ListMyTransfersHandler)
+		authorized.GET("/me/gifts", as.usrHandlers.ListMyGiftsHandler)
+		authorized.GET("/me/goals", as.usrHandlers.ListMyGoalsHandler)
+		authorized.GET("/events", as.evtHandlers.StreamEventsHandler)
+		authorized.POST("/transfers", as.usrHandlers.CreateTransferHandler)
+		authorized.POST("/transfers/batch", as.usrHandlers.CreateTransferBatchHandler)
+		authorized.GET("/transfers/scheduled", as.schHandlers.ListScheduledTransfersV2Handler)
+		authorized.POST("/transfers/scheduled", as.schHandlers.CreateScheduledTransferHandler)
+		authorized.DELETE("/transfers/scheduled/:id", as.schHandlers.CancelScheduledTransferHandler)
+		authorized.POST("/purchases", as.usrHandlers.CreatePurchaseHandler)
+		authorized.GET("/items", as.usrHandlers.ListItemsHandler)
+		authorized.GET("/items/:item", as.usrHandlers.GetItemHandler)
+		authorized.POST("/inventory/:item/return", as.invHandlers.ReturnItemHandler)
+		authorized.POST("/inventory/gift", as.invHandlers.GiftItemHandler)
+		authorized.GET("/wishlist", as.wshHandlers.ListWishlistV2Handler)
+		authorized.POST("/wishlist/:item", as.wshHandlers.AddToWishlistHandler)
+		authorized.DELETE("/wishlist/:item", as.wshHandlers.RemoveFromWishlistHandler)
+		authorized.GET("/notifications", as.wshHandlers.ListNotificationsV2Handler)
+		authorized.POST("/notifications/read", as.wshHandlers.MarkNotificationsReadHandler)
+		authorized.GET("/leaderboard", as.ldbHandlers.LeaderboardHandler)
+		authorized.PUT("/leaderboard/visibility", as.ldbHandlers.SetVisibilityHandler)
+		authorized.POST("/goals", as.svgHandlers.CreateGoalHandler)
+		authorized.POST("/goals/:id/deposit", as.svgHandlers.DepositHandler)
+		authorized.DELETE("/goals/:id", as.svgHandlers.CloseGoalHandler)
+		authorized.GET("/requests", as.reqHandlers.ListCoinRequestsV2Handler)
+		authorized.POST("/requests", as.reqHandlers.CreateCoinRequestHandler)
+		authorized.GET("/requests/:id", as.reqHandlers.GetCoinRequestHandler)
+		authorized.POST("/requests/:id/accept", as.reqHandlers.AcceptCoinRequestHandler)
+		authorized.POST("/requests/:id/decline", as.reqHandlers.DeclineCoinRequestHandler)
+		authorized.DELETE("/requests/:id", as.reqHandlers.CancelCoinRequestHandler)
+		authorized.GET("/teams", as.tmsHandlers.ListTeamsV2Handler)
+		authorized.POST("/teams", as.tmsHandlers.CreateTeamHandler)
+		authorized.GET("/teams/:id", as.tmsHandlers.GetTeamHandler)
+		authorized.PUT("/teams/:id/members/:user", as.tmsHandlers.SetTeamMemberHandler)
+		authorized.DELETE("/teams/:id/members/:user", as.tmsHandlers.RemoveTeamMemberHandler)
+		authorized.POST("/teams/:id/deposit", as.tmsHandlers.DepositToTeamHandler)
+		authorized.POST("/teams/:id/spend", as.tmsHandlers.SpendFromTeamHandler)
+		authorized.GET("/teams/:id/approvals", as.tmsHandlers.ListTeamApprovalsV2Handler)
+		authorized.POST("/teams/:id/approvals/:spendId", as.tmsHandlers.ResolveTeamApprovalHandler)
+
+		operator := authenticated.Group("/operator", meddlers.RequireRole(models.RoleOperator, models.RoleAdmin))
+		operator.Use(validation...)
+		{
+			operator.GET("/purchases", as.flfHandlers.ListPurchasesV2Handler)
+			operator.POST("/purchases/:id/status", as.flfHandlers.ChangePurchaseStatusHandler)
+		}
 
-		meddlers := middlewares.NewMiddlewares(as.tknMng)
-		authorized := api.Group("/", meddlers.JWTMiddleware())
+		admin := authenticated.Group("/admin", meddlers.RequireRole(models.RoleAdmin))
+		admin.Use(validation...)
 		{
-			authorized.GET("/info", as.usrHandlers.InfoHandler)
-			authorized.POST("/sendCoin", as.usrHandlers.SendCoinsHandler)
-			authorized.GET("/buy/:item", as.usrHandlers.BuyItemHandler)
+			admin.GET("/discounts", as.prmHandlers.ListDiscountsV2Handler)
+			admin.POST("/discounts", as.prmHandlers.CreateDiscountHandler)
+			admin.DELETE("/discounts/:id", as.prmHandlers.DeleteDiscountHandler)
+			admin.GET("/promocodes", as.prmHandlers.ListPromoCodesV2Handler)
+			admin.POST("/promocodes", as.prmHandlers.CreatePromoCodeHandler)
+			admin.POST("/items/:item/variants", as.ctlHandlers.CreateVariantHandler)
+			admin.PUT("/variants/:sku", as.ctlHandlers.UpdateVariantHandler)
+			admin.POST("/variants/:sku/restock", as.ctlHandlers.RestockVariantHandler)
+			admin.GET("/policies/:policy/dry-run", as.plcHandlers.DryRunPolicyHandler)
+			admin.GET("/transfers/reviews", as.rvwHandlers.ListTransferReviewsV2Handler)
+			admin.POST("/transfers/reviews/:id", as.rvwHandlers.ResolveTransferReviewHandler)
+			admin.GET("/audit", as.adtHandlers.ListAuditLogHandler)
+			admin.GET("/audit/verify", as.adtHandlers.VerifyAuditLogHandler)
+			admin.GET("/webhooks", as.whkHandlers.ListWebhooksV2Handler)
+			admin.POST("/webhooks", as.whkHandlers.CreateWebhookHandler)
+			admin.PUT("/webhooks/:id", as.whkHandlers.UpdateWebhookHandler)
+			admin.DELETE("/webhooks/:id", as.whkHandlers.DeleteWebhookHandler)
+			admin.POST("/webhooks/:id/replay", as.whkHandlers.ReplayDeadWebhooksHandler)
+			admin.GET("/webhooks/deliveries", as.whkHandlers.ListWebhookDeliveriesV2Handler)
+			admin.POST("/webhooks/deliveries/:id/replay", as.whkHandlers.ReplayWebhookDeliveryHandler)
+			admin.GET("/ledger", as.lgrHandlers.ExportLedgerHandler)
 		}
 	}
 }
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kk7453603/avito_2024_summer/internal/server/openapi"
+)
+
+// ginParam matches the path parameters in gin's notation, `:id`.
+var ginParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+// TestRouter_MatchesOpenAPI проверяет, что каждый маршрут роутера описан в спецификации OpenAPI,
+// а каждая операция спецификации обслуживается роутером.
+func TestRouter_MatchesOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	as := New(context.Background(), &Config{}, &Handlers{}, nil, nil, nil)
+	as.configureRouter()
+
+	routes := make(map[string]bool)
+	for _, r := range as.router.Routes() {
+		routes[r.Method+" "+ginParam.ReplaceAllString(r.Path, "{$1}")] = true
+	}
+
+	operations := make(map[string]bool)
+	for path, item := range openapi.MustLoad().Doc().Paths.Map() {
+		for method := range item.Operations() {
+			operations[method+" "+path] = true
+		}
+	}
+
+	require.Empty(t, missing(routes, operations), "the routes missing from the OpenAPI specification")
+	require.Empty(t, missing(operations, routes), "the OpenAPI operations without a route")
+}
+
+// TestRouter_ServesOpenAPI проверяет отдачу спецификации и страницы документации без авторизации.
+func TestRouter_ServesOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	as := New(context.Background(), &Config{}, &Handlers{}, nil, nil, nil)
+	as.configureRouter()
+
+	for _, path := range []string{"/api/openapi.json", "/api/docs"} {
+		w := httptest.NewRecorder()
+		as.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, path)
+		require.NotEmpty(t, w.Body.String(), path)
+	}
+
+	// Страница документации загружает Redoc только закреплённой версии.
+	w := httptest.NewRecorder()
+	as.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	require.Contains(t, w.Body.String(), "redoc@2.1.5/")
+	require.NotContains(t, w.Body.String(), "latest")
+}
+
+// TestRouter_DeprecatesV1 проверяет заголовки устаревания в ответах API v1 и их отсутствие в ответах API v2.
+func TestRouter_DeprecatesV1(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &Config{
+		V1DeprecatedAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		V1Sunset:       time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC),
+	}
+	as := New(context.Background(), cfg, &Handlers{}, nil, nil, nil)
+	as.configureRouter()
+
+	// Запрос без тела отклоняется проверкой по спецификации, обработчики не вызываются.
+	w := httptest.NewRecorder()
+	as.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/auth", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "@1792281600", w.Header().Get("Deprecation"))
+	require.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	require.Equal(t, `</api/v2>; rel="successor-version"`, w.Header().Get("Link"))
+
+	for _, r := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v2/auth"},
+		{http.MethodGet, "/api/openapi.json"},
+	} {
+		w = httptest.NewRecorder()
+		as.router.ServeHTTP(w, httptest.NewRequest(r.method, r.path, nil))
+		require.Empty(t, w.Header().Get("Deprecation"), r.path)
+		require.Empty(t, w.Header().Get("Sunset"), r.path)
+	}
+}
+
+// TestRouter_ValidatesAfterAuth проверяет, что запросы к маршрутам с авторизацией проверяются по спецификации
+// только после аутентификации: неаутентифицированный клиент с неверным телом получает 401, а не 400.
+func TestRouter_ValidatesAfterAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	as := New(context.Background(), &Config{}, &Handlers{}, nil, nil, nil)
+	as.configureRouter()
+
+	for _, path := range []string{"/api/sendCoin", "/api/v2/transfers", "/api/v2/admin/discounts"} {
+		w := httptest.NewRecorder()
+		as.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"amount": "many"}`)))
+		require.Equal(t, http.StatusUnauthorized, w.Code, path)
+		require.Contains(t, w.Body.String(), `"code":"missing_token"`, path)
+	}
+
+	// Вход проверяется по спецификации без аутентификации.
+	w := httptest.NewRecorder()
+	as.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/auth", strings.NewReader(`{}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"invalid_request"`)
+}
+
+// missing returns the keys of a absent from b.
+func missing(a, b map[string]bool) []string {
+	var res []string
+	for k := range a {
+		if !b[k] {
+			res = append(res, k)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// TestAPIServer_Recovery проверяет, что паника обработчика даёт 500, а http.ErrAbortHandler
+// передаётся HTTP-серверу, чтобы тот оборвал соединение.
+func TestAPIServer_Recovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	as := New(context.Background(), &Config{}, &Handlers{}, nil, nil, nil)
+	as.router.GET("/panic", func(c *gin.Context) { panic("handler failure") })
+	as.router.GET("/abort", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic(http.ErrAbortHandler)
+	})
+
+	w := httptest.NewRecorder()
+	as.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		as.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+}
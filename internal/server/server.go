@@ -17,33 +17,111 @@ import (
 type Config struct {
 	Host string `envconfig:"HOST" default:"localhost"`
 	Port string `envconfig:"PORT" default:"8080"`
+
+	V1DeprecatedAt time.Time `envconfig:"V1_DEPRECATED_AT" required:"true"` // API v1 is deprecated since then
+	V1Sunset       time.Time `envconfig:"V1_SUNSET" required:"true"`        // API v1 stops being served then
 }
 
 type tokenManager interface {
 	ParseClaims(string) (*jwt.MapClaims, error)
 }
 
+type roleProvider interface {
+	GetRoleByUserID(ctx context.Context, userID int) (string, error)
+}
+
+type accountChecker interface {
+	CheckAccount(ctx context.Context, userID int) error
+}
+
+// Handlers groups the sets of HTTP handlers served by the API server.
+type Handlers struct {
+	User        *handlers.UserHandlers              // Main handlers for user
+	Inventory   *handlers.InventoryHandlers         // Handlers for owned items
+	Promotions  *handlers.PromotionHandlers         // Admin handlers for discounts and promo codes
+	Catalog     *handlers.CatalogHandlers           // Admin handlers for the store's assortment
+	Fulfilment  *handlers.FulfilmentHandlers        // Operator handlers for handing out purchases
+	Wishlist    *handlers.WishlistHandlers          // Handlers for wishlists and notifications
+	Leaderboard *handlers.LeaderboardHandlers       // Handlers for the leaderboards
+	Savings     *handlers.SavingsHandlers           // Handlers for savings goals
+	Scheduled   *handlers.ScheduledTransferHandlers // Handlers for scheduled coin transfers
+	Policies    *handlers.PolicyHandlers            // Admin handlers for balance policies
+	Reviews     *handlers.TransferReviewHandlers    // Admin handlers for flagged transfers
+	Requests    *handlers.CoinRequestHandlers       // Handlers for coin requests
+	Teams       *handlers.TeamHandlers              // Handlers for teams and their wallets
+	Audit       *handlers.AuditLogHandlers          // Admin handlers for the audit log
+	Webhooks    *handlers.WebhookHandlers           // Admin handlers for webhook subscriptions
+	Events      *handlers.EventStreamHandlers       // Handlers for the stream of the user's events
+	Ledger      *handlers.LedgerHandlers            // Admin handlers for the finance export
+}
+
 // APIServer represents the API server, including configuration, router, and services.
 type APIServer struct {
-	router      *gin.Engine            // HTTP router for handling requests.
-	cfg         *Config                // Configuration for server settings.
-	ctx         context.Context        // Application context.
-	tknMng      tokenManager           // JWT Token Manager for token parsing
-	usrHandlers *handlers.UserHandlers // Main handlers for user
+	router      *gin.Engine                         // HTTP router for handling requests.
+	cfg         *Config                             // Configuration for server settings.
+	ctx         context.Context                     // Application context.
+	tknMng      tokenManager                        // JWT Token Manager for token parsing
+	roles       roleProvider                        // Provider of users' roles for access checks
+	accounts    accountChecker                      // Checker of the locked and deactivated accounts
+	usrHandlers *handlers.UserHandlers              // Main handlers for user
+	invHandlers *handlers.InventoryHandlers         // Handlers for owned items
+	prmHandlers *handlers.PromotionHandlers         // Admin handlers for discounts and promo codes
+	ctlHandlers *handlers.CatalogHandlers           // Admin handlers for the store's assortment
+	flfHandlers *handlers.FulfilmentHandlers        // Operator handlers for handing out purchases
+	wshHandlers *handlers.WishlistHandlers          // Handlers for wishlists and notifications
+	ldbHandlers *handlers.LeaderboardHandlers       // Handlers for the leaderboards
+	svgHandlers *handlers.SavingsHandlers           // Handlers for savings goals
+	schHandlers *handlers.ScheduledTransferHandlers // Handlers for scheduled coin transfers
+	plcHandlers *handlers.PolicyHandlers            // Admin handlers for balance policies
+	rvwHandlers *handlers.TransferReviewHandlers    // Admin handlers for flagged transfers
+	reqHandlers *handlers.CoinRequestHandlers       // Handlers for coin requests
+	tmsHandlers *handlers.TeamHandlers              // Handlers for teams and their wallets
+	adtHandlers *handlers.AuditLogHandlers          // Admin handlers for the audit log
+	whkHandlers *handlers.WebhookHandlers           // Admin handlers for webhook subscriptions
+	evtHandlers *handlers.EventStreamHandlers       // Handlers for the stream of the user's events
+	lgrHandlers *handlers.LedgerHandlers            // Admin handlers for the finance export
 	server      *http.Server
 }
 
 // New creates a new instance of APIServer with the provided context, configuration, and services.
-func New(ctx context.Context, cfg *Config, usrHandlers *handlers.UserHandlers, tknMng tokenManager) *APIServer {
-	router := gin.Default()
+func New(ctx context.Context, cfg *Config, hs *Handlers, tknMng tokenManager, roles roleProvider, accounts accountChecker) *APIServer {
+	router := gin.New()
+	router.Use(gin.Logger(), gin.CustomRecovery(handlePanic))
 
 	return &APIServer{
 		router:      router,
 		cfg:         cfg,
 		ctx:         ctx,
-		usrHandlers: usrHandlers,
+		usrHandlers: hs.User,
+		invHandlers: hs.Inventory,
+		prmHandlers: hs.Promotions,
+		ctlHandlers: hs.Catalog,
+		flfHandlers: hs.Fulfilment,
+		wshHandlers: hs.Wishlist,
+		ldbHandlers: hs.Leaderboard,
+		svgHandlers: hs.Savings,
+		schHandlers: hs.Scheduled,
+		plcHandlers: hs.Policies,
+		rvwHandlers: hs.Reviews,
+		reqHandlers: hs.Requests,
+		tmsHandlers: hs.Teams,
+		adtHandlers: hs.Audit,
+		whkHandlers: hs.Webhooks,
+		evtHandlers: hs.Events,
+		lgrHandlers: hs.Ledger,
 		tknMng:      tknMng,
+		roles:       roles,
+		accounts:    accounts,
+	}
+}
+
+// handlePanic responds with 500 to the request whose handler has panicked. http.ErrAbortHandler is passed on
+// to the HTTP server, which aborts the connection, the way a handler cuts short a response already being sent.
+func handlePanic(c *gin.Context, err any) {
+	if err == http.ErrAbortHandler {
+		panic(err)
 	}
+	c.AbortWithStatus(http.StatusInternalServerError)
 }
 
 // Start begins the HTTP server, listening on the configured host and port.
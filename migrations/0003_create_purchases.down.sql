@@ -0,0 +1,19 @@
+
+DROP INDEX IF EXISTS idx_purchases_user_item;
+
+DELETE
+FROM transactions
+WHERE kind <> 'transfer';
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_transfer_sender;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_kind;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS purchase_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS kind;
+ALTER TABLE transactions
+    ALTER COLUMN sender_id SET NOT NULL;
+
+DROP TABLE IF EXISTS purchases;
@@ -0,0 +1,27 @@
+-- Создание таблицы purchases (история покупок, нужна для возвратов)
+CREATE TABLE IF NOT EXISTS purchases
+(
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER      NOT NULL,
+    item_slug   VARCHAR(255) NOT NULL,
+    price       INTEGER      NOT NULL CHECK (price >= 0), -- фактически оплаченная сумма
+    returned_at TIMESTAMP,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE RESTRICT
+);
+
+-- Возвраты записываются в transactions: отправитель отсутствует (магазин)
+ALTER TABLE transactions
+    ALTER COLUMN sender_id DROP NOT NULL;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(32) NOT NULL DEFAULT 'transfer';
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS purchase_id INTEGER REFERENCES purchases (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_kind CHECK (kind IN ('transfer', 'refund'));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_transfer_sender CHECK (kind <> 'transfer' OR sender_id IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user_item ON purchases (user_id, item_slug, created_at);
@@ -0,0 +1,5 @@
+
+DROP INDEX IF EXISTS idx_gifts_sender;
+DROP INDEX IF EXISTS idx_gifts_receiver;
+
+DROP TABLE IF EXISTS gifts;
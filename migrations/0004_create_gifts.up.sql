@@ -0,0 +1,17 @@
+-- Создание таблицы gifts (история подарков из инвентаря)
+CREATE TABLE IF NOT EXISTS gifts
+(
+    id          SERIAL PRIMARY KEY,
+    sender_id   INTEGER      NOT NULL,
+    receiver_id INTEGER      NOT NULL,
+    item_slug   VARCHAR(255) NOT NULL,
+    quantity    INTEGER      NOT NULL CHECK (quantity >= 1),
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_gift_sender_receiver CHECK (sender_id <> receiver_id),
+    FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE RESTRICT,
+    FOREIGN KEY (receiver_id) REFERENCES users (id) ON DELETE RESTRICT,
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_gifts_sender ON gifts (sender_id);
+CREATE INDEX IF NOT EXISTS idx_gifts_receiver ON gifts (receiver_id);
@@ -0,0 +1,5 @@
+
+ALTER TABLE store
+    DROP COLUMN IF EXISTS per_user_limit;
+ALTER TABLE store
+    DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,5 @@
+-- Остатки товара и лимиты покупок на одного пользователя (NULL - без ограничений)
+ALTER TABLE store
+    ADD COLUMN IF NOT EXISTS stock INTEGER CHECK (stock >= 0);
+ALTER TABLE store
+    ADD COLUMN IF NOT EXISTS per_user_limit INTEGER CHECK (per_user_limit >= 1);
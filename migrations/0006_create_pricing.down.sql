@@ -0,0 +1,22 @@
+
+DROP INDEX IF EXISTS idx_discounts_period;
+
+DROP TABLE IF EXISTS promo_redemptions;
+
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS promo_code;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS discount;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS list_price;
+
+DROP TABLE IF EXISTS promo_codes;
+DROP TABLE IF EXISTS discounts;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS check_role;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
+
+ALTER TABLE store
+    DROP COLUMN IF EXISTS category;
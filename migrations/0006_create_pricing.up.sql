@@ -0,0 +1,80 @@
+-- Категории товаров (для скидок на категорию)
+ALTER TABLE store
+    ADD COLUMN IF NOT EXISTS category VARCHAR(64);
+
+UPDATE store
+SET category = 'clothes'
+WHERE slug IN ('t-shirt', 'hoody', 'socks', 'pink-hoody');
+UPDATE store
+SET category = 'accessories'
+WHERE slug IN ('cup', 'book', 'pen', 'powerbank', 'umbrella', 'wallet');
+
+-- Роли пользователей (администраторы управляют скидками и промокодами)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'employee';
+ALTER TABLE users
+    ADD CONSTRAINT check_role CHECK (role IN ('employee', 'admin'));
+
+-- Создание таблицы discounts (скидки по расписанию на товар или категорию)
+CREATE TABLE IF NOT EXISTS discounts
+(
+    id         SERIAL PRIMARY KEY,
+    item_slug  VARCHAR(255),
+    category   VARCHAR(64),
+    kind       VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value      INTEGER     NOT NULL CHECK (value >= 1),
+    starts_at  TIMESTAMP   NOT NULL,
+    ends_at    TIMESTAMP   NOT NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_discount_scope CHECK ((item_slug IS NULL) <> (category IS NULL)),
+    CONSTRAINT check_discount_period CHECK (ends_at > starts_at),
+    CONSTRAINT check_discount_percent CHECK (kind <> 'percent' OR value <= 100),
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE CASCADE
+);
+
+-- Создание таблицы promo_codes (max_uses: NULL - многоразовый без ограничений, 1 - одноразовый)
+CREATE TABLE IF NOT EXISTS promo_codes
+(
+    code       VARCHAR(64) PRIMARY KEY,
+    kind       VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value      INTEGER     NOT NULL CHECK (value >= 1),
+    item_slug  VARCHAR(255),
+    category   VARCHAR(64),
+    max_uses   INTEGER CHECK (max_uses >= 1),
+    used       INTEGER     NOT NULL DEFAULT 0,
+    starts_at  TIMESTAMP,
+    ends_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_promo_used CHECK (max_uses IS NULL OR used <= max_uses),
+    CONSTRAINT check_promo_percent CHECK (kind <> 'percent' OR value <= 100),
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE CASCADE
+);
+
+-- Применённые скидки сохраняются в покупке (для возвратов и отчётов)
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS list_price INTEGER CHECK (list_price >= 0);
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0 CHECK (discount >= 0);
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS promo_code VARCHAR(64) REFERENCES promo_codes (code) ON DELETE RESTRICT;
+
+UPDATE purchases
+SET list_price = price
+WHERE list_price IS NULL;
+ALTER TABLE purchases
+    ALTER COLUMN list_price SET NOT NULL;
+
+-- Создание таблицы promo_redemptions (каждый пользователь применяет промокод один раз)
+CREATE TABLE IF NOT EXISTS promo_redemptions
+(
+    code        VARCHAR(64) NOT NULL,
+    user_id     INTEGER     NOT NULL,
+    purchase_id INTEGER     NOT NULL,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_promo_redemption UNIQUE (code, user_id),
+    FOREIGN KEY (code) REFERENCES promo_codes (code) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (purchase_id) REFERENCES purchases (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_discounts_period ON discounts (starts_at, ends_at);
@@ -0,0 +1,45 @@
+
+ALTER TABLE gifts
+    DROP CONSTRAINT IF EXISTS fk_gifts_sku;
+ALTER TABLE gifts
+    DROP COLUMN IF EXISTS sku;
+
+ALTER TABLE purchases
+    DROP CONSTRAINT IF EXISTS fk_purchases_sku;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS sku;
+
+-- Варианты одного товара сворачиваются в одну строку инвентаря
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS fk_inventory_sku;
+UPDATE inventory i
+SET quantity = t.total
+FROM (SELECT user_id, item_slug, MIN(sku) AS sku, SUM(quantity) AS total
+      FROM inventory
+      GROUP BY user_id, item_slug) t
+WHERE i.user_id = t.user_id
+  AND i.sku = t.sku;
+DELETE
+FROM inventory i
+WHERE i.sku <> (SELECT MIN(j.sku)
+                FROM inventory j
+                WHERE j.user_id = i.user_id
+                  AND j.item_slug = i.item_slug);
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS unique_inventory;
+ALTER TABLE inventory
+    ADD CONSTRAINT unique_inventory UNIQUE (user_id, item_slug);
+ALTER TABLE inventory
+    DROP COLUMN IF EXISTS sku;
+
+-- Остатки возвращаются в store (NULL, если хотя бы один вариант без ограничений)
+ALTER TABLE store
+    ADD COLUMN IF NOT EXISTS stock INTEGER CHECK (stock >= 0);
+UPDATE store s
+SET stock = (SELECT CASE WHEN BOOL_OR(v.stock IS NULL) THEN NULL ELSE SUM(v.stock) END
+             FROM item_variants v
+             WHERE v.item_slug = s.slug);
+
+DROP INDEX IF EXISTS idx_item_variants_item;
+DROP INDEX IF EXISTS unique_default_variant;
+DROP TABLE IF EXISTS item_variants;
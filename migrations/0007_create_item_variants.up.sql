@@ -0,0 +1,64 @@
+-- Создание таблицы item_variants (варианты товара: размер, цвет; NULL в stock - без ограничений)
+CREATE TABLE IF NOT EXISTS item_variants
+(
+    sku            VARCHAR(255) PRIMARY KEY,
+    item_slug      VARCHAR(255) NOT NULL,
+    title          VARCHAR(255) NOT NULL,
+    size           VARCHAR(32),
+    color          VARCHAR(64),
+    price_override INTEGER CHECK (price_override >= 0),
+    stock          INTEGER CHECK (stock >= 0),
+    is_default     BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE CASCADE
+);
+
+-- У каждого товара ровно один вариант по умолчанию
+CREATE UNIQUE INDEX IF NOT EXISTS unique_default_variant ON item_variants (item_slug) WHERE is_default;
+CREATE INDEX IF NOT EXISTS idx_item_variants_item ON item_variants (item_slug);
+
+-- Существующие товары получают вариант по умолчанию (sku = slug), остатки переносятся в него
+INSERT INTO item_variants (sku, item_slug, title, stock, is_default)
+SELECT slug, slug, title, stock, TRUE
+FROM store
+ON CONFLICT (sku) DO NOTHING;
+
+ALTER TABLE store
+    DROP COLUMN IF EXISTS stock;
+
+-- Инвентарь хранится по вариантам
+ALTER TABLE inventory
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(255);
+UPDATE inventory
+SET sku = item_slug
+WHERE sku IS NULL;
+ALTER TABLE inventory
+    ALTER COLUMN sku SET NOT NULL;
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS unique_inventory;
+ALTER TABLE inventory
+    ADD CONSTRAINT unique_inventory UNIQUE (user_id, sku);
+ALTER TABLE inventory
+    ADD CONSTRAINT fk_inventory_sku FOREIGN KEY (sku) REFERENCES item_variants (sku) ON DELETE CASCADE;
+
+-- Покупки и подарки запоминают вариант
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(255);
+UPDATE purchases
+SET sku = item_slug
+WHERE sku IS NULL;
+ALTER TABLE purchases
+    ALTER COLUMN sku SET NOT NULL;
+ALTER TABLE purchases
+    ADD CONSTRAINT fk_purchases_sku FOREIGN KEY (sku) REFERENCES item_variants (sku) ON DELETE RESTRICT;
+
+ALTER TABLE gifts
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(255);
+UPDATE gifts
+SET sku = item_slug
+WHERE sku IS NULL;
+ALTER TABLE gifts
+    ALTER COLUMN sku SET NOT NULL;
+ALTER TABLE gifts
+    ADD CONSTRAINT fk_gifts_sku FOREIGN KEY (sku) REFERENCES item_variants (sku) ON DELETE RESTRICT;
@@ -0,0 +1,22 @@
+
+UPDATE users
+SET role = 'employee'
+WHERE role = 'operator';
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS check_role;
+ALTER TABLE users
+    ADD CONSTRAINT check_role CHECK (role IN ('employee', 'admin'));
+
+DROP INDEX IF EXISTS idx_purchase_status_changes_purchase;
+DROP TABLE IF EXISTS purchase_status_changes;
+
+DROP INDEX IF EXISTS idx_purchases_status_office;
+
+ALTER TABLE purchases
+    DROP CONSTRAINT IF EXISTS check_purchase_status;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS office;
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS status;
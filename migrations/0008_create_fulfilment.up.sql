@@ -0,0 +1,37 @@
+-- Статусы выдачи купленного мерча: pending -> ready_for_pickup -> handed_over, отмена с возвратом денег - cancelled
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'pending';
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS office VARCHAR(64); -- офис выдачи, NULL - не выбран
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE purchases
+    ADD CONSTRAINT check_purchase_status CHECK (status IN ('pending', 'ready_for_pickup', 'handed_over', 'cancelled'));
+
+-- Возвращённые покупки считаются отменёнными
+UPDATE purchases
+SET status = 'cancelled'
+WHERE returned_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_purchases_status_office ON purchases (status, office, created_at);
+
+-- Создание таблицы purchase_status_changes (кто и когда менял статус покупки)
+CREATE TABLE IF NOT EXISTS purchase_status_changes
+(
+    id          SERIAL PRIMARY KEY,
+    purchase_id INTEGER     NOT NULL,
+    operator_id INTEGER,
+    from_status VARCHAR(32) NOT NULL,
+    to_status   VARCHAR(32) NOT NULL,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (purchase_id) REFERENCES purchases (id) ON DELETE CASCADE,
+    FOREIGN KEY (operator_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_status_changes_purchase ON purchase_status_changes (purchase_id);
+
+-- Операторы выдачи мерча
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS check_role;
+ALTER TABLE users
+    ADD CONSTRAINT check_role CHECK (role IN ('employee', 'operator', 'admin'));
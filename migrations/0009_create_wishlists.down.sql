@@ -0,0 +1,6 @@
+
+DROP INDEX IF EXISTS idx_notifications_user;
+DROP TABLE IF EXISTS notifications;
+
+DROP INDEX IF EXISTS idx_wishlists_item;
+DROP TABLE IF EXISTS wishlists;
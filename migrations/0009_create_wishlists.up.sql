@@ -0,0 +1,29 @@
+-- Создание таблицы wishlists (товары, которые пользователь хочет купить)
+CREATE TABLE IF NOT EXISTS wishlists
+(
+    user_id    INTEGER      NOT NULL,
+    item_slug  VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_slug),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlists_item ON wishlists (item_slug);
+
+-- Создание таблицы notifications (уведомления пользователей, например о поступлении товара из списка желаний)
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    kind       VARCHAR(32) NOT NULL,
+    item_slug  VARCHAR(255),
+    message    TEXT        NOT NULL,
+    read_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE CASCADE,
+    CONSTRAINT check_notification_kind CHECK (kind IN ('back_in_stock', 'discount'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at);
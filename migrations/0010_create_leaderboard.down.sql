@@ -0,0 +1,5 @@
+
+DROP INDEX IF EXISTS idx_transactions_transfers_received;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS leaderboard_opt_out;
@@ -0,0 +1,7 @@
+-- Пользователь может скрыть себя из рейтинга
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Рейтинг по полученным монетам за период считается только по переводам между пользователями
+CREATE INDEX IF NOT EXISTS idx_transactions_transfers_received
+    ON transactions (created_at, receiver_id) INCLUDE (coins) WHERE kind = 'transfer';
@@ -0,0 +1,8 @@
+
+-- Отложенные монеты возвращаются на баланс
+UPDATE users u
+SET coins = u.coins + g.saved
+FROM (SELECT user_id, SUM(saved) AS saved FROM savings_goals GROUP BY user_id) g
+WHERE u.id = g.user_id;
+
+DROP TABLE IF EXISTS savings_goals;
@@ -0,0 +1,15 @@
+-- Создание таблицы savings_goals (копилки на товар: отложенные монеты не входят в баланс пользователя
+-- и возвращаются в него при покупке товара или закрытии копилки)
+CREATE TABLE IF NOT EXISTS savings_goals
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER      NOT NULL,
+    item_slug  VARCHAR(255) NOT NULL,
+    saved      INTEGER      NOT NULL DEFAULT 0,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_savings_goal UNIQUE (user_id, item_slug),
+    CONSTRAINT check_saved CHECK (saved >= 0),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_slug) REFERENCES store (slug) ON DELETE RESTRICT
+);
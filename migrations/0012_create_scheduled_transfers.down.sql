@@ -0,0 +1,12 @@
+
+DELETE FROM notifications
+WHERE kind IN ('transfer_skipped', 'transfer_failed');
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS check_notification_kind;
+ALTER TABLE notifications
+    ADD CONSTRAINT check_notification_kind CHECK (kind IN ('back_in_stock', 'discount'));
+
+DROP INDEX IF EXISTS idx_scheduled_transfers_sender;
+DROP INDEX IF EXISTS idx_scheduled_transfers_due;
+DROP TABLE IF EXISTS scheduled_transfers;
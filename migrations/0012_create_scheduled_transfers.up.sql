@@ -0,0 +1,29 @@
+-- Создание таблицы scheduled_transfers (разовые переводы в будущем и регулярные переводы по расписанию в формате cron)
+CREATE TABLE IF NOT EXISTS scheduled_transfers
+(
+    id          SERIAL PRIMARY KEY,
+    sender_id   INTEGER     NOT NULL,
+    receiver_id INTEGER     NOT NULL,
+    coins       INTEGER     NOT NULL CHECK (coins > 0),
+    schedule    VARCHAR(64),                           -- NULL - разовый перевод
+    next_run_at TIMESTAMP   NOT NULL,
+    status      VARCHAR(32) NOT NULL DEFAULT 'active',
+    attempts    INTEGER     NOT NULL DEFAULT 0,        -- неудачные попытки текущего запуска
+    last_error  TEXT,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (receiver_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT check_scheduled_transfer_status CHECK (status IN ('active', 'completed', 'skipped', 'failed', 'cancelled')),
+    CONSTRAINT check_scheduled_transfer_users CHECK (sender_id <> receiver_id)
+);
+
+-- Воркер выбирает наступившие переводы по времени запуска
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers (next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_sender ON scheduled_transfers (sender_id);
+
+-- Уведомления о пропущенных и неудавшихся переводах по расписанию
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS check_notification_kind;
+ALTER TABLE notifications
+    ADD CONSTRAINT check_notification_kind CHECK (kind IN ('back_in_stock', 'discount', 'transfer_skipped', 'transfer_failed'));
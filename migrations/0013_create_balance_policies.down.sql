@@ -0,0 +1,25 @@
+
+DELETE FROM notifications
+WHERE kind = 'policy_notice';
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS check_notification_kind;
+ALTER TABLE notifications
+    ADD CONSTRAINT check_notification_kind CHECK (kind IN ('back_in_stock', 'discount', 'transfer_skipped', 'transfer_failed'));
+
+DROP TABLE IF EXISTS policy_runs;
+
+DELETE
+FROM transactions
+WHERE kind IN ('expiry', 'balance_cap');
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_policy_write_off;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_kind;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_kind CHECK (kind IN ('transfer', 'refund'));
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS reason;
+ALTER TABLE transactions
+    ALTER COLUMN receiver_id SET NOT NULL;
@@ -0,0 +1,32 @@
+-- Сгорание монет и ограничение баланса записываются в transactions: получатель отсутствует, указывается причина
+ALTER TABLE transactions
+    ALTER COLUMN receiver_id DROP NOT NULL;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reason TEXT;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_kind;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_kind CHECK (kind IN ('transfer', 'refund', 'expiry', 'balance_cap'));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_policy_write_off CHECK (
+        (kind IN ('expiry', 'balance_cap')) = (receiver_id IS NULL) AND
+        (kind NOT IN ('expiry', 'balance_cap') OR (sender_id IS NOT NULL AND reason IS NOT NULL)));
+
+-- Создание таблицы policy_runs (запуски политик баланса по расписанию: уведомление заранее и применение)
+CREATE TABLE IF NOT EXISTS policy_runs
+(
+    policy     VARCHAR(32) NOT NULL,
+    run_at     TIMESTAMP   NOT NULL,
+    noticed_at TIMESTAMP,
+    applied_at TIMESTAMP,
+    affected   INTEGER     NOT NULL DEFAULT 0, -- пользователей затронуто
+    coins      INTEGER     NOT NULL DEFAULT 0, -- монет списано
+    PRIMARY KEY (policy, run_at),
+    CONSTRAINT check_policy CHECK (policy IN ('expiry', 'balance_cap'))
+);
+
+-- Уведомления о предстоящем списании монет
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS check_notification_kind;
+ALTER TABLE notifications
+    ADD CONSTRAINT check_notification_kind CHECK (kind IN ('back_in_stock', 'discount', 'transfer_skipped', 'transfer_failed', 'policy_notice'));
@@ -0,0 +1,4 @@
+
+DROP INDEX IF EXISTS idx_transactions_sender_created;
+
+DROP TABLE IF EXISTS transfer_reviews;
@@ -0,0 +1,23 @@
+-- Создание таблицы transfer_reviews (переводы, заблокированные антифрод-правилами, в очереди на проверку)
+CREATE TABLE IF NOT EXISTS transfer_reviews
+(
+    id          SERIAL PRIMARY KEY,
+    sender_id   INTEGER     NOT NULL,
+    receiver_id INTEGER     NOT NULL,
+    coins       INTEGER     NOT NULL CHECK (coins >= 1),
+    rule        VARCHAR(32) NOT NULL, -- сработавшее правило
+    status      VARCHAR(16) NOT NULL DEFAULT 'open',
+    reviewer_id INTEGER,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP,
+    CONSTRAINT check_review_status CHECK (status IN ('open', 'approved', 'rejected')),
+    CONSTRAINT check_review_rule CHECK (rule IN ('daily_cap', 'weekly_cap', 'recipient_cap', 'velocity', 'fresh_accounts')),
+    FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (receiver_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_reviews_open ON transfer_reviews (created_at) WHERE status = 'open';
+
+-- Исходящие переводы пользователя за последние дни для проверки лимитов
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_created ON transactions (sender_id, created_at) WHERE kind = 'transfer';
@@ -0,0 +1,7 @@
+
+DROP INDEX IF EXISTS idx_transactions_batch;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS batch_id;
+
+DROP TABLE IF EXISTS transfer_batches;
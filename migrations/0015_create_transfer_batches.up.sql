@@ -0,0 +1,15 @@
+-- Создание таблицы transfer_batches (переводы нескольким получателям одной транзакцией)
+CREATE TABLE IF NOT EXISTS transfer_batches
+(
+    id         SERIAL PRIMARY KEY,
+    sender_id  INTEGER   NOT NULL,
+    coins      INTEGER   NOT NULL CHECK (coins >= 1), -- сумма всех переводов
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE RESTRICT
+);
+
+-- Каждый перевод пакета записывается в transactions отдельно с общим batch_id
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS batch_id INTEGER REFERENCES transfer_batches (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_batch ON transactions (batch_id) WHERE batch_id IS NOT NULL;
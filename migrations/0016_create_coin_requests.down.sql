@@ -0,0 +1,4 @@
+
+DROP TABLE IF EXISTS coin_request_events;
+
+DROP TABLE IF EXISTS coin_requests;
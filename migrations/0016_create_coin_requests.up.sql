@@ -0,0 +1,36 @@
+-- Создание таблицы coin_requests (запросы монет у коллеги: плательщик принимает или отклоняет запрос)
+CREATE TABLE IF NOT EXISTS coin_requests
+(
+    id           SERIAL PRIMARY KEY,
+    requester_id INTEGER     NOT NULL,
+    payer_id     INTEGER     NOT NULL,
+    coins        INTEGER     NOT NULL CHECK (coins >= 1),
+    note         VARCHAR(255),
+    status       VARCHAR(16) NOT NULL DEFAULT 'pending',
+    expires_at   TIMESTAMP   NOT NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_coin_request_status CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired')),
+    CONSTRAINT check_coin_request_users CHECK (requester_id <> payer_id),
+    FOREIGN KEY (requester_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (payer_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_coin_requests_payer ON coin_requests (payer_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_coin_requests_requester ON coin_requests (requester_id);
+-- Фоновая задача переводит просроченные запросы в статус expired
+CREATE INDEX IF NOT EXISTS idx_coin_requests_expiry ON coin_requests (expires_at) WHERE status = 'pending';
+
+-- Создание таблицы coin_request_events (история изменений статуса запроса; actor_id NULL - изменение системой)
+CREATE TABLE IF NOT EXISTS coin_request_events
+(
+    id         SERIAL PRIMARY KEY,
+    request_id INTEGER     NOT NULL,
+    status     VARCHAR(16) NOT NULL,
+    actor_id   INTEGER,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (request_id) REFERENCES coin_requests (id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_coin_request_events_request ON coin_request_events (request_id);
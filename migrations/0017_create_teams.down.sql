@@ -0,0 +1,26 @@
+
+DELETE
+FROM transactions
+WHERE sender_team_id IS NOT NULL OR receiver_team_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_transactions_receiver_team;
+DROP INDEX IF EXISTS idx_transactions_sender_team;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_policy_write_off;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_policy_write_off CHECK (
+        (kind IN ('expiry', 'balance_cap')) = (receiver_id IS NULL) AND
+        (kind NOT IN ('expiry', 'balance_cap') OR (sender_id IS NOT NULL AND reason IS NOT NULL)));
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_receiver_account;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS receiver_team_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS sender_team_id;
+
+DROP TABLE IF EXISTS team_spends;
+
+DROP TABLE IF EXISTS team_members;
+
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,65 @@
+-- Создание таблицы teams (команды с общим кошельком; траты участников выше порога approval_threshold подтверждает владелец)
+CREATE TABLE IF NOT EXISTS teams
+(
+    id                 SERIAL PRIMARY KEY,
+    name               VARCHAR(64) NOT NULL UNIQUE,
+    coins              INTEGER     NOT NULL DEFAULT 0 CHECK (coins >= 0),
+    approval_threshold INTEGER CHECK (approval_threshold >= 1), -- NULL - подтверждение не требуется
+    created_at         TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы team_members (участники команды; spend_limit - лимит трат из кошелька за 30 дней, NULL - без лимита)
+CREATE TABLE IF NOT EXISTS team_members
+(
+    team_id     INTEGER     NOT NULL,
+    user_id     INTEGER     NOT NULL,
+    role        VARCHAR(16) NOT NULL DEFAULT 'member',
+    spend_limit INTEGER CHECK (spend_limit >= 0),
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id),
+    CONSTRAINT check_team_role CHECK (role IN ('owner', 'member')),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members (user_id);
+
+-- Создание таблицы team_spends (траты из кошелька команды: сразу или после подтверждения владельцем)
+CREATE TABLE IF NOT EXISTS team_spends
+(
+    id          SERIAL PRIMARY KEY,
+    team_id     INTEGER     NOT NULL,
+    member_id   INTEGER     NOT NULL,
+    receiver_id INTEGER     NOT NULL,
+    coins       INTEGER     NOT NULL CHECK (coins >= 1),
+    status      VARCHAR(16) NOT NULL,
+    decided_by  INTEGER,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    decided_at  TIMESTAMP,
+    CONSTRAINT check_team_spend_status CHECK (status IN ('sent', 'pending', 'approved', 'rejected')),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (member_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (receiver_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (decided_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_spends_member ON team_spends (team_id, member_id, created_at);
+
+-- Кошелёк команды как счёт в transactions: пополнение - получатель receiver_team_id,
+-- трата участником - отправитель sender_id с кошельком sender_team_id
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS sender_team_id INTEGER REFERENCES teams (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS receiver_team_id INTEGER REFERENCES teams (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_receiver_account CHECK (receiver_id IS NULL OR receiver_team_id IS NULL);
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_policy_write_off;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_policy_write_off CHECK (
+        (kind IN ('expiry', 'balance_cap')) = (receiver_id IS NULL AND receiver_team_id IS NULL) AND
+        (kind NOT IN ('expiry', 'balance_cap') OR (sender_id IS NOT NULL AND reason IS NOT NULL)));
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_team ON transactions (sender_team_id) WHERE sender_team_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_receiver_team ON transactions (receiver_team_id) WHERE receiver_team_id IS NOT NULL;
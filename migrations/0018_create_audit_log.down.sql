@@ -0,0 +1,4 @@
+
+DROP TABLE IF EXISTS audit_log;
+
+DROP FUNCTION IF EXISTS forbid_audit_log_change();
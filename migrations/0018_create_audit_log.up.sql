@@ -0,0 +1,44 @@
+-- Создание таблицы audit_log (журнал событий безопасности и операций с монетами, только добавление).
+-- Записи связаны в цепочку: hash каждой записи считается от prev_hash и её полей, поэтому изменение
+-- или удаление записи обнаруживается проверкой цепочки. actor_id без внешнего ключа - журнал переживает пользователя
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id         BIGSERIAL PRIMARY KEY,
+    actor_id   INTEGER,     -- NULL - действие системы или анонимного пользователя
+    actor      VARCHAR(255),
+    action     VARCHAR(64)  NOT NULL,
+    target     VARCHAR(255) NOT NULL DEFAULT '',
+    details    TEXT,        -- JSON, хранится как текст, чтобы хэш совпадал побайтно
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    user_agent TEXT         NOT NULL DEFAULT '',
+    request_id VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL,
+    prev_hash  CHAR(64)     NOT NULL,
+    hash       CHAR(64)     NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log (action, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log (target, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_request ON audit_log (request_id) WHERE request_id <> '';
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log (created_at);
+
+-- Записи журнала нельзя изменить или удалить
+CREATE OR REPLACE FUNCTION forbid_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION forbid_audit_log_change();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE
+    ON audit_log
+    FOR EACH STATEMENT
+EXECUTE FUNCTION forbid_audit_log_change();
@@ -0,0 +1,46 @@
+-- Создание таблицы outbox_events (события для внешних систем; пишутся в той же транзакции, что и изменение,
+-- и рассылаются фоновой задачей; dispatched_at - события разложены по доставкам подписчикам)
+CREATE TABLE IF NOT EXISTS outbox_events
+(
+    id            BIGSERIAL PRIMARY KEY,
+    event_type    VARCHAR(64) NOT NULL,
+    payload       TEXT        NOT NULL, -- JSON
+    created_at    TIMESTAMP   NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_undispatched ON outbox_events (id) WHERE dispatched_at IS NULL;
+
+-- Создание таблицы webhook_subscriptions (адреса, на которые отправляются события; events пустой - все события)
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id         SERIAL PRIMARY KEY,
+    url        VARCHAR(2048) NOT NULL,
+    secret     VARCHAR(128)  NOT NULL, -- ключ подписи HMAC-SHA256
+    events     TEXT[]        NOT NULL DEFAULT '{}',
+    active     BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP     NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP     NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы webhook_deliveries (доставка события подписчику: повторяется с экспоненциальной задержкой,
+-- после исчерпания попыток переходит в dead и может быть отправлена повторно администратором)
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        BIGINT      NOT NULL,
+    subscription_id INTEGER     NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    created_at      TIMESTAMP   NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMP,
+    UNIQUE (event_id, subscription_id),
+    CONSTRAINT check_webhook_delivery_status CHECK (status IN ('pending', 'delivered', 'dead')),
+    FOREIGN KEY (event_id) REFERENCES outbox_events (id) ON DELETE CASCADE,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, status);
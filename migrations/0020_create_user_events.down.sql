@@ -0,0 +1,12 @@
+
+DROP TRIGGER IF EXISTS purchases_event ON purchases;
+DROP FUNCTION IF EXISTS record_purchase_event();
+
+DROP TRIGGER IF EXISTS transactions_transfer_event ON transactions;
+DROP FUNCTION IF EXISTS record_transfer_event();
+
+DROP TRIGGER IF EXISTS users_balance_event ON users;
+DROP FUNCTION IF EXISTS record_balance_event();
+
+DROP TABLE IF EXISTS user_events;
+DROP FUNCTION IF EXISTS notify_user_event();
@@ -0,0 +1,96 @@
+-- Создание таблицы user_events (изменения, которые видит пользователь в потоке /api/events: баланс, входящие переводы,
+-- покупки и их статусы; события пишут триггеры в той же транзакции, что и изменение, поэтому ни один путь
+-- изменения баланса не пропускается; ID события используется для продолжения потока по Last-Event-ID)
+CREATE TABLE IF NOT EXISTS user_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    kind       VARCHAR(32) NOT NULL,
+    payload    TEXT        NOT NULL, -- JSON
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_user_event_kind CHECK (kind IN ('balance', 'transfer', 'purchase')),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_events_user ON user_events (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_user_events_created ON user_events (created_at);
+
+-- Слушатели канала user_events получают ID пользователя при фиксации транзакции (повторы в одной транзакции
+-- объединяются) и читают его новые события, поэтому поток работает на любой реплике
+CREATE OR REPLACE FUNCTION notify_user_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('user_events', NEW.user_id::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_events_notify
+    AFTER INSERT
+    ON user_events
+    FOR EACH ROW
+EXECUTE FUNCTION notify_user_event();
+
+-- Изменение баланса
+CREATE OR REPLACE FUNCTION record_balance_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO user_events (user_id, kind, payload)
+    VALUES (NEW.id, 'balance', json_build_object('coins', NEW.coins)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_balance_event
+    AFTER UPDATE OF coins
+    ON users
+    FOR EACH ROW
+    WHEN (OLD.coins IS DISTINCT FROM NEW.coins)
+EXECUTE FUNCTION record_balance_event();
+
+-- Входящий перевод: от пользователя, из кошелька команды или возврат от магазина (fromUser - NULL)
+CREATE OR REPLACE FUNCTION record_transfer_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO user_events (user_id, kind, payload)
+    VALUES (NEW.receiver_id, 'transfer', json_build_object(
+            'fromUser', COALESCE((SELECT name FROM teams WHERE id = NEW.sender_team_id),
+                                 (SELECT username FROM users WHERE id = NEW.sender_id)),
+            'amount', NEW.coins,
+            'kind', NEW.kind,
+            'batchId', NEW.batch_id)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_transfer_event
+    AFTER INSERT
+    ON transactions
+    FOR EACH ROW
+    WHEN (NEW.receiver_id IS NOT NULL)
+EXECUTE FUNCTION record_transfer_event();
+
+-- Покупка и изменение её статуса
+CREATE OR REPLACE FUNCTION record_purchase_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.status = NEW.status THEN
+        RETURN NULL;
+    END IF;
+    INSERT INTO user_events (user_id, kind, payload)
+    VALUES (NEW.user_id, 'purchase', json_build_object(
+            'id', NEW.id,
+            'item', NEW.item_slug,
+            'sku', NEW.sku,
+            'price', NEW.price,
+            'status', NEW.status,
+            'office', NEW.office)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER purchases_event
+    AFTER INSERT OR UPDATE OF status
+    ON purchases
+    FOR EACH ROW
+EXECUTE FUNCTION record_purchase_event();
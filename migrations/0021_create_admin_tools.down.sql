@@ -0,0 +1,13 @@
+DELETE
+FROM transactions
+WHERE kind = 'grant';
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_grant;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_kind;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_kind CHECK (kind IN ('transfer', 'refund', 'expiry', 'balance_cap'));
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locked_at;
@@ -0,0 +1,12 @@
+-- Заблокированный аккаунт не может войти (NULL - аккаунт активен)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP;
+
+-- Начисление монет администратором (merchshopctl grant): отправитель отсутствует, указывается причина
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_kind;
+ALTER TABLE transactions
+    ADD CONSTRAINT check_kind CHECK (kind IN ('transfer', 'refund', 'expiry', 'balance_cap', 'grant'));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_grant CHECK (
+        kind <> 'grant' OR (sender_id IS NULL AND sender_team_id IS NULL AND receiver_id IS NOT NULL AND reason IS NOT NULL));
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS directory_managed;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deactivated_at;
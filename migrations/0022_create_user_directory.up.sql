@@ -0,0 +1,7 @@
+-- Деактивированный аккаунт (сотрудник уволился) не может войти и получать монеты, строки пользователя не удаляются
+-- (NULL - аккаунт активен)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+-- Аккаунт ведётся по выгрузке кадрового справочника: синхронизация деактивирует его, если сотрудника нет в выгрузке
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS directory_managed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transfer_reviews_coin_request;
+ALTER TABLE transfer_reviews
+    DROP COLUMN IF EXISTS coin_request_id;
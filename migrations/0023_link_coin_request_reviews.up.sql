@@ -0,0 +1,7 @@
+-- Запрос монет, оплата которого заблокирована антифрод-правилами: одобрение проверки оплачивает запрос,
+-- запрос без оплаты закрывает проверку
+ALTER TABLE transfer_reviews
+    ADD COLUMN IF NOT EXISTS coin_request_id INTEGER REFERENCES coin_requests (id) ON DELETE CASCADE;
+
+-- У запроса не больше одной открытой проверки
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_reviews_coin_request ON transfer_reviews (coin_request_id) WHERE status = 'open';
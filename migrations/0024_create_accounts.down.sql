@@ -0,0 +1,93 @@
+DROP INDEX IF EXISTS idx_transfer_reviews_team_spend;
+
+-- Проверки с кошельком команды не переносятся в прежнюю схему
+DELETE
+FROM transfer_reviews
+WHERE team_spend_id IS NOT NULL
+   OR receiver_account_id IN (SELECT id FROM accounts WHERE team_id IS NOT NULL)
+   OR sender_account_id IN (SELECT id FROM accounts WHERE team_id IS NOT NULL);
+
+ALTER TABLE transfer_reviews
+    ADD COLUMN IF NOT EXISTS receiver_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+UPDATE transfer_reviews r
+SET receiver_id = (SELECT user_id FROM accounts WHERE id = r.receiver_account_id);
+ALTER TABLE transfer_reviews
+    ALTER COLUMN receiver_id SET NOT NULL;
+ALTER TABLE transfer_reviews
+    DROP COLUMN IF EXISTS team_spend_id;
+ALTER TABLE transfer_reviews
+    DROP COLUMN IF EXISTS receiver_account_id;
+ALTER TABLE transfer_reviews
+    DROP COLUMN IF EXISTS sender_account_id;
+ALTER TABLE transfer_reviews
+    RENAME COLUMN initiator_id TO sender_id;
+
+UPDATE team_spends
+SET status = 'rejected'
+WHERE status = 'held';
+ALTER TABLE team_spends
+    DROP CONSTRAINT IF EXISTS check_team_spend_status;
+ALTER TABLE team_spends
+    ADD CONSTRAINT check_team_spend_status CHECK (status IN ('sent', 'pending', 'approved', 'rejected'));
+
+CREATE OR REPLACE FUNCTION record_transfer_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO user_events (user_id, kind, payload)
+    VALUES (NEW.receiver_id, 'transfer', json_build_object(
+            'fromUser', COALESCE((SELECT name FROM teams WHERE id = NEW.sender_team_id),
+                                 (SELECT username FROM users WHERE id = NEW.sender_id)),
+            'amount', NEW.coins,
+            'kind', NEW.kind,
+            'batchId', NEW.batch_id)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS sender_team_id INTEGER REFERENCES teams (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS receiver_team_id INTEGER REFERENCES teams (id) ON DELETE RESTRICT;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_grant;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_policy_write_off;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_transfer_sender;
+
+UPDATE transactions t
+SET sender_team_id   = (SELECT team_id FROM accounts WHERE id = t.sender_account_id),
+    receiver_team_id = (SELECT team_id FROM accounts WHERE id = t.receiver_account_id),
+    sender_id        = COALESCE(t.sender_id, t.initiator_id);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT check_transfer_sender CHECK (kind <> 'transfer' OR sender_id IS NOT NULL);
+ALTER TABLE transactions
+    ADD CONSTRAINT check_receiver_account CHECK (receiver_id IS NULL OR receiver_team_id IS NULL);
+ALTER TABLE transactions
+    ADD CONSTRAINT check_policy_write_off CHECK (
+        (kind IN ('expiry', 'balance_cap')) = (receiver_id IS NULL AND receiver_team_id IS NULL) AND
+        (kind NOT IN ('expiry', 'balance_cap') OR (sender_id IS NOT NULL AND reason IS NOT NULL)));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_grant CHECK (
+        kind <> 'grant' OR (sender_id IS NULL AND sender_team_id IS NULL AND receiver_id IS NOT NULL AND reason IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_team ON transactions (sender_team_id) WHERE sender_team_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_receiver_team ON transactions (receiver_team_id) WHERE receiver_team_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_transactions_initiator_created;
+DROP INDEX IF EXISTS idx_transactions_receiver_account;
+DROP INDEX IF EXISTS idx_transactions_sender_account;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS initiator_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS receiver_account_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS sender_account_id;
+
+DROP TRIGGER IF EXISTS teams_open_account ON teams;
+DROP TRIGGER IF EXISTS users_open_account ON users;
+DROP FUNCTION IF EXISTS open_account();
+
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,140 @@
+-- Создание таблицы accounts (счета, между которыми переводятся монеты: кошелёк пользователя или команды;
+-- name - имя владельца, которое видит вторая сторона перевода)
+CREATE TABLE IF NOT EXISTS accounts
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER UNIQUE,
+    team_id    INTEGER UNIQUE,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_account_owner CHECK (num_nonnulls(user_id, team_id) = 1),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+
+INSERT INTO accounts (user_id, name, created_at)
+SELECT id, username, created_at
+FROM users
+ON CONFLICT DO NOTHING;
+INSERT INTO accounts (team_id, name, created_at)
+SELECT id, name, created_at
+FROM teams
+ON CONFLICT DO NOTHING;
+
+-- Счёт открывается вместе с пользователем или командой
+CREATE OR REPLACE FUNCTION open_account() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_TABLE_NAME = 'users' THEN
+        INSERT INTO accounts (user_id, name, created_at) VALUES (NEW.id, NEW.username, NEW.created_at);
+    ELSE
+        INSERT INTO accounts (team_id, name, created_at) VALUES (NEW.id, NEW.name, NEW.created_at);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_open_account
+    AFTER INSERT
+    ON users
+    FOR EACH ROW
+EXECUTE FUNCTION open_account();
+
+CREATE TRIGGER teams_open_account
+    AFTER INSERT
+    ON teams
+    FOR EACH ROW
+EXECUTE FUNCTION open_account();
+
+-- Транзакции ссылаются на счета отправителя и получателя; initiator_id - пользователь, сделавший перевод
+-- (участник при трате из кошелька команды), по его переводам проверяются антифрод-правила.
+-- sender_id и receiver_id - пользователь-владелец счёта, NULL для кошелька команды
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS sender_account_id INTEGER REFERENCES accounts (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS receiver_account_id INTEGER REFERENCES accounts (id) ON DELETE RESTRICT;
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS initiator_id INTEGER REFERENCES users (id) ON DELETE RESTRICT;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_transfer_sender;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_policy_write_off;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_grant;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_receiver_account;
+
+UPDATE transactions t
+SET sender_account_id   = (SELECT a.id FROM accounts a WHERE a.team_id = t.sender_team_id OR (t.sender_team_id IS NULL AND a.user_id = t.sender_id)),
+    receiver_account_id = (SELECT a.id FROM accounts a WHERE a.team_id = t.receiver_team_id OR a.user_id = t.receiver_id),
+    initiator_id        = CASE WHEN t.kind = 'transfer' THEN t.sender_id END,
+    sender_id           = CASE WHEN t.sender_team_id IS NULL THEN t.sender_id END;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT check_transfer_sender CHECK (
+        kind <> 'transfer' OR (sender_account_id IS NOT NULL AND receiver_account_id IS NOT NULL AND initiator_id IS NOT NULL));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_policy_write_off CHECK (
+        (kind IN ('expiry', 'balance_cap')) = (receiver_account_id IS NULL) AND
+        (kind NOT IN ('expiry', 'balance_cap') OR (sender_id IS NOT NULL AND reason IS NOT NULL)));
+ALTER TABLE transactions
+    ADD CONSTRAINT check_grant CHECK (
+        kind <> 'grant' OR (sender_account_id IS NULL AND receiver_id IS NOT NULL AND reason IS NOT NULL));
+
+DROP INDEX IF EXISTS idx_transactions_sender_team;
+DROP INDEX IF EXISTS idx_transactions_receiver_team;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS sender_team_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS receiver_team_id;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_account ON transactions (sender_account_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_receiver_account ON transactions (receiver_account_id);
+-- Переводы пользователя за последние дни для проверки лимитов, включая пополнения и траты кошельков команд
+CREATE INDEX IF NOT EXISTS idx_transactions_initiator_created ON transactions (initiator_id, created_at) WHERE kind = 'transfer';
+
+-- Входящий перевод: от пользователя, из кошелька команды или возврат от магазина (fromUser - NULL)
+CREATE OR REPLACE FUNCTION record_transfer_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO user_events (user_id, kind, payload)
+    VALUES (NEW.receiver_id, 'transfer', json_build_object(
+            'fromUser', (SELECT name FROM accounts WHERE id = NEW.sender_account_id),
+            'amount', NEW.coins,
+            'kind', NEW.kind,
+            'batchId', NEW.batch_id)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Траты из кошелька команды, заблокированные антифрод-правилами, ждут проверки (held)
+ALTER TABLE team_spends
+    DROP CONSTRAINT IF EXISTS check_team_spend_status;
+ALTER TABLE team_spends
+    ADD CONSTRAINT check_team_spend_status CHECK (status IN ('sent', 'pending', 'held', 'approved', 'rejected'));
+
+-- Проверки переводов между счетами: initiator_id - пользователь, сделавший перевод;
+-- одобрение проверки траты из кошелька команды делает трату, отклонение - отклоняет
+ALTER TABLE transfer_reviews
+    RENAME COLUMN sender_id TO initiator_id;
+ALTER TABLE transfer_reviews
+    ADD COLUMN IF NOT EXISTS sender_account_id INTEGER REFERENCES accounts (id) ON DELETE CASCADE;
+ALTER TABLE transfer_reviews
+    ADD COLUMN IF NOT EXISTS receiver_account_id INTEGER REFERENCES accounts (id) ON DELETE CASCADE;
+ALTER TABLE transfer_reviews
+    ADD COLUMN IF NOT EXISTS team_spend_id INTEGER REFERENCES team_spends (id) ON DELETE CASCADE;
+
+UPDATE transfer_reviews r
+SET sender_account_id   = (SELECT id FROM accounts WHERE user_id = r.initiator_id),
+    receiver_account_id = (SELECT id FROM accounts WHERE user_id = r.receiver_id);
+
+ALTER TABLE transfer_reviews
+    ALTER COLUMN sender_account_id SET NOT NULL;
+ALTER TABLE transfer_reviews
+    ALTER COLUMN receiver_account_id SET NOT NULL;
+ALTER TABLE transfer_reviews
+    DROP COLUMN IF EXISTS receiver_id;
+
+-- У траты не больше одной открытой проверки
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_reviews_team_spend ON transfer_reviews (team_spend_id) WHERE status = 'open';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_log_chain;
+
+-- Цепочки снова сливаются в одну, записи не из цепочки 0 перестают проходить проверку
+ALTER TABLE audit_log
+    DROP COLUMN IF EXISTS chain;
@@ -0,0 +1,6 @@
+-- Журнал разбит на несколько цепочек (chain), у каждой свой prev_hash и своя блокировка при добавлении,
+-- поэтому транзакции, изменяющие разные объекты, не ждут друг друга. Существующие записи остаются в цепочке 0
+ALTER TABLE audit_log
+    ADD COLUMN IF NOT EXISTS chain SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_chain ON audit_log (chain, id);
@@ -0,0 +1,3 @@
+-- Аккаунт без пароля снова может занять первый вошедший
+ALTER TABLE users
+    DROP COLUMN IF EXISTS claim_token_hash;
@@ -0,0 +1,5 @@
+-- Аккаунт, созданный импортом или синхронизацией со справочником, не имеет пароля; первый вход задаёт его
+-- только с одноразовым токеном, выданным вместе с аккаунтом. Хранится SHA-256 токена, после входа - NULL.
+-- Существующим аккаунтам без пароля токен выдаётся заново командой claim-token
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS claim_token_hash VARCHAR(64);
@@ -0,0 +1,3 @@
+-- Синхронизация снова подхватывает активные аккаунты вне справочника
+ALTER TABLE users
+    DROP COLUMN IF EXISTS staff_managed;
@@ -0,0 +1,13 @@
+-- Аккаунт, деактивированный или реактивированный сотрудниками поддержки, принадлежит им: синхронизация
+-- со справочником не подхватывает его снова и не деактивирует. Владение определяется по журналу - синхронизация
+-- меняет только аккаунты из справочника, поэтому такие записи об аккаунтах вне справочника сделаны сотрудниками
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS staff_managed BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users u
+SET staff_managed = TRUE
+WHERE NOT u.directory_managed
+  AND EXISTS (SELECT 1
+              FROM audit_log a
+              WHERE a.target = 'user:' || u.id
+                AND a.action IN ('user.deactivated', 'user.reactivated'));